@@ -0,0 +1,204 @@
+package avl_tree
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+An ordered map from K to V built on top of the AVL tree.  The keys are ordered by a compare
+function, cmp(a,b) < 0 if a < b, 0 if equal and > 0 if a > b.  The map uses the same insert,
+delete and balancing code as AvlTree.  This is a no-lock, not thread safe version.
+
+* 	Put — set the value for a key, returns true if the key is new.								O(log|2(n))
+* 	Get — returns the value for a key and true, or false if the key is not in the map.			O(log|2(n))
+* 	GetOrInsert — returns the current value if there is one, else inserts the value.			O(log|2(n))
+* 	Delete — removes a key, returns true if it was in the map.									O(log|2(n))
+* 	Floor — the largest key that is less than or equal to a key.								O(log|2(n))
+* 	Ceiling — the smallest key that is greater than or equal to a key.							O(log|2(n))
+* 	Range — iterate in order over the keys from lo to hi, both included.						O(log|2(n)+k)
+* 	All — iterate in order over all the keys.													O(n)
+* 	Rank — the number of keys that are less than a key.										O(log|2(n))
+* 	IsEmpty — Returns true if the map is empty													O(1)
+* 	Length — Returns number of keys in the map.													O(1)
+* 	Truncate - Delete all the keys in the map. 													O(1)
+
+*/
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+
+	"github.com/pschlump/pluto/comparable"
+)
+
+// avlMapEntry is the item that AvlMap keeps in the tree.  It carries the compare function
+// for the keys so that it can satisfy comparable.Comparable.
+type avlMapEntry[K, V any] struct {
+	key     K
+	value   V
+	compare func(a, b K) int
+}
+
+// Compare implements the Compare function to satisfy the interface requirements.
+func (aa avlMapEntry[K, V]) Compare(x comparable.Comparable) int {
+	if bb, ok := x.(avlMapEntry[K, V]); ok {
+		return aa.compare(aa.key, bb.key)
+	} else if bb, ok := x.(*avlMapEntry[K, V]); ok {
+		return aa.compare(aa.key, bb.key)
+	}
+	panic(fmt.Sprintf("Passed invalid type %T to a Compare function.", x))
+}
+
+// AvlMap is a generic ordered map from K to V.
+type AvlMap[K, V any] struct {
+	tree    AvlTree[avlMapEntry[K, V]]
+	compare func(a, b K) int
+}
+
+// NewAvlMap returns an empty map with keys ordered by `compare`.
+func NewAvlMap[K, V any](compare func(a, b K) int) *AvlMap[K, V] {
+	if compare == nil {
+		panic("compare function sholud not be a nil")
+	}
+	return &AvlMap[K, V]{compare: compare}
+}
+
+// NewOrderedAvlMap returns an empty map with keys in their natural order.
+func NewOrderedAvlMap[K cmp.Ordered, V any]() *AvlMap[K, V] {
+	return NewAvlMap[K, V](cmp.Compare[K])
+}
+
+// probe builds an entry used to look up `key`.
+func (tt *AvlMap[K, V]) probe(key K) *avlMapEntry[K, V] {
+	return &avlMapEntry[K, V]{key: key, compare: tt.compare}
+}
+
+// IsEmpty will return true if the map is empty
+func (tt *AvlMap[K, V]) IsEmpty() bool {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	return (*tt).tree.IsEmpty()
+}
+
+// Length returns the number of keys in the map.
+func (tt *AvlMap[K, V]) Length() int {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	return (*tt).tree.Length()
+}
+
+// Truncate removes all the keys from the map.
+func (tt *AvlMap[K, V]) Truncate() {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	(*tt).tree.Truncate()
+}
+
+// Put sets the value for `key`, replacing any current value.  It returns true if the key
+// was not already in the map.
+func (tt *AvlMap[K, V]) Put(key K, value V) (isNew bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	return (*tt).tree.Insert(&avlMapEntry[K, V]{key: key, value: value, compare: tt.compare})
+}
+
+// Get returns the value for `key`.  If the key is not in the map then found is false.
+func (tt *AvlMap[K, V]) Get(key K) (value V, found bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	if item := (*tt).tree.Search(tt.probe(key)); item != nil {
+		return item.value, true
+	}
+	return
+}
+
+// GetOrInsert returns the current value for `key` with loaded true if the key is in the map.
+// Otherwise it inserts `value` and returns it with loaded false.
+func (tt *AvlMap[K, V]) GetOrInsert(key K, value V) (actual V, loaded bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	if item := (*tt).tree.Search(tt.probe(key)); item != nil {
+		return item.value, true
+	}
+	(*tt).tree.Insert(&avlMapEntry[K, V]{key: key, value: value, compare: tt.compare})
+	return value, false
+}
+
+// Delete removes `key` from the map.  It returns true if the key was in the map.
+func (tt *AvlMap[K, V]) Delete(key K) (found bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	return (*tt).tree.Delete(tt.probe(key))
+}
+
+// Floor returns the largest key that is less than or equal to `key` and its value.
+// If there is no such key then found is false.
+func (tt *AvlMap[K, V]) Floor(key K) (k K, v V, found bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	if item := (*tt).tree.nlBelow(tt.probe(key), true); item != nil {
+		return item.key, item.value, true
+	}
+	return
+}
+
+// Ceiling returns the smallest key that is greater than or equal to `key` and its value.
+// If there is no such key then found is false.
+func (tt *AvlMap[K, V]) Ceiling(key K) (k K, v V, found bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	if item := (*tt).tree.nlAbove(tt.probe(key), true); item != nil {
+		return item.key, item.value, true
+	}
+	return
+}
+
+// Range returns an iterator over the keys from `lo` to `hi`, both included, in order.
+//
+//	for k, v := range mm.Range(10, 20) {
+//		...
+//	}
+func (tt *AvlMap[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	return func(yield func(K, V) bool) {
+		(*tt).tree.nlRange(tt.probe(lo), tt.probe(hi), func(item *avlMapEntry[K, V]) bool {
+			return yield(item.key, item.value)
+		})
+	}
+}
+
+// All returns an iterator over all the keys in order.
+func (tt *AvlMap[K, V]) All() iter.Seq2[K, V] {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	return func(yield func(K, V) bool) {
+		(*tt).tree.nlRange(nil, nil, func(item *avlMapEntry[K, V]) bool {
+			return yield(item.key, item.value)
+		})
+	}
+}
+
+// Rank returns the number of keys in the map that are less than `key`.  If `key` is in
+// the map this is its 0 based position in order.
+func (tt *AvlMap[K, V]) Rank(key K) int {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	return (*tt).tree.nlRank(tt.probe(key), false)
+}
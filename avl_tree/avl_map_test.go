@@ -0,0 +1,245 @@
+package avl_tree
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAvlMapPutGet(t *testing.T) {
+	mm := NewOrderedAvlMap[string, int]()
+
+	if !mm.IsEmpty() {
+		t.Errorf("Expected empty map")
+	}
+	if _, found := mm.Get("a"); found {
+		t.Errorf("Expected not found in empty map")
+	}
+
+	if !mm.Put("b", 2) {
+		t.Errorf("Expected new key")
+	}
+	mm.Put("a", 1)
+	mm.Put("c", 3)
+	if mm.Put("b", 22) {
+		t.Errorf("Expected replace of existing key")
+	}
+	if mm.Length() != 3 {
+		t.Errorf("Expected length of 3 got %d", mm.Length())
+	}
+	if v, found := mm.Get("b"); !found || v != 22 {
+		t.Errorf("Expected 22 got %d %v", v, found)
+	}
+
+	if v, loaded := mm.GetOrInsert("a", 100); !loaded || v != 1 {
+		t.Errorf("Expected 1 loaded got %d %v", v, loaded)
+	}
+	if v, loaded := mm.GetOrInsert("d", 4); loaded || v != 4 {
+		t.Errorf("Expected 4 inserted got %d %v", v, loaded)
+	}
+	if mm.Length() != 4 {
+		t.Errorf("Expected length of 4 got %d", mm.Length())
+	}
+
+	if !mm.Delete("a") {
+		t.Errorf("Expected delete of a")
+	}
+	if mm.Delete("a") {
+		t.Errorf("Expected a to be gone")
+	}
+	if _, found := mm.Get("a"); found {
+		t.Errorf("Expected a to be gone")
+	}
+
+	mm.Truncate()
+	if !mm.IsEmpty() || mm.Length() != 0 {
+		t.Errorf("Expected empty map after Truncate")
+	}
+}
+
+func TestAvlMapFloorCeiling(t *testing.T) {
+	mm := NewOrderedAvlMap[int, string]()
+	for _, k := range []int{10, 20, 30, 40} {
+		mm.Put(k, strings.Repeat("x", k/10))
+	}
+
+	tests := []struct {
+		key         int
+		floor, ceil int
+		hasF, hasC  bool
+	}{
+		{key: 5, ceil: 10, hasC: true},
+		{key: 10, floor: 10, ceil: 10, hasF: true, hasC: true},
+		{key: 25, floor: 20, ceil: 30, hasF: true, hasC: true},
+		{key: 40, floor: 40, ceil: 40, hasF: true, hasC: true},
+		{key: 45, floor: 40, hasF: true},
+	}
+	for ii, test := range tests {
+		if k, _, found := mm.Floor(test.key); found != test.hasF || (found && k != test.floor) {
+			t.Errorf("Test %d, Floor(%d) expected %d %v got %d %v", ii, test.key, test.floor, test.hasF, k, found)
+		}
+		if k, _, found := mm.Ceiling(test.key); found != test.hasC || (found && k != test.ceil) {
+			t.Errorf("Test %d, Ceiling(%d) expected %d %v got %d %v", ii, test.key, test.ceil, test.hasC, k, found)
+		}
+	}
+	if _, v, _ := mm.Floor(35); v != "xxx" {
+		t.Errorf("Expected xxx got %s", v)
+	}
+}
+
+func TestAvlMapRangeRank(t *testing.T) {
+	mm := NewOrderedAvlMap[int, int]()
+	for k := 0; k < 100; k += 2 {
+		mm.Put(k, k*10)
+	}
+
+	var got []int
+	for k, v := range mm.Range(11, 21) {
+		if v != k*10 {
+			t.Errorf("Expected value %d got %d", k*10, v)
+		}
+		got = append(got, k)
+	}
+	if !slices.Equal(got, []int{12, 14, 16, 18, 20}) {
+		t.Errorf("Range(11,21) got %v", got)
+	}
+
+	got = got[:0]
+	for k := range mm.Range(90, 200) {
+		got = append(got, k)
+		if len(got) == 3 {
+			break
+		}
+	}
+	if !slices.Equal(got, []int{90, 92, 94}) {
+		t.Errorf("Range(90,200) with break got %v", got)
+	}
+
+	n := 0
+	for range mm.Range(30, 20) {
+		n++
+	}
+	if n != 0 {
+		t.Errorf("Expected empty range got %d items", n)
+	}
+
+	if r := mm.Rank(0); r != 0 {
+		t.Errorf("Rank(0) expected 0 got %d", r)
+	}
+	if r := mm.Rank(20); r != 10 {
+		t.Errorf("Rank(20) expected 10 got %d", r)
+	}
+	if r := mm.Rank(21); r != 11 {
+		t.Errorf("Rank(21) expected 11 got %d", r)
+	}
+	if r := mm.Rank(1000); r != 50 {
+		t.Errorf("Rank(1000) expected 50 got %d", r)
+	}
+}
+
+func TestAvlMapComparator(t *testing.T) {
+	// Case insensitive, in reverse order.
+	mm := NewAvlMap[string, int](func(a, b string) int {
+		return strings.Compare(strings.ToLower(b), strings.ToLower(a))
+	})
+	mm.Put("apple", 1)
+	mm.Put("Banana", 2)
+	mm.Put("cherry", 3)
+	if mm.Put("APPLE", 4) {
+		t.Errorf("Expected APPLE to replace apple")
+	}
+
+	var got []string
+	for k := range mm.All() {
+		got = append(got, k)
+	}
+	if !slices.Equal(got, []string{"cherry", "Banana", "APPLE"}) {
+		t.Errorf("Expected reverse order got %v", got)
+	}
+	if v, found := mm.Get("BANANA"); !found || v != 2 {
+		t.Errorf("Expected 2 got %d %v", v, found)
+	}
+}
+
+func TestAvlMapModel(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1001))
+	mm := NewOrderedAvlMap[int, int]()
+	model := make(map[int]int)
+
+	for ii := 0; ii < 3000; ii++ {
+		k := rnd.Intn(200)
+		switch rnd.Intn(4) {
+		case 0, 1:
+			_, had := model[k]
+			if isNew := mm.Put(k, ii); isNew == had {
+				t.Fatalf("Step %d, Put(%d) expected isNew %v", ii, k, !had)
+			}
+			model[k] = ii
+		case 2:
+			_, had := model[k]
+			if found := mm.Delete(k); found != had {
+				t.Fatalf("Step %d, Delete(%d) expected %v", ii, k, had)
+			}
+			delete(model, k)
+		case 3:
+			want, had := model[k]
+			if v, found := mm.Get(k); found != had || v != want {
+				t.Fatalf("Step %d, Get(%d) expected %d %v got %d %v", ii, k, want, had, v, found)
+			}
+		}
+		if mm.Length() != len(model) {
+			t.Fatalf("Step %d, expected length %d got %d", ii, len(model), mm.Length())
+		}
+	}
+
+	keys := make([]int, 0, len(model))
+	for k := range model {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var got []int
+	for k, v := range mm.All() {
+		if v != model[k] {
+			t.Errorf("Key %d expected value %d got %d", k, model[k], v)
+		}
+		got = append(got, k)
+	}
+	if !slices.Equal(got, keys) {
+		t.Errorf("Expected keys %v got %v", keys, got)
+	}
+
+	for k := -1; k <= 201; k++ {
+		pos, exact := slices.BinarySearch(keys, k)
+		if r := mm.Rank(k); r != pos {
+			t.Errorf("Rank(%d) expected %d got %d", k, pos, r)
+		}
+		fk, _, found := mm.Floor(k)
+		if exact {
+			if !found || fk != k {
+				t.Errorf("Floor(%d) expected %d got %d %v", k, k, fk, found)
+			}
+		} else if pos == 0 {
+			if found {
+				t.Errorf("Floor(%d) expected none got %d", k, fk)
+			}
+		} else if !found || fk != keys[pos-1] {
+			t.Errorf("Floor(%d) expected %d got %d %v", k, keys[pos-1], fk, found)
+		}
+		ck, _, found := mm.Ceiling(k)
+		if pos == len(keys) {
+			if found {
+				t.Errorf("Ceiling(%d) expected none got %d", k, ck)
+			}
+		} else if !found || ck != keys[pos] {
+			t.Errorf("Ceiling(%d) expected %d got %d %v", k, keys[pos], ck, found)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package avl_tree
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/pschlump/pluto/containertest"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// testItem makes the data for the shared conformance tests.
+var testItem = containertest.Item[TestTreeNode]{
+	New: func(n int) *TestTreeNode { return &TestTreeNode{S: fmt.Sprintf("%04d", n)} },
+	Key: func(x *TestTreeNode) int { n, _ := strconv.Atoi(x.S); return n },
+}
+
+func TestConformance(t *testing.T) {
+	containertest.TestTree(t, func() iface_list.TreeDataType[TestTreeNode] { return NewAvlTree[TestTreeNode]() }, testItem)
+}
@@ -0,0 +1,67 @@
+package avl_tree
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pschlump/pluto/dot"
+	"github.com/pschlump/pluto/stack"
+)
+
+// WriteDOT writes the tree in graphviz DOT format to `fo`.  Each node is labeled with its
+// data, from opts.Label, followed by its height (h) and balance (b), and the edges to the
+// children are labeled "L" and "R".
+func (tt *AvlTree[T]) WriteDOT(fo io.Writer, opts *dot.Options) (err error) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	if err = dot.WriteHeader(fo, opts); err != nil {
+		return
+	}
+
+	// Non-recursive pre-order walk, each node is given an id when it is pushed.
+	type dotNode struct {
+		node *AvlTreeElement[T]
+		id   int
+	}
+	var stk stack.Stack[dotNode]
+	nextId := 0
+	if tt.root != nil {
+		stk.Push(dotNode{node: tt.root, id: nextId})
+		nextId++
+	}
+	for !stk.IsEmpty() {
+		cur, _ := stk.Pop()
+		name := fmt.Sprintf("n%d", cur.id)
+		label := opts.NodeLabel(*cur.node.data)
+		label += fmt.Sprintf("\nh=%d b=%d", cur.node.height, tt.calcAvlBalance(cur.node))
+		if err = dot.WriteNode(fo, name, map[string]string{"label": label}); err != nil {
+			return
+		}
+		// Number the children left first but push the right one first so the left is walked first.
+		var children []dotNode
+		for _, cc := range []struct {
+			node *AvlTreeElement[T]
+			side string
+		}{{cur.node.left, "L"}, {cur.node.right, "R"}} {
+			if cc.node == nil {
+				continue
+			}
+			if err = dot.WriteEdge(fo, name, fmt.Sprintf("n%d", nextId), map[string]string{"label": cc.side}); err != nil {
+				return
+			}
+			children = append(children, dotNode{node: cc.node, id: nextId})
+			nextId++
+		}
+		for i := len(children) - 1; i >= 0; i-- {
+			stk.Push(children[i])
+		}
+	}
+	return dot.WriteFooter(fo)
+}
@@ -1,25 +1,33 @@
 package avl_tree
 
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
 import (
 	"github.com/pschlump/pluto/comparable"
 	"github.com/pschlump/pluto/stack"
 )
 
-// Implement a state machine based on a YCombinator that allows
-// inorder iteration over a binary tree.
-
-// An iteration type that allows a for loop to walk the tree inorder.
+// An iteration type that allows a for loop to walk the tree inorder, or
+// in reverse order from Rear.
 //
-// This is more moemory effecient than the Walk* functions becasue it
-// manages the stack interally.   It is a tiny bit faster than the
-// Walk* functions.
+//	for it := tree.Front(); !it.Done(); it.Next() {
+//		item := it.Value()
+//		...
+//	}
 //
-// The main benefit is that it can be used to make cleaner code.
+// This is more moemory effecient than the Walk* functions becasue it
+// manages the stack interally.  The tree must not be modified while
+// an iterator is in use.
 type AvlTreeIter[T comparable.Comparable] struct {
-	cur  *AvlTreeElement[T] // Pointer to the current element.
-	tree *AvlTree[T]        // The root of the tree
+	cur     *AvlTreeElement[T] // Pointer to the current element.
+	tree    *AvlTree[T]        // The root of the tree
+	reverse bool               // true if started from Rear, Next moves to smaller items
 
-	// will need a "Stack" of nodes to be able to iterate
+	// The "Stack" of nodes above cur that are still to be visited.
 	stk stack.Stack[*AvlTreeElement[T]]
 }
 
@@ -27,57 +35,44 @@ type AvlTreeIter[T comparable.Comparable] struct {
 
 // Front will start at the inorder traversal beginning of the tree for iteration over tree.
 func (tt *AvlTree[T]) Front() (rv *AvlTreeIter[T]) {
-	// Find the "head" lowest node in tree, point cur at this.
-	// Setup the "Stack" so can walk tree.
-
 	rv = &AvlTreeIter[T]{
 		tree: tt,
 	}
-
-	// findLeftMost is Local function that searches for the left most
-	// node (first inorder node) in the tree.  It has a side-effect
-	// of setting up the "stk" stack.
-	findLeftMost := func(parent *AvlTreeElement[T]) (ptr *AvlTreeElement[T]) {
-		ptr = nil
-		// fmt.Printf ( "%sFindLeftMost/At Top: at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
-		if parent == nil {
-			// fmt.Printf ( "%sFindLeftMost/no tree: at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
-			return
-		}
-		for (*parent).left != nil {
-			// fmt.Printf ( "%sAdvance 1 step. at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
-			rv.stk.Push(parent)
-			parent = (*parent).left
-		}
-		// fmt.Printf ( "%sat bottom at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
-		ptr = parent
-		return
-	}
-
-	rv.cur = findLeftMost(tt.root)
+	rv.cur = rv.pushSpine(tt.root)
 	return
 }
 
-/*
-// Front will start at the last inorder traversal node beginning of the tree for iteration over tree.
-func (tt *AvlTree[T]) Rear() *AvlTreeIter[T] {
-	return &AvlTreeIter[T] {
-		cur: tt.tail,
-		tree: tt,
+// Rear will start at the last inorder node of the tree for iteration over the tree in
+// reverse order.
+func (tt *AvlTree[T]) Rear() (rv *AvlTreeIter[T]) {
+	rv = &AvlTreeIter[T]{
+		tree:    tt,
+		reverse: true,
 	}
+	rv.cur = rv.pushSpine(tt.root)
+	return
 }
 
-// Current will take the node returned from Search or RevrseSearch
-// 		func (tt *AvlTree[T]) Search( t *T ) (rv *AvlTreeElement[T], pos int) {
-// and allow you to start an iteration process from that point.
-func (tt *AvlTree[T]) Current(el *AvlTreeElement[T]) *AvlTreeIter[T] {
-	return &AvlTreeIter[T] {
-		cur: el,
-		tree: tt,
+// pushSpine walks from `parent` to the left most node, or the right most for a reverse
+// iteration, pushing the nodes above it on the stack.  The node at the bottom is returned.
+func (iter *AvlTreeIter[T]) pushSpine(parent *AvlTreeElement[T]) (ptr *AvlTreeElement[T]) {
+	if parent == nil {
+		return nil
+	}
+	for {
+		next := (*parent).left
+		if iter.reverse {
+			next = (*parent).right
+		}
+		if next == nil {
+			return parent
+		}
+		iter.stk.Push(parent)
+		parent = next
 	}
 }
 
-// Value returtt the current data for this element in the list.
+// Value returns the current data for this element in the tree.
 func (iter *AvlTreeIter[T]) Value() *T {
 	if iter.cur != nil {
 		return iter.cur.data
@@ -85,22 +80,25 @@ func (iter *AvlTreeIter[T]) Value() *T {
 	return nil
 }
 
-// Next advances to the next element in the list.
+// Next advances to the next element in the tree, the previous one for an iterator from Rear.
 func (iter *AvlTreeIter[T]) Next() {
-	if iter.cur.next != nil {
-		iter.cur = iter.cur.next
+	if iter.cur == nil {
+		return
 	}
-}
-
-// Prev moves back to the previous element in the list.
-func (iter *AvlTreeIter[T]) Prev() {
-	if iter.cur.prev != nil {
-		iter.cur = iter.cur.prev
+	next := iter.cur.right
+	if iter.reverse {
+		next = iter.cur.left
+	}
+	if next != nil {
+		iter.cur = iter.pushSpine(next)
+	} else if iter.stk.IsEmpty() {
+		iter.cur = nil
+	} else {
+		iter.cur, _ = iter.stk.Pop()
 	}
 }
 
-// Done returtt true if the end of the list has been reached.
+// Done returns true if the end of the tree has been reached.
 func (iter *AvlTreeIter[T]) Done() bool {
-	return iter.cur != nil
+	return iter.cur == nil
 }
-*/
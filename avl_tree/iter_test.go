@@ -0,0 +1,40 @@
+package avl_tree
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTreeIter(t *testing.T) {
+	var Tree1 AvlTree[TestTreeNode]
+
+	if it := Tree1.Front(); !it.Done() || it.Value() != nil {
+		t.Errorf("Expected an empty tree to be Done")
+	}
+
+	for _, k := range []int{5, 2, 9, 0, 3, 7, 8, 1} {
+		Tree1.Insert(&TestTreeNode{S: fmt.Sprintf("%02d", k)})
+	}
+
+	var got []string
+	for it := Tree1.Front(); !it.Done(); it.Next() {
+		got = append(got, it.Value().S)
+	}
+	if fmt.Sprint(got) != "[00 01 02 03 05 07 08 09]" {
+		t.Errorf("Front iteration got %v", got)
+	}
+
+	got = got[:0]
+	for it := Tree1.Rear(); !it.Done(); it.Next() {
+		got = append(got, it.Value().S)
+	}
+	if fmt.Sprint(got) != "[09 08 07 05 03 02 01 00]" {
+		t.Errorf("Rear iteration got %v", got)
+	}
+}
@@ -0,0 +1,239 @@
+package avl_tree
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// setOpsTree builds a tree and a model of it from `keys`.
+func setOpsTree(keys []int) (*AvlTree[TestTreeNode], map[int]bool) {
+	tree := NewAvlTree[TestTreeNode]()
+	model := make(map[int]bool)
+	for _, k := range keys {
+		tree.Insert(&TestTreeNode{S: fmt.Sprintf("%04d", k)})
+		model[k] = true
+	}
+	return tree, model
+}
+
+// setOpsCheck validates `tree` and checks that it has the items in `model`.
+func setOpsCheck(t *testing.T, name string, tree *AvlTree[TestTreeNode], model map[int]bool) {
+	t.Helper()
+	avlValidate(t, tree)
+	var want []string
+	for k := range model {
+		want = append(want, fmt.Sprintf("%04d", k))
+	}
+	sort.Strings(want)
+	var got []string
+	for _, item := range tree.ConvertToSlice() {
+		got = append(got, item.S)
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("%s expcted %v got %v", name, want, got)
+	}
+}
+
+func TestTreeSetOps(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1001))
+	randKeys := func(n, max int) (keys []int) {
+		for ii := 0; ii < n; ii++ {
+			keys = append(keys, rnd.Intn(max))
+		}
+		return
+	}
+
+	for ii, sizes := range [][2]int{{0, 0}, {0, 20}, {20, 0}, {5, 300}, {300, 5}, {200, 200}, {1000, 30}} {
+		yy, ym := setOpsTree(randKeys(sizes[0], 500))
+		zz, zm := setOpsTree(randKeys(sizes[1], 500))
+		name := func(op string) string { return fmt.Sprintf("Test %d, %s", ii, op) }
+
+		union, inter, diff, sym := map[int]bool{}, map[int]bool{}, map[int]bool{}, map[int]bool{}
+		for k := range ym {
+			union[k] = true
+			if zm[k] {
+				inter[k] = true
+			} else {
+				diff[k] = true
+				sym[k] = true
+			}
+		}
+		for k := range zm {
+			union[k] = true
+			if !ym[k] {
+				sym[k] = true
+			}
+		}
+
+		var tt AvlTree[TestTreeNode]
+		tt.Union(yy, zz)
+		setOpsCheck(t, name("Union"), &tt, union)
+		tt.Intersect(yy, zz)
+		setOpsCheck(t, name("Intersect"), &tt, inter)
+		tt.Difference(yy, zz)
+		setOpsCheck(t, name("Difference"), &tt, diff)
+		tt.SymmetricDifference(yy, zz)
+		setOpsCheck(t, name("SymmetricDifference"), &tt, sym)
+		tt.Copy(yy)
+		setOpsCheck(t, name("Copy"), &tt, ym)
+
+		// The inputs are not changed.
+		setOpsCheck(t, name("yy after"), yy, ym)
+		setOpsCheck(t, name("zz after"), zz, zm)
+
+		if !tt.Equal(yy) || !yy.Equal(&tt) {
+			t.Errorf("%s expected the copy to be Equal", name("Equal"))
+		}
+		if yy.Equal(zz) != (len(sym) == 0) {
+			t.Errorf("%s expected %v", name("Equal"), len(sym) == 0)
+		}
+		var in AvlTree[TestTreeNode]
+		in.Intersect(yy, zz)
+		if !in.IsSubset(yy) || !in.IsSubset(zz) {
+			t.Errorf("%s expected the intersection to be a subset", name("IsSubset"))
+		}
+		if yy.IsSubset(zz) != (len(diff) == 0) {
+			t.Errorf("%s expected %v", name("IsSubset"), len(diff) == 0)
+		}
+	}
+}
+
+func TestTreeSplitJoin(t *testing.T) {
+	var keys []int
+	for k := 0; k < 100; k++ {
+		keys = append(keys, k*2)
+	}
+	for _, at := range []int{-1, 0, 50, 51, 100, 198, 199, 500} {
+		tree, model := setOpsTree(keys)
+		left, found, right := tree.Split(&TestTreeNode{S: fmt.Sprintf("%04d", at)})
+		if !tree.IsEmpty() || tree.Length() != 0 {
+			t.Errorf("Split(%d) expected the tree to be empty", at)
+		}
+		if (found != nil) != model[at] {
+			t.Errorf("Split(%d) expected found %v got %v", at, model[at], found)
+		}
+		lm, rm := map[int]bool{}, map[int]bool{}
+		for k := range model {
+			if k < at {
+				lm[k] = true
+			} else if k > at {
+				rm[k] = true
+			}
+		}
+		setOpsCheck(t, fmt.Sprintf("Split(%d) left", at), left, lm)
+		setOpsCheck(t, fmt.Sprintf("Split(%d) right", at), right, rm)
+
+		if err := right.Join(left); at > 0 && at < 198 && err != ErrJoinOrder {
+			t.Errorf("Join(%d) expected ErrJoinOrder got %v", at, err)
+		}
+		if err := left.Join(right); err != nil {
+			t.Errorf("Join(%d) unexpected error %s", at, err)
+		}
+		if !right.IsEmpty() {
+			t.Errorf("Join(%d) expected the joined tree to be empty", at)
+		}
+		delete(model, at)
+		setOpsCheck(t, fmt.Sprintf("Join(%d)", at), left, model)
+	}
+
+	// Join trees of very different heights.
+	small, sm := setOpsTree([]int{1000, 1001})
+	big, bm := setOpsTree(keys)
+	if err := big.Join(small); err != nil {
+		t.Errorf("Join unexpected error %s", err)
+	}
+	for k := range sm {
+		bm[k] = true
+	}
+	setOpsCheck(t, "Join small", big, bm)
+}
+
+// setOpsNodes returns the nodes of `tree`.
+func setOpsNodes(tree *AvlTree[TestTreeNode]) map[*AvlTreeElement[TestTreeNode]]bool {
+	nodes := make(map[*AvlTreeElement[TestTreeNode]]bool)
+	var walk func(cur *AvlTreeElement[TestTreeNode])
+	walk = func(cur *AvlTreeElement[TestTreeNode]) {
+		if cur != nil {
+			nodes[cur] = true
+			walk(cur.left)
+			walk(cur.right)
+		}
+	}
+	walk(tree.root)
+	return nodes
+}
+
+func TestTreeSetOpsShare(t *testing.T) {
+	var keys []int
+	for k := 0; k < 1000; k++ {
+		keys = append(keys, k*2)
+	}
+	yy, ym := setOpsTree(keys)
+	zz, zm := setOpsTree([]int{1, 501, 999, 1001, 1500, 3000})
+
+	var tt AvlTree[TestTreeNode]
+	tt.Union(yy, zz)
+	um := map[int]bool{}
+	for k := range ym {
+		um[k] = true
+	}
+	for k := range zm {
+		um[k] = true
+	}
+	setOpsCheck(t, "Union", &tt, um)
+
+	// Only the nodes on the paths that were split and joined are new, the rest are shared.
+	yn, zn := setOpsNodes(yy), setOpsNodes(zz)
+	nNew := 0
+	for e := range setOpsNodes(&tt) {
+		if !yn[e] && !zn[e] {
+			nNew++
+		}
+	}
+	if nNew > 6*2*11 {
+		t.Errorf("Union expected the nodes to be shared, %d of %d are new", nNew, tt.Length())
+	}
+
+	// A change to the result does not change the inputs, and the other way around.
+	for k := 0; k < 100; k++ {
+		tt.Insert(&TestTreeNode{S: fmt.Sprintf("%04d", k*20+1)})
+		um[k*20+1] = true
+		tt.Delete(&TestTreeNode{S: fmt.Sprintf("%04d", k*6)})
+		delete(um, k*6)
+	}
+	setOpsCheck(t, "Union after change", &tt, um)
+	setOpsCheck(t, "yy after change", yy, ym)
+	setOpsCheck(t, "zz after change", zz, zm)
+	for k := 0; k < 200; k++ {
+		yy.Delete(&TestTreeNode{S: fmt.Sprintf("%04d", k*4)})
+		delete(ym, k*4)
+	}
+	zz.Insert(&TestTreeNode{S: "0003"})
+	zm[3] = true
+	setOpsCheck(t, "Union after yy change", &tt, um)
+	setOpsCheck(t, "yy after yy change", yy, ym)
+	setOpsCheck(t, "zz after zz change", zz, zm)
+
+	// The same for Copy, which shares all the nodes.
+	var cp AvlTree[TestTreeNode]
+	cp.Copy(yy)
+	cp.Insert(&TestTreeNode{S: "0005"})
+	cp.Reverse()
+	yy.Delete(&TestTreeNode{S: "0998"})
+	delete(ym, 998)
+	setOpsCheck(t, "yy after Copy", yy, ym)
+	cp.Reverse()
+	cm := map[int]bool{5: true, 998: true}
+	for k := range ym {
+		cm[k] = true
+	}
+	setOpsCheck(t, "Copy after change", &cp, cm)
+}
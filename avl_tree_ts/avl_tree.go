@@ -10,11 +10,14 @@ BSD 3 Clause Licensed.
 
 Basic operations on a AVL Binary Tree.
 
+* 	ConvertToSlice - return the data in order as a slice.										O(n)
 * 	Insert - create a new element in tree.														O(log|2(n))
-*		Duplicates replace the current node with a new node - this is not reported as
+*		Duplicates replace the current node with a new node - Insert returns false for
 *       a duplicate.
 * 	Delete — Deletes a specified element from the linked list (Element can be fond via Search). O(log|2(n))
-* 	Index - return the Nth item	in the list - in a format usable with Delete.					O(n)
+* 	Index - return the Nth item	in the list - in a format usable with Delete.					O(log|2(n))
+* 	Rank - the number of items less than an item, its position for Index.					O(log|2(n))
+* 	CountRange - the number of items from lo to hi.											O(log|2(n))
 * 	IsEmpty — Returns true if the linked list is empty											O(1)
 * 	Length — Returns number of elements in the list.  0 length is an empty list.				O(1)
 * 	Reverse - Reverse all the nodes in list. 													O(n)
@@ -45,14 +48,18 @@ import (
 	"io"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/pschlump/pluto/comparable"
 	"github.com/pschlump/pluto/g_lib"
+	"github.com/pschlump/pluto/iface_list"
 )
 
 type AvlTreeElement[T comparable.Comparable] struct {
 	data        *T
 	height      int
+	size        int       // number of nodes in this sub-tree
+	cow         *cowToken // the tree that can change this node in place, see nlMutable
 	left, right *AvlTreeElement[T]
 }
 
@@ -61,14 +68,20 @@ type AvlTree[T comparable.Comparable] struct {
 	root   *AvlTreeElement[T]
 	length int
 	lock   sync.RWMutex
+	id     atomic.Uint64            // orders the locks when more than one tree is locked, see lockTrees
+	cow    atomic.Pointer[cowToken] // marks the nodes this tree can change in place, see nlMutable
 }
 
+// At compile time verify that AvlTree can be used behind the iface_list interfaces.
+var _ iface_list.TreeDataType[comparable.Int] = (*AvlTree[comparable.Int])(nil)
+
 // NewAvlTreeElement will create a new node for the ACL Tree
 // Complexity is O(1).
 func NewAvlTreeElement[T comparable.Comparable](x *T) *AvlTreeElement[T] {
 	return &AvlTreeElement[T]{
 		data:   x,
 		height: 1,
+		size:   1,
 		left:   nil,
 		right:  nil,
 	}
@@ -93,6 +106,62 @@ func (tt *AvlTree[T]) calcAvlBalance(e *AvlTreeElement[T]) int {
 	return tt.Height(e.left) - tt.Height(e.right)
 }
 
+// sizeOf returns the number of nodes in the sub-tree at `e`.  This is kept up to date as the
+// tree is modified so that Index and Rank can skip whole sub-trees.
+// Complexity is O(1).
+func (tt *AvlTree[T]) sizeOf(e *AvlTreeElement[T]) int {
+	if e == nil {
+		return 0
+	}
+	return e.size
+}
+
+// setHeightSize re-calculates the height and size of `e` from its children.
+// Complexity is O(1).
+func (tt *AvlTree[T]) setHeightSize(e *AvlTreeElement[T]) {
+	e.height = g_lib.Max(tt.Height(e.left), tt.Height(e.right)) + 1
+	e.size = tt.sizeOf(e.left) + tt.sizeOf(e.right) + 1
+}
+
+// cowToken marks the nodes that a tree can change in place.  Copy and the set operations share
+// nodes between trees, a shared node has a token that none of the trees that share it have, so
+// each of them copies the node before it changes it (copy on write).  A node that is only in
+// one tree has that tree's token and is changed in place.
+type cowToken struct {
+	_ int // not zero size, so each new token is a different pointer
+}
+
+// nlOwner returns the token of the tree.  It is made the first time it is used, so that a zero
+// value AvlTree is still ready to use.
+func (tt *AvlTree[T]) nlOwner() *cowToken {
+	if cow := tt.cow.Load(); cow != nil {
+		return cow
+	}
+	tt.cow.CompareAndSwap(nil, new(cowToken))
+	return tt.cow.Load()
+}
+
+// nlShare gives the tree a new token, after this the tree copies any of the nodes it has now
+// before it changes them.  It is called on each tree that nodes are shared with, and it only
+// needs a read lock, the set operations call it on the trees they read.
+// Complexity is O(1).
+func (tt *AvlTree[T]) nlShare() {
+	tt.cow.Store(new(cowToken))
+}
+
+// nlMutable returns `e` if the tree can change it in place, else a copy of it that the tree
+// can change.  The copy has the same children, so only the nodes on the path that is changed
+// are copied, the rest stay shared.
+// Complexity is O(1).
+func (tt *AvlTree[T]) nlMutable(e *AvlTreeElement[T]) *AvlTreeElement[T] {
+	if e == nil || e.cow == tt.nlOwner() {
+		return e
+	}
+	cp := *e
+	cp.cow = tt.nlOwner()
+	return &cp
+}
+
 // NewAvlTree will create a new AvlTree and return it.
 // Complexity is O(1).
 func NewAvlTree[T comparable.Comparable]() *AvlTree[T] {
@@ -193,8 +262,9 @@ i
 */
 
 // Insert will add a new item to the tree.  If it is a duplicate of an exiting
-// item the new item will replace the existing one.
-func (tt *AvlTree[T]) Insert(item *T) {
+// item the new item will replace the existing one.  True is returned if the item
+// is new, false if it replaced an existing one.
+func (tt *AvlTree[T]) Insert(item *T) (isNew bool) {
 	if tt == nil {
 		panic("tree sholud not be a nil")
 	}
@@ -202,17 +272,19 @@ func (tt *AvlTree[T]) Insert(item *T) {
 	tt.lock.Lock()
 	defer tt.lock.Unlock()
 
-	tt.nlInsert(item)
+	return tt.nlInsert(item)
 }
 
-func (tt *AvlTree[T]) nlInsert(item *T) {
+func (tt *AvlTree[T]) nlInsert(item *T) (isNew bool) {
 
 	node := NewAvlTreeElement[T](item)
+	node.cow = tt.nlOwner()
 	if (*tt).nlIsEmpty() {
 		tt.root = node
 		tt.length = 1
-		return
+		return true
 	}
+	n := tt.length
 
 	// Recursive with tail-recursion handeling the AVL rotation.
 	var insert func(root **AvlTreeElement[T])
@@ -226,139 +298,120 @@ func (tt *AvlTree[T]) nlInsert(item *T) {
 			node.right = (*root).right
 			(*root) = node
 		} else if c < 0 {
+			*root = tt.nlMutable(*root) // copy a shared node before it is changed
 			insert(&((*root).left))
 		} else {
+			*root = tt.nlMutable(*root)
 			insert(&((*root).right))
 		}
 
-		// AVL section ----------------------------------------------------------------------------------
-		(*root).height = g_lib.Max(tt.Height((*root).left), tt.Height((*root).right)) + 1
-
-		b := tt.calcAvlBalance(*root)
-
-		if g_lib.Abs(b) > 1 { // If we have a height difference that is larer than 1 ( may be < -2, or +2.
-
-			z := (*root) // can change 'z' via *root
-			if z != nil && z.left != nil && z.left.left != nil {
-				// a) Left Left Case
-				// t1, t2, t3 and t4 are subtrees.
-				//          z                                      y
-				//        / \                                   /   \
-				//       y   T4      Right Rotate (z)          x      z
-				//      / \          - - - - - - - - ->      /  \    /  \
-				//     x   T3                               T1  T2  T3  T4
-				//    / \
-				//  T1   T2
-				y := z.left
-				x := y.left
-				t4 := z.right
-				t3 := y.right
-				t2 := x.right
-				t1 := x.left
-				y.left = x
-				y.right = z
-				x.left = t1
-				x.right = t2
-				z.left = t3
-				z.right = t4
-				// re-calculate - the heights based on the "subtrees" (t1, t2, t3, t4)
-				x.height = g_lib.Max(tt.Height(t1), tt.Height(t2)) + 1
-				z.height = g_lib.Max(tt.Height(t3), tt.Height(t4)) + 1
-				y.height = g_lib.Max(tt.Height(x), tt.Height(z)) + 1
-				(*root) = y
-
-			} else if z != nil && z.left != nil && z.left.right != nil {
-				// b) Left Right Case
-				// T1, T2, T3 and T4 are subtrees.
-				//      z                               z                           x
-				//     / \                            /   \                        /  \
-				//    y   T4  Left Rotate (y)        x    T4  Right Rotate(z)    y      z
-				//   / \      - - - - - - - - ->    /  \      - - - - - - - ->  / \    / \
-				// T1   x                          y    T3                    T1  T2 T3  T4
-				//     / \                        / \
-				//   T2   T3                    T1   T2
-				y := z.left
-				x := y.right
-				t4 := z.right
-				t3 := x.right
-				t2 := x.left
-				t1 := y.left
-				x.left = y
-				x.right = z
-				y.left = t1
-				y.right = t2
-				z.left = t3
-				z.right = t4
-				// re-calculate - the heights based on the "subtrees" (t1, t2, t3, t4)
-				y.height = g_lib.Max(tt.Height(t1), tt.Height(t2)) + 1
-				z.height = g_lib.Max(tt.Height(t3), tt.Height(t4)) + 1
-				x.height = g_lib.Max(tt.Height(y), tt.Height(z)) + 1
-				(*root) = x
-
-			} else if z != nil && z.right != nil && z.right.right != nil {
-				// c) Right Right Case
-				// T1, T2, T3 and T4 are subtrees.
-				//   z                                y
-				//  /  \                            /   \
-				// T1   y     Left Rotate(z)       z      x
-				//     /  \   - - - - - - - ->    / \    / \
-				//    T2   x                     T1  T2 T3  T4
-				//        / \
-				//      T3  T4
-				y := z.right
-				x := y.right
-				t4 := x.right
-				t3 := x.left
-				t2 := y.left
-				t1 := z.left
-				y.left = z
-				y.right = x
-				z.left = t1
-				z.right = t2
-				x.left = t3
-				x.right = t4
-				// re-calculate - the heights based on the "subtrees" (t1, t2, t3, t4)
-				z.height = g_lib.Max(tt.Height(t1), tt.Height(t2)) + 1
-				x.height = g_lib.Max(tt.Height(t3), tt.Height(t4)) + 1
-				y.height = g_lib.Max(tt.Height(y), tt.Height(z)) + 1
-				(*root) = y
-
-			} else if z != nil && z.right != nil && z.right.left != nil {
-				// d) Right Left Case
-				// T1, T2, T3 and T4 are subtrees.
-				//    z                            z                            x
-				//   / \                          / \                          /  \
-				// T1   y   Right Rotate (y)    T1   x      Left Rotate(z)   z      y
-				//     / \  - - - - - - - - ->     /  \   - - - - - - - ->  / \    / \
-				//    x   T4                      T2   y                  T1  T2  T3  T4
-				//   / \                              /  \
-				// T2   T3                           T3   T4
-				y := z.right
-				x := y.left
-				t4 := y.right
-				t3 := x.right
-				t2 := x.left
-				t1 := z.left
-				x.left = z
-				x.right = y
-				z.left = t1
-				z.right = t2
-				y.left = t3
-				y.right = t4
-				// re-calculate - the heights based on the "subtrees" (t1, t2, t3, t4)
-				z.height = g_lib.Max(tt.Height(t1), tt.Height(t2)) + 1
-				y.height = g_lib.Max(tt.Height(t3), tt.Height(t4)) + 1
-				x.height = g_lib.Max(tt.Height(y), tt.Height(z)) + 1
-				(*root) = x
-
-			} else {
-				panic("should never get to this point")
-			}
-		}
+		tt.nlRebalance(root)
 	}
 
 	insert(&((*tt).root))
+	return tt.length > n
+}
 
+// nlRebalance re-calculates the height and size of the node at `root` from its children and
+// if the node is out of balance does the AVL rotation.  `root` is the pointer that points at
+// the node so that it can be replaced with the new top of the sub-tree.  The case is picked
+// from the balance of the child on the heavy side.
+// Complexity is O(1).
+func (tt *AvlTree[T]) nlRebalance(root **AvlTreeElement[T]) {
+	z := tt.nlMutable(*root) // can change 'z' via *root
+	if z == nil {
+		return
+	}
+	(*root) = z
+	tt.setHeightSize(z)
+
+	b := tt.calcAvlBalance(z)
+	if b > 1 && tt.calcAvlBalance(z.left) >= 0 {
+		// a) Left Left Case
+		// t1, t2, t3 and t4 are subtrees.
+		//          z                                      y
+		//        / \                                   /   \
+		//       y   T4      Right Rotate (z)          x      z
+		//      / \          - - - - - - - - ->      /  \    /  \
+		//     x   T3                               T1  T2  T3  T4
+		//    / \
+		//  T1   T2
+		y := tt.nlMutable(z.left)
+		t3 := y.right
+		y.right = z
+		z.left = t3
+		// re-calculate - the heights and sizes from the bottom up, x is unchanged.
+		tt.setHeightSize(z)
+		tt.setHeightSize(y)
+		(*root) = y
+
+	} else if b > 1 {
+		// b) Left Right Case
+		// T1, T2, T3 and T4 are subtrees.
+		//      z                               z                           x
+		//     / \                            /   \                        /  \
+		//    y   T4  Left Rotate (y)        x    T4  Right Rotate(z)    y      z
+		//   / \      - - - - - - - - ->    /  \      - - - - - - - ->  / \    / \
+		// T1   x                          y    T3                    T1  T2 T3  T4
+		//     / \                        / \
+		//   T2   T3                    T1   T2
+		y := tt.nlMutable(z.left)
+		x := tt.nlMutable(y.right)
+		t3 := x.right
+		t2 := x.left
+		x.left = y
+		x.right = z
+		y.right = t2
+		z.left = t3
+		// re-calculate - the heights and sizes from the bottom up.
+		tt.setHeightSize(y)
+		tt.setHeightSize(z)
+		tt.setHeightSize(x)
+		(*root) = x
+
+	} else if b < -1 && tt.calcAvlBalance(z.right) <= 0 {
+		// c) Right Right Case
+		// T1, T2, T3 and T4 are subtrees.
+		//   z                                y
+		//  /  \                            /   \
+		// T1   y     Left Rotate(z)       z      x
+		//     /  \   - - - - - - - ->    / \    / \
+		//    T2   x                     T1  T2 T3  T4
+		//        / \
+		//      T3  T4
+		y := tt.nlMutable(z.right)
+		t2 := y.left
+		y.left = z
+		z.right = t2
+		// re-calculate - the heights and sizes from the bottom up, x is unchanged.
+		tt.setHeightSize(z)
+		tt.setHeightSize(y)
+		(*root) = y
+
+	} else if b < -1 {
+		// d) Right Left Case
+		// T1, T2, T3 and T4 are subtrees.
+		//    z                            z                            x
+		//   / \                          / \                          /  \
+		// T1   y   Right Rotate (y)    T1   x      Left Rotate(z)   z      y
+		//     / \  - - - - - - - - ->     /  \   - - - - - - - ->  / \    / \
+		//    x   T4                      T2   y                  T1  T2  T3  T4
+		//   / \                              /  \
+		// T2   T3                           T3   T4
+		y := tt.nlMutable(z.right)
+		x := tt.nlMutable(y.left)
+		t3 := x.right
+		t2 := x.left
+		x.left = z
+		x.right = y
+		z.right = t2
+		y.left = t3
+		// re-calculate - the heights and sizes from the bottom up.
+		tt.setHeightSize(z)
+		tt.setHeightSize(y)
+		tt.setHeightSize(x)
+		(*root) = x
+	}
 }
 
 // Length returns the number of elements in the list.
@@ -458,201 +511,50 @@ func (tt *AvlTree[T]) nlDelete(find *T) (found bool) {
 		return false
 	}
 
-	findLeftMostInRightSubtree := func(parent **AvlTreeElement[T]) (found bool, pAtIt **AvlTreeElement[T]) {
-		this := **parent
-		if *parent == nil {
-			return
-		}
-		for this.right != nil {
-			parent = &(this.right)
-			this = **parent
-		}
-		found = true
-		pAtIt = parent
-		return
-	}
-
-	// Iterative search through tree (can be used above)
+	// Iterative search through tree, keep the path so the nodes above can be re-balanced.
+	var path []**AvlTreeElement[T]
 	cur := &tt.root // ptr to ptr to tree
-	for tt != nil {
+	for *cur != nil {
+		*cur = tt.nlMutable(*cur) // the nodes on the path are changed, copy any that are shared
+		path = append(path, cur)
 		c := (*find).Compare(*(*cur).data)
-		if c == 0 {
-			(*tt).length--
-			if (*cur).left == nil && (*cur).right == nil {
-				(*cur) = nil // just delete the node, it has no children.
-			} else if (*cur).left != nil && (*cur).right == nil {
-				(*cur) = (*cur).left // Has only left children, promote them.
-			} else if (*cur).left == nil && (*cur).right != nil {
-				(*cur) = (*cur).right // Has only right children, promote them.
-			} else { // has both children.
-				// Has only right children, promote them.
-				found, pAtIt := findLeftMostInRightSubtree(&((*cur).right)) // Find lft mos of right sub-tree
-				if !found {
-					panic("Can't have a missing sub-tree.")
-				}
-				(*cur).data = (*pAtIt).data // promote node's data.
-				(*pAtIt) = (*pAtIt).right   // Left most can have a right sub-tree - but it is left most so it can't have a more left tree.
-			}
-			// return true
-			goto rb
-		}
-		if c < 0 && (*cur).left != nil {
+		if c < 0 {
 			cur = &((*cur).left)
-		} else if c > 0 && (*cur).right != nil {
+		} else if c > 0 {
 			cur = &((*cur).right)
 		} else {
 			break
 		}
 	}
+	if *cur == nil {
+		return false // Not Found
+	}
 
-	// Not Found
-	return false
-
-rb:
-	// Recursive with tail-recursion handeling the AVL rotation.
-	var rebalance func(root **AvlTreeElement[T])
-	rebalance = func(root **AvlTreeElement[T]) {
-		if *root == nil {
-			// *root = node
-			// tt.length++
-			// } else if c := (*(node.data)).Compare( (*root).data ); c == 0 {
-			return
-		} else if c := (*find).Compare(*((*root).data)); c == 0 {
-			// (*root) = node
-			return
-		} else if c < 0 {
-			rebalance(&((*root).left))
-		} else {
-			rebalance(&((*root).right))
-		}
-
-		// AVL section ----------------------------------------------------------------------------------
-		(*root).height = g_lib.Max(tt.Height((*root).left), tt.Height((*root).right)) + 1
-
-		b := tt.calcAvlBalance(*root)
-
-		if g_lib.Abs(b) > 1 { // If we have a height difference that is larer than 1 ( may be < -2, or +2.
-
-			z := (*root) // can change 'z' via *root
-			if z != nil && z.left != nil && z.left.left != nil {
-				// a) Left Left Case
-				// t1, t2, t3 and t4 are subtrees.
-				//          z                                      y
-				//        / \                                   /   \
-				//       y   T4      Right Rotate (z)          x      z
-				//      / \          - - - - - - - - ->      /  \    /  \
-				//     x   T3                               T1  T2  T3  T4
-				//    / \
-				//  T1   T2
-				y := z.left
-				x := y.left
-				t4 := z.right
-				t3 := y.right
-				t2 := x.right
-				t1 := x.left
-				y.left = x
-				y.right = z
-				x.left = t1
-				x.right = t2
-				z.left = t3
-				z.right = t4
-				// re-calculate - the heights based on the "subtrees" (t1, t2, t3, t4)
-				x.height = g_lib.Max(tt.Height(t1), tt.Height(t2)) + 1
-				z.height = g_lib.Max(tt.Height(t3), tt.Height(t4)) + 1
-				y.height = g_lib.Max(tt.Height(x), tt.Height(z)) + 1
-				(*root) = y
-
-			} else if z != nil && z.left != nil && z.left.right != nil {
-				// b) Left Right Case
-				// T1, T2, T3 and T4 are subtrees.
-				//      z                               z                           x
-				//     / \                            /   \                        /  \
-				//    y   T4  Left Rotate (y)        x    T4  Right Rotate(z)    y      z
-				//   / \      - - - - - - - - ->    /  \      - - - - - - - ->  / \    / \
-				// T1   x                          y    T3                    T1  T2 T3  T4
-				//     / \                        / \
-				//   T2   T3                    T1   T2
-				y := z.left
-				x := y.right
-				t4 := z.right
-				t3 := x.right
-				t2 := x.left
-				t1 := y.left
-				x.left = y
-				x.right = z
-				y.left = t1
-				y.right = t2
-				z.left = t3
-				z.right = t4
-				// re-calculate - the heights based on the "subtrees" (t1, t2, t3, t4)
-				y.height = g_lib.Max(tt.Height(t1), tt.Height(t2)) + 1
-				z.height = g_lib.Max(tt.Height(t3), tt.Height(t4)) + 1
-				x.height = g_lib.Max(tt.Height(y), tt.Height(z)) + 1
-				(*root) = x
-
-			} else if z != nil && z.right != nil && z.right.right != nil {
-				// c) Right Right Case
-				// T1, T2, T3 and T4 are subtrees.
-				//   z                                y
-				//  /  \                            /   \
-				// T1   y     Left Rotate(z)       z      x
-				//     /  \   - - - - - - - ->    / \    / \
-				//    T2   x                     T1  T2 T3  T4
-				//        / \
-				//      T3  T4
-				y := z.right
-				x := y.right
-				t4 := x.right
-				t3 := x.left
-				t2 := y.left
-				t1 := z.left
-				y.left = z
-				y.right = x
-				z.left = t1
-				z.right = t2
-				x.left = t3
-				x.right = t4
-				// re-calculate - the heights based on the "subtrees" (t1, t2, t3, t4)
-				z.height = g_lib.Max(tt.Height(t1), tt.Height(t2)) + 1
-				x.height = g_lib.Max(tt.Height(t3), tt.Height(t4)) + 1
-				y.height = g_lib.Max(tt.Height(y), tt.Height(z)) + 1
-				(*root) = y
-
-			} else if z != nil && z.right != nil && z.right.left != nil {
-				// d) Right Left Case
-				// T1, T2, T3 and T4 are subtrees.
-				//    z                            z                            x
-				//   / \                          / \                          /  \
-				// T1   y   Right Rotate (y)    T1   x      Left Rotate(z)   z      y
-				//     / \  - - - - - - - - ->     /  \   - - - - - - - ->  / \    / \
-				//    x   T4                      T2   y                  T1  T2  T3  T4
-				//   / \                              /  \
-				// T2   T3                           T3   T4
-				y := z.right
-				x := y.left
-				t4 := y.right
-				t3 := x.right
-				t2 := x.left
-				t1 := z.left
-				x.left = z
-				x.right = y
-				z.left = t1
-				z.right = t2
-				y.left = t3
-				y.right = t4
-				// re-calculate - the heights based on the "subtrees" (t1, t2, t3, t4)
-				z.height = g_lib.Max(tt.Height(t1), tt.Height(t2)) + 1
-				y.height = g_lib.Max(tt.Height(t3), tt.Height(t4)) + 1
-				x.height = g_lib.Max(tt.Height(y), tt.Height(z)) + 1
-				(*root) = x
-
-			} else {
-				panic("should never get to this point")
-			}
+	tt.length--
+	if (*cur).left == nil && (*cur).right == nil {
+		(*cur) = nil // just delete the node, it has no children.
+	} else if (*cur).left != nil && (*cur).right == nil {
+		(*cur) = (*cur).left // Has only left children, promote them.
+	} else if (*cur).left == nil && (*cur).right != nil {
+		(*cur) = (*cur).right // Has only right children, promote them.
+	} else { // has both children.
+		// Find the left most of the right sub-tree, the path to it also needs re-balancing.
+		pAtIt := &((*cur).right)
+		*pAtIt = tt.nlMutable(*pAtIt)
+		path = append(path, pAtIt)
+		for (*pAtIt).left != nil { // Walk the tree pointers, not a copy of the node.
+			pAtIt = &((*pAtIt).left)
+			*pAtIt = tt.nlMutable(*pAtIt)
+			path = append(path, pAtIt)
 		}
+		(*cur).data = (*pAtIt).data // promote node's data.
+		(*pAtIt) = (*pAtIt).right   // Left most can have a right sub-tree - but it is left most so it can't have a more left tree.
 	}
 
-	rebalance(&((*tt).root))
+	// Fix heights and sizes, and rotate, from the bottom of the path back up to the root.
+	for ii := len(path) - 1; ii >= 0; ii-- {
+		tt.nlRebalance(path[ii])
+	}
 
 	return true
 }
@@ -773,23 +675,22 @@ func (tt *AvlTree[T]) Reverse() {
 		return
 	}
 
-	var postTraversal func(cur *AvlTreeElement[T])
-	postTraversal = func(cur *AvlTreeElement[T]) {
+	var postTraversal func(cur *AvlTreeElement[T]) *AvlTreeElement[T]
+	postTraversal = func(cur *AvlTreeElement[T]) *AvlTreeElement[T] {
 		if cur == nil {
-			return
-		}
-		if (*cur).left != nil {
-			postTraversal((*cur).left)
-		}
-		if (*cur).right != nil {
-			postTraversal((*cur).right)
+			return nil
 		}
-		(*cur).left, (*cur).right = (*cur).right, (*cur).left
+		cur = tt.nlMutable(cur)
+		left, right := postTraversal((*cur).left), postTraversal((*cur).right)
+		(*cur).left, (*cur).right = right, left
+		return cur
 	}
-	postTraversal(tt.root)
+	tt.root = postTraversal(tt.root)
 }
 
-// Index walks  the tree and returns the N-th item in the tree.
+// Index returns the N-th item in the tree, 0 based, in order.  It uses the sub-tree sizes
+// to go directly down to the item.
+// Complexity is O(log|2(n)).
 func (tt *AvlTree[T]) Index(pos int) (item *T) {
 	if tt == nil {
 		panic("tree sholud not be a nil")
@@ -797,41 +698,72 @@ func (tt *AvlTree[T]) Index(pos int) (item *T) {
 
 	tt.lock.RLock()
 	defer tt.lock.RUnlock()
+	return tt.nlIndex(pos)
+}
 
-	if (*tt).nlIsEmpty() {
-		return nil
-	}
-
+// nlIndex returns the N-th item in the tree without locking.
+func (tt *AvlTree[T]) nlIndex(pos int) (item *T) {
 	if pos < 0 || pos >= tt.length {
 		return nil
 	}
 
-	var n = 0
-	var done = false
-	var inorderTraversal func(cur *AvlTreeElement[T])
-	inorderTraversal = func(cur *AvlTreeElement[T]) {
-		if cur == nil {
-			return
-		}
-		if !done {
-			if (*cur).left != nil {
-				inorderTraversal((*cur).left)
-			}
-		}
-		// fmt.Printf ( "InOrder - Before Set, Top n=%d, pos=%d,    value=%+v     at:%s\n", n, pos, item, dbgo.LF() )
-		if n == pos {
-			item = (*cur).data
-			// fmt.Printf ( "*********** Set \n")
-			done = true
+	cur := tt.root
+	for cur != nil {
+		n := tt.sizeOf(cur.left)
+		if pos < n {
+			cur = cur.left
+		} else if pos > n {
+			pos -= n + 1 // skip the left sub-tree and this node
+			cur = cur.right
+		} else {
+			return cur.data
 		}
-		n++
-		if !done {
-			if (*cur).right != nil {
-				inorderTraversal((*cur).right)
-			}
+	}
+	return nil
+}
+
+// Rank returns the number of items in the tree that are less than `find`.  If `find` is in
+// the tree this is its position as used by Index.
+// Complexity is O(log|2(n)).
+func (tt *AvlTree[T]) Rank(find *T) int {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+	return tt.nlRank(find, false)
+}
+
+// CountRange returns the number of items in the tree from `lo` to `hi`, both included.
+// Complexity is O(log|2(n)).
+func (tt *AvlTree[T]) CountRange(lo, hi *T) int {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+	n := tt.nlRank(hi, true) - tt.nlRank(lo, false)
+	if n < 0 { // lo > hi
+		return 0
+	}
+	return n
+}
+
+// nlRank returns the number of items that are less than `find`, or less than or equal to
+// `find` if `orEqual` is true, without locking.
+func (tt *AvlTree[T]) nlRank(find *T, orEqual bool) (n int) {
+	cur := tt.root
+	for cur != nil {
+		c := (*find).Compare(*cur.data)
+		if c > 0 || (c == 0 && orEqual) {
+			n += tt.sizeOf(cur.left) + 1 // the left sub-tree and this node are below find
+			cur = cur.right
+		} else {
+			cur = cur.left
 		}
 	}
-	inorderTraversal(tt.root)
 	return
 }
 
@@ -877,6 +809,20 @@ func (tt *AvlTree[T]) nlDepth() (d int) {
 	return
 }
 
+// ConvertToSlice returns the data in the tree in order.
+// Complexity is O(n).
+func (tt *AvlTree[T]) ConvertToSlice() (rv []*T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	rv = []*T{}
+	tt.WalkInOrder(func(pos, depth int, data *T, userData interface{}) bool {
+		rv = append(rv, data)
+		return true
+	}, nil)
+	return
+}
+
 type ApplyFunction[T comparable.Comparable] func(pos, depth int, data *T, userData interface{}) bool
 
 // WalkInOrder walks the tree applying the function 'fx' to each node.  If 'fx' returns false then the
@@ -994,96 +940,4 @@ func (tt *AvlTree[T]) WalkPostOrder(fx ApplyFunction[T], userData interface{}) {
 	postOrderTraversal(tt.root, 0)
 }
 
-// Copy makes a deep copy of one tree to another.
-func (tt *AvlTree[T]) Copy(yy *AvlTree[T]) {
-	if tt == nil {
-		panic("tree sholud not be a nil")
-	}
-
-	tt.lock.Lock()
-	yy.lock.Lock()
-	defer yy.lock.Unlock()
-	defer tt.lock.Unlock()
-
-	tt.nlTruncate()
-	yy.nlWalkInOrder(func(_, _ int, data *T, _ interface{}) bool {
-		tt.nlInsert(data)
-		return true
-	}, nil)
-}
-
-// Union is a set union, tt = yy union zz.
-// Set union - if a duplicate then insert will use the new one.
-// Complexity is O(n).
-func (tt *AvlTree[T]) Union(yy, zz *AvlTree[T]) {
-	if tt == nil {
-		panic("tree sholud not be a nil")
-	}
-
-	tt.lock.Lock()
-	yy.lock.Lock()
-	zz.lock.Lock()
-	defer zz.lock.Unlock()
-	defer yy.lock.Unlock()
-	defer tt.lock.Unlock()
-
-	tt.nlTruncate()
-	yy.nlWalkInOrder(func(_, _ int, data *T, _ interface{}) bool {
-		tt.nlInsert(data)
-		return true
-	}, nil)
-	zz.nlWalkInOrder(func(_, _ int, data *T, _ interface{}) bool {
-		tt.nlInsert(data)
-		return true
-	}, nil)
-}
-
-// Minus is a set minus, tt = yy - zz.
-// Complexity is O(n).
-func (tt *AvlTree[T]) Minus(yy, zz *AvlTree[T]) {
-	if tt == nil {
-		panic("tree sholud not be a nil")
-	}
-
-	tt.lock.Lock()
-	yy.lock.Lock()
-	zz.lock.Lock()
-	defer zz.lock.Unlock()
-	defer yy.lock.Unlock()
-	defer tt.lock.Unlock()
-
-	tt.nlTruncate()
-	yy.nlWalkInOrder(func(_, _ int, data *T, _ interface{}) bool {
-		// func (tt *AvlTree[T]) Search(find *T) (item *T) {
-		if zz.nlSearch(data) == nil {
-			tt.nlInsert(data)
-		}
-		return true
-	}, nil)
-}
-
-// Intersect take the set intersection.  tt = yy intersect zz
-// Complexity is O(n).
-func (tt *AvlTree[T]) Intersect(yy, zz *AvlTree[T]) {
-	if tt == nil {
-		panic("tree sholud not be a nil")
-	}
-
-	tt.lock.Lock()
-	yy.lock.Lock()
-	zz.lock.Lock()
-	defer zz.lock.Unlock()
-	defer yy.lock.Unlock()
-	defer tt.lock.Unlock()
-
-	tt.nlTruncate()
-	yy.nlWalkInOrder(func(_, _ int, data *T, _ interface{}) bool {
-		// func (tt *AvlTree[T]) Search(find *T) (item *T) {
-		if zz.nlSearch(data) != nil {
-			tt.nlInsert(data)
-		}
-		return true
-	}, nil)
-}
-
 /* vim: set noai ts=4 sw=4: */
@@ -6,18 +6,19 @@ Copyright (C) Philip Schlump, 2012-2021.
 BSD 3 Clause Licensed.
 */
 
-// TODO - add a avlValidate (tree) that recrusivly checks heights and verifys all are 1,0,-1
-
 import (
 	"fmt"
+	"math/rand"
 	"os"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/pschlump/MiscLib"
 	"github.com/pschlump/dbgo"
 	"github.com/pschlump/pluto/comparable"
 	"github.com/pschlump/pluto/g_lib"
+	"github.com/pschlump/pluto/iface_list"
 )
 
 // TestTreeNode is an Inteface Matcing data type for the Nodes that supports the Comparable
@@ -810,3 +811,128 @@ const db7 = false
 const db10 = false
 const db11 = false
 const db13 = false
+
+func TestTreeDataType(t *testing.T) {
+	var tr iface_list.TreeDataType[TestTreeNode] = NewAvlTree[TestTreeNode]()
+
+	for _, s := range []string{"03", "01", "02"} {
+		if !tr.Insert(&TestTreeNode{S: s}) {
+			t.Errorf("Expected %s to be new", s)
+		}
+	}
+	if tr.Insert(&TestTreeNode{S: "02"}) {
+		t.Errorf("Expected 02 to be a duplicate")
+	}
+	if tr.Length() != 3 {
+		t.Errorf("Expected length 3 got %d", tr.Length())
+	}
+
+	got := ""
+	for _, v := range tr.ConvertToSlice() {
+		got += v.S
+	}
+	if got != "010203" {
+		t.Errorf("ConvertToSlice error, expcted %s got %s", "010203", got)
+	}
+
+	if !tr.Delete(&TestTreeNode{S: "01"}) {
+		t.Errorf("Expected to delete 01")
+	}
+	if x := tr.FindMin(); x == nil || x.S != "02" {
+		t.Errorf("FindMin error, expcted 02 got %v", x)
+	}
+
+	tr.Truncate()
+	if !tr.IsEmpty() || len(tr.ConvertToSlice()) != 0 {
+		t.Errorf("Expected empty tree after Truncate")
+	}
+}
+
+// avlValidate recursively checks that every node has the correct height and size, that the
+// balance is -1, 0 or 1 and that the items are in order.
+func avlValidate(t *testing.T, tt *AvlTree[TestTreeNode]) {
+	t.Helper()
+	var check func(cur *AvlTreeElement[TestTreeNode], lo, hi *TestTreeNode) (height, size int)
+	check = func(cur *AvlTreeElement[TestTreeNode], lo, hi *TestTreeNode) (height, size int) {
+		if cur == nil {
+			return 0, 0
+		}
+		if (lo != nil && lo.S >= cur.data.S) || (hi != nil && hi.S <= cur.data.S) {
+			t.Errorf("Item %s is out of order", cur.data.S)
+		}
+		lh, ls := check(cur.left, lo, cur.data)
+		rh, rs := check(cur.right, cur.data, hi)
+		height = g_lib.Max(lh, rh) + 1
+		size = ls + rs + 1
+		if cur.height != height {
+			t.Errorf("Item %s expected height %d got %d", cur.data.S, height, cur.height)
+		}
+		if cur.size != size {
+			t.Errorf("Item %s expected size %d got %d", cur.data.S, size, cur.size)
+		}
+		if b := lh - rh; b < -1 || b > 1 {
+			t.Errorf("Item %s is out of balance, %d", cur.data.S, b)
+		}
+		return
+	}
+	if _, size := check(tt.root, nil, nil); size != tt.length {
+		t.Errorf("Expected length %d got %d", size, tt.length)
+	}
+}
+
+func TestTreeOrderStatistics(t *testing.T) {
+	var Tree1 AvlTree[TestTreeNode]
+	model := make(map[int]bool)
+	rnd := rand.New(rand.NewSource(1001))
+
+	for ii := 0; ii < 2000; ii++ {
+		k := rnd.Intn(300)
+		if rnd.Intn(3) == 0 {
+			Tree1.Delete(&TestTreeNode{S: fmt.Sprintf("%04d", k)})
+			delete(model, k)
+		} else {
+			Tree1.Insert(&TestTreeNode{S: fmt.Sprintf("%04d", k)})
+			model[k] = true
+		}
+		if ii%100 == 0 {
+			avlValidate(t, &Tree1)
+		}
+	}
+	avlValidate(t, &Tree1)
+
+	keys := make([]int, 0, len(model))
+	for k := range model {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	for pos, k := range keys {
+		item := Tree1.Index(pos)
+		if item == nil || item.S != fmt.Sprintf("%04d", k) {
+			t.Errorf("Index(%d) expcted %04d got %v", pos, k, item)
+		}
+	}
+	if Tree1.Index(-1) != nil || Tree1.Index(len(keys)) != nil {
+		t.Errorf("Expected nil for Index out of range")
+	}
+
+	for k := -1; k <= 301; k++ {
+		pos := sort.SearchInts(keys, k)
+		if r := Tree1.Rank(&TestTreeNode{S: fmt.Sprintf("%04d", k)}); r != pos {
+			t.Errorf("Rank(%04d) expcted %d got %d", k, pos, r)
+		}
+	}
+
+	for _, rr := range [][2]int{{0, 299}, {10, 20}, {20, 10}, {150, 150}, {-5, 50}, {290, 400}} {
+		lo, hi := rr[0], rr[1]
+		want := 0
+		for _, k := range keys {
+			if k >= lo && k <= hi {
+				want++
+			}
+		}
+		if n := Tree1.CountRange(&TestTreeNode{S: fmt.Sprintf("%04d", lo)}, &TestTreeNode{S: fmt.Sprintf("%04d", hi)}); n != want {
+			t.Errorf("CountRange(%d,%d) expcted %d got %d", lo, hi, want, n)
+		}
+	}
+}
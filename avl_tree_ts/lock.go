@@ -0,0 +1,74 @@
+package avl_tree_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"sort"
+	"sync/atomic"
+
+	"github.com/pschlump/pluto/comparable"
+)
+
+// treeIDs hands out the IDs that fix the order in which trees are locked.
+var treeIDs atomic.Uint64
+
+// lockID returns the ID of the tree.  A tree gets its ID the first time it is locked along
+// with other trees, so that a zero value AvlTree is still ready to use.
+func (tt *AvlTree[T]) lockID() uint64 {
+	if id := tt.id.Load(); id != 0 {
+		return id
+	}
+	tt.id.CompareAndSwap(0, treeIDs.Add(1))
+	return tt.id.Load()
+}
+
+// lockTrees locks the trees in `wr` for writing and the trees in `rd` for reading.  The locks
+// are always taken in order of the tree IDs so that two goroutines that work on the same trees,
+// in any argument order, can not deadlock.  A tree that is passed more than once, like
+// a.Union(a, b), is locked once, for writing if it is in `wr`.  The returned function releases
+// the locks.
+func lockTrees[T comparable.Comparable](wr []*AvlTree[T], rd []*AvlTree[T]) (unlock func()) {
+	type treeLock struct {
+		tree  *AvlTree[T]
+		write bool
+	}
+	var locks []treeLock
+	add := func(tree *AvlTree[T], write bool) {
+		for ii := range locks {
+			if locks[ii].tree == tree {
+				locks[ii].write = locks[ii].write || write
+				return
+			}
+		}
+		locks = append(locks, treeLock{tree: tree, write: write})
+	}
+	for _, tree := range wr {
+		add(tree, true)
+	}
+	for _, tree := range rd {
+		add(tree, false)
+	}
+
+	sort.Slice(locks, func(i, j int) bool { return locks[i].tree.lockID() < locks[j].tree.lockID() })
+	for _, ll := range locks {
+		if ll.write {
+			ll.tree.lock.Lock()
+		} else {
+			ll.tree.lock.RLock()
+		}
+	}
+
+	return func() {
+		for ii := len(locks) - 1; ii >= 0; ii-- {
+			if locks[ii].write {
+				locks[ii].tree.lock.Unlock()
+			} else {
+				locks[ii].tree.lock.RUnlock()
+			}
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package avl_tree_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+Set operations on the AVL tree built from split and join.  Join puts two trees and a middle
+node back together in time proportional to the difference in their heights, Split cuts a
+tree at an item in O(log|2(n)).  The set operations split one tree by the root of the other
+and work down both halves, so merging m items into n items, m <= n, costs O(m log(n/m+1))
+compares instead of the O(m log(n+m)) of inserting one at a time.
+
+The set operations leave their input trees unchanged without copying them.  Split and join
+copy only the nodes on the paths they change, the sub-trees that are not touched are shared
+between the inputs and the result, so the work and the new nodes are both O(m log(n/m+1)).
+A shared node is copied by whichever tree changes it first (copy on write, see nlMutable), so
+a later Insert or Delete on the result does not change the inputs or the other way around.
+
+All the trees passed to an operation are locked for the whole of the operation, the tree that
+is changed for writing and the others for reading.  The locks are taken in a fixed order, by a per-tree ID, so operations on the same
+trees from different goroutines can not deadlock, and the receiver can also be an operand,
+a.Union(a, b).
+
+* 	Split — cut the tree into the items below and above an item.								O(log|2(n))
+* 	Join — append a tree where all the items are larger than the items in this tree.			O(log|2(n))
+* 	Copy — make a copy of a tree, the nodes are shared until one of the trees changes.			O(1)
+* 	Union — tt = yy union zz.																	O(m log(n/m+1))
+* 	Intersect — tt = yy intersect zz.															O(m log(n/m+1))
+* 	Difference — tt = yy - zz, Minus is the same.												O(m log(n/m+1))
+* 	SymmetricDifference — tt = the items in only one of yy or zz.								O(m log(n/m+1))
+* 	IsSubset — true if every item in the tree is also in yy.									O(n log|2(m))
+* 	Equal — true if the tree and yy have the same items.										O(n)
+
+*/
+
+import (
+	"errors"
+	"iter"
+)
+
+// ErrJoinOrder is returned by Join if the items in the tree being joined are not all larger
+// than the items in the tree.
+var ErrJoinOrder = errors.New("Items to Join are not in Order")
+
+// Split cuts the tree at `find`.  The items less than `find` are returned in `left`, the
+// items greater than `find` in `right`, and the item equal to `find`, if there is one, in
+// `found`.  The nodes are moved, not copied, so `tt` is empty after the split.  Only a node that
+// is shared with another tree, see Copy, is copied.
+// Complexity is O(log|2(n)).
+func (tt *AvlTree[T]) Split(find *T) (left *AvlTree[T], found *T, right *AvlTree[T]) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.Lock()
+	defer tt.lock.Unlock()
+
+	l, found, r := tt.nlSplit(tt.root, find)
+	tt.nlTruncate()
+	return tt.nlNewTree(l), found, tt.nlNewTree(r)
+}
+
+// Join appends the items in `yy` to the tree.  All the items in `yy` must be larger than the
+// items in `tt` or ErrJoinOrder is returned and neither tree is changed.  The nodes are moved,
+// not copied, so `yy` is empty after the join.
+// Complexity is O(log|2(n)).
+func (tt *AvlTree[T]) Join(yy *AvlTree[T]) error {
+	if tt == nil || yy == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	defer lockTrees([]*AvlTree[T]{tt, yy}, nil)()
+
+	if tt.root != nil && yy.root != nil {
+		if (*tt.nlFindMax()).Compare(*yy.nlFindMin()) >= 0 {
+			return ErrJoinOrder
+		}
+	}
+	tt.root = tt.nlJoin2(tt.root, yy.root)
+	tt.length = tt.sizeOf(tt.root)
+	yy.root = nil
+	yy.length = 0
+	return nil
+}
+
+// Copy makes a copy of yy in tt.  The items and the nodes are shared, each tree copies the
+// nodes on the path it changes the first time it changes them.
+// Complexity is O(1).
+func (tt *AvlTree[T]) Copy(yy *AvlTree[T]) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	defer lockTrees([]*AvlTree[T]{tt}, []*AvlTree[T]{yy})()
+
+	yy.nlShare()
+	tt.nlShare()
+	tt.root = yy.root
+	tt.length = yy.length
+}
+
+// Union is a set union, tt = yy union zz.
+// Set union - if a duplicate then the item from zz is used.
+// Complexity is O(m log(n/m+1)), m the size of the smaller tree.
+func (tt *AvlTree[T]) Union(yy, zz *AvlTree[T]) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	defer lockTrees([]*AvlTree[T]{tt}, []*AvlTree[T]{yy, zz})()
+
+	tt.nlShareWith(yy, zz)
+	tt.root = tt.nlUnion(yy.root, zz.root)
+	tt.length = tt.sizeOf(tt.root)
+}
+
+// Intersect take the set intersection.  tt = yy intersect zz.  The items from yy are used.
+// Complexity is O(m log(n/m+1)), m the size of the smaller tree.
+func (tt *AvlTree[T]) Intersect(yy, zz *AvlTree[T]) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	defer lockTrees([]*AvlTree[T]{tt}, []*AvlTree[T]{yy, zz})()
+
+	tt.nlShareWith(yy, zz)
+	tt.root = tt.nlIntersect(yy.root, zz.root)
+	tt.length = tt.sizeOf(tt.root)
+}
+
+// Difference is a set minus, tt = yy - zz.
+// Complexity is O(m log(n/m+1)), m the size of the smaller tree.
+func (tt *AvlTree[T]) Difference(yy, zz *AvlTree[T]) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	defer lockTrees([]*AvlTree[T]{tt}, []*AvlTree[T]{yy, zz})()
+
+	tt.nlShareWith(yy, zz)
+	tt.root = tt.nlDifference(yy.root, zz.root)
+	tt.length = tt.sizeOf(tt.root)
+}
+
+// Minus is a set minus, tt = yy - zz.  It is the same as Difference.
+// Complexity is O(m log(n/m+1)), m the size of the smaller tree.
+func (tt *AvlTree[T]) Minus(yy, zz *AvlTree[T]) {
+	tt.Difference(yy, zz)
+}
+
+// SymmetricDifference sets tt to the items that are in yy or zz but not in both.
+// Complexity is O(m log(n/m+1)), m the size of the smaller tree.
+func (tt *AvlTree[T]) SymmetricDifference(yy, zz *AvlTree[T]) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	defer lockTrees([]*AvlTree[T]{tt}, []*AvlTree[T]{yy, zz})()
+
+	tt.nlShareWith(yy, zz)
+	tt.root = tt.nlSymmetricDifference(yy.root, zz.root)
+	tt.length = tt.sizeOf(tt.root)
+}
+
+// IsSubset returns true if every item in tt is also in yy.
+// Complexity is O(n log|2(m)).
+func (tt *AvlTree[T]) IsSubset(yy *AvlTree[T]) bool {
+	if tt == nil || yy == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	defer lockTrees(nil, []*AvlTree[T]{tt, yy})()
+
+	if tt.length > yy.length {
+		return false
+	}
+	isSubset := true
+	tt.nlRange(nil, nil, func(item *T) bool {
+		isSubset = yy.nlSearch(item) != nil
+		return isSubset
+	})
+	return isSubset
+}
+
+// Equal returns true if tt and yy have the same items, compared with Compare.
+// Complexity is O(n).
+func (tt *AvlTree[T]) Equal(yy *AvlTree[T]) bool {
+	if tt == nil || yy == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	defer lockTrees(nil, []*AvlTree[T]{tt, yy})()
+
+	if tt.length != yy.length {
+		return false
+	}
+	next, stop := iter.Pull(func(yield func(*T) bool) { yy.nlRange(nil, nil, yield) })
+	defer stop()
+	equal := true
+	tt.nlRange(nil, nil, func(item *T) bool {
+		other, ok := next()
+		equal = ok && (*item).Compare(*other) == 0
+		return equal
+	})
+	return equal
+}
+
+// -------------------------------------------------------------------------------------------------------
+// The node level join and split.  These change the nodes of `tt` in place and copy any other
+// node before they change it, so nodes that are shared with other trees are left as they are.
+
+// nlNewTree wraps the nodes at `root` in a new tree.
+func (tt *AvlTree[T]) nlNewTree(root *AvlTreeElement[T]) *AvlTree[T] {
+	return &AvlTree[T]{root: root, length: tt.sizeOf(root)}
+}
+
+// nlShareWith gives tt and the trees that it is about to share nodes with new tokens, so
+// that none of them changes a shared node in place.
+func (tt *AvlTree[T]) nlShareWith(yy, zz *AvlTree[T]) {
+	yy.nlShare()
+	zz.nlShare()
+	tt.nlShare()
+}
+
+// nlJoin returns a balanced tree of the items in `l`, then the node `k`, then the items in `r`.
+// All of `l` must be less than `k` and all of `r` greater.
+func (tt *AvlTree[T]) nlJoin(l, k, r *AvlTreeElement[T]) *AvlTreeElement[T] {
+	if tt.Height(l) > tt.Height(r)+1 {
+		return tt.nlJoinRight(l, k, r)
+	} else if tt.Height(r) > tt.Height(l)+1 {
+		return tt.nlJoinLeft(l, k, r)
+	}
+	k = tt.nlMutable(k)
+	k.left, k.right = l, r
+	tt.setHeightSize(k)
+	return k
+}
+
+// nlJoinRight walks down the right side of the taller `l` to a sub-tree that is about the
+// height of `r`, hangs `k` and `r` there, and re-balances on the way back up.
+func (tt *AvlTree[T]) nlJoinRight(l, k, r *AvlTreeElement[T]) *AvlTreeElement[T] {
+	if tt.Height(l) <= tt.Height(r)+1 {
+		k = tt.nlMutable(k)
+		k.left, k.right = l, r
+		tt.setHeightSize(k)
+		return k
+	}
+	l = tt.nlMutable(l)
+	l.right = tt.nlJoinRight(l.right, k, r)
+	tt.nlRebalance(&l)
+	return l
+}
+
+// nlJoinLeft is the mirror of nlJoinRight for when `r` is the taller tree.
+func (tt *AvlTree[T]) nlJoinLeft(l, k, r *AvlTreeElement[T]) *AvlTreeElement[T] {
+	if tt.Height(r) <= tt.Height(l)+1 {
+		k = tt.nlMutable(k)
+		k.left, k.right = l, r
+		tt.setHeightSize(k)
+		return k
+	}
+	r = tt.nlMutable(r)
+	r.left = tt.nlJoinLeft(l, k, r.left)
+	tt.nlRebalance(&r)
+	return r
+}
+
+// nlJoin2 joins `l` and `r` with no middle node by taking the largest node out of `l`.
+func (tt *AvlTree[T]) nlJoin2(l, r *AvlTreeElement[T]) *AvlTreeElement[T] {
+	if l == nil {
+		return r
+	}
+	rest, last := tt.nlSplitLast(l)
+	return tt.nlJoin(rest, last, r)
+}
+
+// nlSplitLast takes the largest node out of the sub-tree at `e`.
+func (tt *AvlTree[T]) nlSplitLast(e *AvlTreeElement[T]) (rest, last *AvlTreeElement[T]) {
+	if e.right == nil {
+		return e.left, e
+	}
+	rest, last = tt.nlSplitLast(e.right)
+	return tt.nlJoin(e.left, e, rest), last
+}
+
+// nlSplit cuts the sub-tree at `e` into the nodes less than `find` and the nodes greater than
+// `find`.  The node equal to `find` is dropped and its item returned in `found`.
+func (tt *AvlTree[T]) nlSplit(e *AvlTreeElement[T], find *T) (l *AvlTreeElement[T], found *T, r *AvlTreeElement[T]) {
+	if e == nil {
+		return nil, nil, nil
+	}
+	c := (*find).Compare(*e.data)
+	if c == 0 {
+		return e.left, e.data, e.right
+	} else if c < 0 {
+		ll, found, lr := tt.nlSplit(e.left, find)
+		return ll, found, tt.nlJoin(lr, e, e.right)
+	}
+	rl, found, rr := tt.nlSplit(e.right, find)
+	return tt.nlJoin(e.left, e, rl), found, rr
+}
+
+// nlUnion merges the nodes of `t1` and `t2`, for a duplicate the item from `t2` is kept.
+func (tt *AvlTree[T]) nlUnion(t1, t2 *AvlTreeElement[T]) *AvlTreeElement[T] {
+	if t1 == nil {
+		return t2
+	} else if t2 == nil {
+		return t1
+	}
+	l2, found, r2 := tt.nlSplit(t2, t1.data)
+	if found != nil {
+		t1 = tt.nlMutable(t1)
+		t1.data = found
+	}
+	l, r := tt.nlUnion(t1.left, l2), tt.nlUnion(t1.right, r2)
+	return tt.nlJoin(l, t1, r)
+}
+
+// nlIntersect keeps the nodes of `t1` that are also in `t2`.
+func (tt *AvlTree[T]) nlIntersect(t1, t2 *AvlTreeElement[T]) *AvlTreeElement[T] {
+	if t1 == nil || t2 == nil {
+		return nil
+	}
+	l2, found, r2 := tt.nlSplit(t2, t1.data)
+	l, r := tt.nlIntersect(t1.left, l2), tt.nlIntersect(t1.right, r2)
+	if found != nil {
+		return tt.nlJoin(l, t1, r)
+	}
+	return tt.nlJoin2(l, r)
+}
+
+// nlDifference keeps the nodes of `t1` that are not in `t2`.
+func (tt *AvlTree[T]) nlDifference(t1, t2 *AvlTreeElement[T]) *AvlTreeElement[T] {
+	if t1 == nil || t2 == nil {
+		return t1
+	}
+	l1, _, r1 := tt.nlSplit(t1, t2.data)
+	l, r := tt.nlDifference(l1, t2.left), tt.nlDifference(r1, t2.right)
+	return tt.nlJoin2(l, r)
+}
+
+// nlSymmetricDifference keeps the nodes that are in only one of `t1` or `t2`.
+func (tt *AvlTree[T]) nlSymmetricDifference(t1, t2 *AvlTreeElement[T]) *AvlTreeElement[T] {
+	if t1 == nil {
+		return t2
+	} else if t2 == nil {
+		return t1
+	}
+	l2, found, r2 := tt.nlSplit(t2, t1.data)
+	l, r := tt.nlSymmetricDifference(t1.left, l2), tt.nlSymmetricDifference(t1.right, r2)
+	if found == nil {
+		return tt.nlJoin(l, t1, r)
+	}
+	return tt.nlJoin2(l, r)
+}
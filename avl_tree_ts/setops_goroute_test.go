@@ -0,0 +1,98 @@
+package avl_tree_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+// These tests are meant to be run with the race detector, `go test -race`.
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTreeSetOpsAliasing(t *testing.T) {
+	aa, am := setOpsTree([]int{1, 2, 3, 4})
+	bb, _ := setOpsTree([]int{3, 4, 5})
+
+	aa.Union(aa, bb)
+	am[5] = true
+	setOpsCheck(t, "a.Union(a, b)", aa, am)
+
+	aa.Difference(aa, bb)
+	setOpsCheck(t, "a.Difference(a, b)", aa, map[int]bool{1: true, 2: true})
+
+	aa.Union(aa, aa)
+	setOpsCheck(t, "a.Union(a, a)", aa, map[int]bool{1: true, 2: true})
+
+	bb.Intersect(aa, bb)
+	setOpsCheck(t, "b.Intersect(a, b)", bb, map[int]bool{})
+
+	aa.Copy(aa)
+	setOpsCheck(t, "a.Copy(a)", aa, map[int]bool{1: true, 2: true})
+
+	if !aa.Equal(aa) || !aa.IsSubset(aa) {
+		t.Errorf("Expected a tree to be Equal to and a subset of itself")
+	}
+	if err := aa.Join(aa); err != ErrJoinOrder {
+		t.Errorf("Expected ErrJoinOrder joining a tree to itself got %v", err)
+	}
+
+	aa.SymmetricDifference(aa, aa)
+	setOpsCheck(t, "a.SymmetricDifference(a, a)", aa, map[int]bool{})
+}
+
+func TestTreeSetOpsConcurrent(t *testing.T) {
+	var keys [3][]int
+	for ii := 0; ii < 200; ii++ {
+		keys[ii%3] = append(keys[ii%3], ii)
+	}
+	aa, _ := setOpsTree(keys[0])
+	bb, _ := setOpsTree(keys[1])
+	cc, _ := setOpsTree(keys[2])
+
+	ops := []func(){
+		func() { aa.Union(bb, cc) },
+		func() { bb.Union(aa, cc) },
+		func() { cc.Union(bb, aa) },
+		func() { aa.Union(aa, bb) },
+		func() { bb.Intersect(cc, bb) },
+		func() { cc.Difference(aa, cc) },
+		func() { aa.SymmetricDifference(cc, aa) },
+		func() { bb.Copy(cc) },
+		func() { aa.Equal(bb) },
+		func() { bb.IsSubset(aa) },
+		func() { cc.Insert(&TestTreeNode{S: fmt.Sprintf("%04d", 1000)}) },
+		func() { aa.Search(&TestTreeNode{S: fmt.Sprintf("%04d", 10)}) },
+	}
+
+	var wg sync.WaitGroup
+	for gg := 0; gg < 8; gg++ {
+		wg.Add(1)
+		go func(gg int) {
+			defer wg.Done()
+			for ii := 0; ii < 200; ii++ {
+				ops[(gg+ii*5)%len(ops)]()
+			}
+		}(gg)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t.Fatalf("Set operations on shared trees did not finish, deadlock")
+	}
+
+	for _, tree := range []*AvlTree[TestTreeNode]{aa, bb, cc} {
+		avlValidate(t, tree)
+	}
+}
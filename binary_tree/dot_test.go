@@ -0,0 +1,45 @@
+package binary_tree
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pschlump/pluto/dot"
+)
+
+func TestWriteDOT(t *testing.T) {
+	var Tree1 BinaryTree[TestTreeNode]
+
+	for _, ss := range []string{"05", "02", "09", "00"} {
+		Tree1.Insert(&TestTreeNode{S: ss})
+	}
+
+	var buf bytes.Buffer
+	opts := &dot.Options{Label: func(x any) string { return x.(TestTreeNode).S }}
+	if err := Tree1.WriteDOT(&buf, opts); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expect := `digraph "G" {
+	"n0" [label="05"];
+	"n0" -> "n1" [label="L"];
+	"n0" -> "n2" [label="R"];
+	"n1" [label="02"];
+	"n1" -> "n3" [label="L"];
+	"n3" [label="00"];
+	"n2" [label="09"];
+}
+`
+	if got := buf.String(); got != expect {
+		t.Errorf("WriteDOT error, expcted\n%s\ngot\n%s", expect, got)
+	}
+
+	if _, err := dot.Parse(&buf); err != nil {
+		t.Errorf("Output is not valid DOT: %s", err)
+	}
+}
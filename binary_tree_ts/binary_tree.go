@@ -10,6 +10,7 @@ BSD 3 Clause Licensed.
 
 Basic operations on a Binary Tree.
 
+* 	ConvertToSlice - return the data in order as a slice.										O(n)
 * 	Insert - create a new element in tree.														O(log|2(n))
 * 	Delete — Deletes a specified element from the linked list (Element can be fond via Search). O(log|2(n))
 * 	Index - return the Nth item	in the list - in a format usable with Delete.					O(n)
@@ -43,6 +44,7 @@ import (
 	"github.com/pschlump/dbgo"
 	"github.com/pschlump/pluto/comparable"
 	"github.com/pschlump/pluto/g_lib"
+	"github.com/pschlump/pluto/iface_list"
 	// "github.com/pschlump/MiscLib"
 )
 
@@ -58,6 +60,9 @@ type BinaryTree[T comparable.Comparable] struct {
 	lock   sync.RWMutex
 }
 
+// At compile time verify that BinaryTree can be used behind the iface_list interfaces.
+var _ iface_list.TreeDataType[comparable.Int] = (*BinaryTree[comparable.Int])(nil)
+
 // -------------------------------------------------------------------------------------------------------
 
 // Create a new BinaryTree and return it.
@@ -241,18 +246,12 @@ func (tt *BinaryTree[T]) nlDelete(find *T) (found bool) {
 	}
 
 	findLeftMostInRightSubtree := func(parent **BinaryTreeElement[T]) (found bool, pAtIt **BinaryTreeElement[T]) {
-		// fmt.Printf ( "%sFindLeftMost/At Top: at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
-		this := **parent
 		if *parent == nil {
-			// fmt.Printf ( "%sFindLeftMost/no tree: at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
 			return
 		}
-		for this.right != nil {
-			// fmt.Printf ( "%sAdvance 1 step. at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
-			parent = &(this.right)
-			this = **parent
+		for (*parent).left != nil { // Walk the tree pointers, not a copy of the node.
+			parent = &((*parent).left)
 		}
-		// fmt.Printf ( "%sat bottom at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
 		found = true
 		pAtIt = parent
 		return
@@ -515,6 +514,20 @@ func (tt *BinaryTree[T]) nlDepth() (d int) {
 	return
 }
 
+// ConvertToSlice returns the data in the tree in order.
+// Complexity is O(n).
+func (tt *BinaryTree[T]) ConvertToSlice() (rv []*T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	rv = []*T{}
+	tt.WalkInOrder(func(pos, depth int, data *T, userData interface{}) bool {
+		rv = append(rv, data)
+		return true
+	}, nil)
+	return
+}
+
 type ApplyFunction[T comparable.Comparable] func(pos, depth int, data *T, userData interface{}) bool
 
 func (tt *BinaryTree[T]) WalkInOrder(fx ApplyFunction[T], userData interface{}) {
@@ -655,18 +668,12 @@ func (tt *BinaryTree[T]) nlDeleteMatch(find *T, fx func(a, b *T) int) (found boo
 	}
 
 	findLeftMostInRightSubtree := func(parent **BinaryTreeElement[T]) (found bool, pAtIt **BinaryTreeElement[T]) {
-		// fmt.Printf ( "%sFindLeftMost/At Top: at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
-		this := **parent
 		if *parent == nil {
-			// fmt.Printf ( "%sFindLeftMost/no tree: at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
 			return
 		}
-		for this.right != nil {
-			// fmt.Printf ( "%sAdvance 1 step. at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
-			parent = &(this.right)
-			this = **parent
+		for (*parent).left != nil { // Walk the tree pointers, not a copy of the node.
+			parent = &((*parent).left)
 		}
-		// fmt.Printf ( "%sat bottom at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
 		found = true
 		pAtIt = parent
 		return
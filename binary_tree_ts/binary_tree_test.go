@@ -15,6 +15,7 @@ import (
 	"github.com/pschlump/MiscLib"
 	"github.com/pschlump/dbgo"
 	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/iface_list"
 )
 
 // TestTreeNode is an Inteface Matcing data type for the Nodes that supports the Comparable
@@ -580,3 +581,39 @@ const db4 = false
 const db5 = false
 const db6 = false
 const db8 = false
+
+func TestTreeDataType(t *testing.T) {
+	var tr iface_list.TreeDataType[TestTreeNode] = NewBinaryTree[TestTreeNode]()
+
+	for _, s := range []string{"03", "01", "02"} {
+		if !tr.Insert(&TestTreeNode{S: s}) {
+			t.Errorf("Expected %s to be new", s)
+		}
+	}
+	if tr.Insert(&TestTreeNode{S: "02"}) {
+		t.Errorf("Expected 02 to be a duplicate")
+	}
+	if tr.Length() != 3 {
+		t.Errorf("Expected length 3 got %d", tr.Length())
+	}
+
+	got := ""
+	for _, v := range tr.ConvertToSlice() {
+		got += v.S
+	}
+	if got != "010203" {
+		t.Errorf("ConvertToSlice error, expcted %s got %s", "010203", got)
+	}
+
+	if !tr.Delete(&TestTreeNode{S: "01"}) {
+		t.Errorf("Expected to delete 01")
+	}
+	if x := tr.FindMin(); x == nil || x.S != "02" {
+		t.Errorf("FindMin error, expcted 02 got %v", x)
+	}
+
+	tr.Truncate()
+	if !tr.IsEmpty() || len(tr.ConvertToSlice()) != 0 {
+		t.Errorf("Expected empty tree after Truncate")
+	}
+}
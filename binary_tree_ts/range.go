@@ -0,0 +1,182 @@
+package binary_tree_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+Range queries on the tree.  These go down only the sub-trees that can hold an answer so they
+do not need a full WalkInOrder.  The read lock is held for the whole of a RangeWalk or a
+range loop.
+
+* 	Floor — the largest item that is less than or equal to an item.							O(h)
+* 	Ceiling — the smallest item that is greater than or equal to an item.						O(h)
+* 	Lower — the largest item that is strictly less than an item.								O(h)
+* 	Higher — the smallest item that is strictly greater than an item.							O(h)
+* 	RangeWalk — call a function in order for each item from lo to hi.							O(h+k)
+* 	Range — an iter.Seq over the items from lo to hi.											O(h+k)
+* 	All — an iter.Seq over all the items.														O(n)
+
+h is the height of the tree, O(n) in the worst case as the tree is not balanced, and k is the number of items in the range.
+
+*/
+
+import (
+	"iter"
+
+	"github.com/pschlump/pluto/stack"
+)
+
+// Floor returns the largest item in the tree that is less than or equal to `find`.
+// If there is no such item then nil is returned.
+func (tt *BinaryTree[T]) Floor(find *T) (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return tt.nlBelow(find, true)
+}
+
+// Ceiling returns the smallest item in the tree that is greater than or equal to `find`.
+// If there is no such item then nil is returned.
+func (tt *BinaryTree[T]) Ceiling(find *T) (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return tt.nlAbove(find, true)
+}
+
+// Lower returns the largest item in the tree that is strictly less than `find`.
+// If there is no such item then nil is returned.
+func (tt *BinaryTree[T]) Lower(find *T) (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return tt.nlBelow(find, false)
+}
+
+// Higher returns the smallest item in the tree that is strictly greater than `find`.
+// If there is no such item then nil is returned.
+func (tt *BinaryTree[T]) Higher(find *T) (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return tt.nlAbove(find, false)
+}
+
+// RangeWalk calls `fx` in order for each item from `lo` to `hi`, both included.  A nil `lo`
+// or `hi` leaves that end of the range open.  The walk stops if `fx` returns false.
+// The read lock is held during the walk so `fx` must not modify the tree.
+func (tt *BinaryTree[T]) RangeWalk(lo, hi *T, fx func(item *T) bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	tt.nlRange(lo, hi, fx)
+}
+
+// Range returns an iterator over the items from `lo` to `hi`, both included, in order.  A nil
+// `lo` or `hi` leaves that end of the range open.  The read lock is
+// held for the whole loop so the body must not modify the tree.
+//
+//	for item := range tree.Range(&lo, &hi) {
+//		...
+//	}
+func (tt *BinaryTree[T]) Range(lo, hi *T) iter.Seq[*T] {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	return func(yield func(*T) bool) {
+		tt.RangeWalk(lo, hi, yield)
+	}
+}
+
+// All returns an iterator over all the items in the tree in order.
+func (tt *BinaryTree[T]) All() iter.Seq[*T] {
+	return tt.Range(nil, nil)
+}
+
+// nlBelow returns the largest item that is less than `find`, or equal to it if `orEqual` is
+// true.  Nil is returned if there is none.
+func (tt *BinaryTree[T]) nlBelow(find *T, orEqual bool) (item *T) {
+	for cur := tt.root; cur != nil; {
+		c := (*find).Compare(*cur.data)
+		if c > 0 || (c == 0 && orEqual) {
+			item = cur.data // best so far, look for a larger one on the right
+			if c == 0 {
+				return
+			}
+			cur = cur.right
+		} else {
+			cur = cur.left
+		}
+	}
+	return
+}
+
+// nlAbove returns the smallest item that is greater than `find`, or equal to it if `orEqual`
+// is true.  Nil is returned if there is none.
+func (tt *BinaryTree[T]) nlAbove(find *T, orEqual bool) (item *T) {
+	for cur := tt.root; cur != nil; {
+		c := (*find).Compare(*cur.data)
+		if c < 0 || (c == 0 && orEqual) {
+			item = cur.data // best so far, look for a smaller one on the left
+			if c == 0 {
+				return
+			}
+			cur = cur.left
+		} else {
+			cur = cur.right
+		}
+	}
+	return
+}
+
+// nlRange calls `fx` in order for each item from `lo` to `hi` without locking.  This is not
+// recursive, it keeps its own stack of the nodes still to visit.  Sub-trees that are all
+// below `lo` are never pushed and the walk stops at the first item above `hi`.
+func (tt *BinaryTree[T]) nlRange(lo, hi *T, fx func(item *T) bool) {
+	var stk stack.Stack[*BinaryTreeElement[T]]
+	pushLeft := func(cur *BinaryTreeElement[T]) {
+		for cur != nil {
+			if lo != nil && (*lo).Compare(*cur.data) > 0 {
+				cur = cur.right // cur and everything to its left is below lo
+			} else {
+				stk.Push(cur)
+				cur = cur.left
+			}
+		}
+	}
+	pushLeft(tt.root)
+	for !stk.IsEmpty() {
+		cur, _ := stk.Pop()
+		if hi != nil && (*hi).Compare(*cur.data) < 0 {
+			return
+		}
+		if !fx(cur.data) {
+			return
+		}
+		pushLeft(cur.right)
+	}
+}
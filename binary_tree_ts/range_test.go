@@ -0,0 +1,119 @@
+package binary_tree_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func rangeKey(k int) *TestTreeNode {
+	return &TestTreeNode{S: fmt.Sprintf("%04d", k)}
+}
+
+func TestTreeFloorCeiling(t *testing.T) {
+	var Tree1 BinaryTree[TestTreeNode]
+
+	if Tree1.Floor(rangeKey(5)) != nil || Tree1.Ceiling(rangeKey(5)) != nil {
+		t.Errorf("Expected nil from an empty tree")
+	}
+
+	for _, k := range []int{30, 10, 40, 20} {
+		Tree1.Insert(rangeKey(k))
+	}
+
+	tests := []struct {
+		find                          int
+		floor, ceiling, lower, higher string
+	}{
+		{find: 5, floor: "", ceiling: "0010", lower: "", higher: "0010"},
+		{find: 10, floor: "0010", ceiling: "0010", lower: "", higher: "0020"},
+		{find: 25, floor: "0020", ceiling: "0030", lower: "0020", higher: "0030"},
+		{find: 40, floor: "0040", ceiling: "0040", lower: "0030", higher: ""},
+		{find: 45, floor: "0040", ceiling: "", lower: "0040", higher: ""},
+	}
+	str := func(item *TestTreeNode) string {
+		if item == nil {
+			return ""
+		}
+		return item.S
+	}
+	for ii, test := range tests {
+		if s := str(Tree1.Floor(rangeKey(test.find))); s != test.floor {
+			t.Errorf("Test %d, Floor expcted %s got %s", ii, test.floor, s)
+		}
+		if s := str(Tree1.Ceiling(rangeKey(test.find))); s != test.ceiling {
+			t.Errorf("Test %d, Ceiling expcted %s got %s", ii, test.ceiling, s)
+		}
+		if s := str(Tree1.Lower(rangeKey(test.find))); s != test.lower {
+			t.Errorf("Test %d, Lower expcted %s got %s", ii, test.lower, s)
+		}
+		if s := str(Tree1.Higher(rangeKey(test.find))); s != test.higher {
+			t.Errorf("Test %d, Higher expcted %s got %s", ii, test.higher, s)
+		}
+	}
+}
+
+func TestTreeRange(t *testing.T) {
+	var Tree1 BinaryTree[TestTreeNode]
+	var keys []int
+	rnd := rand.New(rand.NewSource(1001))
+	for _, k := range rnd.Perm(200) {
+		if k%3 != 0 {
+			Tree1.Insert(rangeKey(k))
+			keys = append(keys, k)
+		}
+	}
+	sort.Ints(keys)
+
+	for ii := 0; ii < 100; ii++ {
+		lo, hi := rnd.Intn(220)-10, rnd.Intn(220)-10
+		var want []string
+		for _, k := range keys {
+			if k >= lo && k <= hi {
+				want = append(want, fmt.Sprintf("%04d", k))
+			}
+		}
+		var got []string
+		for item := range Tree1.Range(rangeKey(lo), rangeKey(hi)) {
+			got = append(got, item.S)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("Range(%d,%d) expcted %v got %v", lo, hi, want, got)
+		}
+	}
+
+	// Open ends and stopping early.
+	n := 0
+	Tree1.RangeWalk(nil, rangeKey(10), func(item *TestTreeNode) bool {
+		n++
+		return true
+	})
+	if n != 7 { // 1 2 4 5 7 8 10
+		t.Errorf("RangeWalk(nil,10) expcted 7 got %d", n)
+	}
+	var got []string
+	for item := range Tree1.Range(rangeKey(190), nil) {
+		got = append(got, item.S)
+		if len(got) == 3 {
+			break
+		}
+	}
+	if fmt.Sprint(got) != "[0190 0191 0193]" {
+		t.Errorf("Range(190,nil) with break got %v", got)
+	}
+
+	n = 0
+	for range Tree1.All() {
+		n++
+	}
+	if n != len(keys) {
+		t.Errorf("All expcted %d got %d", len(keys), n)
+	}
+}
@@ -0,0 +1,209 @@
+package bst
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+The operations that only read a binary search tree, for the trees that keep the number of
+nodes in each sub-tree in the node.  These do not depend on how the tree is balanced, so
+../rb_tree, ../treap and their _ts versions share them and only have the insert, delete and
+balance code of their own.  Each tree passes its root, the functions do no locking, the tree
+takes its own lock around the call.
+
+* 	Find - return the node that matches an item.												O(h)
+* 	Min, Max - the smallest and largest item.													O(h)
+* 	Index - return the Nth item in order.														O(h)
+* 	Rank - the number of items less than an item, its position for Index.					O(h)
+* 	Depth - the number of nodes on the longest path from the root.								O(n)
+* 	ConvertToSlice - return the data in order as a slice.										O(n)
+* 	Dump - print the tree.																		O(n)
+*	WalkInOrder, WalkPreOrder, WalkPostOrder - Apply a function to all the nodes.				O(n)
+*	NewIter - an iterator in order or in reverse order.
+
+h is the height of the tree, O(log|2(n)) for a balanced tree.
+
+*/
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/g_lib"
+)
+
+// Find returns the node that matches `find`, nil if there is none.
+// Complexity is O(h).
+func Find[T comparable.Comparable, N Node[T, N]](root N, find *T) N {
+	var null N
+	cur := root
+	for cur != null {
+		c := (*find).Compare(*cur.GetData())
+		if c == 0 {
+			return cur
+		} else if c < 0 {
+			cur = cur.Left()
+		} else {
+			cur = cur.Right()
+		}
+	}
+	return null
+}
+
+// Min returns the smallest item, nil if the tree is empty.
+// Complexity is O(h).
+func Min[T comparable.Comparable, N Node[T, N]](root N) (item *T) {
+	var null N
+	for cur := root; cur != null; cur = cur.Left() {
+		item = cur.GetData()
+	}
+	return
+}
+
+// Max returns the largest item, nil if the tree is empty.
+// Complexity is O(h).
+func Max[T comparable.Comparable, N Node[T, N]](root N) (item *T) {
+	var null N
+	for cur := root; cur != null; cur = cur.Right() {
+		item = cur.GetData()
+	}
+	return
+}
+
+// Index returns the N-th item in the tree, 0 based, in order.  It uses the sub-tree sizes
+// to go directly down to the item.
+// Complexity is O(h).
+func Index[T comparable.Comparable, N Node[T, N]](root N, pos int) (item *T) {
+	var null N
+	if pos < 0 || pos >= root.Size() {
+		return nil
+	}
+	cur := root
+	for cur != null {
+		n := cur.Left().Size()
+		if pos < n {
+			cur = cur.Left()
+		} else if pos > n {
+			pos -= n + 1 // skip the left sub-tree and this node
+			cur = cur.Right()
+		} else {
+			return cur.GetData()
+		}
+	}
+	return nil
+}
+
+// Rank returns the number of items in the tree that are less than `find`.  If `find` is in
+// the tree this is its position as used by Index.
+// Complexity is O(h).
+func Rank[T comparable.Comparable, N Node[T, N]](root N, find *T) (n int) {
+	var null N
+	for cur := root; cur != null; {
+		if (*find).Compare(*cur.GetData()) > 0 {
+			n += cur.Left().Size() + 1
+			cur = cur.Right()
+		} else {
+			cur = cur.Left()
+		}
+	}
+	return
+}
+
+// Depth returns the height of the tree, the number of nodes on the longest path from the root.
+// Complexity is O(n).
+func Depth[T comparable.Comparable, N Node[T, N]](root N) int {
+	var null N
+	if root == null {
+		return 0
+	}
+	return g_lib.Max(Depth[T](root.Left()), Depth[T](root.Right())) + 1
+}
+
+// ConvertToSlice returns the data in the tree in order.
+// Complexity is O(n).
+func ConvertToSlice[T comparable.Comparable, N Node[T, N]](root N) (rv []*T) {
+	rv = make([]*T, 0, root.Size())
+	WalkInOrder(root, func(pos, depth int, data *T, userData interface{}) bool {
+		rv = append(rv, data)
+		return true
+	}, nil)
+	return
+}
+
+// Dump will print out the tree to the file `fo`, one item a line indented by its depth.
+// Complexity is O(n).
+func Dump[T comparable.Comparable, N Node[T, N]](fo io.Writer, root N) {
+	k := Depth[T](root) * 4
+	WalkInOrder(root, func(pos, depth int, data *T, userData interface{}) bool {
+		fmt.Fprintf(fo, "%s%v%s\n", strings.Repeat(" ", 4*depth), *data, strings.Repeat(" ", k-(4*depth)))
+		return true
+	}, nil)
+}
+
+// WalkInOrder calls `fx` for each item in order.  The walk stops if `fx` returns false.
+// Complexity is O(n).
+func WalkInOrder[T comparable.Comparable, N Node[T, N]](root N, fx func(pos, depth int, data *T, userData interface{}) bool, userData interface{}) {
+	var null N
+	p := 0
+	b := true
+	var inorderTraversal func(cur N, n int)
+	inorderTraversal = func(cur N, n int) {
+		if cur == null || !b {
+			return
+		}
+		inorderTraversal(cur.Left(), n+1)
+		if b {
+			b = fx(p, n, cur.GetData(), userData)
+			p++
+		}
+		inorderTraversal(cur.Right(), n+1)
+	}
+	inorderTraversal(root, 0)
+}
+
+// WalkPreOrder calls `fx` for each node before its children.  The walk stops if `fx`
+// returns false.
+// Complexity is O(n).
+func WalkPreOrder[T comparable.Comparable, N Node[T, N]](root N, fx func(pos, depth int, data *T, userData interface{}) bool, userData interface{}) {
+	var null N
+	p := 0
+	b := true
+	var preOrderTraversal func(cur N, n int)
+	preOrderTraversal = func(cur N, n int) {
+		if cur == null || !b {
+			return
+		}
+		b = fx(p, n, cur.GetData(), userData)
+		p++
+		preOrderTraversal(cur.Left(), n+1)
+		preOrderTraversal(cur.Right(), n+1)
+	}
+	preOrderTraversal(root, 0)
+}
+
+// WalkPostOrder calls `fx` for each node after its children.  The walk stops if `fx`
+// returns false.
+// Complexity is O(n).
+func WalkPostOrder[T comparable.Comparable, N Node[T, N]](root N, fx func(pos, depth int, data *T, userData interface{}) bool, userData interface{}) {
+	var null N
+	p := 0
+	b := true
+	var postOrderTraversal func(cur N, n int)
+	postOrderTraversal = func(cur N, n int) {
+		if cur == null || !b {
+			return
+		}
+		postOrderTraversal(cur.Left(), n+1)
+		postOrderTraversal(cur.Right(), n+1)
+		if b {
+			b = fx(p, n, cur.GetData(), userData)
+			p++
+		}
+	}
+	postOrderTraversal(root, 0)
+}
@@ -0,0 +1,217 @@
+package bst
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/pschlump/pluto/comparable"
+)
+
+// TestTreeNode is an Inteface Matcing data type for the Nodes that supports the Comparable
+// interface.  This means that it has a Compare fucntion.
+type TestTreeNode struct {
+	S string
+}
+
+// At compile time verify that this is a correct type/interface setup.
+var _ comparable.Comparable = (*TestTreeNode)(nil)
+
+// Compare implements the Compare function to satisfy the interface requirements.
+func (aa TestTreeNode) Compare(x comparable.Comparable) int {
+	if bb, ok := x.(TestTreeNode); ok {
+		if aa.S < bb.S {
+			return -1
+		} else if aa.S > bb.S {
+			return 1
+		}
+	} else if bb, ok := x.(*TestTreeNode); ok {
+		if aa.S < bb.S {
+			return -1
+		} else if aa.S > bb.S {
+			return 1
+		}
+	} else {
+		panic(fmt.Sprintf("Passed invalid type %T to a Compare function.", x))
+	}
+	return 0
+}
+
+// testNode is a node of a tree that is not balanced, enough to test the functions.
+type testNode struct {
+	data        *TestTreeNode
+	size        int
+	left, right *testNode
+}
+
+func (ee *testNode) GetData() *TestTreeNode { return ee.data }
+func (ee *testNode) Left() *testNode        { return ee.left }
+func (ee *testNode) Right() *testNode       { return ee.right }
+
+func (ee *testNode) Size() int {
+	if ee == nil {
+		return 0
+	}
+	return ee.size
+}
+
+// insert adds `s` under `cur` and returns the new sub-tree.
+func insert(cur *testNode, s string) *testNode {
+	if cur == nil {
+		return &testNode{data: &TestTreeNode{S: s}, size: 1}
+	}
+	if s < cur.data.S {
+		cur.left = insert(cur.left, s)
+	} else if s > cur.data.S {
+		cur.right = insert(cur.right, s)
+	}
+	cur.size = cur.left.Size() + cur.right.Size() + 1
+	return cur
+}
+
+func TestTreeSearch(t *testing.T) {
+	var root *testNode
+
+	if Find(root, &TestTreeNode{S: "05"}) != nil || Min[TestTreeNode](root) != nil || Max[TestTreeNode](root) != nil {
+		t.Errorf("Expected nothing in an empty tree")
+	}
+	if Index[TestTreeNode](root, 0) != nil || Depth[TestTreeNode](root) != 0 || len(ConvertToSlice[TestTreeNode](root)) != 0 {
+		t.Errorf("Expected nothing in an empty tree")
+	}
+	for _, s := range []string{"05", "02", "09", "00", "03"} {
+		root = insert(root, s)
+	}
+	if x := Find(root, &TestTreeNode{S: "09"}); x == nil || x.data.S != "09" {
+		t.Errorf("Expected to find 09")
+	}
+	if x := Find(root, &TestTreeNode{S: "04"}); x != nil {
+		t.Errorf("Expected nil got %v", x)
+	}
+	if x := Min[TestTreeNode](root); x.S != "00" {
+		t.Errorf("Min expcted 00 got %s", x.S)
+	}
+	if x := Max[TestTreeNode](root); x.S != "09" {
+		t.Errorf("Max expcted 09 got %s", x.S)
+	}
+	if x := Index[TestTreeNode](root, 1); x.S != "02" {
+		t.Errorf("Index(1) expcted 02 got %s", x.S)
+	}
+	if x := Index[TestTreeNode](root, 5); x != nil {
+		t.Errorf("Index(5) expcted nil got %s", x.S)
+	}
+	if n := Rank(root, &TestTreeNode{S: "04"}); n != 3 {
+		t.Errorf("Rank(04) expcted 3 got %d", n)
+	}
+	if d := Depth[TestTreeNode](root); d != 3 {
+		t.Errorf("Depth expcted 3 got %d", d)
+	}
+
+	var buf bytes.Buffer
+	Dump[TestTreeNode](&buf, root)
+	if buf.String() != "        {00}    \n    {02}        \n        {03}    \n{05}            \n    {09}        \n" {
+		t.Errorf("Dump got %q", buf.String())
+	}
+}
+
+func TestTreeWalk(t *testing.T) {
+	var root *testNode
+	for _, s := range []string{"05", "02", "09", "00", "03"} {
+		root = insert(root, s)
+	}
+	walk := func(fn func(root *testNode, fx func(pos, depth int, data *TestTreeNode, userData interface{}) bool, userData interface{}), stopAt int) (got []string) {
+		fn(root, func(pos, depth int, data *TestTreeNode, userData interface{}) bool {
+			got = append(got, fmt.Sprintf("%d:%d:%s", pos, depth, data.S))
+			return pos+1 != stopAt
+		}, nil)
+		return
+	}
+	tests := []struct {
+		name   string
+		fn     func(root *testNode, fx func(pos, depth int, data *TestTreeNode, userData interface{}) bool, userData interface{})
+		stopAt int
+		expect string
+	}{
+		{"InOrder", WalkInOrder[TestTreeNode, *testNode], 0, "[0:2:00 1:1:02 2:2:03 3:0:05 4:1:09]"},
+		{"PreOrder", WalkPreOrder[TestTreeNode, *testNode], 0, "[0:0:05 1:1:02 2:2:00 3:2:03 4:1:09]"},
+		{"PostOrder", WalkPostOrder[TestTreeNode, *testNode], 0, "[0:2:00 1:2:03 2:1:02 3:1:09 4:0:05]"},
+		{"InOrder stop", WalkInOrder[TestTreeNode, *testNode], 2, "[0:2:00 1:1:02]"},
+		{"PreOrder stop", WalkPreOrder[TestTreeNode, *testNode], 2, "[0:0:05 1:1:02]"},
+		{"PostOrder stop", WalkPostOrder[TestTreeNode, *testNode], 2, "[0:2:00 1:2:03]"},
+	}
+	for _, test := range tests {
+		if got := fmt.Sprint(walk(test.fn, test.stopAt)); got != test.expect {
+			t.Errorf("%s expcted %s got %s", test.name, test.expect, got)
+		}
+	}
+}
+
+func TestTreeIter(t *testing.T) {
+	var root *testNode
+
+	if it := NewIter[TestTreeNode](root, false); !it.Done() || it.Value() != nil {
+		t.Errorf("Expected an empty tree to be Done")
+	}
+
+	for _, k := range []int{5, 2, 9, 0, 3, 7, 8, 1} {
+		root = insert(root, fmt.Sprintf("%02d", k))
+	}
+
+	var got []string
+	for it := NewIter[TestTreeNode](root, false); !it.Done(); it.Next() {
+		got = append(got, it.Value().S)
+	}
+	if fmt.Sprint(got) != "[00 01 02 03 05 07 08 09]" {
+		t.Errorf("Front iteration got %v", got)
+	}
+
+	got = got[:0]
+	for it := NewIter[TestTreeNode](root, true); !it.Done(); it.Next() {
+		got = append(got, it.Value().S)
+	}
+	if fmt.Sprint(got) != "[09 08 07 05 03 02 01 00]" {
+		t.Errorf("Rear iteration got %v", got)
+	}
+}
+
+func TestTreeOrderStatistics(t *testing.T) {
+	var root *testNode
+	model := make(map[string]bool)
+	rnd := rand.New(rand.NewSource(1001))
+	for ii := 0; ii < 500; ii++ {
+		s := fmt.Sprintf("%04d", rnd.Intn(1000))
+		root = insert(root, s)
+		model[s] = true
+	}
+	keys := make([]string, 0, len(model))
+	for s := range model {
+		keys = append(keys, s)
+	}
+	sort.Strings(keys)
+
+	got := ConvertToSlice[TestTreeNode](root)
+	if len(got) != len(keys) {
+		t.Fatalf("ConvertToSlice expcted %d items got %d", len(keys), len(got))
+	}
+	for pos, s := range keys {
+		if got[pos].S != s {
+			t.Errorf("ConvertToSlice[%d] expcted %s got %s", pos, s, got[pos].S)
+		}
+		if x := Index[TestTreeNode](root, pos); x == nil || x.S != s {
+			t.Errorf("Index(%d) expcted %s got %v", pos, s, x)
+		}
+		if n := Rank(root, &TestTreeNode{S: s}); n != pos {
+			t.Errorf("Rank(%s) expcted %d got %d", s, pos, n)
+		}
+	}
+	if n := Rank(root, &TestTreeNode{S: "9999"}); n != len(keys) {
+		t.Errorf("Rank(9999) expcted %d got %d", len(keys), n)
+	}
+}
@@ -0,0 +1,94 @@
+package bst
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/stack"
+)
+
+// An iteration type that allows a for loop to walk the tree inorder, or
+// in reverse order.  A tree embeds it in its own iterator type:
+//
+//	for it := tree.Front(); !it.Done(); it.Next() {
+//		item := it.Value()
+//		...
+//	}
+//
+// This is more moemory effecient than the Walk* functions becasue it
+// manages the stack interally.  The tree must not be modified while
+// an iterator is in use.
+type Iter[T comparable.Comparable, N Node[T, N]] struct {
+	cur     N    // Pointer to the current element.
+	reverse bool // true for a reverse iteration, Next moves to smaller items
+
+	// The "Stack" of nodes above cur that are still to be visited.
+	stk stack.Stack[N]
+}
+
+// NewIter starts an iteration at the first inorder node under `root`, or at the last one if
+// `reverse` is true.
+// Complexity is O(h).
+func NewIter[T comparable.Comparable, N Node[T, N]](root N, reverse bool) (rv Iter[T, N]) {
+	rv.reverse = reverse
+	rv.cur = rv.pushSpine(root)
+	return
+}
+
+// pushSpine walks from `parent` to the left most node, or the right most for a reverse
+// iteration, pushing the nodes above it on the stack.  The node at the bottom is returned.
+func (iter *Iter[T, N]) pushSpine(parent N) (ptr N) {
+	var null N
+	if parent == null {
+		return null
+	}
+	for {
+		next := parent.Left()
+		if iter.reverse {
+			next = parent.Right()
+		}
+		if next == null {
+			return parent
+		}
+		iter.stk.Push(parent)
+		parent = next
+	}
+}
+
+// Value returns the current data for this element in the tree.
+func (iter *Iter[T, N]) Value() *T {
+	var null N
+	if iter.cur != null {
+		return iter.cur.GetData()
+	}
+	return nil
+}
+
+// Next advances to the next element in the tree, the previous one for a reverse iteration.
+func (iter *Iter[T, N]) Next() {
+	var null N
+	if iter.cur == null {
+		return
+	}
+	next := iter.cur.Right()
+	if iter.reverse {
+		next = iter.cur.Left()
+	}
+	if next != null {
+		iter.cur = iter.pushSpine(next)
+	} else if iter.stk.IsEmpty() {
+		iter.cur = null
+	} else {
+		iter.cur, _ = iter.stk.Pop()
+	}
+}
+
+// Done returns true if the end of the tree has been reached.
+func (iter *Iter[T, N]) Done() bool {
+	var null N
+	return iter.cur == null
+}
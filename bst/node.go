@@ -0,0 +1,18 @@
+package bst
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+// Node is a node of a tree, N is the pointer type of the node itself.  A nil N is an empty
+// sub-tree, so Size has to work on a nil node.  This is in a file of its own because the
+// ../comparable package hides the built in comparable.
+type Node[T any, N any] interface {
+	comparable
+	GetData() *T // the item in the node
+	Left() N     // the sub-tree of smaller items
+	Right() N    // the sub-tree of larger items
+	Size() int   // the number of nodes in the sub-tree, 0 for nil
+}
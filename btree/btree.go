@@ -0,0 +1,735 @@
+package btree
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+A B+tree.  This is a no-lock, not thread safe version.  It has the same methods as the AVL
+tree in ../avl_tree so they can be swapped.  Each node holds up to `degree` items or
+children in a slice, so there are far fewer nodes and pointers than in a binary tree, and a
+search touches only about log|degree(n) nodes.  All the data is in the leaves, the internal
+nodes only hold copies of keys to guide a search, and the leaves are linked in order so a
+range scan is a walk along the leaves.  This makes it a good choice for very large sets.
+
+* 	ConvertToSlice - return the data in order as a slice.										O(n)
+* 	Insert - create a new element in tree.														O(log(n))
+*		Duplicates replace the current item - Insert returns false for a duplicate.
+* 	Delete — Deletes a specified element from the tree.										O(log(n))
+* 	Index - return the Nth item in order.														O(log(n))
+* 	Rank - the number of items less than an item, its position for Index.					O(log(n))
+* 	IsEmpty — Returns true if the tree is empty													O(1)
+* 	Length — Returns number of elements in the tree.											O(1)
+* 	Search — Returns the matching element from the tree.										O(log(n))
+* 	Truncate - Delete all the nodes in tree. 													O(1)
+*	FindMin - the smallest item.																O(log|degree(n))
+*	FindMax - the largest item.																	O(log|degree(n))
+*	Depth -> int the number of levels in the tree.												O(log|degree(n))
+* 	DeleteAtHead — Deletes the smallest element of the tree.  									O(log(n))
+* 	DeleteAtTail — Deletes the largest element of the tree. 									O(log(n))
+*	WalkInOrder - Apply a function to all the items in order.									O(n)
+*	BulkLoad - replace the data in the tree with a sorted slice.								O(n)
+*	Front, Rear - iterators in order and in reverse order.
+
+*/
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	// "sync"
+
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// DefaultDegree is the degree used by NewBTree and by a zero value BTree.
+const DefaultDegree = 64
+
+// MinDegree is the smallest degree that a tree can have.
+const MinDegree = 4
+
+// BTreeNode is a leaf or an internal node.  In a leaf `items` is the data.  In an internal
+// node `items` are the separator keys, all the data in children[i] is less than items[i]
+// and all the data in children[i+1] is greater than or equal to it.
+type BTreeNode[T comparable.Comparable] struct {
+	items      []*T
+	children   []*BTreeNode[T] // nil for a leaf
+	size       int             // number of data items in this sub-tree
+	prev, next *BTreeNode[T]   // the leaves are linked in order
+}
+
+// BTree is a generic B+tree
+type BTree[T comparable.Comparable] struct {
+	root   *BTreeNode[T]
+	length int
+	degree int // max items in a leaf, max children in an internal node
+	// lock   sync.RWMutex
+}
+
+// At compile time verify that BTree can be used behind the iface_list interfaces.
+var _ iface_list.TreeDataType[comparable.Int] = (*BTree[comparable.Int])(nil)
+
+type ApplyFunction[T comparable.Comparable] func(pos, depth int, data *T, userData interface{}) bool
+
+// -------------------------------------------------------------------------------------------------------
+
+// Create a new BTree with the DefaultDegree and return it.
+// Complexity is O(1).
+func NewBTree[T comparable.Comparable]() *BTree[T] {
+	return NewBTreeDegree[T](DefaultDegree)
+}
+
+// Create a new BTree where each node holds up to `degree` items or children.  A larger
+// degree makes the tree shallower and uses the cache better but moves more data on each
+// insert and delete.  The degree must be at least MinDegree.
+// Complexity is O(1).
+func NewBTreeDegree[T comparable.Comparable](degree int) *BTree[T] {
+	if degree < MinDegree {
+		panic(fmt.Sprintf("degree must be at least %d", MinDegree))
+	}
+	return &BTree[T]{
+		degree: degree,
+	}
+}
+
+// isLeaf returns true if `nd` holds data.
+func (nd *BTreeNode[T]) isLeaf() bool {
+	return nd.children == nil
+}
+
+// nlDegree returns the degree of the tree, setting the default for a zero value tree.
+func (tt *BTree[T]) nlDegree() int {
+	if tt.degree == 0 {
+		tt.degree = DefaultDegree
+	}
+	return tt.degree
+}
+
+// minFill is the fewest items or children that a node other than the root can have.
+func (tt *BTree[T]) minFill() int {
+	return tt.nlDegree() / 2
+}
+
+// newLeaf makes a leaf with room for a split without a re-allocation.
+func (tt *BTree[T]) newLeaf() *BTreeNode[T] {
+	return &BTreeNode[T]{
+		items: make([]*T, 0, tt.nlDegree()+1),
+	}
+}
+
+// newInternal makes an internal node with room for a split without a re-allocation.
+func (tt *BTree[T]) newInternal() *BTreeNode[T] {
+	return &BTreeNode[T]{
+		items:    make([]*T, 0, tt.nlDegree()),
+		children: make([]*BTreeNode[T], 0, tt.nlDegree()+1),
+	}
+}
+
+// upperBound returns the index of the first item that is greater than `find`, in an
+// internal node this is the child that can hold `find`.
+func upperBound[T comparable.Comparable](items []*T, find *T) int {
+	lo, hi := 0, len(items)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if (*find).Compare(*items[mid]) < 0 {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
+// lowerBound returns the index of the first item that is greater than or equal to `find`.
+func lowerBound[T comparable.Comparable](items []*T, find *T) int {
+	lo, hi := 0, len(items)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if (*find).Compare(*items[mid]) <= 0 {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// IsEmpty will return true if the tree is empty
+// Complexity is O(1).
+func (tt *BTree[T]) IsEmpty() bool {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.root == nil
+}
+
+// Length returns the number of elements in the tree.
+// Complexity is O(1).
+func (tt *BTree[T]) Length() int {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.length
+}
+
+// Degree returns the most items or children that a node can hold.
+// Complexity is O(1).
+func (tt *BTree[T]) Degree() int {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.nlDegree()
+}
+
+// Truncate removes all data from the tree.
+// Complexity is O(1).
+func (tt *BTree[T]) Truncate() {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.Lock()
+	// defer tt.lock.Unlock()
+
+	tt.root = nil
+	tt.length = 0
+}
+
+// Insert will add a new item to the tree.  If it is a duplicate of an existing
+// item the new item will replace the existing one.  True is returned if the item
+// is new, false if it replaced an existing one.
+// Complexity is O(log(n)).
+func (tt *BTree[T]) Insert(item *T) (isNew bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.Lock()
+	// defer tt.lock.Unlock()
+
+	return tt.nlInsert(item)
+}
+
+func (tt *BTree[T]) nlInsert(item *T) (isNew bool) {
+	if tt.root == nil {
+		tt.root = tt.newLeaf()
+	}
+	isNew, sep, right := tt.nlInsertNode(tt.root, item)
+	if right != nil {
+		// The root split, add a new level above it.
+		left := tt.root
+		tt.root = tt.newInternal()
+		tt.root.items = append(tt.root.items, sep)
+		tt.root.children = append(tt.root.children, left, right)
+		tt.root.size = left.size + right.size
+	}
+	if isNew {
+		tt.length++
+	}
+	return
+}
+
+// nlInsertNode adds `item` to the sub-tree at `nd`.  If `nd` is over full after the insert
+// it is split and the new right half is returned with the key that separates the halves.
+func (tt *BTree[T]) nlInsertNode(nd *BTreeNode[T], item *T) (isNew bool, sep *T, right *BTreeNode[T]) {
+	if nd.isLeaf() {
+		pos := lowerBound(nd.items, item)
+		if pos < len(nd.items) && (*item).Compare(*nd.items[pos]) == 0 {
+			nd.items[pos] = item
+			return false, nil, nil
+		}
+		nd.items = insertAt(nd.items, pos, item)
+		nd.size++
+		if len(nd.items) > tt.nlDegree() {
+			sep, right = tt.splitLeaf(nd)
+		}
+		return true, sep, right
+	}
+
+	pos := upperBound(nd.items, item)
+	isNew, childSep, childRight := tt.nlInsertNode(nd.children[pos], item)
+	if isNew {
+		nd.size++
+	}
+	if childRight != nil {
+		nd.items = insertAt(nd.items, pos, childSep)
+		nd.children = insertAt(nd.children, pos+1, childRight)
+		if len(nd.children) > tt.nlDegree() {
+			sep, right = tt.splitInternal(nd)
+		}
+	}
+	return isNew, sep, right
+}
+
+// splitLeaf moves the top half of the items in `nd` to a new leaf that is linked in after it.
+func (tt *BTree[T]) splitLeaf(nd *BTreeNode[T]) (sep *T, right *BTreeNode[T]) {
+	mid := len(nd.items) / 2
+	right = tt.newLeaf()
+	right.items = append(right.items, nd.items[mid:]...)
+	clear(nd.items[mid:])
+	nd.items = nd.items[:mid]
+	nd.size, right.size = len(nd.items), len(right.items)
+
+	right.prev, right.next = nd, nd.next
+	if nd.next != nil {
+		nd.next.prev = right
+	}
+	nd.next = right
+	return right.items[0], right
+}
+
+// splitInternal moves the top half of the children in `nd` to a new node.  The middle key
+// moves up to the parent.
+func (tt *BTree[T]) splitInternal(nd *BTreeNode[T]) (sep *T, right *BTreeNode[T]) {
+	mid := len(nd.items) / 2
+	sep = nd.items[mid]
+	right = tt.newInternal()
+	right.items = append(right.items, nd.items[mid+1:]...)
+	right.children = append(right.children, nd.children[mid+1:]...)
+	clear(nd.items[mid:])
+	clear(nd.children[mid+1:])
+	nd.items = nd.items[:mid]
+	nd.children = nd.children[:mid+1]
+	for _, ch := range right.children {
+		right.size += ch.size
+	}
+	nd.size -= right.size
+	return sep, right
+}
+
+// insertAt inserts `x` at position `pos` in `s`.
+func insertAt[E any](s []E, pos int, x E) []E {
+	var zero E
+	s = append(s, zero)
+	copy(s[pos+1:], s[pos:])
+	s[pos] = x
+	return s
+}
+
+// removeAt removes the element at position `pos` from `s`.
+func removeAt[E any](s []E, pos int) []E {
+	var zero E
+	copy(s[pos:], s[pos+1:])
+	s[len(s)-1] = zero
+	return s[:len(s)-1]
+}
+
+// Delete removes the item that matches `find` from the tree.  True is returned if an item
+// was removed.
+// Complexity is O(log(n)).
+func (tt *BTree[T]) Delete(find *T) (found bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.Lock()
+	// defer tt.lock.Unlock()
+
+	return tt.nlDelete(find)
+}
+
+func (tt *BTree[T]) nlDelete(find *T) (found bool) {
+	if tt.root == nil || !tt.nlDeleteNode(tt.root, find) {
+		return false
+	}
+	tt.length--
+	if tt.root.isLeaf() {
+		if len(tt.root.items) == 0 {
+			tt.root = nil
+		}
+	} else if len(tt.root.children) == 1 {
+		// The root has only one child, remove a level.
+		tt.root = tt.root.children[0]
+	}
+	return true
+}
+
+// nlDeleteNode removes `find` from the sub-tree at `nd`.  A child that is left with too few
+// items borrows from a sibling or is merged with one.
+func (tt *BTree[T]) nlDeleteNode(nd *BTreeNode[T], find *T) (found bool) {
+	if nd.isLeaf() {
+		pos := lowerBound(nd.items, find)
+		if pos == len(nd.items) || (*find).Compare(*nd.items[pos]) != 0 {
+			return false
+		}
+		nd.items = removeAt(nd.items, pos)
+		nd.size--
+		return true
+	}
+
+	pos := upperBound(nd.items, find)
+	if !tt.nlDeleteNode(nd.children[pos], find) {
+		return false
+	}
+	nd.size--
+	tt.fixChild(nd, pos)
+	return true
+}
+
+// fill returns the number of items in a leaf or children in an internal node.
+func (nd *BTreeNode[T]) fill() int {
+	if nd.isLeaf() {
+		return len(nd.items)
+	}
+	return len(nd.children)
+}
+
+// fixChild makes children[pos] of `nd` have at least minFill items or children.
+func (tt *BTree[T]) fixChild(nd *BTreeNode[T], pos int) {
+	least := tt.minFill()
+	if nd.children[pos].fill() >= least {
+		return
+	}
+	if pos > 0 && nd.children[pos-1].fill() > least {
+		tt.borrowLeft(nd, pos)
+	} else if pos < len(nd.children)-1 && nd.children[pos+1].fill() > least {
+		tt.borrowRight(nd, pos)
+	} else if pos > 0 {
+		tt.merge(nd, pos-1)
+	} else {
+		tt.merge(nd, pos)
+	}
+}
+
+// borrowLeft moves the last item or child of children[pos-1] to the front of children[pos].
+func (tt *BTree[T]) borrowLeft(nd *BTreeNode[T], pos int) {
+	child, left := nd.children[pos], nd.children[pos-1]
+	if child.isLeaf() {
+		last := len(left.items) - 1
+		child.items = insertAt(child.items, 0, left.items[last])
+		left.items = removeAt(left.items, last)
+		child.size++
+		left.size--
+		nd.items[pos-1] = child.items[0]
+		return
+	}
+	last := len(left.children) - 1
+	moved := left.children[last]
+	child.items = insertAt(child.items, 0, nd.items[pos-1])
+	child.children = insertAt(child.children, 0, moved)
+	nd.items[pos-1] = left.items[last-1]
+	left.items = removeAt(left.items, last-1)
+	left.children = removeAt(left.children, last)
+	child.size += moved.size
+	left.size -= moved.size
+}
+
+// borrowRight moves the first item or child of children[pos+1] to the end of children[pos].
+func (tt *BTree[T]) borrowRight(nd *BTreeNode[T], pos int) {
+	child, right := nd.children[pos], nd.children[pos+1]
+	if child.isLeaf() {
+		child.items = append(child.items, right.items[0])
+		right.items = removeAt(right.items, 0)
+		child.size++
+		right.size--
+		nd.items[pos] = right.items[0]
+		return
+	}
+	moved := right.children[0]
+	child.items = append(child.items, nd.items[pos])
+	child.children = append(child.children, moved)
+	nd.items[pos] = right.items[0]
+	right.items = removeAt(right.items, 0)
+	right.children = removeAt(right.children, 0)
+	child.size += moved.size
+	right.size -= moved.size
+}
+
+// merge moves everything in children[pos+1] in to children[pos] and removes children[pos+1].
+func (tt *BTree[T]) merge(nd *BTreeNode[T], pos int) {
+	left, right := nd.children[pos], nd.children[pos+1]
+	if left.isLeaf() {
+		left.items = append(left.items, right.items...)
+		left.next = right.next
+		if right.next != nil {
+			right.next.prev = left
+		}
+	} else {
+		left.items = append(left.items, nd.items[pos])
+		left.items = append(left.items, right.items...)
+		left.children = append(left.children, right.children...)
+	}
+	left.size += right.size
+	nd.items = removeAt(nd.items, pos)
+	nd.children = removeAt(nd.children, pos+1)
+}
+
+// DeleteAtHead removes the smallest item in the tree.
+// Complexity is O(log(n)).
+func (tt *BTree[T]) DeleteAtHead() (found bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.Lock()
+	// defer tt.lock.Unlock()
+
+	if tt.root == nil {
+		return false
+	}
+	return tt.nlDelete(tt.nlFindMin())
+}
+
+// DeleteAtTail removes the largest item in the tree.
+// Complexity is O(log(n)).
+func (tt *BTree[T]) DeleteAtTail() (found bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.Lock()
+	// defer tt.lock.Unlock()
+
+	if tt.root == nil {
+		return false
+	}
+	return tt.nlDelete(tt.nlFindMax())
+}
+
+// Search will walk the tree looking for `find` and retrn the found item
+// if it is in the tree. If it is not found then `nil` will be returned.
+// Complexity is O(log(n)).
+func (tt *BTree[T]) Search(find *T) (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	if tt.root == nil {
+		return nil
+	}
+	leaf := tt.nlFindLeaf(find)
+	if pos := lowerBound(leaf.items, find); pos < len(leaf.items) && (*find).Compare(*leaf.items[pos]) == 0 {
+		return leaf.items[pos]
+	}
+	return nil
+}
+
+// nlFindLeaf returns the leaf that would hold `find`.  The tree must not be empty.
+func (tt *BTree[T]) nlFindLeaf(find *T) *BTreeNode[T] {
+	nd := tt.root
+	for !nd.isLeaf() {
+		nd = nd.children[upperBound(nd.items, find)]
+	}
+	return nd
+}
+
+// nlFirstLeaf returns the left most leaf, nil if the tree is empty.
+func (tt *BTree[T]) nlFirstLeaf() *BTreeNode[T] {
+	nd := tt.root
+	for nd != nil && !nd.isLeaf() {
+		nd = nd.children[0]
+	}
+	return nd
+}
+
+// nlLastLeaf returns the right most leaf, nil if the tree is empty.
+func (tt *BTree[T]) nlLastLeaf() *BTreeNode[T] {
+	nd := tt.root
+	for nd != nil && !nd.isLeaf() {
+		nd = nd.children[len(nd.children)-1]
+	}
+	return nd
+}
+
+// FindMin returns the smallest item in the tree, nil if the tree is empty.
+// Complexity is O(log|degree(n)).
+func (tt *BTree[T]) FindMin() (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.nlFindMin()
+}
+
+func (tt *BTree[T]) nlFindMin() (item *T) {
+	if leaf := tt.nlFirstLeaf(); leaf != nil {
+		return leaf.items[0]
+	}
+	return nil
+}
+
+// FindMax returns the largest item in the tree, nil if the tree is empty.
+// Complexity is O(log|degree(n)).
+func (tt *BTree[T]) FindMax() (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.nlFindMax()
+}
+
+func (tt *BTree[T]) nlFindMax() (item *T) {
+	if leaf := tt.nlLastLeaf(); leaf != nil {
+		return leaf.items[len(leaf.items)-1]
+	}
+	return nil
+}
+
+// Index returns the N-th item in the tree, 0 based, in order.  It uses the sub-tree sizes
+// to go directly down to the item.
+// Complexity is O(log(n)).
+func (tt *BTree[T]) Index(pos int) (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	if pos < 0 || pos >= tt.length {
+		return nil
+	}
+	nd := tt.root
+	for !nd.isLeaf() {
+		for _, ch := range nd.children {
+			if pos < ch.size {
+				nd = ch
+				break
+			}
+			pos -= ch.size // skip this sub-tree
+		}
+	}
+	return nd.items[pos]
+}
+
+// Rank returns the number of items in the tree that are less than `find`.  If `find` is in
+// the tree this is its position as used by Index.
+// Complexity is O(log(n)).
+func (tt *BTree[T]) Rank(find *T) (n int) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	nd := tt.root
+	if nd == nil {
+		return 0
+	}
+	for !nd.isLeaf() {
+		pos := upperBound(nd.items, find)
+		for _, ch := range nd.children[:pos] {
+			n += ch.size
+		}
+		nd = nd.children[pos]
+	}
+	return n + lowerBound(nd.items, find)
+}
+
+// Depth returns the number of levels in the tree, every leaf is at the same depth.
+// Complexity is O(log|degree(n)).
+func (tt *BTree[T]) Depth() (d int) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.nlDepth()
+}
+
+func (tt *BTree[T]) nlDepth() (d int) {
+	for nd := tt.root; nd != nil; d++ {
+		if nd.isLeaf() {
+			nd = nil
+		} else {
+			nd = nd.children[0]
+		}
+	}
+	return
+}
+
+// ConvertToSlice returns the data in the tree in order.
+// Complexity is O(n).
+func (tt *BTree[T]) ConvertToSlice() (rv []*T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	rv = make([]*T, 0, tt.length)
+	for leaf := tt.nlFirstLeaf(); leaf != nil; leaf = leaf.next {
+		rv = append(rv, leaf.items...)
+	}
+	return
+}
+
+// Dump will print out the tree to the file `fo`, one node per line indented by its depth.
+func (tt *BTree[T]) Dump(fo io.Writer) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	var dump func(nd *BTreeNode[T], depth int)
+	dump = func(nd *BTreeNode[T], depth int) {
+		var keys []string
+		for _, item := range nd.items {
+			keys = append(keys, fmt.Sprintf("%v", *item))
+		}
+		fmt.Fprintf(fo, "%s[%s] size=%d\n", strings.Repeat(" ", 4*depth), strings.Join(keys, " "), nd.size)
+		for _, ch := range nd.children {
+			dump(ch, depth+1)
+		}
+	}
+	if tt.root != nil {
+		dump(tt.root, 0)
+	}
+}
+
+// WalkInOrder calls `fx` for each item in order, `depth` is the depth of the leaves.  The
+// walk stops if `fx` returns false.
+// Complexity is O(n).
+func (tt *BTree[T]) WalkInOrder(fx ApplyFunction[T], userData interface{}) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	p := 0
+	depth := tt.nlDepth() - 1
+	for leaf := tt.nlFirstLeaf(); leaf != nil; leaf = leaf.next {
+		for _, item := range leaf.items {
+			if !fx(p, depth, item, userData) {
+				return
+			}
+			p++
+		}
+	}
+}
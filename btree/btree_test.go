@@ -0,0 +1,326 @@
+package btree
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/pschlump/pluto/comparable"
+)
+
+// TestTreeNode is an Inteface Matcing data type for the Nodes that supports the Comparable
+// interface.  This means that it has a Compare fucntion.
+type TestTreeNode struct {
+	S string
+}
+
+// At compile time verify that this is a correct type/interface setup.
+var _ comparable.Comparable = (*TestTreeNode)(nil)
+
+// Compare implements the Compare function to satisfy the interface requirements.
+func (aa TestTreeNode) Compare(x comparable.Comparable) int {
+	if bb, ok := x.(TestTreeNode); ok {
+		if aa.S < bb.S {
+			return -1
+		} else if aa.S > bb.S {
+			return 1
+		}
+	} else if bb, ok := x.(*TestTreeNode); ok {
+		if aa.S < bb.S {
+			return -1
+		} else if aa.S > bb.S {
+			return 1
+		}
+	} else {
+		panic(fmt.Sprintf("Passed invalid type %T to a Compare function.", x))
+	}
+	return 0
+}
+
+func key(k int) *TestTreeNode {
+	return &TestTreeNode{S: fmt.Sprintf("%04d", k)}
+}
+
+// validate checks that every leaf is at the same depth, that every node other than the root
+// is at least half full and not over full, that the sizes and keys are right and that the
+// leaves are linked in order.
+func validate(t *testing.T, tt *BTree[TestTreeNode]) {
+	t.Helper()
+	if tt.root == nil {
+		if tt.length != 0 {
+			t.Errorf("Empty tree has length %d", tt.length)
+		}
+		return
+	}
+	degree, least := tt.Degree(), tt.Degree()/2
+	leafDepth := -1
+	var leaves []*BTreeNode[TestTreeNode]
+	var check func(nd *BTreeNode[TestTreeNode], depth int, lo, hi *TestTreeNode) (size int)
+	check = func(nd *BTreeNode[TestTreeNode], depth int, lo, hi *TestTreeNode) (size int) {
+		if nd != tt.root && (nd.fill() < least || nd.fill() > degree) {
+			t.Errorf("Node at depth %d has fill %d, degree %d", depth, nd.fill(), degree)
+		}
+		for ii, item := range nd.items {
+			if (lo != nil && lo.S > item.S) || (hi != nil && hi.S <= item.S) || (ii > 0 && nd.items[ii-1].S >= item.S) {
+				t.Errorf("Item %s is out of order", item.S)
+			}
+		}
+		if nd.isLeaf() {
+			if leafDepth == -1 {
+				leafDepth = depth
+			} else if leafDepth != depth {
+				t.Errorf("Leaves at depth %d and %d", leafDepth, depth)
+			}
+			leaves = append(leaves, nd)
+			size = len(nd.items)
+		} else {
+			if len(nd.children) != len(nd.items)+1 {
+				t.Errorf("Node has %d keys and %d children", len(nd.items), len(nd.children))
+			}
+			for ii, ch := range nd.children {
+				clo, chi := lo, hi
+				if ii > 0 {
+					clo = nd.items[ii-1]
+				}
+				if ii < len(nd.items) {
+					chi = nd.items[ii]
+				}
+				size += check(ch, depth+1, clo, chi)
+			}
+		}
+		if nd.size != size {
+			t.Errorf("Node at depth %d expected size %d got %d", depth, size, nd.size)
+		}
+		return
+	}
+	if size := check(tt.root, 0, nil, nil); size != tt.length {
+		t.Errorf("Expected length %d got %d", size, tt.length)
+	}
+	if leafDepth+1 != tt.Depth() {
+		t.Errorf("Expected depth %d got %d", leafDepth+1, tt.Depth())
+	}
+	for ii, leaf := range leaves {
+		var prev, next *BTreeNode[TestTreeNode]
+		if ii > 0 {
+			prev = leaves[ii-1]
+		}
+		if ii < len(leaves)-1 {
+			next = leaves[ii+1]
+		}
+		if leaf.prev != prev || leaf.next != next {
+			t.Errorf("Leaf %d is not linked to its neighbours", ii)
+		}
+	}
+}
+
+func TestTreeInsertSearch(t *testing.T) {
+	var Tree1 BTree[TestTreeNode]
+
+	if !Tree1.IsEmpty() || Tree1.Search(key(5)) != nil || Tree1.FindMin() != nil || Tree1.Depth() != 0 {
+		t.Errorf("Expected empty tree")
+	}
+	if Tree1.Degree() != DefaultDegree {
+		t.Errorf("Expected the zero value to have degree %d got %d", DefaultDegree, Tree1.Degree())
+	}
+	for _, k := range []int{5, 2, 9, 0, 3} {
+		if !Tree1.Insert(key(k)) {
+			t.Errorf("Expected %d to be new", k)
+		}
+	}
+	dup := key(9)
+	if Tree1.Insert(dup) {
+		t.Errorf("Expected 9 to be a duplicate")
+	}
+	if Tree1.Length() != 5 {
+		t.Errorf("Expected length 5 got %d", Tree1.Length())
+	}
+	if x := Tree1.Search(key(9)); x != dup {
+		t.Errorf("Expected the duplicate to replace the item")
+	}
+	if x := Tree1.Search(key(4)); x != nil {
+		t.Errorf("Expected nil got %v", x)
+	}
+	if x := Tree1.FindMin(); x.S != "0000" {
+		t.Errorf("FindMin expcted 0000 got %s", x.S)
+	}
+	if x := Tree1.FindMax(); x.S != "0009" {
+		t.Errorf("FindMax expcted 0009 got %s", x.S)
+	}
+
+	var got []string
+	Tree1.WalkInOrder(func(pos, depth int, data *TestTreeNode, userData interface{}) bool {
+		got = append(got, data.S)
+		return pos < 2
+	}, nil)
+	if fmt.Sprint(got) != "[0000 0002 0003]" {
+		t.Errorf("WalkInOrder expcted [0000 0002 0003] got %v", got)
+	}
+
+	var buf bytes.Buffer
+	Tree1.Dump(&buf)
+	if buf.String() != "[{0000} {0002} {0003} {0005} {0009}] size=5\n" {
+		t.Errorf("Dump got %q", buf.String())
+	}
+
+	if !Tree1.DeleteAtHead() || !Tree1.DeleteAtTail() {
+		t.Errorf("Expected DeleteAtHead and DeleteAtTail to remove an item")
+	}
+	if Tree1.Length() != 3 || Tree1.FindMin().S != "0002" || Tree1.FindMax().S != "0005" {
+		t.Errorf("Expected 0002 0003 0005 got %v", Tree1.ConvertToSlice())
+	}
+	Tree1.Truncate()
+	if !Tree1.IsEmpty() || Tree1.DeleteAtHead() || Tree1.Depth() != 0 {
+		t.Errorf("Expected empty tree after Truncate")
+	}
+}
+
+func TestTreeDegree(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for a degree below MinDegree")
+		}
+	}()
+	NewBTreeDegree[TestTreeNode](MinDegree - 1)
+}
+
+func TestTreeModel(t *testing.T) {
+	for _, degree := range []int{4, 5, 7, 16} {
+		Tree1 := NewBTreeDegree[TestTreeNode](degree)
+		model := make(map[int]bool)
+		rnd := rand.New(rand.NewSource(1001))
+
+		for ii := 0; ii < 5000; ii++ {
+			k := rnd.Intn(500)
+			if rnd.Intn(5) < 2 {
+				if found := Tree1.Delete(key(k)); found != model[k] {
+					t.Fatalf("Degree %d step %d, Delete(%d) expected %v", degree, ii, k, model[k])
+				}
+				delete(model, k)
+			} else {
+				if isNew := Tree1.Insert(key(k)); isNew == model[k] {
+					t.Fatalf("Degree %d step %d, Insert(%d) expected isNew %v", degree, ii, k, !model[k])
+				}
+				model[k] = true
+			}
+			if ii%100 == 0 {
+				validate(t, Tree1)
+			}
+		}
+		validate(t, Tree1)
+
+		keys := make([]int, 0, len(model))
+		for k := range model {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+		if Tree1.Length() != len(keys) {
+			t.Errorf("Degree %d expected length %d got %d", degree, len(keys), Tree1.Length())
+		}
+		for pos, k := range keys {
+			if x := Tree1.Index(pos); x == nil || x.S != key(k).S {
+				t.Errorf("Degree %d Index(%d) expcted %04d got %v", degree, pos, k, x)
+			}
+			if r := Tree1.Rank(key(k)); r != pos {
+				t.Errorf("Degree %d Rank(%d) expcted %d got %d", degree, k, pos, r)
+			}
+		}
+		if Tree1.Index(-1) != nil || Tree1.Index(len(keys)) != nil {
+			t.Errorf("Degree %d expected nil for an Index out of range", degree)
+		}
+
+		// Deleting everything leaves an empty, valid tree.
+		for _, k := range keys {
+			if !Tree1.Delete(key(k)) {
+				t.Errorf("Degree %d expected to delete %d", degree, k)
+			}
+		}
+		validate(t, Tree1)
+		if !Tree1.IsEmpty() || Tree1.Length() != 0 {
+			t.Errorf("Degree %d expected empty tree", degree)
+		}
+	}
+}
+
+func TestTreeRange(t *testing.T) {
+	Tree1 := NewBTreeDegree[TestTreeNode](4)
+	for k := 0; k < 100; k += 2 {
+		Tree1.Insert(key(k))
+	}
+
+	tests := []struct {
+		find                          int
+		floor, ceiling, lower, higher string
+	}{
+		{find: 10, floor: "0010", ceiling: "0010", lower: "0008", higher: "0012"},
+		{find: 11, floor: "0010", ceiling: "0012", lower: "0010", higher: "0012"},
+		{find: -1, floor: "", ceiling: "0000", lower: "", higher: "0000"},
+		{find: 0, floor: "0000", ceiling: "0000", lower: "", higher: "0002"},
+		{find: 98, floor: "0098", ceiling: "0098", lower: "0096", higher: ""},
+		{find: 99, floor: "0098", ceiling: "", lower: "0098", higher: ""},
+	}
+	str := func(x *TestTreeNode) string {
+		if x == nil {
+			return ""
+		}
+		return x.S
+	}
+	for _, tc := range tests {
+		find := key(tc.find)
+		if got := str(Tree1.Floor(find)); got != tc.floor {
+			t.Errorf("Floor(%d) expcted %s got %s", tc.find, tc.floor, got)
+		}
+		if got := str(Tree1.Ceiling(find)); got != tc.ceiling {
+			t.Errorf("Ceiling(%d) expcted %s got %s", tc.find, tc.ceiling, got)
+		}
+		if got := str(Tree1.Lower(find)); got != tc.lower {
+			t.Errorf("Lower(%d) expcted %s got %s", tc.find, tc.lower, got)
+		}
+		if got := str(Tree1.Higher(find)); got != tc.higher {
+			t.Errorf("Higher(%d) expcted %s got %s", tc.find, tc.higher, got)
+		}
+	}
+
+	var got []string
+	for item := range Tree1.Range(key(15), key(25)) {
+		got = append(got, item.S)
+	}
+	if fmt.Sprint(got) != "[0016 0018 0020 0022 0024]" {
+		t.Errorf("Range(15,25) got %v", got)
+	}
+
+	got = got[:0]
+	Tree1.RangeWalk(nil, key(4), func(item *TestTreeNode) bool {
+		got = append(got, item.S)
+		return true
+	})
+	if fmt.Sprint(got) != "[0000 0002 0004]" {
+		t.Errorf("RangeWalk(nil,4) got %v", got)
+	}
+
+	n := 0
+	for range Tree1.All() {
+		n++
+		if n == 10 {
+			break
+		}
+	}
+	if n != 10 {
+		t.Errorf("Expected All to stop after 10 got %d", n)
+	}
+
+	var empty BTree[TestTreeNode]
+	if empty.Floor(key(1)) != nil || empty.Ceiling(key(1)) != nil {
+		t.Errorf("Expected nil from an empty tree")
+	}
+	for range empty.All() {
+		t.Errorf("Expected no items from an empty tree")
+	}
+}
@@ -0,0 +1,81 @@
+package btree
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"errors"
+)
+
+var ErrNotInOrder = errors.New("Items to Load are not in Order")
+
+// BulkLoad replaces the data in the tree with `data`, which must be sorted with no
+// duplicates.  The tree is built bottom up, a level at a time, with every node as full as
+// it can be while still leaving each one at least half full.  This is much faster than
+// calling Insert for each item and gives a tree with the fewest nodes.  If `data` is not in
+// order then ErrNotInOrder is returned and the tree is not changed.
+// Complexity is O(n).
+func (tt *BTree[T]) BulkLoad(data []*T) error {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.Lock()
+	// defer tt.lock.Unlock()
+
+	for ii := 1; ii < len(data); ii++ {
+		if (*data[ii-1]).Compare(*data[ii]) >= 0 {
+			return ErrNotInOrder
+		}
+	}
+
+	tt.root = nil
+	tt.length = len(data)
+	if len(data) == 0 {
+		return nil
+	}
+
+	// Build the leaves, and keep the smallest item under each node to use as the key for it
+	// in the level above.
+	degree := tt.nlDegree()
+	nLeaves := (len(data) + degree - 1) / degree
+	level := make([]*BTreeNode[T], 0, nLeaves)
+	mins := make([]*T, 0, nLeaves)
+	var prev *BTreeNode[T]
+	for ii := 0; ii < nLeaves; ii++ {
+		leaf := tt.newLeaf()
+		leaf.items = append(leaf.items, data[ii*len(data)/nLeaves:(ii+1)*len(data)/nLeaves]...)
+		leaf.size = len(leaf.items)
+		leaf.prev = prev
+		if prev != nil {
+			prev.next = leaf
+		}
+		prev = leaf
+		level = append(level, leaf)
+		mins = append(mins, leaf.items[0])
+	}
+
+	// Build each level above from the one below until there is only the root.
+	for len(level) > 1 {
+		nNodes := (len(level) + degree - 1) / degree
+		upper := make([]*BTreeNode[T], 0, nNodes)
+		upperMins := make([]*T, 0, nNodes)
+		for ii := 0; ii < nNodes; ii++ {
+			start, end := ii*len(level)/nNodes, (ii+1)*len(level)/nNodes
+			nd := tt.newInternal()
+			nd.children = append(nd.children, level[start:end]...)
+			nd.items = append(nd.items, mins[start+1:end]...)
+			for _, ch := range nd.children {
+				nd.size += ch.size
+			}
+			upper = append(upper, nd)
+			upperMins = append(upperMins, mins[start])
+		}
+		level, mins = upper, upperMins
+	}
+	tt.root = level[0]
+	return nil
+}
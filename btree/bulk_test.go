@@ -0,0 +1,61 @@
+package btree
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"testing"
+)
+
+func TestBulkLoad(t *testing.T) {
+	for _, degree := range []int{4, 5, 16} {
+		for _, n := range []int{0, 1, degree - 1, degree, degree + 1, 2*degree + 1, 1000} {
+			data := make([]*TestTreeNode, n)
+			for ii := range data {
+				data[ii] = key(ii * 2)
+			}
+
+			Tree1 := NewBTreeDegree[TestTreeNode](degree)
+			Tree1.Insert(key(1)) // replaced by the load
+			if err := Tree1.BulkLoad(data); err != nil {
+				t.Fatalf("Degree %d n %d, unexpected error %s", degree, n, err)
+			}
+			validate(t, Tree1)
+			if Tree1.Length() != n {
+				t.Errorf("Degree %d n %d, expected length %d got %d", degree, n, n, Tree1.Length())
+			}
+			for ii, item := range Tree1.ConvertToSlice() {
+				if item != data[ii] {
+					t.Errorf("Degree %d n %d, at %d expcted %s got %s", degree, n, ii, data[ii].S, item.S)
+				}
+			}
+
+			// The loaded tree can be changed like any other.
+			for ii := 0; ii < n; ii += 3 {
+				Tree1.Delete(data[ii])
+				Tree1.Insert(key(ii*2 + 1))
+			}
+			validate(t, Tree1)
+		}
+	}
+}
+
+func TestBulkLoadNotInOrder(t *testing.T) {
+	Tree1 := NewBTreeDegree[TestTreeNode](4)
+	Tree1.Insert(key(1))
+
+	for _, data := range [][]*TestTreeNode{
+		{key(1), key(3), key(2)},
+		{key(1), key(2), key(2)},
+	} {
+		if err := Tree1.BulkLoad(data); err != ErrNotInOrder {
+			t.Errorf("Expected ErrNotInOrder got %v", err)
+		}
+		if Tree1.Length() != 1 || Tree1.Search(key(1)) == nil {
+			t.Errorf("Expected the tree to be unchanged")
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package btree
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"github.com/pschlump/pluto/comparable"
+)
+
+// An iteration type that allows a for loop to walk the tree inorder, or
+// in reverse order from Rear.
+//
+//	for it := tree.Front(); !it.Done(); it.Next() {
+//		item := it.Value()
+//		...
+//	}
+//
+// The iterator follows the links between the leaves so it needs no stack.
+// The tree must not be modified while an iterator is in use.
+type BTreeIter[T comparable.Comparable] struct {
+	leaf    *BTreeNode[T] // The current leaf, nil at the end.
+	pos     int           // Position of the current item in the leaf.
+	tree    *BTree[T]     // The tree
+	reverse bool          // true if started from Rear, Next moves to smaller items
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// Front will start at the inorder traversal beginning of the tree for iteration over tree.
+func (tt *BTree[T]) Front() (rv *BTreeIter[T]) {
+	return &BTreeIter[T]{
+		leaf: tt.nlFirstLeaf(),
+		tree: tt,
+	}
+}
+
+// Rear will start at the last inorder item of the tree for iteration over the tree in
+// reverse order.
+func (tt *BTree[T]) Rear() (rv *BTreeIter[T]) {
+	rv = &BTreeIter[T]{
+		leaf:    tt.nlLastLeaf(),
+		tree:    tt,
+		reverse: true,
+	}
+	if rv.leaf != nil {
+		rv.pos = len(rv.leaf.items) - 1
+	}
+	return
+}
+
+// Value returns the current data for this element in the tree.
+func (iter *BTreeIter[T]) Value() *T {
+	if iter.leaf != nil {
+		return iter.leaf.items[iter.pos]
+	}
+	return nil
+}
+
+// Next advances to the next element in the tree, the previous one for an iterator from Rear.
+func (iter *BTreeIter[T]) Next() {
+	if iter.leaf == nil {
+		return
+	}
+	if iter.reverse {
+		iter.pos--
+		if iter.pos < 0 {
+			iter.leaf = iter.leaf.prev
+			if iter.leaf != nil {
+				iter.pos = len(iter.leaf.items) - 1
+			}
+		}
+		return
+	}
+	iter.pos++
+	if iter.pos >= len(iter.leaf.items) {
+		iter.leaf, iter.pos = iter.leaf.next, 0
+	}
+}
+
+// Done returns true if the end of the tree has been reached.
+func (iter *BTreeIter[T]) Done() bool {
+	return iter.leaf == nil
+}
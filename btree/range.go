@@ -0,0 +1,178 @@
+package btree
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+Range queries on the tree.  These go down to one leaf and then follow the links between the
+leaves, so a scan reads the items in the order they are stored.
+
+* 	Floor — the largest item that is less than or equal to an item.							O(log(n))
+* 	Ceiling — the smallest item that is greater than or equal to an item.						O(log(n))
+* 	Lower — the largest item that is strictly less than an item.								O(log(n))
+* 	Higher — the smallest item that is strictly greater than an item.							O(log(n))
+* 	RangeWalk — call a function in order for each item from lo to hi.							O(log(n)+k)
+* 	Range — an iter.Seq over the items from lo to hi.											O(log(n)+k)
+* 	All — an iter.Seq over all the items.														O(n)
+
+k is the number of items in the range.
+
+*/
+
+import (
+	"iter"
+)
+
+// Floor returns the largest item in the tree that is less than or equal to `find`.
+// If there is no such item then nil is returned.
+func (tt *BTree[T]) Floor(find *T) (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.nlBelow(find, true)
+}
+
+// Ceiling returns the smallest item in the tree that is greater than or equal to `find`.
+// If there is no such item then nil is returned.
+func (tt *BTree[T]) Ceiling(find *T) (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.nlAbove(find, true)
+}
+
+// Lower returns the largest item in the tree that is strictly less than `find`.
+// If there is no such item then nil is returned.
+func (tt *BTree[T]) Lower(find *T) (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.nlBelow(find, false)
+}
+
+// Higher returns the smallest item in the tree that is strictly greater than `find`.
+// If there is no such item then nil is returned.
+func (tt *BTree[T]) Higher(find *T) (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.nlAbove(find, false)
+}
+
+// RangeWalk calls `fx` in order for each item from `lo` to `hi`, both included.  A nil `lo`
+// or `hi` leaves that end of the range open.  The walk stops if `fx` returns false.
+func (tt *BTree[T]) RangeWalk(lo, hi *T, fx func(item *T) bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	tt.nlRange(lo, hi, fx)
+}
+
+// Range returns an iterator over the items from `lo` to `hi`, both included, in order.  A nil
+// `lo` or `hi` leaves that end of the range open.
+//
+//	for item := range tree.Range(&lo, &hi) {
+//		...
+//	}
+func (tt *BTree[T]) Range(lo, hi *T) iter.Seq[*T] {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	return func(yield func(*T) bool) {
+		tt.RangeWalk(lo, hi, yield)
+	}
+}
+
+// All returns an iterator over all the items in the tree in order.
+func (tt *BTree[T]) All() iter.Seq[*T] {
+	return tt.Range(nil, nil)
+}
+
+// nlSeek returns the leaf and position of the first item that is greater than `find`, or
+// equal to it if `orEqual` is true.  The position may be the end of the leaf, then the item
+// is the first one in the next leaf.
+func (tt *BTree[T]) nlSeek(find *T, orEqual bool) (leaf *BTreeNode[T], pos int) {
+	leaf = tt.nlFindLeaf(find)
+	if orEqual {
+		return leaf, lowerBound(leaf.items, find)
+	}
+	return leaf, upperBound(leaf.items, find)
+}
+
+// nlBelow returns the largest item that is less than `find`, or equal to it if `orEqual` is
+// true.  Nil is returned if there is none.
+func (tt *BTree[T]) nlBelow(find *T, orEqual bool) (item *T) {
+	if tt.root == nil {
+		return nil
+	}
+	leaf, pos := tt.nlSeek(find, !orEqual)
+	if pos > 0 {
+		return leaf.items[pos-1]
+	}
+	if leaf.prev != nil {
+		return leaf.prev.items[len(leaf.prev.items)-1]
+	}
+	return nil
+}
+
+// nlAbove returns the smallest item that is greater than `find`, or equal to it if `orEqual`
+// is true.  Nil is returned if there is none.
+func (tt *BTree[T]) nlAbove(find *T, orEqual bool) (item *T) {
+	if tt.root == nil {
+		return nil
+	}
+	leaf, pos := tt.nlSeek(find, orEqual)
+	if pos < len(leaf.items) {
+		return leaf.items[pos]
+	}
+	if leaf.next != nil {
+		return leaf.next.items[0]
+	}
+	return nil
+}
+
+// nlRange calls `fx` in order for each item from `lo` to `hi` without locking.  It goes down
+// to the leaf that holds `lo` and then walks along the leaves until it passes `hi`.
+func (tt *BTree[T]) nlRange(lo, hi *T, fx func(item *T) bool) {
+	if tt.root == nil {
+		return
+	}
+	leaf, pos := tt.nlFirstLeaf(), 0
+	if lo != nil {
+		leaf, pos = tt.nlSeek(lo, true)
+	}
+	for ; leaf != nil; leaf, pos = leaf.next, 0 {
+		for _, item := range leaf.items[pos:] {
+			if hi != nil && (*hi).Compare(*item) < 0 {
+				return
+			}
+			if !fx(item) {
+				return
+			}
+		}
+	}
+}
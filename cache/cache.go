@@ -0,0 +1,243 @@
+package cache
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+Caches from keys to values with a limit on the number of items and/or on the total cost of
+the items.  When a Put goes over a limit, items are evicted to make room.
+
+* 	LRU - evicts the least recently used item.
+* 	LFU - evicts the least frequently used item, and of those the least recently used.
+
+Both have the same methods:
+
+* 	Get - returns the value for a key and marks it as used, counts a hit or a miss.				O(1)
+* 	Peek - returns the value for a key without marking it as used or counting it.				O(1)
+* 	Put - sets the value for a key, evicting other items if a limit is passed.					O(1) per item evicted
+* 	Delete - removes a key, returns true if it was in the cache.								O(1)
+* 	Length - the number of items in the cache.													O(1)
+* 	TotalCost - the sum of the costs of the items in the cache.									O(1)
+* 	Stats - the counts of hits, misses, evictions and expirations.								O(1)
+* 	RemoveExpired - remove all the items that are past their TTL.								O(n)
+* 	Truncate - remove all the items.  The stats are kept.										O(1)
+
+The items are kept in a dll (../dll) in the order they will be evicted, with a ../hash_tab_dll
+of the same items, by key, as the index.  Each item has a pointer to its list element so it can
+be moved or removed in O(1) once the index has found it.  The hash table grows with the number
+of items, so a lookup is O(1).  See ../cache_ts for a thread safe version built on ../dll_ts.
+
+The keys are hashed with the Hasher in the Config.  Without one a string key is hashed with
+hash/maphash and any other key is hashed as the string from fmt.Sprint, so two keys that are ==
+have to print the same.  That is not true of the float 0 and -0, set a Hasher for float keys.
+
+With a TTL an item expires that long after it was last Put.  An expired item is removed when
+it is found by Get, or by RemoveExpired, so it can still count against the limits until then.
+
+*/
+
+import (
+	"fmt"
+	"time"
+
+	// The package is renamed so that it does not hide the comparable constraint used for K.
+	pcomparable "github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/dll"
+	hash_tab_dll "github.com/pschlump/pluto/hash_tab_dll"
+	"github.com/pschlump/pluto/hashing"
+)
+
+// EvictReason is passed to the OnEvict function to say why an item left the cache.
+type EvictReason int
+
+const (
+	EvictCapacity EvictReason = iota // removed to make room, over MaxItems or MaxCost
+	EvictExpired                     // past its TTL
+	EvictRemoved                     // removed by Delete
+)
+
+func (rr EvictReason) String() string {
+	switch rr {
+	case EvictCapacity:
+		return "Capacity"
+	case EvictExpired:
+		return "Expired"
+	case EvictRemoved:
+		return "Removed"
+	}
+	return "Unknown"
+}
+
+// Config sets the limits and options for a cache.  A zero value for a limit is no limit.
+type Config[K comparable, V any] struct {
+	MaxItems int                                      // the most items in the cache
+	MaxCost  int64                                    // the most total cost of the items in the cache
+	Cost     func(key K, value V) int64               // the cost of an item, if nil each item costs 1
+	TTL      time.Duration                            // how long an item lives after a Put
+	OnEvict  func(key K, value V, reason EvictReason) // called when an item leaves the cache, not on Truncate
+	Now      func() time.Time                         // the clock for the TTL, if nil time.Now
+	Hasher   hashing.Hasher[K]                        // the hash of a key for the index, if nil see the package doc
+}
+
+// Stats are the counts for a cache since it was created.
+type Stats struct {
+	Hits        uint64 // Get found the key
+	Misses      uint64 // Get did not find the key, or found it expired
+	Evictions   uint64 // items removed to make room
+	Expirations uint64 // items removed because they were past their TTL
+}
+
+// HitRate returns the proportion of Get calls that were hits, 0 if there were none.
+func (ss Stats) HitRate() float64 {
+	if ss.Hits+ss.Misses == 0 {
+		return 0
+	}
+	return float64(ss.Hits) / float64(ss.Hits+ss.Misses)
+}
+
+// entry is the item kept in the list.
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	cost    int64
+	expires time.Time                    // zero if there is no TTL
+	freq    int                          // the number of uses, for the LFU
+	el      *dll.DllElement[entry[K, V]] // the element of this item in the list it is in
+}
+
+// At compile time verify that entry can be kept in a dll.
+var _ pcomparable.Equality = (*entry[int, int])(nil)
+
+// IsEqual compares the keys, so that an entry can be kept in a dll and in the index.
+func (ee entry[K, V]) IsEqual(x pcomparable.Equality) bool {
+	switch bb := x.(type) {
+	case entry[K, V]:
+		return ee.key == bb.key
+	case *entry[K, V]:
+		return ee.key == bb.key
+	}
+	return false
+}
+
+// core is the config, the index, the total cost and the stats, shared by LRU and LFU.
+type core[K comparable, V any] struct {
+	cfg   Config[K, V]
+	items *hash_tab_dll.HashTab[entry[K, V]] // the items by key
+	cost  int64
+	stats Stats
+}
+
+// nIndexBuckets is the number of buckets the index starts with, it grows as items are added.
+const nIndexBuckets = 16
+
+func newCore[K comparable, V any](cfg Config[K, V]) core[K, V] {
+	if cfg.MaxItems < 0 || cfg.MaxCost < 0 {
+		panic("cache limits can not be negative")
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if cfg.Hasher == nil {
+		cfg.Hasher = KeyHasher[K]()
+	}
+	cc := core[K, V]{cfg: cfg}
+	cc.items = cc.newIndex()
+	return cc
+}
+
+// newIndex returns an empty index that hashes the items by key.
+func (cc *core[K, V]) newIndex() *hash_tab_dll.HashTab[entry[K, V]] {
+	hh := cc.cfg.Hasher
+	byKey := hashing.HasherFunc[entry[K, V]](func(ee entry[K, V]) uint64 { return hh.Hash(ee.key) })
+	return hash_tab_dll.NewHashTab[entry[K, V]](nIndexBuckets, hashing.WithHasher[entry[K, V]](byKey))
+}
+
+// KeyHasher returns the Hasher used when the Config does not have one.  A string is hashed
+// with hash/maphash, any other key is hashed as the string from fmt.Sprint.  Each call makes a
+// new random seed.
+// Complexity is O(1).
+func KeyHasher[K comparable]() hashing.Hasher[K] {
+	mh := hashing.NewMapHash[string]()
+	return hashing.HasherFunc[K](func(key K) uint64 {
+		if ss, ok := any(key).(string); ok {
+			return mh.Hash(ss)
+		}
+		return mh.Hash(fmt.Sprint(key))
+	})
+}
+
+// find returns the item for `key`, nil if it is not in the cache.
+func (cc *core[K, V]) find(key K) *entry[K, V] {
+	if el := cc.items.Search(&entry[K, V]{key: key}); el != nil {
+		return el.Data
+	}
+	return nil
+}
+
+// length returns the number of items in the cache.
+func (cc *core[K, V]) length() int {
+	return cc.items.Length()
+}
+
+// set puts the value, its cost and its expire time in `ee`.
+func (cc *core[K, V]) set(ee *entry[K, V], value V) {
+	cost := cc.costOf(ee.key, value)
+	cc.cost += cost - ee.cost
+	ee.value, ee.cost = value, cost
+	if cc.cfg.TTL > 0 {
+		ee.expires = cc.cfg.Now().Add(cc.cfg.TTL)
+	}
+}
+
+// costOf returns the cost of `value` for `key`, 1 if there is no Cost function.
+func (cc *core[K, V]) costOf(key K, value V) int64 {
+	if cc.cfg.Cost != nil {
+		return cc.cfg.Cost(key, value)
+	}
+	return 1
+}
+
+// tooBig returns true if an item that costs `cost` is over MaxCost by itself, it can never fit.
+func (cc *core[K, V]) tooBig(cost int64) bool {
+	return cc.cfg.MaxCost > 0 && cost > cc.cfg.MaxCost
+}
+
+// isExpired returns true if `ee` is past its TTL.
+func (cc *core[K, V]) isExpired(ee *entry[K, V]) bool {
+	return cc.cfg.TTL > 0 && !cc.cfg.Now().Before(ee.expires)
+}
+
+// overLimit returns true if `n` items with a total cost of `cost` do not fit.
+func (cc *core[K, V]) overLimit(n int, cost int64) bool {
+	return (cc.cfg.MaxItems > 0 && n > cc.cfg.MaxItems) || (cc.cfg.MaxCost > 0 && cost > cc.cfg.MaxCost)
+}
+
+// removed counts an item that has been taken out of the cache and calls OnEvict.
+func (cc *core[K, V]) removed(ee *entry[K, V], reason EvictReason) {
+	cc.cost -= ee.cost
+	switch reason {
+	case EvictCapacity:
+		cc.stats.Evictions++
+	case EvictExpired:
+		cc.stats.Expirations++
+	}
+	if cc.cfg.OnEvict != nil {
+		cc.cfg.OnEvict(ee.key, ee.value, reason)
+	}
+}
+
+// TotalCost returns the sum of the costs of the items in the cache.
+// Complexity is O(1).
+func (cc *core[K, V]) TotalCost() int64 {
+	return cc.cost
+}
+
+// Stats returns the counts of hits, misses, evictions and expirations.
+// Complexity is O(1).
+func (cc *core[K, V]) Stats() Stats {
+	return cc.stats
+}
@@ -0,0 +1,44 @@
+package cache_test
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"testing"
+
+	"github.com/pschlump/pluto/cache"
+	"github.com/pschlump/pluto/cache/cachetest"
+)
+
+var _ cachetest.Cache = (*cache.LRU[string, int])(nil)
+var _ cachetest.Cache = (*cache.LFU[string, int])(nil)
+
+var cacheTypes = []cachetest.CacheType{
+	{Name: "LRU", NewFn: func(cfg cache.Config[string, int]) cachetest.Cache { return cache.NewLRUConfig(cfg) }},
+	{Name: "LFU", LFU: true, NewFn: func(cfg cache.Config[string, int]) cachetest.Cache { return cache.NewLFUConfig(cfg) }},
+}
+
+func TestConformance(t *testing.T) {
+	cachetest.TestCache(t, cacheTypes)
+}
+
+func TestNew(t *testing.T) {
+	Cache1 := cache.NewLRU[int, string](2)
+	Cache1.Put(1, "a")
+	Cache1.Put(2, "b")
+	Cache1.Put(3, "c")
+	if _, found := Cache1.Peek(1); found || Cache1.Length() != 2 {
+		t.Errorf("Expected 1 to be evicted from the LRU")
+	}
+	Cache2 := cache.NewLFU[int, string](2)
+	Cache2.Put(1, "a")
+	Cache2.Get(1)
+	Cache2.Put(2, "b")
+	Cache2.Put(3, "c")
+	if v, found := Cache2.Peek(1); !found || v != "a" || Cache2.Length() != 2 {
+		t.Errorf("Expected 1 to be kept in the LFU got %s %v", v, found)
+	}
+}
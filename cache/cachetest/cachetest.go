@@ -0,0 +1,379 @@
+package cachetest
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+
+A conformance suite for the caches in ../../cache and ../../cache_ts, like ../../containertest is
+for the containers.  It is a package of its own because the caches are built on containers that
+use containertest in their tests.
+
+*	TestCache — the LRU or LFU eviction order, the cost and TTL limits, OnEvict, the stats and a
+		randomized check against a simple O(n) cache.
+
+A package runs the suite from one of its own tests, for example:
+
+	func TestConformance(t *testing.T) {
+		cachetest.TestCache(t, []cachetest.CacheType{
+			{Name: "LRU", NewFn: func(cfg cache.Config[string, int]) cachetest.Cache { return cache.NewLRUConfig(cfg) }},
+		})
+	}
+
+The randomized check uses a fixed seed so a failure can be repeated.
+
+*/
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pschlump/pluto/cache"
+	"github.com/pschlump/pluto/hashing"
+)
+
+// seed is fixed so that a failure can be repeated.
+const seed = 1001
+
+// Cache is the set of methods that the LRU and LFU caches have.
+type Cache interface {
+	Get(key string) (int, bool)
+	Peek(key string) (int, bool)
+	Put(key string, value int)
+	Delete(key string) bool
+	Length() int
+	TotalCost() int64
+	Stats() cache.Stats
+	RemoveExpired() int
+	Truncate()
+}
+
+// CacheType is one kind of cache for TestCache.  LFU is true for a cache that evicts the least
+// frequently used item, false for one that evicts the least recently used.
+type CacheType struct {
+	Name  string
+	LFU   bool
+	NewFn func(cfg cache.Config[string, int]) Cache
+}
+
+// TestCache checks the eviction order, the cost and TTL limits, OnEvict and the stats of each
+// of `types`, and runs a randomized check against a simple O(n) cache.
+func TestCache(t *testing.T, types []CacheType) {
+	t.Helper()
+	for _, ct := range types {
+		t.Run(ct.Name, func(t *testing.T) {
+			if ct.LFU {
+				t.Run("LFU", func(t *testing.T) { testLFU(t, ct.NewFn) })
+			} else {
+				t.Run("LRU", func(t *testing.T) { testLRU(t, ct.NewFn) })
+			}
+			t.Run("Cost", func(t *testing.T) { testCacheCost(t, ct) })
+			t.Run("TTL", func(t *testing.T) { testCacheTTL(t, ct.NewFn) })
+			t.Run("Model", func(t *testing.T) { testCacheModel(t, ct, nil) })
+			// All the keys hash the same, so they are all in one bucket of the index.
+			t.Run("ModelOneBucket", func(t *testing.T) {
+				testCacheModel(t, ct, hashing.HasherFunc[string](func(key string) uint64 { return 12345 << 1 }))
+			})
+		})
+	}
+}
+
+// evictLog records the calls to OnEvict as "key:reason".
+type evictLog []string
+
+func (ee *evictLog) onEvict(key string, value int, reason cache.EvictReason) {
+	*ee = append(*ee, fmt.Sprintf("%s:%s", key, reason))
+}
+
+func (ee *evictLog) String() string {
+	return strings.Join(*ee, " ")
+}
+
+func testLRU(t *testing.T, newFn func(cfg cache.Config[string, int]) Cache) {
+	var log evictLog
+	Cache1 := newFn(cache.Config[string, int]{MaxItems: 3, OnEvict: log.onEvict})
+
+	Cache1.Put("a", 1)
+	Cache1.Put("b", 2)
+	Cache1.Put("c", 3)
+	if v, found := Cache1.Get("a"); !found || v != 1 {
+		t.Errorf("Get(a) expcted 1 got %d %v", v, found)
+	}
+	Cache1.Put("d", 4) // b is the least recently used
+	if _, found := Cache1.Peek("b"); found {
+		t.Errorf("Expected b to be evicted")
+	}
+	Cache1.Peek("c")   // does not count as a use
+	Cache1.Put("e", 5) // so c goes
+	Cache1.Put("a", 10)
+	Cache1.Put("f", 6) // d goes, a was used by the Put
+	if log.String() != "b:Capacity c:Capacity d:Capacity" {
+		t.Errorf("Expected b, c and d to be evicted got %s", log.String())
+	}
+	if v, _ := Cache1.Get("a"); v != 10 {
+		t.Errorf("Expected a to be replaced with 10 got %d", v)
+	}
+	if Cache1.Length() != 3 {
+		t.Errorf("Expected length 3 got %d", Cache1.Length())
+	}
+
+	if !Cache1.Delete("e") || Cache1.Delete("e") {
+		t.Errorf("Expected the first Delete of e to find it and the second not to")
+	}
+	if log[len(log)-1] != "e:Removed" {
+		t.Errorf("Expected the Delete to call OnEvict with Removed got %s", log.String())
+	}
+	Cache1.Get("zz")
+	if ss := Cache1.Stats(); ss.Hits != 2 || ss.Misses != 1 || ss.Evictions != 3 {
+		t.Errorf("Unexpected stats %+v", ss)
+	}
+
+	Cache1.Truncate()
+	if Cache1.Length() != 0 || Cache1.TotalCost() != 0 {
+		t.Errorf("Expected empty after Truncate")
+	}
+	if _, found := Cache1.Peek("a"); found {
+		t.Errorf("Expected a to be gone after Truncate")
+	}
+}
+
+func testLFU(t *testing.T, newFn func(cfg cache.Config[string, int]) Cache) {
+	var log evictLog
+	Cache1 := newFn(cache.Config[string, int]{MaxItems: 3, OnEvict: log.onEvict})
+
+	Cache1.Put("a", 1)
+	Cache1.Put("b", 2)
+	Cache1.Put("c", 3)
+	Cache1.Get("a")
+	Cache1.Get("a")
+	Cache1.Get("b")
+	Cache1.Put("d", 4) // c has the lowest count
+	Cache1.Put("e", 5) // d has the lowest count, a new key is not evicted for itself
+	if log.String() != "c:Capacity d:Capacity" {
+		t.Errorf("Expected c and d to be evicted got %s", log.String())
+	}
+
+	// Of the items with the same count the least recently used goes.
+	Cache2 := newFn(cache.Config[string, int]{MaxItems: 2})
+	Cache2.Put("x", 1)
+	Cache2.Put("y", 2)
+	Cache2.Get("x")
+	Cache2.Get("y")
+	Cache2.Put("z", 3)
+	if _, found := Cache2.Peek("x"); found {
+		t.Errorf("Expected x to be evicted")
+	}
+
+	// After a Delete of the only item with the lowest count the next one is found.
+	Cache3 := newFn(cache.Config[string, int]{MaxItems: 2})
+	Cache3.Put("p", 1)
+	Cache3.Put("q", 2)
+	Cache3.Get("q")
+	Cache3.Get("q")
+	Cache3.Delete("p")
+	Cache3.Put("r", 3)
+	Cache3.Get("r")
+	Cache3.Put("s", 4) // r has 2 uses, q has 3
+	if _, found := Cache3.Peek("r"); found {
+		t.Errorf("Expected r to be evicted")
+	}
+	if _, found := Cache3.Peek("q"); !found {
+		t.Errorf("Expected q to be kept")
+	}
+}
+
+func testCacheCost(t *testing.T, ct CacheType) {
+	var log evictLog
+	Cache1 := ct.NewFn(cache.Config[string, int]{
+		MaxCost: 10,
+		Cost:    func(key string, value int) int64 { return int64(value) },
+		OnEvict: log.onEvict,
+	})
+	Cache1.Put("a", 4)
+	Cache1.Put("b", 4)
+	if Cache1.TotalCost() != 8 {
+		t.Errorf("Expected cost 8 got %d", Cache1.TotalCost())
+	}
+	Cache1.Put("c", 5) // a goes
+	if Cache1.TotalCost() != 9 || Cache1.Length() != 2 {
+		t.Errorf("Expected cost 9 and 2 items got %d %d", Cache1.TotalCost(), Cache1.Length())
+	}
+	Cache1.Put("c", 7) // b goes, the new cost of c is too much with it
+	if Cache1.TotalCost() != 7 || Cache1.Length() != 1 {
+		t.Errorf("Expected cost 7 and 1 item got %d %d", Cache1.TotalCost(), Cache1.Length())
+	}
+	Cache1.Put("d", 11) // too big by itself, not kept, and c is not evicted to make room
+	if _, found := Cache1.Peek("d"); found || Cache1.TotalCost() != 7 {
+		t.Errorf("Expected d not to be kept, cost %d", Cache1.TotalCost())
+	}
+	if v, found := Cache1.Peek("c"); !found || v != 7 {
+		t.Errorf("Expected c to be kept got %d %v", v, found)
+	}
+	Cache1.Put("e", 2)
+	Cache1.Put("c", 20) // too big, only c goes
+	if _, found := Cache1.Peek("e"); !found || Cache1.Length() != 1 || Cache1.TotalCost() != 2 {
+		t.Errorf("Expected only e to be left, cost %d", Cache1.TotalCost())
+	}
+	if log.String() != "a:Capacity b:Capacity d:Capacity c:Capacity" {
+		t.Errorf("Unexpected evictions %s", log.String())
+	}
+	if ss := Cache1.Stats(); ss.Evictions != 4 {
+		t.Errorf("Expected 4 evictions got %d", ss.Evictions)
+	}
+
+	if ct.LFU {
+		// The item put is the only one with the lowest count, so the next count is evicted.
+		Cache2 := ct.NewFn(cache.Config[string, int]{MaxCost: 3, Cost: func(key string, value int) int64 { return int64(value) }})
+		Cache2.Put("a", 1)
+		Cache2.Put("b", 1)
+		Cache2.Get("b")
+		Cache2.Get("b")
+		Cache2.Put("a", 3)
+		if v, found := Cache2.Peek("a"); !found || v != 3 || Cache2.Length() != 1 {
+			t.Errorf("Expected only a to be left got %d %v %d", v, found, Cache2.Length())
+		}
+	}
+}
+
+func testCacheTTL(t *testing.T, newFn func(cfg cache.Config[string, int]) Cache) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var log evictLog
+	Cache1 := newFn(cache.Config[string, int]{
+		TTL:     time.Minute,
+		OnEvict: log.onEvict,
+		Now:     func() time.Time { return now },
+	})
+	Cache1.Put("a", 1)
+	now = now.Add(30 * time.Second)
+	Cache1.Put("b", 2)
+	Cache1.Put("c", 3)
+	now = now.Add(30 * time.Second) // a expires now
+	if _, found := Cache1.Peek("a"); found {
+		t.Errorf("Expected Peek not to find the expired a")
+	}
+	if _, found := Cache1.Get("a"); found {
+		t.Errorf("Expected Get not to find the expired a")
+	}
+	if v, found := Cache1.Get("b"); !found || v != 2 {
+		t.Errorf("Expected b to still be there")
+	}
+	Cache1.Put("c", 30) // a Put starts the TTL again
+	now = now.Add(45 * time.Second)
+	if n := Cache1.RemoveExpired(); n != 1 {
+		t.Errorf("Expected RemoveExpired to remove 1 got %d", n)
+	}
+	if log.String() != "a:Expired b:Expired" {
+		t.Errorf("Unexpected evictions %s", log.String())
+	}
+	if v, found := Cache1.Get("c"); !found || v != 30 || Cache1.Length() != 1 {
+		t.Errorf("Expected only c to be left")
+	}
+	if ss := Cache1.Stats(); ss.Expirations != 2 || ss.Misses != 1 || ss.Hits != 2 || ss.HitRate() != 2.0/3.0 {
+		t.Errorf("Unexpected stats %+v", ss)
+	}
+}
+
+// modelCache is a simple O(n) version of the caches to test against.  An LRU is an LFU where
+// every item has the same count.
+type modelCache struct {
+	maxItems int
+	lfu      bool
+	tick     int
+	items    map[string]*modelItem
+}
+
+type modelItem struct {
+	value, freq, used int
+}
+
+func (mm *modelCache) use(it *modelItem) {
+	mm.tick++
+	it.used = mm.tick
+	if mm.lfu {
+		it.freq++
+	}
+}
+
+func (mm *modelCache) Get(key string) (int, bool) {
+	if it, found := mm.items[key]; found {
+		mm.use(it)
+		return it.value, true
+	}
+	return 0, false
+}
+
+func (mm *modelCache) Put(key string, value int) {
+	if it, found := mm.items[key]; found {
+		it.value = value
+		mm.use(it)
+		return
+	}
+	if len(mm.items) == mm.maxItems {
+		victim := ""
+		for k, it := range mm.items {
+			if vi := mm.items[victim]; victim == "" || it.freq < vi.freq || (it.freq == vi.freq && it.used < vi.used) {
+				victim = k
+			}
+		}
+		delete(mm.items, victim)
+	}
+	it := &modelItem{value: value}
+	mm.items[key] = it
+	mm.use(it)
+}
+
+func (mm *modelCache) keys() string {
+	var rv []string
+	for k := range mm.items {
+		rv = append(rv, k)
+	}
+	sort.Strings(rv)
+	return strings.Join(rv, " ")
+}
+
+// testCacheModel checks a cache against modelCache, with `hh` as the Hasher of the index if it
+// is not nil.
+func testCacheModel(t *testing.T, ct CacheType, hh hashing.Hasher[string]) {
+	var keys []string
+	Cache1 := ct.NewFn(cache.Config[string, int]{
+		MaxItems: 8,
+		OnEvict:  func(key string, value int, reason cache.EvictReason) { keys = append(keys, key) },
+		Hasher:   hh,
+	})
+	model := &modelCache{maxItems: 8, lfu: ct.LFU, items: make(map[string]*modelItem)}
+	rnd := rand.New(rand.NewSource(seed))
+
+	for ii := 0; ii < 5000; ii++ {
+		key := fmt.Sprintf("%02d", rnd.Intn(20))
+		if rnd.Intn(2) == 0 {
+			got, found := Cache1.Get(key)
+			want, wantFound := model.Get(key)
+			if found != wantFound || got != want {
+				t.Fatalf("Step %d, Get(%s) expcted %d %v got %d %v", ii, key, want, wantFound, got, found)
+			}
+		} else {
+			Cache1.Put(key, ii)
+			model.Put(key, ii)
+		}
+		if Cache1.Length() != len(model.items) {
+			t.Fatalf("Step %d, expected length %d got %d", ii, len(model.items), Cache1.Length())
+		}
+	}
+	var got []string
+	for k := range model.items {
+		if _, found := Cache1.Peek(k); found {
+			got = append(got, k)
+		}
+	}
+	sort.Strings(got)
+	if strings.Join(got, " ") != model.keys() {
+		t.Errorf("Expected keys %s got %s", model.keys(), strings.Join(got, " "))
+	}
+	if ss := Cache1.Stats(); int(ss.Evictions) != len(keys) {
+		t.Errorf("Expected %d evictions got %d", len(keys), ss.Evictions)
+	}
+}
@@ -0,0 +1,232 @@
+package cache
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"github.com/pschlump/pluto/dll"
+)
+
+// LFU is a cache that evicts the least frequently used item, and of the items used the same
+// number of times the least recently used one.  There is a list of items for each use count,
+// so a Get moves an item from one list to the next in O(1).
+type LFU[K comparable, V any] struct {
+	core[K, V]
+	freqs   map[int]*dll.Dll[entry[K, V]] // the items for each use count, most recently used at the head
+	minFreq int                           // the lowest use count, if freqs[minFreq] is nil it has to be found
+}
+
+// NewLFU creates a new LFU cache that holds up to `maxItems` items.
+// Complexity is O(1).
+func NewLFU[K comparable, V any](maxItems int) *LFU[K, V] {
+	if maxItems < 1 {
+		panic("maxItems too small")
+	}
+	return NewLFUConfig(Config[K, V]{MaxItems: maxItems})
+}
+
+// NewLFUConfig creates a new LFU cache with the limits and options in `cfg`.
+// Complexity is O(1).
+func NewLFUConfig[K comparable, V any](cfg Config[K, V]) *LFU[K, V] {
+	return &LFU[K, V]{
+		core:  newCore(cfg),
+		freqs: make(map[int]*dll.Dll[entry[K, V]]),
+	}
+}
+
+// Get returns the value for `key` and adds one to its use count.  If the key is not in the
+// cache, or has expired, then found is false.
+// Complexity is O(1).
+func (tt *LFU[K, V]) Get(key K) (value V, found bool) {
+	if tt == nil {
+		panic("cache sholud not be a nil")
+	}
+	ee := tt.find(key)
+	if ee == nil {
+		tt.stats.Misses++
+		return
+	}
+	if tt.isExpired(ee) {
+		tt.remove(ee, EvictExpired)
+		tt.stats.Misses++
+		return value, false
+	}
+	tt.stats.Hits++
+	tt.touch(ee)
+	return ee.value, true
+}
+
+// Peek returns the value for `key` without changing its use count or the stats.
+// Complexity is O(1).
+func (tt *LFU[K, V]) Peek(key K) (value V, found bool) {
+	if tt == nil {
+		panic("cache sholud not be a nil")
+	}
+	if ee := tt.find(key); ee != nil && !tt.isExpired(ee) {
+		return ee.value, true
+	}
+	return
+}
+
+// Put sets the value for `key`.  A new key has a use count of 1, putting a key that is already
+// in the cache counts as a use.  If this goes over a limit then the least frequently used items
+// are evicted until it does not, a new key is not evicted to make room for itself.  An item that
+// is over MaxCost by itself is not kept, it is passed to OnEvict before any other item is
+// evicted.
+// Complexity is O(1) for each item evicted.
+func (tt *LFU[K, V]) Put(key K, value V) {
+	if tt == nil {
+		panic("cache sholud not be a nil")
+	}
+	if ee := tt.find(key); ee != nil {
+		tt.set(ee, value)
+		if tt.tooBig(ee.cost) {
+			tt.remove(ee, EvictCapacity)
+			return
+		}
+		tt.touch(ee)
+		for tt.overLimit(tt.length(), tt.cost) && tt.length() > 1 {
+			tt.remove(tt.victim(ee), EvictCapacity)
+		}
+		return
+	}
+
+	ee := &entry[K, V]{key: key, freq: 1}
+	tt.set(ee, value)
+	if tt.tooBig(ee.cost) {
+		tt.removed(ee, EvictCapacity)
+		return
+	}
+	for tt.overLimit(tt.length()+1, tt.cost) && tt.length() > 0 {
+		tt.remove(tt.victim(nil), EvictCapacity)
+	}
+	tt.link(ee)
+	tt.items.Insert(ee)
+	tt.minFreq = 1
+}
+
+// link adds `ee` at the head of the list for its use count and sets its element.
+func (tt *LFU[K, V]) link(ee *entry[K, V]) {
+	list, ok := tt.freqs[ee.freq]
+	if !ok {
+		list = dll.NewDll[entry[K, V]]()
+		tt.freqs[ee.freq] = list
+	}
+	list.InsertBeforeHead(ee)
+	ee.el, _ = list.Index(0)
+}
+
+// unlink takes `ee` out of the list for its use count.
+func (tt *LFU[K, V]) unlink(ee *entry[K, V]) {
+	list := tt.freqs[ee.freq]
+	list.DeleteFound(ee.el)
+	if list.IsEmpty() {
+		delete(tt.freqs, ee.freq)
+	}
+}
+
+// touch adds one to the use count of `ee` and moves it to the next list.
+func (tt *LFU[K, V]) touch(ee *entry[K, V]) {
+	tt.unlink(ee)
+	if ee.freq == tt.minFreq && tt.freqs[ee.freq] == nil {
+		tt.minFreq++
+	}
+	ee.freq++
+	tt.link(ee)
+}
+
+// victim returns the item to evict, the least recently used of the items with the lowest use
+// count, but not `keep`.
+func (tt *LFU[K, V]) victim(keep *entry[K, V]) *entry[K, V] {
+	if tt.freqs[tt.minFreq] == nil {
+		tt.minFreq = tt.lowestFreq(0)
+	}
+	freq := tt.minFreq
+	if keep != nil && keep.freq == freq && tt.freqs[freq].Length() == 1 {
+		freq = tt.lowestFreq(freq)
+	}
+	ee, _ := tt.freqs[freq].PeekTail()
+	return ee
+}
+
+// lowestFreq returns the lowest use count that is above `above`.  It is only needed after a
+// Delete or an expire of the last item with the lowest count, or when the item that has to be
+// kept is the only one with the lowest count.
+// Complexity is O(k), k the number of different use counts.
+func (tt *LFU[K, V]) lowestFreq(above int) (rv int) {
+	for freq := range tt.freqs {
+		if freq > above && (rv == 0 || freq < rv) {
+			rv = freq
+		}
+	}
+	return
+}
+
+// remove takes `ee` out of the cache.
+func (tt *LFU[K, V]) remove(ee *entry[K, V], reason EvictReason) {
+	tt.unlink(ee)
+	tt.items.Delete(ee)
+	tt.removed(ee, reason)
+}
+
+// Delete removes `key` from the cache and returns true if it was there.  OnEvict is called
+// with EvictRemoved.
+// Complexity is O(1).
+func (tt *LFU[K, V]) Delete(key K) (found bool) {
+	if tt == nil {
+		panic("cache sholud not be a nil")
+	}
+	if ee := tt.find(key); ee != nil {
+		tt.remove(ee, EvictRemoved)
+		return true
+	}
+	return false
+}
+
+// Length returns the number of items in the cache, including any that have expired but not
+// been removed yet.
+// Complexity is O(1).
+func (tt *LFU[K, V]) Length() int {
+	if tt == nil {
+		panic("cache sholud not be a nil")
+	}
+	return tt.length()
+}
+
+// RemoveExpired removes all the items that are past their TTL and returns how many there were.
+// Complexity is O(n).
+func (tt *LFU[K, V]) RemoveExpired() (n int) {
+	if tt == nil {
+		panic("cache sholud not be a nil")
+	}
+	if tt.cfg.TTL <= 0 {
+		return 0
+	}
+	var expired []*entry[K, V]
+	for _, list := range tt.freqs {
+		for ii := list.Front(); !ii.Done(); ii.Next() {
+			if ee := ii.Value(); tt.isExpired(ee) {
+				expired = append(expired, ee)
+			}
+		}
+	}
+	for _, ee := range expired {
+		tt.remove(ee, EvictExpired)
+	}
+	return len(expired)
+}
+
+// Truncate removes all the items from the cache without calling OnEvict.  The stats are kept.
+// Complexity is O(1).
+func (tt *LFU[K, V]) Truncate() {
+	if tt == nil {
+		panic("cache sholud not be a nil")
+	}
+	tt.items = tt.newIndex()
+	tt.freqs = make(map[int]*dll.Dll[entry[K, V]])
+	tt.cost = 0
+	tt.minFreq = 0
+}
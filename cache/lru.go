@@ -0,0 +1,174 @@
+package cache
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"github.com/pschlump/pluto/dll"
+)
+
+// LRU is a cache that evicts the least recently used item.
+type LRU[K comparable, V any] struct {
+	core[K, V]
+	list dll.Dll[entry[K, V]] // most recently used at the head
+}
+
+// NewLRU creates a new LRU cache that holds up to `maxItems` items.
+// Complexity is O(1).
+func NewLRU[K comparable, V any](maxItems int) *LRU[K, V] {
+	if maxItems < 1 {
+		panic("maxItems too small")
+	}
+	return NewLRUConfig(Config[K, V]{MaxItems: maxItems})
+}
+
+// NewLRUConfig creates a new LRU cache with the limits and options in `cfg`.
+// Complexity is O(1).
+func NewLRUConfig[K comparable, V any](cfg Config[K, V]) *LRU[K, V] {
+	return &LRU[K, V]{
+		core: newCore(cfg),
+	}
+}
+
+// Get returns the value for `key` and makes it the most recently used item.  If the key is not
+// in the cache, or has expired, then found is false.
+// Complexity is O(1).
+func (tt *LRU[K, V]) Get(key K) (value V, found bool) {
+	if tt == nil {
+		panic("cache sholud not be a nil")
+	}
+	ee := tt.find(key)
+	if ee == nil {
+		tt.stats.Misses++
+		return
+	}
+	if tt.isExpired(ee) {
+		tt.remove(ee, EvictExpired)
+		tt.stats.Misses++
+		return value, false
+	}
+	tt.stats.Hits++
+	tt.list.Current(ee.el, 0).MoveToFront()
+	return ee.value, true
+}
+
+// Peek returns the value for `key` without changing the order of eviction or the stats.
+// Complexity is O(1).
+func (tt *LRU[K, V]) Peek(key K) (value V, found bool) {
+	if tt == nil {
+		panic("cache sholud not be a nil")
+	}
+	if ee := tt.find(key); ee != nil && !tt.isExpired(ee) {
+		return ee.value, true
+	}
+	return
+}
+
+// Put sets the value for `key` and makes it the most recently used item.  If this goes over a
+// limit then the least recently used items are evicted until it does not.  An item that is
+// over MaxCost by itself is not kept, it is passed to OnEvict before any other item is evicted.
+// Complexity is O(1) for each item evicted.
+func (tt *LRU[K, V]) Put(key K, value V) {
+	if tt == nil {
+		panic("cache sholud not be a nil")
+	}
+	if ee := tt.find(key); ee != nil {
+		tt.set(ee, value)
+		if tt.tooBig(ee.cost) {
+			tt.remove(ee, EvictCapacity)
+			return
+		}
+		tt.list.Current(ee.el, 0).MoveToFront()
+		for tt.overLimit(tt.length(), tt.cost) && tt.list.Length() > 1 {
+			tt.evict()
+		}
+		return
+	}
+
+	ee := &entry[K, V]{key: key}
+	tt.set(ee, value)
+	if tt.tooBig(ee.cost) {
+		tt.removed(ee, EvictCapacity)
+		return
+	}
+	for tt.overLimit(tt.length()+1, tt.cost) && tt.list.Length() > 0 {
+		tt.evict()
+	}
+	tt.list.InsertBeforeHead(ee)
+	ee.el, _ = tt.list.Index(0)
+	tt.items.Insert(ee)
+}
+
+// evict removes the least recently used item.
+func (tt *LRU[K, V]) evict() {
+	ee, _ := tt.list.PopTail()
+	tt.items.Delete(ee)
+	tt.removed(ee, EvictCapacity)
+}
+
+// remove takes `ee` out of the cache.
+func (tt *LRU[K, V]) remove(ee *entry[K, V], reason EvictReason) {
+	tt.list.DeleteFound(ee.el)
+	tt.items.Delete(ee)
+	tt.removed(ee, reason)
+}
+
+// Delete removes `key` from the cache and returns true if it was there.  OnEvict is called
+// with EvictRemoved.
+// Complexity is O(1).
+func (tt *LRU[K, V]) Delete(key K) (found bool) {
+	if tt == nil {
+		panic("cache sholud not be a nil")
+	}
+	if ee := tt.find(key); ee != nil {
+		tt.remove(ee, EvictRemoved)
+		return true
+	}
+	return false
+}
+
+// Length returns the number of items in the cache, including any that have expired but not
+// been removed yet.
+// Complexity is O(1).
+func (tt *LRU[K, V]) Length() int {
+	if tt == nil {
+		panic("cache sholud not be a nil")
+	}
+	return tt.length()
+}
+
+// RemoveExpired removes all the items that are past their TTL and returns how many there were.
+// Complexity is O(n).
+func (tt *LRU[K, V]) RemoveExpired() (n int) {
+	if tt == nil {
+		panic("cache sholud not be a nil")
+	}
+	if tt.cfg.TTL <= 0 {
+		return 0
+	}
+	for ii := tt.list.Front(); !ii.Done(); {
+		if ee := ii.Value(); tt.isExpired(ee) {
+			ii.Remove()
+			tt.items.Delete(ee)
+			tt.removed(ee, EvictExpired)
+			n++
+		} else {
+			ii.Next()
+		}
+	}
+	return
+}
+
+// Truncate removes all the items from the cache without calling OnEvict.  The stats are kept.
+// Complexity is O(1).
+func (tt *LRU[K, V]) Truncate() {
+	if tt == nil {
+		panic("cache sholud not be a nil")
+	}
+	tt.items = tt.newIndex()
+	tt.list.Truncate()
+	tt.cost = 0
+}
@@ -0,0 +1,213 @@
+package cache_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+Caches from keys to values with a limit on the number of items and/or on the total cost of
+the items.  When a Put goes over a limit, items are evicted to make room.
+
+* 	LRU - evicts the least recently used item.
+* 	LFU - evicts the least frequently used item, and of those the least recently used.
+
+Both have the same methods:
+
+* 	Get - returns the value for a key and marks it as used, counts a hit or a miss.				O(1)
+* 	Peek - returns the value for a key without marking it as used or counting it.				O(1)
+* 	Put - sets the value for a key, evicting other items if a limit is passed.					O(1) per item evicted
+* 	Delete - removes a key, returns true if it was in the cache.								O(1)
+* 	Length - the number of items in the cache.													O(1)
+* 	TotalCost - the sum of the costs of the items in the cache.									O(1)
+* 	Stats - the counts of hits, misses, evictions and expirations.								O(1)
+* 	RemoveExpired - remove all the items that are past their TTL.								O(n)
+* 	Truncate - remove all the items.  The stats are kept.										O(1)
+
+This is the thread safe version of ../cache, it has the exact same interface and uses the same
+EvictReason and Stats types.  The items are kept in a dll (../dll_ts) in the order they will be
+evicted, with a ../hash_tab_dll of the same items, by key, as the index.  The hash table grows
+with the number of items, so a lookup is O(1).  Each method holds a mutex on the cache for the
+whole call, so the index and the lists always agree.  A Get changes the order of eviction so it needs the same lock as
+a Put, a read lock would not help.
+
+OnEvict is called with the cache locked.  It must not call a method of the same cache, that
+will deadlock.
+
+With a TTL an item expires that long after it was last Put.  An expired item is removed when
+it is found by Get, or by RemoveExpired, so it can still count against the limits until then.
+
+The keys are hashed with the Hasher in the Config, without one see ../cache.
+
+*/
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pschlump/pluto/cache"
+	// The package is renamed so that it does not hide the comparable constraint used for K.
+	pcomparable "github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/dll_ts"
+	hash_tab_dll "github.com/pschlump/pluto/hash_tab_dll"
+	"github.com/pschlump/pluto/hashing"
+)
+
+// EvictReason is passed to the OnEvict function to say why an item left the cache.
+type EvictReason = cache.EvictReason
+
+const (
+	EvictCapacity = cache.EvictCapacity // removed to make room, over MaxItems or MaxCost
+	EvictExpired  = cache.EvictExpired  // past its TTL
+	EvictRemoved  = cache.EvictRemoved  // removed by Delete
+)
+
+// Config sets the limits and options for a cache.  A zero value for a limit is no limit.
+type Config[K comparable, V any] struct {
+	MaxItems int                                      // the most items in the cache
+	MaxCost  int64                                    // the most total cost of the items in the cache
+	Cost     func(key K, value V) int64               // the cost of an item, if nil each item costs 1
+	TTL      time.Duration                            // how long an item lives after a Put
+	OnEvict  func(key K, value V, reason EvictReason) // called when an item leaves the cache, not on Truncate
+	Now      func() time.Time                         // the clock for the TTL, if nil time.Now
+	Hasher   hashing.Hasher[K]                        // the hash of a key for the index, if nil see ../cache
+}
+
+// Stats are the counts for a cache since it was created.
+type Stats = cache.Stats
+
+// entry is the item kept in the list.
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	cost    int64
+	expires time.Time                       // zero if there is no TTL
+	freq    int                             // the number of uses, for the LFU
+	el      *dll_ts.DllElement[entry[K, V]] // the element of this item in the list it is in
+}
+
+// At compile time verify that entry can be kept in a dll.
+var _ pcomparable.Equality = (*entry[int, int])(nil)
+
+// IsEqual compares the keys, so that an entry can be kept in a dll and in the index.
+func (ee entry[K, V]) IsEqual(x pcomparable.Equality) bool {
+	switch bb := x.(type) {
+	case entry[K, V]:
+		return ee.key == bb.key
+	case *entry[K, V]:
+		return ee.key == bb.key
+	}
+	return false
+}
+
+// core is the config, the index, the total cost, the stats and the lock, shared by LRU and LFU.
+type core[K comparable, V any] struct {
+	cfg   Config[K, V]
+	items *hash_tab_dll.HashTab[entry[K, V]] // the items by key
+	cost  int64
+	stats Stats
+	mu    sync.Mutex
+}
+
+// nIndexBuckets is the number of buckets the index starts with, it grows as items are added.
+const nIndexBuckets = 16
+
+// init sets up the core in place, it has a mutex so it can not be returned by value.
+func (cc *core[K, V]) init(cfg Config[K, V]) {
+	if cfg.MaxItems < 0 || cfg.MaxCost < 0 {
+		panic("cache limits can not be negative")
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if cfg.Hasher == nil {
+		cfg.Hasher = cache.KeyHasher[K]()
+	}
+	cc.cfg = cfg
+	cc.items = cc.newIndex()
+}
+
+// newIndex returns an empty index that hashes the items by key.
+func (cc *core[K, V]) newIndex() *hash_tab_dll.HashTab[entry[K, V]] {
+	hh := cc.cfg.Hasher
+	byKey := hashing.HasherFunc[entry[K, V]](func(ee entry[K, V]) uint64 { return hh.Hash(ee.key) })
+	return hash_tab_dll.NewHashTab[entry[K, V]](nIndexBuckets, hashing.WithHasher[entry[K, V]](byKey))
+}
+
+// find returns the item for `key`, nil if it is not in the cache.
+func (cc *core[K, V]) find(key K) *entry[K, V] {
+	if el := cc.items.Search(&entry[K, V]{key: key}); el != nil {
+		return el.Data
+	}
+	return nil
+}
+
+// length returns the number of items in the cache.
+func (cc *core[K, V]) length() int {
+	return cc.items.Length()
+}
+
+// set puts the value, its cost and its expire time in `ee`.
+func (cc *core[K, V]) set(ee *entry[K, V], value V) {
+	cost := cc.costOf(ee.key, value)
+	cc.cost += cost - ee.cost
+	ee.value, ee.cost = value, cost
+	if cc.cfg.TTL > 0 {
+		ee.expires = cc.cfg.Now().Add(cc.cfg.TTL)
+	}
+}
+
+// costOf returns the cost of `value` for `key`, 1 if there is no Cost function.
+func (cc *core[K, V]) costOf(key K, value V) int64 {
+	if cc.cfg.Cost != nil {
+		return cc.cfg.Cost(key, value)
+	}
+	return 1
+}
+
+// tooBig returns true if an item that costs `cost` is over MaxCost by itself, it can never fit.
+func (cc *core[K, V]) tooBig(cost int64) bool {
+	return cc.cfg.MaxCost > 0 && cost > cc.cfg.MaxCost
+}
+
+// isExpired returns true if `ee` is past its TTL.
+func (cc *core[K, V]) isExpired(ee *entry[K, V]) bool {
+	return cc.cfg.TTL > 0 && !cc.cfg.Now().Before(ee.expires)
+}
+
+// overLimit returns true if `n` items with a total cost of `cost` do not fit.
+func (cc *core[K, V]) overLimit(n int, cost int64) bool {
+	return (cc.cfg.MaxItems > 0 && n > cc.cfg.MaxItems) || (cc.cfg.MaxCost > 0 && cost > cc.cfg.MaxCost)
+}
+
+// removed counts an item that has been taken out of the cache and calls OnEvict.
+func (cc *core[K, V]) removed(ee *entry[K, V], reason EvictReason) {
+	cc.cost -= ee.cost
+	switch reason {
+	case EvictCapacity:
+		cc.stats.Evictions++
+	case EvictExpired:
+		cc.stats.Expirations++
+	}
+	if cc.cfg.OnEvict != nil {
+		cc.cfg.OnEvict(ee.key, ee.value, reason)
+	}
+}
+
+// TotalCost returns the sum of the costs of the items in the cache.
+// Complexity is O(1).
+func (cc *core[K, V]) TotalCost() int64 {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return cc.cost
+}
+
+// Stats returns the counts of hits, misses, evictions and expirations.
+// Complexity is O(1).
+func (cc *core[K, V]) Stats() Stats {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	return cc.stats
+}
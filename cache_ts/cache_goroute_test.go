@@ -0,0 +1,66 @@
+package cache_ts_test
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+// This test is meant to be run with the race detector, `go test -race`.
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pschlump/pluto/cache"
+)
+
+func TestCacheGoroutines(t *testing.T) {
+	for _, ct := range cacheTypes {
+		// Each goroutine puts, gets and deletes its own keys.  The limit is lower than the number
+		// of keys so there are evictions all the time, the counts have to add up at the end.
+		const nKeys, maxItems = 200, 100
+		var nEvict atomic.Int64
+		Cache1 := ct.NewFn(cache.Config[string, int]{
+			MaxItems: maxItems,
+			TTL:      time.Hour,
+			OnEvict:  func(key string, value int, reason cache.EvictReason) { nEvict.Add(1) },
+		})
+
+		var wg sync.WaitGroup
+		for gg := 0; gg < 4; gg++ {
+			wg.Add(1)
+			go func(gg int) {
+				defer wg.Done()
+				for k := 0; k < nKeys; k++ {
+					key := fmt.Sprintf("g%d-%04d", gg, k)
+					Cache1.Put(key, k)
+					if got, found := Cache1.Get(key); found && got != k {
+						t.Errorf("%s: Get(%s) expcted %d got %d", ct.Name, key, k, got)
+					}
+					if k%10 == 0 {
+						Cache1.Delete(key)
+					}
+					if Cache1.Length() > maxItems {
+						t.Errorf("%s: Expected at most %d items got %d", ct.Name, maxItems, Cache1.Length())
+					}
+				}
+				Cache1.RemoveExpired()
+				Cache1.Stats()
+				Cache1.TotalCost()
+			}(gg)
+		}
+		wg.Wait()
+
+		// Every item that was put is either still in the cache or was passed to OnEvict.
+		if int(nEvict.Load())+Cache1.Length() != 4*nKeys {
+			t.Errorf("%s: Expected %d items evicted or kept got %d + %d", ct.Name, 4*nKeys, nEvict.Load(), Cache1.Length())
+		}
+		if ss := Cache1.Stats(); ss.Hits+ss.Misses != 4*nKeys {
+			t.Errorf("%s: Expected %d Gets got %+v", ct.Name, 4*nKeys, ss)
+		}
+	}
+}
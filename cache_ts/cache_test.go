@@ -0,0 +1,32 @@
+package cache_ts_test
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"testing"
+
+	"github.com/pschlump/pluto/cache"
+	"github.com/pschlump/pluto/cache/cachetest"
+	"github.com/pschlump/pluto/cache_ts"
+)
+
+var _ cachetest.Cache = (*cache_ts.LRU[string, int])(nil)
+var _ cachetest.Cache = (*cache_ts.LFU[string, int])(nil)
+
+// The Config of ../cache is converted, the two have the same fields.
+var cacheTypes = []cachetest.CacheType{
+	{Name: "LRU", NewFn: func(cfg cache.Config[string, int]) cachetest.Cache {
+		return cache_ts.NewLRUConfig(cache_ts.Config[string, int](cfg))
+	}},
+	{Name: "LFU", LFU: true, NewFn: func(cfg cache.Config[string, int]) cachetest.Cache {
+		return cache_ts.NewLFUConfig(cache_ts.Config[string, int](cfg))
+	}},
+}
+
+func TestConformance(t *testing.T) {
+	cachetest.TestCache(t, cacheTypes)
+}
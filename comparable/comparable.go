@@ -6,6 +6,8 @@ Copyright (C) Philip Schlump, 2012-2021.
 BSD 3 Clause Licensed.
 */
 
+import "fmt"
+
 type Comparable interface {
 	// Compare will return -1 (or a value less than 0) if a.Compare(b) has a < b,
 	// 0 if the two are considered to be equal, and
@@ -18,3 +20,45 @@ type Equality interface {
 	// IsEqual will return true if the 2 items are equal.
 	IsEqual(b Equality) bool
 }
+
+// Int is an int that is both Comparable and Equality.  The containers use it for the compile
+// time check, next to each type, that the container implements its ../iface_list interface:
+//
+//	var _ iface_list.TreeDataType[comparable.Int] = (*AvlTree[comparable.Int])(nil)
+type Int int
+
+// At compile time verify that Int implements both interfaces.
+var _ Comparable = Int(0)
+var _ Equality = Int(0)
+
+// Compare returns -1, 0 or 1 for aa less than, equal to or greater than `x`, an Int or *Int.
+// Complexity is O(1).
+func (aa Int) Compare(x Comparable) int {
+	var bb Int
+	switch v := x.(type) {
+	case Int:
+		bb = v
+	case *Int:
+		bb = *v
+	default:
+		panic(fmt.Sprintf("Passed invalid type %T to a Compare function.", x))
+	}
+	if aa < bb {
+		return -1
+	} else if aa > bb {
+		return 1
+	}
+	return 0
+}
+
+// IsEqual returns true if aa and `x`, an Int or *Int, are the same value.
+// Complexity is O(1).
+func (aa Int) IsEqual(x Equality) bool {
+	switch v := x.(type) {
+	case Int:
+		return aa == v
+	case *Int:
+		return aa == *v
+	}
+	return false
+}
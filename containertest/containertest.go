@@ -0,0 +1,772 @@
+package containertest
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+
+A conformance suite for the containers in this module.  Each Test* function takes a
+constructor for one of the iface_list interfaces and runs the same battery of checks on
+what it returns, so every implementation of an interface comes with the same guarantees.
+
+*	TestContainer — IsEmpty, Length and Truncate.
+*	TestStack — LIFO order, Peek and Pop on an empty stack return an error.
+*	TestQueue — FIFO order, Peek and Pop on an empty queue return an error.
+*	TestLinear — TestStack, TestQueue, PeekTail/PopTail/Reverse and a randomized check against a slice.
+*	TestTree — Insert/Search/Delete, in order data, Index, FindMin/FindMax and a randomized check against a map.
+*	TestTreeInvariant — a tree's own balance rules, checked by the tree's package, after random Inserts and Deletes.
+*	TestSet — Insert/Search/Delete and a randomized check against a map.
+*	TestPriorityQueue — Pop order with duplicates and a randomized check against a sorted slice.
+*	TestHasher — a hash table puts its items in the buckets its Hasher picks.
+
+A package runs the suite from one of its own tests, for example:
+
+	func TestConformance(t *testing.T) {
+		containertest.TestLinear(t, func() iface_list.LinearDataType[TestDemo] { return NewSll[TestDemo]() }, testItem)
+	}
+
+The randomized checks use a fixed seed so a failure can be repeated.
+
+*/
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/pschlump/pluto/hashing"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// Item tells the suite how to make data for a container.  New(n) returns a new item made
+// from the number n and Key returns the number an item was made from.  Two items made from
+// the same number must be equal, and for the ordered containers New(a) must Compare less
+// than New(b) when a < b.
+type Item[T any] struct {
+	New func(n int) *T
+	Key func(x *T) int
+}
+
+const (
+	seed     = 1001 // Fixed so that a failure can be repeated
+	nOps     = 2000 // Number of random operations in each model check
+	keyRange = 64   // Random keys are in 0..keyRange-1 so there are lots of duplicates
+)
+
+// keys returns the numbers the items in `data` were made from.
+func (it Item[T]) keys(data []*T) (rv []int) {
+	rv = make([]int, 0, len(data))
+	for _, x := range data {
+		rv = append(rv, it.Key(x))
+	}
+	return
+}
+
+// is reports whether `x` is not nil and was made from `n`.
+func (it Item[T]) is(x *T, n int) bool {
+	return x != nil && it.Key(x) == n
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedKeys returns the keys in the model set in order.
+func sortedKeys(model map[int]bool) (rv []int) {
+	rv = make([]int, 0, len(model))
+	for k := range model {
+		rv = append(rv, k)
+	}
+	sort.Ints(rv)
+	return
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// TestContainer checks IsEmpty, Length and Truncate.  `add` puts a new item made from `n`
+// into the container.
+func TestContainer(t *testing.T, newFn func() iface_list.Container, add func(c iface_list.Container, n int)) {
+	t.Helper()
+
+	c := newFn()
+	if !c.IsEmpty() || c.Length() != 0 {
+		t.Fatalf("New container should be empty, got IsEmpty()=%v Length()=%d", c.IsEmpty(), c.Length())
+	}
+
+	for i := 0; i < 10; i++ {
+		add(c, i)
+		if c.IsEmpty() {
+			t.Errorf("Container should not be empty after %d adds", i+1)
+		}
+		if c.Length() != i+1 {
+			t.Errorf("Length error, expected %d got %d", i+1, c.Length())
+		}
+	}
+
+	c.Truncate()
+	if !c.IsEmpty() || c.Length() != 0 {
+		t.Errorf("Container should be empty after Truncate, got IsEmpty()=%v Length()=%d", c.IsEmpty(), c.Length())
+	}
+	c.Truncate() // Truncate of an empty container is fine.
+
+	// Still usable after Truncate.
+	for i := 0; i < 3; i++ {
+		add(c, i)
+	}
+	if c.Length() != 3 {
+		t.Errorf("Length after Truncate and 3 adds, expected 3 got %d", c.Length())
+	}
+}
+
+// TestStack checks that the stack is LIFO and that Peek and Pop return an error when the
+// stack is empty.
+func TestStack[T any](t *testing.T, newFn func() iface_list.StackDataType[T], it Item[T]) {
+	t.Helper()
+
+	t.Run("Container", func(t *testing.T) {
+		TestContainer(t, func() iface_list.Container { return newFn() }, func(c iface_list.Container, n int) {
+			c.(iface_list.StackDataType[T]).Push(it.New(n))
+		})
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		st := newFn()
+		if _, err := st.Peek(); err == nil {
+			t.Errorf("Expected error from Peek on empty stack")
+		}
+		if _, err := st.Pop(); err == nil {
+			t.Errorf("Expected error from Pop on empty stack")
+		}
+		if x := st.ConvertToSlice(); len(x) != 0 {
+			t.Errorf("Expected empty slice got %d items", len(x))
+		}
+	})
+
+	t.Run("LIFO", func(t *testing.T) {
+		st := newFn()
+		for i := 0; i < 10; i++ {
+			st.Push(it.New(i))
+		}
+		if x, err := st.Peek(); err != nil || !it.is(x, 9) {
+			t.Errorf("Peek error, expected 9 got %v %v", x, err)
+		}
+		if st.Length() != 10 {
+			t.Errorf("Peek should not change Length, expected 10 got %d", st.Length())
+		}
+		expect := []int{9, 8, 7, 6, 5, 4, 3, 2, 1, 0}
+		if got := it.keys(st.ConvertToSlice()); !equalInts(got, expect) {
+			t.Errorf("ConvertToSlice error, expected %v got %v", expect, got)
+		}
+		for _, e := range expect {
+			x, err := st.Pop()
+			if err != nil {
+				t.Fatalf("Unexpected error %s from Pop", err)
+			}
+			if !it.is(x, e) {
+				t.Errorf("Pop error, expected %d got %d", e, it.Key(x))
+			}
+		}
+		if _, err := st.Pop(); err == nil || !st.IsEmpty() {
+			t.Errorf("Expected empty stack after popping everything")
+		}
+	})
+}
+
+// TestQueue checks that the queue is FIFO and that Peek and Pop return an error when the
+// queue is empty.
+func TestQueue[T any](t *testing.T, newFn func() iface_list.QueueDataType[T], it Item[T]) {
+	t.Helper()
+
+	t.Run("Container", func(t *testing.T) {
+		TestContainer(t, func() iface_list.Container { return newFn() }, func(c iface_list.Container, n int) {
+			c.(iface_list.QueueDataType[T]).Enque(it.New(n))
+		})
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		qu := newFn()
+		if _, err := qu.Peek(); err == nil {
+			t.Errorf("Expected error from Peek on empty queue")
+		}
+		if _, err := qu.Pop(); err == nil {
+			t.Errorf("Expected error from Pop on empty queue")
+		}
+	})
+
+	t.Run("FIFO", func(t *testing.T) {
+		qu := newFn()
+		for i := 0; i < 10; i++ {
+			qu.Enque(it.New(i))
+		}
+		if x, err := qu.Peek(); err != nil || !it.is(x, 0) {
+			t.Errorf("Peek error, expected 0 got %v %v", x, err)
+		}
+		expect := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		if got := it.keys(qu.ConvertToSlice()); !equalInts(got, expect) {
+			t.Errorf("ConvertToSlice error, expected %v got %v", expect, got)
+		}
+		for _, e := range expect {
+			x, err := qu.Pop()
+			if err != nil {
+				t.Fatalf("Unexpected error %s from Pop", err)
+			}
+			if !it.is(x, e) {
+				t.Errorf("Pop error, expected %d got %d", e, it.Key(x))
+			}
+		}
+		if _, err := qu.Pop(); err == nil || !qu.IsEmpty() {
+			t.Errorf("Expected empty queue after popping everything")
+		}
+	})
+}
+
+// TestLinear runs TestStack and TestQueue then checks PeekTail, PopTail and Reverse, and
+// finishes with a randomized check of all the operations against a slice.
+func TestLinear[T any](t *testing.T, newFn func() iface_list.LinearDataType[T], it Item[T]) {
+	t.Helper()
+
+	t.Run("Stack", func(t *testing.T) {
+		TestStack(t, func() iface_list.StackDataType[T] { return newFn() }, it)
+	})
+	t.Run("Queue", func(t *testing.T) {
+		TestQueue(t, func() iface_list.QueueDataType[T] { return newFn() }, it)
+	})
+
+	t.Run("Tail", func(t *testing.T) {
+		ll := newFn()
+		if _, err := ll.PeekTail(); err == nil {
+			t.Errorf("Expected error from PeekTail on empty list")
+		}
+		if _, err := ll.PopTail(); err == nil {
+			t.Errorf("Expected error from PopTail on empty list")
+		}
+		ll.Reverse() // Reverse of an empty list is fine.
+
+		ll.Push(it.New(1))
+		ll.Enque(it.New(2))
+		ll.Push(it.New(0))
+		if x, err := ll.PeekTail(); err != nil || !it.is(x, 2) {
+			t.Errorf("PeekTail error, expected 2 got %v %v", x, err)
+		}
+		ll.Reverse()
+		if got := it.keys(ll.ConvertToSlice()); !equalInts(got, []int{2, 1, 0}) {
+			t.Errorf("Reverse error, expected [2 1 0] got %v", got)
+		}
+		if x, err := ll.PopTail(); err != nil || !it.is(x, 0) {
+			t.Errorf("PopTail error, expected 0 got %v %v", x, err)
+		}
+		if x, err := ll.Pop(); err != nil || !it.is(x, 2) {
+			t.Errorf("Pop error, expected 2 got %v %v", x, err)
+		}
+		if x, err := ll.PopTail(); err != nil || !it.is(x, 1) {
+			t.Errorf("PopTail error, expected 1 got %v %v", x, err)
+		}
+		if !ll.IsEmpty() {
+			t.Errorf("Expected empty list got length %d", ll.Length())
+		}
+	})
+
+	t.Run("Model", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(seed))
+		ll := newFn()
+		var model []int
+		for op := 0; op < nOps; op++ {
+			n := rng.Intn(keyRange)
+			switch rng.Intn(7) {
+			case 0:
+				ll.Push(it.New(n))
+				model = append([]int{n}, model...)
+			case 1:
+				ll.Enque(it.New(n))
+				model = append(model, n)
+			case 2:
+				x, err := ll.Pop()
+				if len(model) == 0 {
+					if err == nil {
+						t.Fatalf("op %d: expected error from Pop on empty list", op)
+					}
+				} else {
+					if err != nil || !it.is(x, model[0]) {
+						t.Fatalf("op %d: Pop expected %d got %v %v", op, model[0], x, err)
+					}
+					model = model[1:]
+				}
+			case 3:
+				x, err := ll.PopTail()
+				if len(model) == 0 {
+					if err == nil {
+						t.Fatalf("op %d: expected error from PopTail on empty list", op)
+					}
+				} else {
+					if err != nil || !it.is(x, model[len(model)-1]) {
+						t.Fatalf("op %d: PopTail expected %d got %v %v", op, model[len(model)-1], x, err)
+					}
+					model = model[:len(model)-1]
+				}
+			case 4:
+				x, err := ll.Peek()
+				if len(model) == 0 {
+					if err == nil {
+						t.Fatalf("op %d: expected error from Peek on empty list", op)
+					}
+				} else if err != nil || !it.is(x, model[0]) {
+					t.Fatalf("op %d: Peek expected %d got %v %v", op, model[0], x, err)
+				}
+			case 5:
+				x, err := ll.PeekTail()
+				if len(model) == 0 {
+					if err == nil {
+						t.Fatalf("op %d: expected error from PeekTail on empty list", op)
+					}
+				} else if err != nil || !it.is(x, model[len(model)-1]) {
+					t.Fatalf("op %d: PeekTail expected %d got %v %v", op, model[len(model)-1], x, err)
+				}
+			case 6:
+				ll.Reverse()
+				for i, j := 0, len(model)-1; i < j; i, j = i+1, j-1 {
+					model[i], model[j] = model[j], model[i]
+				}
+			}
+			if ll.Length() != len(model) || ll.IsEmpty() != (len(model) == 0) {
+				t.Fatalf("op %d: Length expected %d got %d", op, len(model), ll.Length())
+			}
+			if got := it.keys(ll.ConvertToSlice()); !equalInts(got, model) {
+				t.Fatalf("op %d: list expected %v got %v", op, model, got)
+			}
+		}
+	})
+}
+
+// TestTree checks Insert, Search and Delete, that the data is kept in order, Index,
+// FindMin/FindMax, DeleteAtHead/DeleteAtTail, and finishes with a randomized check
+// against a map.
+func TestTree[T any](t *testing.T, newFn func() iface_list.TreeDataType[T], it Item[T]) {
+	t.Helper()
+
+	t.Run("Container", func(t *testing.T) {
+		TestContainer(t, func() iface_list.Container { return newFn() }, func(c iface_list.Container, n int) {
+			c.(iface_list.TreeDataType[T]).Insert(it.New(n))
+		})
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		tr := newFn()
+		if tr.FindMin() != nil || tr.FindMax() != nil {
+			t.Errorf("Expected nil from FindMin/FindMax on empty tree")
+		}
+		if tr.Search(it.New(1)) != nil {
+			t.Errorf("Expected nil from Search on empty tree")
+		}
+		if tr.Index(0) != nil {
+			t.Errorf("Expected nil from Index on empty tree")
+		}
+		if tr.Delete(it.New(1)) || tr.DeleteAtHead() || tr.DeleteAtTail() {
+			t.Errorf("Expected false from Delete on empty tree")
+		}
+		if tr.Depth() != 0 {
+			t.Errorf("Expected Depth 0 on empty tree got %d", tr.Depth())
+		}
+		if x := tr.ConvertToSlice(); len(x) != 0 {
+			t.Errorf("Expected empty slice got %d items", len(x))
+		}
+	})
+
+	t.Run("Ordered", func(t *testing.T) {
+		const n = 100
+		rng := rand.New(rand.NewSource(seed))
+		tr := newFn()
+		for _, k := range rng.Perm(n) {
+			if !tr.Insert(it.New(k)) {
+				t.Errorf("Insert of %d should be new", k)
+			}
+		}
+		if tr.Insert(it.New(10)) {
+			t.Errorf("Insert of duplicate 10 should not be new")
+		}
+		if tr.Length() != n {
+			t.Errorf("Length error, expected %d got %d", n, tr.Length())
+		}
+
+		expect := make([]int, n)
+		for i := range expect {
+			expect[i] = i
+		}
+		if got := it.keys(tr.ConvertToSlice()); !equalInts(got, expect) {
+			t.Errorf("ConvertToSlice error, expected %v got %v", expect, got)
+		}
+		for i := 0; i < n; i++ {
+			if x := tr.Index(i); !it.is(x, i) {
+				t.Errorf("Index(%d) error got %v", i, x)
+			}
+			if x := tr.Search(it.New(i)); !it.is(x, i) {
+				t.Errorf("Search(%d) error got %v", i, x)
+			}
+		}
+		if tr.Index(-1) != nil || tr.Index(n) != nil {
+			t.Errorf("Expected nil from Index out of range")
+		}
+		if tr.Search(it.New(n+1)) != nil {
+			t.Errorf("Expected nil from Search for missing item")
+		}
+		if !it.is(tr.FindMin(), 0) || !it.is(tr.FindMax(), n-1) {
+			t.Errorf("FindMin/FindMax error got %v %v", tr.FindMin(), tr.FindMax())
+		}
+		if d := tr.Depth(); d < 1 || d > n {
+			t.Errorf("Depth error, got %d for %d items", d, n)
+		}
+
+		// Delete the even ones.
+		for i := 0; i < n; i += 2 {
+			if !tr.Delete(it.New(i)) {
+				t.Errorf("Delete(%d) should be found", i)
+			}
+		}
+		if tr.Delete(it.New(0)) {
+			t.Errorf("Second Delete(0) should not be found")
+		}
+		if tr.Length() != n/2 {
+			t.Errorf("Length after delete error, expected %d got %d", n/2, tr.Length())
+		}
+		for i := 0; i < n; i++ {
+			if found := tr.Search(it.New(i)) != nil; found != (i%2 == 1) {
+				t.Errorf("Search(%d) after delete, found=%v", i, found)
+			}
+		}
+
+		if !tr.DeleteAtHead() || !it.is(tr.FindMin(), 3) {
+			t.Errorf("DeleteAtHead error, new min %v", tr.FindMin())
+		}
+		if !tr.DeleteAtTail() || !it.is(tr.FindMax(), n-3) {
+			t.Errorf("DeleteAtTail error, new max %v", tr.FindMax())
+		}
+		if tr.Length() != n/2-2 {
+			t.Errorf("Length error, expected %d got %d", n/2-2, tr.Length())
+		}
+	})
+
+	t.Run("Model", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(seed))
+		tr := newFn()
+		model := make(map[int]bool)
+		for op := 0; op < nOps; op++ {
+			n := rng.Intn(keyRange)
+			switch rng.Intn(6) {
+			case 0, 1:
+				if isNew := tr.Insert(it.New(n)); isNew != !model[n] {
+					t.Fatalf("op %d: Insert(%d) returned %v", op, n, isNew)
+				}
+				model[n] = true
+			case 2:
+				if found := tr.Delete(it.New(n)); found != model[n] {
+					t.Fatalf("op %d: Delete(%d) returned %v", op, n, found)
+				}
+				delete(model, n)
+			case 3:
+				if found := tr.Search(it.New(n)); (found != nil) != model[n] || (found != nil && !it.is(found, n)) {
+					t.Fatalf("op %d: Search(%d) returned %v", op, n, found)
+				}
+			case 4:
+				keys := sortedKeys(model)
+				if found := tr.DeleteAtHead(); found != (len(keys) > 0) {
+					t.Fatalf("op %d: DeleteAtHead returned %v", op, found)
+				}
+				if len(keys) > 0 {
+					delete(model, keys[0])
+				}
+			case 5:
+				keys := sortedKeys(model)
+				if found := tr.DeleteAtTail(); found != (len(keys) > 0) {
+					t.Fatalf("op %d: DeleteAtTail returned %v", op, found)
+				}
+				if len(keys) > 0 {
+					delete(model, keys[len(keys)-1])
+				}
+			}
+			if tr.Length() != len(model) || tr.IsEmpty() != (len(model) == 0) {
+				t.Fatalf("op %d: Length expected %d got %d", op, len(model), tr.Length())
+			}
+			keys := sortedKeys(model)
+			if got := it.keys(tr.ConvertToSlice()); !equalInts(got, keys) {
+				t.Fatalf("op %d: tree expected %v got %v", op, keys, got)
+			}
+			if len(keys) > 0 {
+				if !it.is(tr.FindMin(), keys[0]) || !it.is(tr.FindMax(), keys[len(keys)-1]) {
+					t.Fatalf("op %d: FindMin/FindMax expected %d %d", op, keys[0], keys[len(keys)-1])
+				}
+				if i := rng.Intn(len(keys)); !it.is(tr.Index(i), keys[i]) {
+					t.Fatalf("op %d: Index(%d) expected %d", op, i, keys[i])
+				}
+			}
+		}
+	})
+}
+
+// TestTreeInvariant checks the rules that keep a tree balanced, that are different for each
+// kind of tree.  It does random Inserts and Deletes and calls `valid` on the tree every 250
+// operations, at the end, and after each of the items is deleted in turn.  The data in the
+// tree is checked by TestTree, `valid` only needs to check the tree's own rules.
+func TestTreeInvariant[T any](t *testing.T, newFn func() iface_list.TreeDataType[T], it Item[T], valid func(t *testing.T, tr iface_list.TreeDataType[T])) {
+	t.Helper()
+	const nSteps, nKeys = 5000, 500
+
+	rng := rand.New(rand.NewSource(seed))
+	tr := newFn()
+	valid(t, tr)
+	for op := 0; op < nSteps; op++ {
+		if n := rng.Intn(nKeys); rng.Intn(5) < 2 {
+			tr.Delete(it.New(n))
+		} else {
+			tr.Insert(it.New(n))
+		}
+		if op%250 == 0 {
+			valid(t, tr)
+		}
+	}
+	valid(t, tr)
+	for !tr.IsEmpty() {
+		tr.DeleteAtHead()
+		valid(t, tr)
+	}
+}
+
+// TestSet checks Insert, Search and Delete, that a duplicate Insert replaces the item,
+// and finishes with a randomized check against a map.
+func TestSet[T any](t *testing.T, newFn func() iface_list.SetDataType[T], it Item[T]) {
+	t.Helper()
+
+	t.Run("Container", func(t *testing.T) {
+		TestContainer(t, func() iface_list.Container { return newFn() }, func(c iface_list.Container, n int) {
+			c.(iface_list.SetDataType[T]).Insert(it.New(n))
+		})
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		st := newFn()
+		if st.Search(it.New(1)) != nil {
+			t.Errorf("Expected nil from Search on empty set")
+		}
+		if st.Delete(it.New(1)) {
+			t.Errorf("Expected false from Delete on empty set")
+		}
+	})
+
+	t.Run("InsertDelete", func(t *testing.T) {
+		const n = 100
+		st := newFn()
+		for i := 0; i < n; i++ {
+			st.Insert(it.New(i))
+		}
+		st.Insert(it.New(10))
+		if st.Length() != n {
+			t.Errorf("Duplicate Insert should replace, expected length %d got %d", n, st.Length())
+		}
+		for i := 0; i < n; i++ {
+			if x := st.Search(it.New(i)); !it.is(x, i) {
+				t.Errorf("Search(%d) error got %v", i, x)
+			}
+		}
+		if st.Search(it.New(n+1)) != nil {
+			t.Errorf("Expected nil from Search for missing item")
+		}
+		for i := 0; i < n; i += 2 {
+			if !st.Delete(it.New(i)) {
+				t.Errorf("Delete(%d) should be found", i)
+			}
+		}
+		if st.Delete(it.New(0)) {
+			t.Errorf("Second Delete(0) should not be found")
+		}
+		if st.Length() != n/2 {
+			t.Errorf("Length after delete error, expected %d got %d", n/2, st.Length())
+		}
+		for i := 0; i < n; i++ {
+			if found := st.Search(it.New(i)) != nil; found != (i%2 == 1) {
+				t.Errorf("Search(%d) after delete, found=%v", i, found)
+			}
+		}
+	})
+
+	t.Run("Model", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(seed))
+		st := newFn()
+		model := make(map[int]bool)
+		for op := 0; op < nOps; op++ {
+			n := rng.Intn(keyRange)
+			switch rng.Intn(3) {
+			case 0:
+				st.Insert(it.New(n))
+				model[n] = true
+			case 1:
+				if found := st.Delete(it.New(n)); found != model[n] {
+					t.Fatalf("op %d: Delete(%d) returned %v", op, n, found)
+				}
+				delete(model, n)
+			case 2:
+				if found := st.Search(it.New(n)); (found != nil) != model[n] || (found != nil && !it.is(found, n)) {
+					t.Fatalf("op %d: Search(%d) returned %v", op, n, found)
+				}
+			}
+			if st.Length() != len(model) || st.IsEmpty() != (len(model) == 0) {
+				t.Fatalf("op %d: Length expected %d got %d", op, len(model), st.Length())
+			}
+		}
+		for n := 0; n < keyRange; n++ {
+			if found := st.Search(it.New(n)) != nil; found != model[n] {
+				t.Errorf("Search(%d) at end, found=%v", n, found)
+			}
+		}
+	})
+}
+
+// TestPriorityQueue checks that Pop returns the items smallest first, duplicates included,
+// and finishes with a randomized check against a sorted slice.
+func TestPriorityQueue[T any](t *testing.T, newFn func() iface_list.PriorityQueueDataType[T], it Item[T]) {
+	t.Helper()
+
+	t.Run("Container", func(t *testing.T) {
+		TestContainer(t, func() iface_list.Container { return newFn() }, func(c iface_list.Container, n int) {
+			c.(iface_list.PriorityQueueDataType[T]).Insert(it.New(n))
+		})
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		pq := newFn()
+		if pq.Peek() != nil || pq.Pop() != nil {
+			t.Errorf("Expected nil from Peek/Pop on empty priority queue")
+		}
+	})
+
+	t.Run("Order", func(t *testing.T) {
+		const n = 100
+		rng := rand.New(rand.NewSource(seed))
+		pq := newFn()
+		var expect []int
+		for _, k := range rng.Perm(n) {
+			pq.Insert(it.New(k % (n / 2))) // every value twice
+			expect = append(expect, k%(n/2))
+		}
+		sort.Ints(expect)
+		if pq.Length() != n {
+			t.Errorf("Length error, expected %d got %d", n, pq.Length())
+		}
+		for i, e := range expect {
+			if x := pq.Peek(); !it.is(x, e) {
+				t.Fatalf("%d: Peek expected %d got %v", i, e, x)
+			}
+			if x := pq.Pop(); !it.is(x, e) {
+				t.Fatalf("%d: Pop expected %d got %v", i, e, x)
+			}
+		}
+		if !pq.IsEmpty() || pq.Pop() != nil {
+			t.Errorf("Expected empty priority queue after popping everything")
+		}
+	})
+
+	t.Run("Model", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(seed))
+		pq := newFn()
+		var model []int // kept sorted
+		for op := 0; op < nOps; op++ {
+			n := rng.Intn(keyRange)
+			switch rng.Intn(3) {
+			case 0, 1:
+				pq.Insert(it.New(n))
+				i := sort.SearchInts(model, n)
+				model = append(model, 0)
+				copy(model[i+1:], model[i:])
+				model[i] = n
+			case 2:
+				x := pq.Pop()
+				if len(model) == 0 {
+					if x != nil {
+						t.Fatalf("op %d: expected nil from Pop on empty priority queue", op)
+					}
+				} else {
+					if !it.is(x, model[0]) {
+						t.Fatalf("op %d: Pop expected %d got %v", op, model[0], x)
+					}
+					model = model[1:]
+				}
+			}
+			if pq.Length() != len(model) || pq.IsEmpty() != (len(model) == 0) {
+				t.Fatalf("op %d: Length expected %d got %d", op, len(model), pq.Length())
+			}
+			if len(model) > 0 && !it.is(pq.Peek(), model[0]) {
+				t.Fatalf("op %d: Peek expected %d", op, model[0])
+			}
+		}
+	})
+}
+
+// TestHasher checks that a hash table puts its items where the Hasher passed to `newFn`
+// says.  `bucketOf` returns the bucket that `x` is stored in, or -1 if it is not in the table.
+// With a Hasher that gives every item the same hash all of them go in one bucket and each is
+// still found and deleted, with a Hasher of the key they are spread over more than one.  The
+// HighBits Hasher sets bit 31 and the top bits, a bucket index made from it must not be negative.
+func TestHasher[T any](t *testing.T, newFn func(hh hashing.Hasher[T]) iface_list.SetDataType[T], it Item[T], bucketOf func(st iface_list.SetDataType[T], x *T) int) {
+	t.Helper()
+	const n = 40
+
+	tests := []struct {
+		name   string
+		hh     hashing.Hasher[T]
+		oneBkt bool
+	}{
+		{"Constant", hashing.HasherFunc[T](func(x T) uint64 { return 12345 << 1 }), true},
+		{"Key", hashing.HasherFunc[T](func(x T) uint64 { return uint64(it.Key(&x)) << 1 }), false},
+		{"HighBits", hashing.HasherFunc[T](func(x T) uint64 { return uint64(it.Key(&x))<<1 | 0xffffffff80000000 }), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			st := newFn(test.hh)
+			for i := 0; i < n; i++ {
+				st.Insert(it.New(i))
+			}
+			if st.Length() != n {
+				t.Errorf("Expected length %d got %d", n, st.Length())
+			}
+			bkts := make(map[int]bool)
+			for i := 0; i < n; i++ {
+				x := st.Search(it.New(i))
+				if !it.is(x, i) {
+					t.Fatalf("Search(%d) error got %v", i, x)
+				}
+				b := bucketOf(st, x)
+				if b < 0 {
+					t.Fatalf("Search(%d) found an item that is not in a bucket", i)
+				}
+				bkts[b] = true
+			}
+			if test.oneBkt && len(bkts) != 1 {
+				t.Errorf("Expected all the items in 1 bucket got %d buckets", len(bkts))
+			}
+			if !test.oneBkt && len(bkts) < 2 {
+				t.Errorf("Expected the items to be spread over more than 1 bucket")
+			}
+			if st.Search(it.New(n+1)) != nil {
+				t.Errorf("Expected nil from Search for missing item")
+			}
+			for i := 0; i < n; i += 2 {
+				if !st.Delete(it.New(i)) {
+					t.Errorf("Delete(%d) should be found", i)
+				}
+			}
+			if st.Length() != n/2 {
+				t.Errorf("Length after delete error, expected %d got %d", n/2, st.Length())
+			}
+			for i := 0; i < n; i++ {
+				if found := st.Search(it.New(i)) != nil; found != (i%2 == 1) {
+					t.Errorf("Search(%d) after delete, found=%v", i, found)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package dag
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/pschlump/pluto/containertest"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// setAdapter makes the vertices of a graph, which are passed by value, look like a
+// SetDataType, which passes pointers.
+type setAdapter struct {
+	*DirectedAcyclicGraph[TestTreeNode]
+}
+
+func (sa setAdapter) Insert(data *TestTreeNode)      { sa.DirectedAcyclicGraph.Insert(*data) }
+func (sa setAdapter) Delete(data *TestTreeNode) bool { return sa.DirectedAcyclicGraph.Delete(*data) }
+func (sa setAdapter) Search(data *TestTreeNode) *TestTreeNode {
+	return sa.DirectedAcyclicGraph.Search(*data)
+}
+
+var testItem = containertest.Item[TestTreeNode]{
+	New: func(n int) *TestTreeNode { return &TestTreeNode{S: fmt.Sprintf("%04d", n)} },
+	Key: func(x *TestTreeNode) int { n, _ := strconv.Atoi(x.S); return n },
+}
+
+func TestConformance(t *testing.T) {
+	containertest.TestSet(t, func() iface_list.SetDataType[TestTreeNode] {
+		return setAdapter{NewDirectedAcyclicGraph[TestTreeNode]()}
+	}, testItem)
+}
@@ -10,463 +10,482 @@ BSD 3 Clause Licensed.
 
 Basic operations on a Directed Acyclic Graph (DAG).
 
-* 	Delete — Deletes a specified element from the linked list (Element can be fond via Search). O(log|2(n))
-* 	IsEmpty — Returns true if the linked list is empty											O(1)
-* 	Length — Returns number of elements in the list.  0 length is an empty list.				O(1)
-* 	Search — Returns the given element from a linked list.  Search is from head to tail.		O(log|2(n))
-* 	Truncate - Delete all the nodes in list. 													O(1)
+Vertices are kept in a slice that is sorted by `Compare` so that a vertex can be found by
+binary search and so that every operation that returns more than one vertex returns them
+in a deterministic order.  Each vertex keeps a sorted list of its successors (out edges)
+and its predecessors (in edges).
+
+* 	Insert — Adds a vertex to the graph.  A duplicate replaces the data in the vertex,		O(n)
+*		the edges of the vertex are kept.
+* 	Delete — Deletes a vertex and all of the edges to and from it.							O(n)
+* 	AddEdge — Adds an edge from -> to.  An error is returned if the edge would create a		O(n+e)
+*		cycle or if either vertex is not in the graph.  AddWeightedEdge sets a weight.
+* 	RemoveEdge — Removes the edge from -> to.													O(d)
+* 	Successors — Returns the vertices that have an edge from the vertex.						O(log|2(n))
+* 	Predecessors — Returns the vertices that have an edge to the vertex.						O(log|2(n))
+* 	InDegree, OutDegree — Number of edges to/from a vertex.									O(log|2(n))
+* 	IsEmpty — Returns true if the graph has no vertices										O(1)
+* 	Length — Returns number of vertices in the graph.  0 length is an empty graph.			O(1)
+* 	Search — Returns the given vertex data from the graph.									O(log|2(n))
+* 	Truncate - Delete all the vertices and edges in the graph.									O(1)
+*	FindMin, FindMax - smallest and largest vertex by Compare.								O(1)
+*	Index - return the Nth vertex in Compare order.											O(1)
+*	Reverse - Reverse the direction of every edge in the graph.								O(n+e)
+*	Depth - Number of vertices on the longest path in the graph.								O((n+e) log|2(n))
+*	TopologicalSort - All vertices, each before the vertices it has an edge to.				O((n+e) log|2(n))
+*	Layers - Vertices in groups that can be run in parallel.									O((n+e) log|2(n))
+*	Reachable, Ancestors, Descendants - What can reach, or be reached from, a vertex.		O(n+e)
+*	TransitiveClosure, TransitiveReduction - New graph with the most/fewest edges.			O(n*(n+e))
+*	ShortestPath, LongestPath - Path between 2 vertices by the sum of the edge weights.		O((n+e) log|2(n))
+*	CriticalPath - The path in the graph with the largest sum of the edge weights.			O((n+e) log|2(n))
 
 The Depth First Search is a composite data structure.  It uses the Go builtin "map" to mark the
 nodes that have been visited and the ../stack to collect the nodes that are stil to be visited.
-The algorythm is a non-recursive depth-first search.
+The algorythm is a non-recursive depth-first search.  The Breadth First Search is the same
+but uses ../queue in place of the stack.
 
-	WalkDepthFirst
-
-	InsertNode ( Node, ChildOf )
-	InsertEdge ( Node, ToNode )
+	WalkDepthFirst ( start, fx, userData ), DepthFirst ( start ) iter.Seq
+	WalkBreadthFirst ( start, fx, userData ), BreadthFirst ( start ) iter.Seq
 
 */
 
 import (
+	"errors"
 	"fmt"
 	"io"
-	"strings"
+	"sort"
 
 	"github.com/pschlump/pluto/comparable"
-	"github.com/pschlump/pluto/g_lib"
+	"github.com/pschlump/pluto/stack"
 	// "github.com/pschlump/dbgo"
 	// "github.com/pschlump/MiscLib"
 )
 
+// DirectedAcyclicGraphNode is a single vertex in the graph with its edges.
 type DirectedAcyclicGraphNode[T comparable.Comparable] struct {
-	data        *T
-	left, right *DirectedAcyclicGraphNode[T] // to be removed.
-	neighbor    *[]DirectedAcyclicGraphNode[T]
+	data         *T
+	successors   []*DirectedAcyclicGraphNode[T] // Edges from this node, sorted by Compare
+	predecessors []*DirectedAcyclicGraphNode[T] // Edges to this node, sorted by Compare
+}
+
+// dagEdge is the key used to find the weight of an edge.
+type dagEdge[T comparable.Comparable] struct {
+	from, to *DirectedAcyclicGraphNode[T]
 }
 
-// DirectedAcyclicGraph is a generic binary tree
+// DirectedAcyclicGraph is a generic directed graph that will not allow a cycle to be created.
 type DirectedAcyclicGraph[T comparable.Comparable] struct {
-	root   *DirectedAcyclicGraphNode[T]
-	length int // Number of Nodes in Graph
+	nodes   []*DirectedAcyclicGraphNode[T] // All vertices sorted by Compare
+	nEdges  int                            // Number of Edges in Graph
+	weights map[dagEdge[T]]float64         // Weight of each edge
 }
 
-// IsEmpty will return true if the binary-tree is empty
+// An error to indicate that a vertex is not in the graph.
+var ErrNotFound = errors.New("Vertex Not Found")
+
+// An error to indicate that the graph has, or an edge would have created, a cycle.
+// The error returned is a *CycleError that names the vertices; use errors.Is to test for it.
+var ErrCycle = errors.New("Cycle in graph")
+
+// -------------------------------------------------------------------------------------------------------
+
+// NewDirectedAcyclicGraph creates an empty graph and returns it.
+func NewDirectedAcyclicGraph[T comparable.Comparable]() *DirectedAcyclicGraph[T] {
+	return &DirectedAcyclicGraph[T]{}
+}
+
+// GetData returns the data stored in the vertex.
+func (nn *DirectedAcyclicGraphNode[T]) GetData() *T {
+	return nn.data
+}
+
+// IsEmpty will return true if the graph has no vertices.
 func (tt DirectedAcyclicGraph[T]) IsEmpty() bool {
-	return tt.root == nil
+	return len(tt.nodes) == 0
 }
 
-// Truncate removes all data from the tree.
+// Truncate removes all vertices and edges from the graph.
 func (tt *DirectedAcyclicGraph[T]) Truncate() {
-	(*tt).root = nil
-	(*tt).length = 0
+	(*tt).nodes = nil
+	(*tt).nEdges = 0
+	(*tt).weights = nil
 }
 
-// Insert will add a new item to the tree.  If it is a duplicate of an exiting
-// item the new item will replace the existing one.
+// nlFind does a binary search for `find` and returns the position it is at or the
+// position it should be inserted at, and true if it was found.
+func (tt *DirectedAcyclicGraph[T]) nlFind(find T) (pos int, found bool) {
+	pos = sort.Search(len(tt.nodes), func(i int) bool {
+		return find.Compare(*(tt.nodes[i].data)) <= 0
+	})
+	found = pos < len(tt.nodes) && find.Compare(*(tt.nodes[pos].data)) == 0
+	return
+}
+
+// nlNode returns the vertex for `find` or nil if it is not in the graph.
+func (tt *DirectedAcyclicGraph[T]) nlNode(find T) *DirectedAcyclicGraphNode[T] {
+	if pos, found := tt.nlFind(find); found {
+		return tt.nodes[pos]
+	}
+	return nil
+}
+
+// Insert will add a new vertex to the graph.  If it is a duplicate of an exiting
+// vertex the new data will replace the existing data, the edges are kept.
 func (tt *DirectedAcyclicGraph[T]) Insert(item T) {
 	if tt == nil {
-		panic("tree sholud not be a nil")
+		panic("graph sholud not be a nil")
 	}
-	node := &DirectedAcyclicGraphNode[T]{data: &item}
-	node.left = nil
-	node.right = nil
-	if (*tt).IsEmpty() {
-		tt.root = node
-		tt.length = 1
+	pos, found := tt.nlFind(item)
+	if found {
+		tt.nodes[pos].data = &item
 		return
 	}
-
-	// Simple is recursive, can be replce with an iterative tree traversal.
-	var insert func(root **DirectedAcyclicGraphNode[T])
-	insert = func(root **DirectedAcyclicGraphNode[T]) {
-		if *root == nil {
-			*root = node
-			tt.length++
-			// } else if c := (*(node.data)).Compare( (*root).data ); c == 0 {
-		} else if c := item.Compare(*((*root).data)); c == 0 {
-			(*root) = node
-		} else if c < 0 {
-			insert(&((*root).left))
-		} else {
-			insert(&((*root).right))
-		}
-	}
-
-	insert(&((*tt).root))
+	node := &DirectedAcyclicGraphNode[T]{data: &item}
+	tt.nodes = append(tt.nodes, nil)
+	copy(tt.nodes[pos+1:], tt.nodes[pos:])
+	tt.nodes[pos] = node
 }
 
-// Length returns the number of elements in the list.
+// Length returns the number of vertices in the graph.
 func (tt *DirectedAcyclicGraph[T]) Length() int {
-	return (*tt).length
+	return len((*tt).nodes)
+}
+
+// EdgeLength returns the number of edges in the graph.
+func (tt *DirectedAcyclicGraph[T]) EdgeLength() int {
+	return (*tt).nEdges
 }
 
-// Search will walk the tree looking for `find` and retrn the found item
-// if it is in the tree. If it is not found then `nil` will be returned.
+// Search will look for `find` and retrn the found vertex data if it is in the graph.
+// If it is not found then `nil` will be returned.
 func (tt *DirectedAcyclicGraph[T]) Search(find T) (item *T) {
 	if tt == nil {
-		panic("tree sholud not be a nil")
+		panic("graph sholud not be a nil")
 	}
-	if (*tt).IsEmpty() {
-		return nil
+	if node := tt.nlNode(find); node != nil {
+		return node.data
 	}
+	return nil
+}
 
-	// Iterative search through tree (can be used above)
-	cur := tt.root
-	for tt != nil {
-		c := find.Compare(*cur.data)
-		if c == 0 {
-			item = cur.data
-			return
-		}
-		if c < 0 && cur.left != nil {
-			cur = (*cur).left
-		} else if c > 0 && cur.right != nil {
-			cur = (*cur).right
-		} else {
-			break
+// insertNode adds `node` to the sorted list `list` if it is not already in it.
+func insertNode[T comparable.Comparable](list []*DirectedAcyclicGraphNode[T], node *DirectedAcyclicGraphNode[T]) ([]*DirectedAcyclicGraphNode[T], bool) {
+	pos := sort.Search(len(list), func(i int) bool {
+		return (*node.data).Compare(*(list[i].data)) <= 0
+	})
+	if pos < len(list) && list[pos] == node {
+		return list, false
+	}
+	list = append(list, nil)
+	copy(list[pos+1:], list[pos:])
+	list[pos] = node
+	return list, true
+}
+
+// removeNode takes `node` out of the list `list`.
+func removeNode[T comparable.Comparable](list []*DirectedAcyclicGraphNode[T], node *DirectedAcyclicGraphNode[T]) ([]*DirectedAcyclicGraphNode[T], bool) {
+	for i, nn := range list {
+		if nn == node {
+			return append(list[:i], list[i+1:]...), true
 		}
 	}
+	return list, false
+}
+
+// AddEdge adds an edge from the vertex `from` to the vertex `to` with a weight of 1.  Both
+// vertices must already be in the graph or ErrNotFound is returned.  If the edge would
+// create a cycle then a *CycleError is returned and the graph is not changed.  Adding an
+// edge that already exists is not an error.
+func (tt *DirectedAcyclicGraph[T]) AddEdge(from, to T) error {
+	return tt.AddWeightedEdge(from, to, 1)
+}
+
+// AddWeightedEdge is AddEdge with a weight on the edge.  The weight is used by ShortestPath,
+// LongestPath and CriticalPath.  Adding an edge that already exists changes its weight.
+func (tt *DirectedAcyclicGraph[T]) AddWeightedEdge(from, to T, weight float64) error {
+	if tt == nil {
+		panic("graph sholud not be a nil")
+	}
+	fromNode, toNode := tt.nlNode(from), tt.nlNode(to)
+	if fromNode == nil || toNode == nil {
+		return ErrNotFound
+	}
+	if path := tt.nlPath(toNode, fromNode); path != nil {
+		return &CycleError[T]{Vertices: nodeData(path)}
+	}
+	tt.nlAddEdge(fromNode, toNode, weight)
 	return nil
 }
 
-// Dump will print out the tree to the file `fo`.
-func (tt *DirectedAcyclicGraph[T]) Dump(fo io.Writer) {
-	var inorderTraversal func(cur *DirectedAcyclicGraphNode[T], n int, fo io.Writer)
-	inorderTraversal = func(cur *DirectedAcyclicGraphNode[T], n int, fo io.Writer) {
-		if cur == nil {
-			return
-		}
-		if (*cur).left != nil {
-			inorderTraversal((*cur).left, n+1, fo)
-		}
-		fmt.Fprintf(fo, "%s%v%s (left=%p/%p, right=%p/%p) self=%p\n", strings.Repeat(" ", 4*n), *((*cur).data), strings.Repeat(" ", 20-(4*n)), (*cur).left, &((*cur).left), (*cur).right, &((*cur).right), cur)
-		if (*cur).right != nil {
-			inorderTraversal((*cur).right, n+1, fo)
-		}
+// nlAddEdge links `fromNode` to `toNode` without checking for a cycle.
+func (tt *DirectedAcyclicGraph[T]) nlAddEdge(fromNode, toNode *DirectedAcyclicGraphNode[T], weight float64) {
+	var added bool
+	fromNode.successors, added = insertNode(fromNode.successors, toNode)
+	if added {
+		toNode.predecessors, _ = insertNode(toNode.predecessors, fromNode)
+		tt.nEdges++
 	}
-	inorderTraversal(tt.root, 0, fo)
+	if tt.weights == nil {
+		tt.weights = make(map[dagEdge[T]]float64)
+	}
+	tt.weights[dagEdge[T]{from: fromNode, to: toNode}] = weight
 }
 
-func (tt *DirectedAcyclicGraph[T]) Delete(find T) (found bool) {
+// EdgeWeight returns the weight of the edge from `from` to `to` and true, or false if
+// there is no such edge.
+func (tt *DirectedAcyclicGraph[T]) EdgeWeight(from, to T) (weight float64, found bool) {
+	fromNode, toNode := tt.nlNode(from), tt.nlNode(to)
+	if fromNode == nil || toNode == nil {
+		return 0, false
+	}
+	weight, found = tt.weights[dagEdge[T]{from: fromNode, to: toNode}]
+	return
+}
+
+// RemoveEdge removes the edge from the vertex `from` to the vertex `to`.  It returns
+// true if the edge was found.
+func (tt *DirectedAcyclicGraph[T]) RemoveEdge(from, to T) (found bool) {
 	if tt == nil {
-		panic("tree sholud not be a nil")
+		panic("graph sholud not be a nil")
 	}
-	if (*tt).IsEmpty() {
+	fromNode, toNode := tt.nlNode(from), tt.nlNode(to)
+	if fromNode == nil || toNode == nil {
 		return false
 	}
+	fromNode.successors, found = removeNode(fromNode.successors, toNode)
+	if found {
+		toNode.predecessors, _ = removeNode(toNode.predecessors, fromNode)
+		delete(tt.weights, dagEdge[T]{from: fromNode, to: toNode})
+		tt.nEdges--
+	}
+	return
+}
 
-	findLeftMostInRightSubtree := func(parent **DirectedAcyclicGraphNode[T]) (found bool, pAtIt **DirectedAcyclicGraphNode[T]) {
-		// fmt.Printf ( "%sFindLeftMost/At Top: at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
-		this := **parent
-		if *parent == nil {
-			// fmt.Printf ( "%sFindLeftMost/no tree: at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
-			return
-		}
-		for this.right != nil {
-			// fmt.Printf ( "%sAdvance 1 step. at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
-			parent = &(this.right)
-			this = **parent
+// HasEdge returns true if there is an edge from the vertex `from` to the vertex `to`.
+func (tt *DirectedAcyclicGraph[T]) HasEdge(from, to T) bool {
+	fromNode, toNode := tt.nlNode(from), tt.nlNode(to)
+	if fromNode == nil || toNode == nil {
+		return false
+	}
+	for _, nn := range fromNode.successors {
+		if nn == toNode {
+			return true
 		}
-		// fmt.Printf ( "%sat bottom at:%s%s\n", MiscLib.ColorCyan, dbgo.LF(), MiscLib.ColorReset)
-		found = true
-		pAtIt = parent
-		return
 	}
+	return false
+}
 
-	// Iterative search through tree (can be used above)
-	cur := &tt.root // ptr to ptr to tree
-	for tt != nil {
-		// fmt.Printf ( "at:%s\n", dbgo.LF())
-		c := find.Compare(*(*cur).data)
-		if c == 0 {
-			// fmt.Printf ( "FOUND! now remove it! at:%s\n", dbgo.LF())
-			(*tt).length--
-			if (*cur).left == nil && (*cur).right == nil {
-				// fmt.Printf ( "at:%s\n", dbgo.LF())
-				(*cur) = nil // just delete the node, it has no children.
-			} else if (*cur).left != nil && (*cur).right == nil {
-				// fmt.Printf ( "at:%s\n", dbgo.LF())
-				(*cur) = (*cur).left // Has only left children, promote them.
-			} else if (*cur).left == nil && (*cur).right != nil {
-				// fmt.Printf ( "at:%s\n", dbgo.LF())
-				(*cur) = (*cur).right // Has only right children, promote them.
-			} else { // has both children.
-				// fmt.Printf ( "at:%s\n", dbgo.LF())
-				// Has only right children, promote them.
-				found, pAtIt := findLeftMostInRightSubtree(&((*cur).right)) // Find lft mos of right sub-tree
-				if !found {
-					// fmt.Printf ( "%sAbout to Panic: Failed to have a subtree. AT:%s%s\n", MiscLib.ColorRed, dbgo.LF(), MiscLib.ColorReset)
-					panic("Can't have a missing sub-tree.")
-				}
-				// fmt.Printf ( "at:%s\n", dbgo.LF())
-				(*cur).data = (*pAtIt).data // promote node's data.
-				// fmt.Printf ( "at:%s\n", dbgo.LF())
-				(*pAtIt) = (*pAtIt).right // Left most can have a right sub-tree - but it is left most so it can't have a more left tree.
-				// fmt.Printf ( "at:%s\n", dbgo.LF())
+// nlPath is a non-recursive depth-first search that returns the vertices on a path from
+// `from` to `to`, or nil if `to` can not be reached from `from` by following edges.
+func (tt *DirectedAcyclicGraph[T]) nlPath(from, to *DirectedAcyclicGraphNode[T]) (path []*DirectedAcyclicGraphNode[T]) {
+	parent := make(map[*DirectedAcyclicGraphNode[T]]*DirectedAcyclicGraphNode[T])
+	parent[from] = nil
+	var stk stack.Stack[*DirectedAcyclicGraphNode[T]]
+	stk.Push(from)
+	for !stk.IsEmpty() {
+		cur, _ := stk.Pop()
+		if cur == to {
+			for ; cur != nil; cur = parent[cur] {
+				path = append(path, cur)
 			}
-			return true
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return
 		}
-		// fmt.Printf ( "at:%s\n", dbgo.LF())
-		if c < 0 && (*cur).left != nil {
-			// fmt.Printf ( "Go Left at:%s\n", dbgo.LF())
-			cur = &((*cur).left)
-		} else if c > 0 && (*cur).right != nil {
-			// fmt.Printf ( "Go Right at:%s\n", dbgo.LF())
-			cur = &((*cur).right)
-		} else {
-			// fmt.Printf ( "not found - in loop - at:%s\n", dbgo.LF())
-			break
+		for _, nn := range cur.successors {
+			if _, seen := parent[nn]; !seen {
+				parent[nn] = cur
+				stk.Push(nn)
+			}
 		}
 	}
-	// fmt.Printf ( "NOT Found --- at:%s\n", dbgo.LF())
-	return false
+	return nil
 }
 
-/*
-        {00}
-    {02}
-        {03}
-{05}
-    {09}
-*/
+// nodeData converts a list of nodes into the list of data in them.
+func nodeData[T comparable.Comparable](list []*DirectedAcyclicGraphNode[T]) (rv []*T) {
+	rv = make([]*T, 0, len(list))
+	for _, nn := range list {
+		rv = append(rv, nn.data)
+	}
+	return
+}
 
-func (tt *DirectedAcyclicGraph[T]) FindMin() (item *T) {
+// Successors returns the vertices that `item` has an edge to, in Compare order.
+// If `item` is not in the graph then nil is returned.
+func (tt *DirectedAcyclicGraph[T]) Successors(item T) []*T {
+	if node := tt.nlNode(item); node != nil {
+		return nodeData(node.successors)
+	}
+	return nil
+}
+
+// Predecessors returns the vertices that have an edge to `item`, in Compare order.
+// If `item` is not in the graph then nil is returned.
+func (tt *DirectedAcyclicGraph[T]) Predecessors(item T) []*T {
+	if node := tt.nlNode(item); node != nil {
+		return nodeData(node.predecessors)
+	}
+	return nil
+}
+
+// InDegree returns the number of edges to `item`.  0 is returned if `item` is not in the graph.
+func (tt *DirectedAcyclicGraph[T]) InDegree(item T) int {
+	if node := tt.nlNode(item); node != nil {
+		return len(node.predecessors)
+	}
+	return 0
+}
+
+// OutDegree returns the number of edges from `item`.  0 is returned if `item` is not in the graph.
+func (tt *DirectedAcyclicGraph[T]) OutDegree(item T) int {
+	if node := tt.nlNode(item); node != nil {
+		return len(node.successors)
+	}
+	return 0
+}
+
+// Dump will print out the graph to the file `fo`, one vertex per line followed by the
+// vertices that it has edges to.
+func (tt *DirectedAcyclicGraph[T]) Dump(fo io.Writer) {
+	for _, nn := range tt.nodes {
+		fmt.Fprintf(fo, "%v ->", *(nn.data))
+		for _, ss := range nn.successors {
+			fmt.Fprintf(fo, " %v", *(ss.data))
+		}
+		fmt.Fprintf(fo, "\n")
+	}
+}
+
+// Delete removes the vertex `find` and all the edges to and from it.  It returns true
+// if the vertex was found.
+func (tt *DirectedAcyclicGraph[T]) Delete(find T) (found bool) {
 	if tt == nil {
-		panic("tree sholud not be a nil")
+		panic("graph sholud not be a nil")
 	}
-	if (*tt).IsEmpty() {
-		return nil
+	pos, found := tt.nlFind(find)
+	if !found {
+		return false
 	}
+	tt.nlDeleteAt(pos)
+	return true
+}
 
-	// Iterative search through tree (can be used above)
-	cur := tt.root
-	if (*cur).left == nil {
-		return (*cur).data
+// nlDeleteAt removes the vertex at `pos` in the sorted list of vertices.
+func (tt *DirectedAcyclicGraph[T]) nlDeleteAt(pos int) {
+	node := tt.nodes[pos]
+	for _, nn := range node.successors {
+		nn.predecessors, _ = removeNode(nn.predecessors, node)
+		delete(tt.weights, dagEdge[T]{from: node, to: nn})
 	}
-	for cur.left != nil {
-		cur = (*cur).left
+	for _, nn := range node.predecessors {
+		nn.successors, _ = removeNode(nn.successors, node)
+		delete(tt.weights, dagEdge[T]{from: nn, to: node})
 	}
-	return (*cur).data
+	tt.nEdges -= len(node.successors) + len(node.predecessors)
+	tt.nodes = append(tt.nodes[:pos], tt.nodes[pos+1:]...)
 }
 
-func (tt *DirectedAcyclicGraph[T]) FindMax() (item *T) {
+// FindMin returns the smallest vertex by Compare, or nil if the graph is empty.
+func (tt *DirectedAcyclicGraph[T]) FindMin() (item *T) {
 	if tt == nil {
-		panic("tree sholud not be a nil")
+		panic("graph sholud not be a nil")
 	}
 	if (*tt).IsEmpty() {
 		return nil
 	}
+	return tt.nodes[0].data
+}
 
-	// Iterative search through tree (can be used above)
-	cur := tt.root
-	if (*cur).right == nil {
-		return (*cur).data
+// FindMax returns the largest vertex by Compare, or nil if the graph is empty.
+func (tt *DirectedAcyclicGraph[T]) FindMax() (item *T) {
+	if tt == nil {
+		panic("graph sholud not be a nil")
 	}
-	for cur.right != nil {
-		cur = (*cur).right
+	if (*tt).IsEmpty() {
+		return nil
 	}
-	return (*cur).data
+	return tt.nodes[len(tt.nodes)-1].data
 }
 
+// DeleteAtHead removes the smallest vertex by Compare.
 func (tt *DirectedAcyclicGraph[T]) DeleteAtHead() (found bool) {
 	if tt == nil {
-		panic("tree sholud not be a nil")
+		panic("graph sholud not be a nil")
 	}
 	if (*tt).IsEmpty() {
 		return false
 	}
-
-	x := tt.FindMin()
-	tt.Delete(*x)
+	tt.nlDeleteAt(0)
 	return true
 }
 
+// DeleteAtTail removes the largest vertex by Compare.
 func (tt *DirectedAcyclicGraph[T]) DeleteAtTail() (found bool) {
 	if tt == nil {
-		panic("tree sholud not be a nil")
+		panic("graph sholud not be a nil")
 	}
 	if (*tt).IsEmpty() {
 		return false
 	}
-
-	x := tt.FindMax()
-	tt.Delete(*x)
+	tt.nlDeleteAt(len(tt.nodes) - 1)
 	return true
 }
 
+// Reverse changes the direction of every edge in the graph, the weights stay with the edges.
+// A graph without cycles still has no cycles after this.
 func (tt *DirectedAcyclicGraph[T]) Reverse() {
 	if tt == nil {
-		panic("tree sholud not be a nil")
+		panic("graph sholud not be a nil")
 	}
-	if (*tt).IsEmpty() {
-		return
+	for _, nn := range tt.nodes {
+		nn.successors, nn.predecessors = nn.predecessors, nn.successors
 	}
-
-	var postTraversal func(cur *DirectedAcyclicGraphNode[T])
-	postTraversal = func(cur *DirectedAcyclicGraphNode[T]) {
-		if cur == nil {
-			return
-		}
-		if (*cur).left != nil {
-			postTraversal((*cur).left)
-		}
-		if (*cur).right != nil {
-			postTraversal((*cur).right)
-		}
-		(*cur).left, (*cur).right = (*cur).right, (*cur).left
+	weights := make(map[dagEdge[T]]float64, len(tt.weights))
+	for ee, ww := range tt.weights {
+		weights[dagEdge[T]{from: ee.to, to: ee.from}] = ww
 	}
-	postTraversal(tt.root)
+	tt.weights = weights
 }
 
+// Index returns the vertex at `pos` in Compare order, or nil if `pos` is out of range.
 func (tt *DirectedAcyclicGraph[T]) Index(pos int) (item *T) {
 	if tt == nil {
-		panic("tree sholud not be a nil")
+		panic("graph sholud not be a nil")
 	}
-	if (*tt).IsEmpty() {
+	if pos < 0 || pos >= len(tt.nodes) {
 		return nil
 	}
-
-	if pos < 0 || pos >= tt.length {
-		return nil
-	}
-
-	var n = 0
-	var done = false
-	var inorderTraversal func(cur *DirectedAcyclicGraphNode[T])
-	inorderTraversal = func(cur *DirectedAcyclicGraphNode[T]) {
-		if cur == nil {
-			return
-		}
-		if !done {
-			if (*cur).left != nil {
-				inorderTraversal((*cur).left)
-			}
-		}
-		// fmt.Printf ( "InOrder - Before Set, Top n=%d, pos=%d,    value=%+v     at:%s\n", n, pos, item, dbgo.LF() )
-		if n == pos {
-			item = (*cur).data
-			// fmt.Printf ( "*********** Set \n")
-			done = true
-		}
-		n++
-		if !done {
-			if (*cur).right != nil {
-				inorderTraversal((*cur).right)
-			}
-		}
-	}
-	inorderTraversal(tt.root)
-	return
+	return tt.nodes[pos].data
 }
 
+// Depth returns the number of vertices on the longest path in the graph.
 func (tt *DirectedAcyclicGraph[T]) Depth() (d int) {
 	if tt == nil {
-		panic("tree sholud not be a nil")
+		panic("graph sholud not be a nil")
 	}
-	if (*tt).IsEmpty() {
+
+	levels, err := tt.nlLevels()
+	if err != nil {
 		return 0
 	}
-
-	d = 0
-	var inorderTraversal func(cur *DirectedAcyclicGraphNode[T])
-	inorderTraversal = func(cur *DirectedAcyclicGraphNode[T]) {
-		if cur == nil {
-			return
-		}
-		if (*cur).left != nil {
-			inorderTraversal((*cur).left)
-			d = g_lib.Max[int](d, d+1)
+	for _, lv := range levels {
+		if lv+1 > d {
+			d = lv + 1
 		}
-		if (*cur).right != nil {
-			inorderTraversal((*cur).right)
-			d = g_lib.Max[int](d, d+1)
-		}
-	}
-	if tt.root != nil {
-		inorderTraversal(tt.root)
 	}
 	return
 }
 
 type ApplyFunction[T comparable.Comparable] func(pos, depth int, data *T, userData interface{}) bool
 
+// WalkInOrder calls `fx` on each vertex in Compare order, ignoring the edges.  The depth
+// passed is always 0.  The walk stops if `fx` returns false.
 func (tt *DirectedAcyclicGraph[T]) WalkInOrder(fx ApplyFunction[T], userData interface{}) {
-
-	p := 0
-	b := true
-	var inorderTraversal func(cur *DirectedAcyclicGraphNode[T], n int)
-	inorderTraversal = func(cur *DirectedAcyclicGraphNode[T], n int) {
-		if cur == nil {
+	for p, nn := range tt.nodes {
+		if !fx(p, 0, nn.data, userData) {
 			return
 		}
-		if b {
-			if (*cur).left != nil {
-				inorderTraversal((*cur).left, n+1)
-			}
-		}
-		// ----------------------------------------------------------------------
-		b = b && fx(p, n, (*cur).data, userData)
-		p++
-		// ----------------------------------------------------------------------
-		if b {
-			if (*cur).right != nil {
-				inorderTraversal((*cur).right, n+1)
-			}
-		}
-	}
-	inorderTraversal(tt.root, 0)
-}
-
-func (tt *DirectedAcyclicGraph[T]) WalkPreOrder(fx ApplyFunction[T], userData interface{}) {
-
-	p := 0
-	b := true
-	var preOrderTraversal func(cur *DirectedAcyclicGraphNode[T], n int)
-	preOrderTraversal = func(cur *DirectedAcyclicGraphNode[T], n int) {
-		if cur == nil {
-			return
-		}
-		// ----------------------------------------------------------------------
-		b = b && fx(p, n, (*cur).data, userData)
-		// ----------------------------------------------------------------------
-		if b {
-			if (*cur).left != nil {
-				preOrderTraversal((*cur).left, n+1)
-			}
-		}
-		p++
-		if b {
-			if (*cur).right != nil {
-				preOrderTraversal((*cur).right, n+1)
-			}
-		}
-	}
-	preOrderTraversal(tt.root, 0)
-}
-
-func (tt *DirectedAcyclicGraph[T]) WalkPostOrder(fx ApplyFunction[T], userData interface{}) {
-
-	p := 0
-	b := true
-	var postOrderTraversal func(cur *DirectedAcyclicGraphNode[T], n int)
-	postOrderTraversal = func(cur *DirectedAcyclicGraphNode[T], n int) {
-		if cur == nil {
-			return
-		}
-		if b {
-			if (*cur).left != nil {
-				postOrderTraversal((*cur).left, n+1)
-			}
-		}
-		p++
-		if b {
-			if (*cur).right != nil {
-				postOrderTraversal((*cur).right, n+1)
-			}
-		}
-		// ----------------------------------------------------------------------
-		b = b && fx(p, n, (*cur).data, userData)
-		// ----------------------------------------------------------------------
 	}
-	postOrderTraversal(tt.root, 0)
 }
@@ -7,6 +7,7 @@ BSD 3 Clause Licensed.
 */
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -330,7 +331,16 @@ func TestTreeDepth(t *testing.T) {
 	}
 
 	n := Tree1.Depth()
-	if n == 3 {
+	if n != 1 {
+		t.Errorf("Unexpecd Depth, got %d expected 1", n)
+	}
+
+	// 05 -> 02 -> 00, 05 -> 09
+	Tree1.AddEdge(TestTreeNode{S: "05"}, TestTreeNode{S: "02"})
+	Tree1.AddEdge(TestTreeNode{S: "02"}, TestTreeNode{S: "00"})
+	Tree1.AddEdge(TestTreeNode{S: "05"}, TestTreeNode{S: "09"})
+	n = Tree1.Depth()
+	if n != 3 {
 		t.Errorf("Unexpecd Depth, got %d expected 3", n)
 	}
 }
@@ -452,11 +462,6 @@ func TestTreeDeleteAtHead(t *testing.T) {
 	}
 }
 
-/*
-func (tt *DirectedAcyclicGraph[T]) WalkPreOrder(fx ApplyFunction[T], userData interface{}) {
-func (tt *DirectedAcyclicGraph[T]) WalkPostOrder(fx ApplyFunction[T], userData interface{}) {
-*/
-
 func TestTreeWalkInOrder(t *testing.T) {
 	// type ApplyFunction[T comparable.Comparable] func ( pos, depth int, data *T, userData interface{} ) bool
 	// func (tt *DirectedAcyclicGraph[T]) DeleteAtHead(find T) ( found bool ) {
@@ -492,71 +497,111 @@ func TestTreeWalkInOrder(t *testing.T) {
 
 }
 
-func TestTreeWalkPreOrder(t *testing.T) {
-	// type ApplyFunction[T comparable.Comparable] func ( pos, depth int, data *T, userData interface{} ) bool
-	// func (tt *DirectedAcyclicGraph[T]) DeleteAtHead(find T) ( found bool ) {
-	var Tree1 DirectedAcyclicGraph[TestTreeNode]
+func TestDagAddEdge(t *testing.T) {
+	var Dag1 DirectedAcyclicGraph[TestTreeNode]
 
-	Tree1.Insert(TestTreeNode{S: "05"})
-	Tree1.Insert(TestTreeNode{S: "02"})
-	Tree1.Insert(TestTreeNode{S: "09"})
-	Tree1.Insert(TestTreeNode{S: "00"})
-	Tree1.Insert(TestTreeNode{S: "03"})
-	if db3 {
-		fmt.Printf("at:%s tree=\n", dbgo.LF())
-		Tree1.Dump(os.Stdout)
+	Dag1.Insert(TestTreeNode{S: "a"})
+	Dag1.Insert(TestTreeNode{S: "b"})
+	Dag1.Insert(TestTreeNode{S: "c"})
+	Dag1.Insert(TestTreeNode{S: "d"})
+
+	if err := Dag1.AddEdge(TestTreeNode{S: "a"}, TestTreeNode{S: "x"}); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound got %v", err)
+	}
+	if err := Dag1.AddEdge(TestTreeNode{S: "a"}, TestTreeNode{S: "a"}); !errors.Is(err, ErrCycle) {
+		t.Errorf("Expected ErrCycle for self edge got %v", err)
 	}
 
-	var x []string
-	var fx ApplyFunction[TestTreeNode]
-	fx = func(pos, depth int, data *TestTreeNode, y interface{}) bool {
-		x = append(x, data.S)
-		return true
+	// a -> b -> c -> d, a -> c
+	for _, ee := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}, {"a", "c"}, {"a", "c"}} {
+		if err := Dag1.AddEdge(TestTreeNode{S: ee[0]}, TestTreeNode{S: ee[1]}); err != nil {
+			t.Errorf("Unexpected error adding %s -> %s: %s", ee[0], ee[1], err)
+		}
+	}
+	if n := Dag1.EdgeLength(); n != 4 {
+		t.Errorf("Expected 4 edges got %d", n)
+	}
+	if n := Dag1.Length(); n != 4 {
+		t.Errorf("Expected 4 vertices got %d", n)
 	}
-	Tree1.WalkPreOrder(fx, nil)
 
-	if db7 {
-		fmt.Printf("PreOrder Output: %s\n", x)
+	err := Dag1.AddEdge(TestTreeNode{S: "d"}, TestTreeNode{S: "a"})
+	if !errors.Is(err, ErrCycle) {
+		t.Errorf("Expected ErrCycle got %v", err)
+	}
+	var ce *CycleError[TestTreeNode]
+	if !errors.As(err, &ce) {
+		t.Errorf("Expected a *CycleError got %T", err)
+	} else if s := ce.Error(); s != "Cycle in graph: {a} -> {b} -> {c} -> {d} -> {a}" && s != "Cycle in graph: {a} -> {c} -> {d} -> {a}" {
+		t.Errorf("Unexpected cycle %s", s)
+	}
+	if Dag1.HasEdge(TestTreeNode{S: "d"}, TestTreeNode{S: "a"}) {
+		t.Errorf("Edge that makes a cycle should not have been added")
+	}
+
+	if n := Dag1.OutDegree(TestTreeNode{S: "a"}); n != 2 {
+		t.Errorf("Expected out degree 2 got %d", n)
+	}
+	if n := Dag1.InDegree(TestTreeNode{S: "c"}); n != 2 {
+		t.Errorf("Expected in degree 2 got %d", n)
+	}
+	if n := Dag1.InDegree(TestTreeNode{S: "x"}); n != 0 {
+		t.Errorf("Expected in degree 0 for missing vertex got %d", n)
+	}
+
+	var x []string
+	for _, pp := range Dag1.Successors(TestTreeNode{S: "a"}) {
+		x = append(x, pp.S)
+	}
+	expect := []string{"b", "c"}
+	if !reflect.DeepEqual(x, expect) {
+		t.Errorf("Successors error, expcted %s got %s", expect, x)
 	}
 
-	//PreOrder Output: [05 02 00 03 09]
-	expect := []string{"05", "02", "00", "03", "09"}
+	x = nil
+	for _, pp := range Dag1.Predecessors(TestTreeNode{S: "c"}) {
+		x = append(x, pp.S)
+	}
+	expect = []string{"a", "b"}
 	if !reflect.DeepEqual(x, expect) {
-		t.Errorf("PreOrder error, expcted %s got %s", expect, x)
+		t.Errorf("Predecessors error, expcted %s got %s", expect, x)
+	}
+	if db8 {
+		Dag1.Dump(os.Stdout)
 	}
 }
 
-func TestTreeWalkPostOrder(t *testing.T) {
-	// type ApplyFunction[T comparable.Comparable] func ( pos, depth int, data *T, userData interface{} ) bool
-	// func (tt *DirectedAcyclicGraph[T]) DeleteAtHead(find T) ( found bool ) {
-	var Tree1 DirectedAcyclicGraph[TestTreeNode]
+func TestDagRemoveEdge(t *testing.T) {
+	var Dag1 DirectedAcyclicGraph[TestTreeNode]
 
-	Tree1.Insert(TestTreeNode{S: "05"})
-	Tree1.Insert(TestTreeNode{S: "02"})
-	Tree1.Insert(TestTreeNode{S: "09"})
-	Tree1.Insert(TestTreeNode{S: "00"})
-	Tree1.Insert(TestTreeNode{S: "03"})
-	if db3 {
-		fmt.Printf("at:%s tree=\n", dbgo.LF())
-		Tree1.Dump(os.Stdout)
-	}
+	Dag1.Insert(TestTreeNode{S: "a"})
+	Dag1.Insert(TestTreeNode{S: "b"})
+	Dag1.Insert(TestTreeNode{S: "c"})
+	Dag1.AddEdge(TestTreeNode{S: "a"}, TestTreeNode{S: "b"})
+	Dag1.AddEdge(TestTreeNode{S: "b"}, TestTreeNode{S: "c"})
 
-	var x []string
-	var fx ApplyFunction[TestTreeNode]
-	fx = func(pos, depth int, data *TestTreeNode, y interface{}) bool {
-		x = append(x, data.S)
-		return true
+	if found := Dag1.RemoveEdge(TestTreeNode{S: "a"}, TestTreeNode{S: "c"}); found {
+		t.Errorf("Removed an edge that does not exist")
+	}
+	if found := Dag1.RemoveEdge(TestTreeNode{S: "b"}, TestTreeNode{S: "c"}); !found {
+		t.Errorf("Failed to remove edge")
+	}
+	if n := Dag1.InDegree(TestTreeNode{S: "c"}); n != 0 {
+		t.Errorf("Expected in degree 0 got %d", n)
 	}
-	Tree1.WalkPostOrder(fx, nil)
 
-	if db7 {
-		fmt.Printf("PostOrder Output: %s\n", x)
+	// Now c -> a is not a cycle.
+	if err := Dag1.AddEdge(TestTreeNode{S: "c"}, TestTreeNode{S: "a"}); err != nil {
+		t.Errorf("Unexpected error %s", err)
 	}
 
-	// PostOrder Output: [00 03 02 09 05]
-	expect := []string{"00", "03", "02", "09", "05"}
-	if !reflect.DeepEqual(x, expect) {
-		t.Errorf("PostOrder error, expcted %s got %s", expect, x)
+	// Deleting a vertex removes its edges.
+	Dag1.Delete(TestTreeNode{S: "a"})
+	if n := Dag1.EdgeLength(); n != 0 {
+		t.Errorf("Expected 0 edges got %d", n)
+	}
+	if n := Dag1.OutDegree(TestTreeNode{S: "c"}); n != 0 {
+		t.Errorf("Expected out degree 0 got %d", n)
 	}
 }
 
@@ -566,3 +611,4 @@ const db4 = false
 const db5 = false
 const db6 = false
 const db7 = false
+const db8 = false
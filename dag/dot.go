@@ -0,0 +1,80 @@
+package dag
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/dot"
+)
+
+// WriteDOT writes the graph in graphviz DOT format to `fo`.  The label of each vertex,
+// from opts.Label, is used as the DOT node name so it should be unique.  Edges with a
+// weight other than 1 are labeled with the weight.  The output can be read back with ReadDOT.
+func (tt *DirectedAcyclicGraph[T]) WriteDOT(fo io.Writer, opts *dot.Options) (err error) {
+	if err = dot.WriteHeader(fo, opts); err != nil {
+		return
+	}
+	for _, nn := range tt.nodes {
+		if err = dot.WriteNode(fo, opts.NodeLabel(*nn.data), nil); err != nil {
+			return
+		}
+	}
+	for _, nn := range tt.nodes {
+		for _, ss := range nn.successors {
+			var attrs map[string]string
+			if ww := tt.weights[dagEdge[T]{from: nn, to: ss}]; ww != 1 {
+				attrs = map[string]string{"label": strconv.FormatFloat(ww, 'g', -1, 64)}
+			}
+			if err = dot.WriteEdge(fo, opts.NodeLabel(*nn.data), opts.NodeLabel(*ss.data), attrs); err != nil {
+				return
+			}
+		}
+	}
+	return dot.WriteFooter(fo)
+}
+
+// ReadDOT builds a graph from a simple digraph, see dot.Parse for what can be read.
+// `fromID` turns a DOT node name into the data for the vertex.  An edge's weight is taken
+// from a numeric `weight` or `label` attribute, 1 if it has neither.  If the edges make a
+// cycle then the *CycleError from AddEdge is returned.
+func ReadDOT[T comparable.Comparable](fi io.Reader, fromID func(id string) (T, error)) (*DirectedAcyclicGraph[T], error) {
+	gr, err := dot.Parse(fi)
+	if err != nil {
+		return nil, err
+	}
+
+	tt := NewDirectedAcyclicGraph[T]()
+	data := make(map[string]T, len(gr.Nodes))
+	for _, id := range gr.Nodes {
+		item, err := fromID(id)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: %w", dot.Quote(id), err)
+		}
+		data[id] = item
+		tt.Insert(item)
+	}
+	for _, ee := range gr.Edges {
+		weight := 1.0
+		if ww, found := ee.Attrs["weight"]; found {
+			if weight, err = strconv.ParseFloat(ww, 64); err != nil {
+				return nil, fmt.Errorf("edge %s -> %s: invalid weight: %w", dot.Quote(ee.From), dot.Quote(ee.To), err)
+			}
+		} else if ww, found := ee.Attrs["label"]; found {
+			if lw, err := strconv.ParseFloat(ww, 64); err == nil {
+				weight = lw
+			}
+		}
+		if err = tt.AddWeightedEdge(data[ee.From], data[ee.To], weight); err != nil {
+			return nil, err
+		}
+	}
+	return tt, nil
+}
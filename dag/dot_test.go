@@ -0,0 +1,84 @@
+package dag
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pschlump/pluto/dot"
+)
+
+func testNodeFromID(id string) (TestTreeNode, error) {
+	return TestTreeNode{S: id}, nil
+}
+
+func TestWriteDOT(t *testing.T) {
+	dd := buildTestDag(t, []string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}})
+	dd.AddWeightedEdge(TestTreeNode{S: "a"}, TestTreeNode{S: "c"}, 2.5)
+
+	var buf bytes.Buffer
+	opts := &dot.Options{Name: "deps", Label: func(x any) string { return x.(TestTreeNode).S }}
+	if err := dd.WriteDOT(&buf, opts); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expect := `digraph "deps" {
+	"a";
+	"b";
+	"c";
+	"a" -> "b";
+	"a" -> "c" [label="2.5"];
+	"b" -> "c";
+}
+`
+	if got := buf.String(); got != expect {
+		t.Errorf("WriteDOT error, expcted\n%s\ngot\n%s", expect, got)
+	}
+
+	// And read it back in.
+	d2, err := ReadDOT(&buf, testNodeFromID)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if d2.Length() != 3 || d2.EdgeLength() != 3 {
+		t.Errorf("Expected 3 vertices and 3 edges got %d %d", d2.Length(), d2.EdgeLength())
+	}
+	if ww, found := d2.EdgeWeight(TestTreeNode{S: "a"}, TestTreeNode{S: "c"}); !found || ww != 2.5 {
+		t.Errorf("Expected weight 2.5 got %v %v", ww, found)
+	}
+}
+
+func TestReadDOT(t *testing.T) {
+	src := `digraph jobs {
+		fetch -> compile -> test -> pkg;
+		fetch -> lint -> pkg [weight=4];
+		docs;
+	}`
+	dd, err := ReadDOT(strings.NewReader(src), testNodeFromID)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	order, _ := dd.TopologicalSort()
+	if got, expect := strings.Join(toStrings(order), " "), "docs fetch compile lint test pkg"; got != expect {
+		t.Errorf("ReadDOT error, expcted %s got %s", expect, got)
+	}
+	if ww, _ := dd.EdgeWeight(TestTreeNode{S: "lint"}, TestTreeNode{S: "pkg"}); ww != 4 {
+		t.Errorf("Expected weight 4 got %v", ww)
+	}
+
+	_, err = ReadDOT(strings.NewReader(`digraph { a -> b -> a }`), testNodeFromID)
+	if !errors.Is(err, ErrCycle) {
+		t.Errorf("Expected ErrCycle got %v", err)
+	}
+
+	_, err = ReadDOT(strings.NewReader(`digraph { a -> }`), testNodeFromID)
+	if !errors.Is(err, dot.ErrSyntax) {
+		t.Errorf("Expected dot.ErrSyntax got %v", err)
+	}
+}
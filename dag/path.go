@@ -0,0 +1,236 @@
+package dag
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"errors"
+
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/stack"
+)
+
+// An error to indicate that there is no path between two vertices.
+var ErrNoPath = errors.New("No Path")
+
+// Reachable returns true if there is a path from `from` to `to`.  A vertex can always
+// reach itself.  False is returned if either vertex is not in the graph.
+// Complexity is O(n+e).
+func (tt *DirectedAcyclicGraph[T]) Reachable(from, to T) bool {
+	fromNode, toNode := tt.nlNode(from), tt.nlNode(to)
+	if fromNode == nil || toNode == nil {
+		return false
+	}
+	return tt.nlPath(fromNode, toNode) != nil
+}
+
+// Descendants returns all the vertices that can be reached from `item`, not including
+// `item`, in Compare order.  Nil is returned if `item` is not in the graph.
+// Complexity is O(n+e).
+func (tt *DirectedAcyclicGraph[T]) Descendants(item T) []*T {
+	node := tt.nlNode(item)
+	if node == nil {
+		return nil
+	}
+	return tt.nlInOrder(nlReach(node, func(nn *DirectedAcyclicGraphNode[T]) []*DirectedAcyclicGraphNode[T] { return nn.successors }))
+}
+
+// Ancestors returns all the vertices that `item` can be reached from, not including
+// `item`, in Compare order.  Nil is returned if `item` is not in the graph.
+// Complexity is O(n+e).
+func (tt *DirectedAcyclicGraph[T]) Ancestors(item T) []*T {
+	node := tt.nlNode(item)
+	if node == nil {
+		return nil
+	}
+	return tt.nlInOrder(nlReach(node, func(nn *DirectedAcyclicGraphNode[T]) []*DirectedAcyclicGraphNode[T] { return nn.predecessors }))
+}
+
+// nlReach is a non-recursive depth-first search from `node` following the edges returned
+// by `next`.  It returns the set of vertices found, not including `node`.
+func nlReach[T comparable.Comparable](node *DirectedAcyclicGraphNode[T], next func(nn *DirectedAcyclicGraphNode[T]) []*DirectedAcyclicGraphNode[T]) map[*DirectedAcyclicGraphNode[T]]bool {
+	visited := make(map[*DirectedAcyclicGraphNode[T]]bool)
+	var stk stack.Stack[*DirectedAcyclicGraphNode[T]]
+	stk.Push(node)
+	for !stk.IsEmpty() {
+		cur, _ := stk.Pop()
+		for _, nn := range next(cur) {
+			if !visited[nn] {
+				visited[nn] = true
+				stk.Push(nn)
+			}
+		}
+	}
+	return visited
+}
+
+// nlInOrder returns the data for the vertices in the set `set` in Compare order.
+func (tt *DirectedAcyclicGraph[T]) nlInOrder(set map[*DirectedAcyclicGraphNode[T]]bool) []*T {
+	rv := make([]*T, 0, len(set))
+	for _, nn := range tt.nodes {
+		if set[nn] {
+			rv = append(rv, nn.data)
+		}
+	}
+	return rv
+}
+
+// nlCopyVertices returns a new graph with a copy of each vertex and no edges, and a map
+// from the vertices in this graph to the ones in the new graph.
+func (tt *DirectedAcyclicGraph[T]) nlCopyVertices() (rv *DirectedAcyclicGraph[T], to map[*DirectedAcyclicGraphNode[T]]*DirectedAcyclicGraphNode[T]) {
+	rv = NewDirectedAcyclicGraph[T]()
+	rv.nodes = make([]*DirectedAcyclicGraphNode[T], 0, len(tt.nodes))
+	to = make(map[*DirectedAcyclicGraphNode[T]]*DirectedAcyclicGraphNode[T], len(tt.nodes))
+	for _, nn := range tt.nodes {
+		data := *nn.data
+		cp := &DirectedAcyclicGraphNode[T]{data: &data}
+		rv.nodes = append(rv.nodes, cp) // Still in Compare order
+		to[nn] = cp
+	}
+	return
+}
+
+// TransitiveClosure returns a new graph with the same vertices that has an edge from u to
+// v for every v that can be reached from u in this graph.  Edges that are in this graph
+// keep their weight, new edges have a weight of 1.
+// Complexity is O(n*(n+e)).
+func (tt *DirectedAcyclicGraph[T]) TransitiveClosure() *DirectedAcyclicGraph[T] {
+	rv, to := tt.nlCopyVertices()
+	for _, nn := range tt.nodes {
+		for dd := range nlReach(nn, func(nn *DirectedAcyclicGraphNode[T]) []*DirectedAcyclicGraphNode[T] { return nn.successors }) {
+			weight, found := tt.weights[dagEdge[T]{from: nn, to: dd}]
+			if !found {
+				weight = 1
+			}
+			rv.nlAddEdge(to[nn], to[dd], weight)
+		}
+	}
+	return rv
+}
+
+// TransitiveReduction returns a new graph with the same vertices and the fewest edges
+// that still let every vertex reach the same vertices as in this graph.  An edge u -> v
+// is left out when v can also be reached from u by a longer path.  The edges that are
+// kept keep their weight.
+// Complexity is O(n*(n+e)).
+func (tt *DirectedAcyclicGraph[T]) TransitiveReduction() *DirectedAcyclicGraph[T] {
+	rv, to := tt.nlCopyVertices()
+	for _, nn := range tt.nodes {
+		// Everything that can be reached in 2 or more steps.
+		far := make(map[*DirectedAcyclicGraphNode[T]]bool)
+		for _, ss := range nn.successors {
+			for dd := range nlReach(ss, func(nn *DirectedAcyclicGraphNode[T]) []*DirectedAcyclicGraphNode[T] { return nn.successors }) {
+				far[dd] = true
+			}
+		}
+		for _, ss := range nn.successors {
+			if !far[ss] {
+				rv.nlAddEdge(to[nn], to[ss], tt.weights[dagEdge[T]{from: nn, to: ss}])
+			}
+		}
+	}
+	return rv
+}
+
+// ShortestPath returns the path from `from` to `to` with the smallest sum of edge weights,
+// and that sum.  Weights may be negative.  ErrNotFound is returned if either vertex is not
+// in the graph and ErrNoPath if `to` can not be reached from `from`.
+// Complexity is O((n+e) log|2(n)).
+func (tt *DirectedAcyclicGraph[T]) ShortestPath(from, to T) (path []*T, length float64, err error) {
+	return tt.nlBestPath(from, to, func(a, b float64) bool { return a < b })
+}
+
+// LongestPath returns the path from `from` to `to` with the largest sum of edge weights,
+// and that sum.  ErrNotFound is returned if either vertex is not in the graph and ErrNoPath
+// if `to` can not be reached from `from`.
+// Complexity is O((n+e) log|2(n)).
+func (tt *DirectedAcyclicGraph[T]) LongestPath(from, to T) (path []*T, length float64, err error) {
+	return tt.nlBestPath(from, to, func(a, b float64) bool { return a > b })
+}
+
+// nlBestPath relaxes the edges in topological order keeping the distance that `better`
+// likes best.
+func (tt *DirectedAcyclicGraph[T]) nlBestPath(from, to T, better func(a, b float64) bool) (path []*T, length float64, err error) {
+	if tt == nil {
+		panic("graph sholud not be a nil")
+	}
+	fromNode, toNode := tt.nlNode(from), tt.nlNode(to)
+	if fromNode == nil || toNode == nil {
+		return nil, 0, ErrNotFound
+	}
+	order, err := tt.nlTopologicalSort()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	dist := map[*DirectedAcyclicGraphNode[T]]float64{fromNode: 0}
+	prev := make(map[*DirectedAcyclicGraphNode[T]]*DirectedAcyclicGraphNode[T])
+	for _, nn := range order {
+		dn, found := dist[nn]
+		if !found {
+			continue // not reachable from `from`
+		}
+		for _, ss := range nn.successors {
+			dd := dn + tt.weights[dagEdge[T]{from: nn, to: ss}]
+			if ds, found := dist[ss]; !found || better(dd, ds) {
+				dist[ss] = dd
+				prev[ss] = nn
+			}
+		}
+	}
+
+	length, found := dist[toNode]
+	if !found {
+		return nil, 0, ErrNoPath
+	}
+	return nlPathTo(prev, toNode), length, nil
+}
+
+// CriticalPath returns the path in the graph with the largest sum of edge weights, and that
+// sum.  A path may start at any vertex, a single vertex is a path of length 0.  When more
+// than one path has the largest sum the first one found in TopologicalSort order is
+// returned.  An empty graph has an empty path of length 0.
+// Complexity is O((n+e) log|2(n)).
+func (tt *DirectedAcyclicGraph[T]) CriticalPath() (path []*T, length float64, err error) {
+	if tt == nil {
+		panic("graph sholud not be a nil")
+	}
+	order, err := tt.nlTopologicalSort()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(order) == 0 {
+		return []*T{}, 0, nil
+	}
+
+	// Every vertex starts with the path of length 0 that is just itself.
+	dist := make(map[*DirectedAcyclicGraphNode[T]]float64, len(order))
+	prev := make(map[*DirectedAcyclicGraphNode[T]]*DirectedAcyclicGraphNode[T])
+	end := order[0]
+	for _, nn := range order {
+		if dist[nn] > dist[end] {
+			end = nn
+		}
+		for _, ss := range nn.successors {
+			if dd := dist[nn] + tt.weights[dagEdge[T]{from: nn, to: ss}]; dd > dist[ss] {
+				dist[ss] = dd
+				prev[ss] = nn
+			}
+		}
+	}
+	return nlPathTo(prev, end), dist[end], nil
+}
+
+// nlPathTo follows `prev` back from `end` and returns the path in edge order.
+func nlPathTo[T comparable.Comparable](prev map[*DirectedAcyclicGraphNode[T]]*DirectedAcyclicGraphNode[T], end *DirectedAcyclicGraphNode[T]) (path []*T) {
+	for cur := end; cur != nil; cur = prev[cur] {
+		path = append(path, cur.data)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return
+}
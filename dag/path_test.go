@@ -0,0 +1,139 @@
+package dag
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReachable(t *testing.T) {
+	dd := walkTestDag(t)
+
+	if !dd.Reachable(TestTreeNode{S: "a"}, TestTreeNode{S: "g"}) {
+		t.Errorf("Expected g to be reachable from a")
+	}
+	if dd.Reachable(TestTreeNode{S: "b"}, TestTreeNode{S: "c"}) {
+		t.Errorf("Expected c not to be reachable from b")
+	}
+	if !dd.Reachable(TestTreeNode{S: "b"}, TestTreeNode{S: "b"}) {
+		t.Errorf("Expected b to reach itself")
+	}
+	if dd.Reachable(TestTreeNode{S: "b"}, TestTreeNode{S: "z"}) {
+		t.Errorf("Expected missing vertex not to be reachable")
+	}
+
+	if got, expect := toStrings(dd.Descendants(TestTreeNode{S: "c"})), []string{"e", "f", "g"}; !reflect.DeepEqual(got, expect) {
+		t.Errorf("Descendants error, expcted %s got %s", expect, got)
+	}
+	if got, expect := toStrings(dd.Ancestors(TestTreeNode{S: "g"})), []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(got, expect) {
+		t.Errorf("Ancestors error, expcted %s got %s", expect, got)
+	}
+	if got := dd.Ancestors(TestTreeNode{S: "z"}); got != nil {
+		t.Errorf("Expected nil for missing vertex got %v", got)
+	}
+}
+
+func TestTransitiveClosureReduction(t *testing.T) {
+	// a -> b -> c, a -> c is redundant.
+	dd := buildTestDag(t, []string{"a", "b", "c"}, [][2]string{{"a", "b"}, {"b", "c"}, {"a", "c"}})
+
+	red := dd.TransitiveReduction()
+	if n := red.EdgeLength(); n != 2 {
+		t.Errorf("Expected 2 edges in reduction got %d", n)
+	}
+	if red.HasEdge(TestTreeNode{S: "a"}, TestTreeNode{S: "c"}) {
+		t.Errorf("Expected a -> c to be removed")
+	}
+	if n := dd.EdgeLength(); n != 3 {
+		t.Errorf("Original graph should not change, got %d edges", n)
+	}
+
+	cl := red.TransitiveClosure()
+	if n := cl.EdgeLength(); n != 3 {
+		t.Errorf("Expected 3 edges in closure got %d", n)
+	}
+	if !cl.HasEdge(TestTreeNode{S: "a"}, TestTreeNode{S: "c"}) {
+		t.Errorf("Expected a -> c in closure")
+	}
+	if n := cl.Length(); n != 3 {
+		t.Errorf("Expected 3 vertices got %d", n)
+	}
+}
+
+func TestShortestLongestPath(t *testing.T) {
+	dd := NewDirectedAcyclicGraph[TestTreeNode]()
+	for _, vv := range []string{"s", "a", "b", "t", "x"} {
+		dd.Insert(TestTreeNode{S: vv})
+	}
+	// s -2-> a -2-> t, s -1-> b -5-> t, s -9-> t
+	dd.AddWeightedEdge(TestTreeNode{S: "s"}, TestTreeNode{S: "a"}, 2)
+	dd.AddWeightedEdge(TestTreeNode{S: "a"}, TestTreeNode{S: "t"}, 2)
+	dd.AddWeightedEdge(TestTreeNode{S: "s"}, TestTreeNode{S: "b"}, 1)
+	dd.AddWeightedEdge(TestTreeNode{S: "b"}, TestTreeNode{S: "t"}, 5)
+	dd.AddWeightedEdge(TestTreeNode{S: "s"}, TestTreeNode{S: "t"}, 9)
+
+	if ww, found := dd.EdgeWeight(TestTreeNode{S: "b"}, TestTreeNode{S: "t"}); !found || ww != 5 {
+		t.Errorf("Expected weight 5 got %v %v", ww, found)
+	}
+
+	path, length, err := dd.ShortestPath(TestTreeNode{S: "s"}, TestTreeNode{S: "t"})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if got, expect := toStrings(path), []string{"s", "a", "t"}; !reflect.DeepEqual(got, expect) || length != 4 {
+		t.Errorf("ShortestPath error, expcted %s 4 got %s %v", expect, got, length)
+	}
+
+	path, length, err = dd.LongestPath(TestTreeNode{S: "s"}, TestTreeNode{S: "t"})
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if got, expect := toStrings(path), []string{"s", "t"}; !reflect.DeepEqual(got, expect) || length != 9 {
+		t.Errorf("LongestPath error, expcted %s 9 got %s %v", expect, got, length)
+	}
+
+	if _, _, err = dd.ShortestPath(TestTreeNode{S: "s"}, TestTreeNode{S: "x"}); err != ErrNoPath {
+		t.Errorf("Expected ErrNoPath got %v", err)
+	}
+	if _, _, err = dd.ShortestPath(TestTreeNode{S: "s"}, TestTreeNode{S: "z"}); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound got %v", err)
+	}
+
+	// Reverse keeps the weights with the edges.
+	dd.Reverse()
+	if ww, found := dd.EdgeWeight(TestTreeNode{S: "t"}, TestTreeNode{S: "b"}); !found || ww != 5 {
+		t.Errorf("Expected weight 5 after Reverse got %v %v", ww, found)
+	}
+}
+
+func TestCriticalPath(t *testing.T) {
+	// Tasks with durations on the edges out of them.
+	dd := NewDirectedAcyclicGraph[TestTreeNode]()
+	for _, vv := range []string{"fetch", "compile", "lint", "test", "pkg"} {
+		dd.Insert(TestTreeNode{S: vv})
+	}
+	dd.AddWeightedEdge(TestTreeNode{S: "fetch"}, TestTreeNode{S: "compile"}, 3)
+	dd.AddWeightedEdge(TestTreeNode{S: "fetch"}, TestTreeNode{S: "lint"}, 3)
+	dd.AddWeightedEdge(TestTreeNode{S: "compile"}, TestTreeNode{S: "test"}, 10)
+	dd.AddWeightedEdge(TestTreeNode{S: "lint"}, TestTreeNode{S: "pkg"}, 2)
+	dd.AddWeightedEdge(TestTreeNode{S: "test"}, TestTreeNode{S: "pkg"}, 7)
+
+	path, length, err := dd.CriticalPath()
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if got, expect := toStrings(path), []string{"fetch", "compile", "test", "pkg"}; !reflect.DeepEqual(got, expect) || length != 20 {
+		t.Errorf("CriticalPath error, expcted %s 20 got %s %v", expect, got, length)
+	}
+
+	var empty DirectedAcyclicGraph[TestTreeNode]
+	path, length, err = empty.CriticalPath()
+	if err != nil || len(path) != 0 || length != 0 {
+		t.Errorf("Expected empty path got %v %v %v", path, length, err)
+	}
+}
@@ -0,0 +1,167 @@
+package dag
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/heap"
+)
+
+// CycleError is returned when a cycle is found in the graph.  Vertices is the list
+// of vertices on the cycle in edge order, the last vertex has an edge back to the first.
+//
+// errors.Is(err, ErrCycle) is true for a CycleError.
+type CycleError[T comparable.Comparable] struct {
+	Vertices []*T
+}
+
+// Error implements the error interface.
+func (ce *CycleError[T]) Error() string {
+	ss := make([]string, 0, len(ce.Vertices)+1)
+	for _, vv := range ce.Vertices {
+		ss = append(ss, fmt.Sprintf("%v", *vv))
+	}
+	if len(ce.Vertices) > 0 {
+		ss = append(ss, fmt.Sprintf("%v", *(ce.Vertices[0])))
+	}
+	return fmt.Sprintf("Cycle in graph: %s", strings.Join(ss, " -> "))
+}
+
+// Is allows errors.Is(err, ErrCycle) to match a CycleError.
+func (ce *CycleError[T]) Is(target error) bool {
+	return target == ErrCycle
+}
+
+// TopologicalSort returns all the vertices in an order where every vertex comes before
+// the vertices it has an edge to.  This is Kahn's algorithm.  When more than one vertex
+// is ready at the same time the smallest by Compare is taken first so the order is
+// always the same for the same graph.  If a cycle is found a *CycleError is returned.
+// Complexity is O((n+e) log|2(n)).
+func (tt *DirectedAcyclicGraph[T]) TopologicalSort() (rv []*T, err error) {
+	if tt == nil {
+		panic("graph sholud not be a nil")
+	}
+
+	order, err := tt.nlTopologicalSort()
+	if err != nil {
+		return nil, err
+	}
+	return nodeData(order), nil
+}
+
+// Layers returns the vertices in groups.  All the vertices in a group have their
+// predecessors in earlier groups, so the vertices in a group can be run in parallel once
+// the earlier groups are done.  Each vertex is put in the earliest group it can be in,
+// and each group is in Compare order.  If a cycle is found a *CycleError is returned.
+// Complexity is O((n+e) log|2(n)).
+func (tt *DirectedAcyclicGraph[T]) Layers() (rv [][]*T, err error) {
+	if tt == nil {
+		panic("graph sholud not be a nil")
+	}
+
+	levels, err := tt.nlLevels()
+	if err != nil {
+		return nil, err
+	}
+	for _, nn := range tt.nodes { // tt.nodes is in Compare order
+		lv := levels[nn]
+		for len(rv) <= lv {
+			rv = append(rv, []*T{})
+		}
+		rv[lv] = append(rv[lv], nn.data)
+	}
+	return
+}
+
+// nlTopologicalSort is Kahn's algorithm using a heap to pick the smallest ready vertex.
+func (tt *DirectedAcyclicGraph[T]) nlTopologicalSort() (order []*DirectedAcyclicGraphNode[T], err error) {
+	inDegree := make(map[*DirectedAcyclicGraphNode[T]]int, len(tt.nodes))
+	ready := heap.NewHeap[T]()
+	for _, nn := range tt.nodes {
+		inDegree[nn] = len(nn.predecessors)
+		if len(nn.predecessors) == 0 {
+			ready.Push(nn.data)
+		}
+	}
+
+	order = make([]*DirectedAcyclicGraphNode[T], 0, len(tt.nodes))
+	for ready.Length() > 0 {
+		nn := tt.nlNode(*(ready.Pop()))
+		order = append(order, nn)
+		for _, ss := range nn.successors {
+			inDegree[ss]--
+			if inDegree[ss] == 0 {
+				ready.Push(ss.data)
+			}
+		}
+	}
+
+	if len(order) < len(tt.nodes) {
+		return nil, tt.nlFindCycle(inDegree)
+	}
+	return
+}
+
+// nlLevels returns for each vertex the number of vertices on the longest path that ends
+// at it, less 1.  Sources are at level 0.
+func (tt *DirectedAcyclicGraph[T]) nlLevels() (levels map[*DirectedAcyclicGraphNode[T]]int, err error) {
+	order, err := tt.nlTopologicalSort()
+	if err != nil {
+		return nil, err
+	}
+	levels = make(map[*DirectedAcyclicGraphNode[T]]int, len(order))
+	for _, nn := range order {
+		lv := levels[nn] // Final, all the predecessors of nn come before it in order.
+		levels[nn] = lv
+		for _, ss := range nn.successors {
+			if lv+1 > levels[ss] {
+				levels[ss] = lv + 1
+			}
+		}
+	}
+	return
+}
+
+// nlFindCycle is called when Kahn's algorithm could not remove every vertex.  The vertices
+// that are left, the ones with an inDegree above 0, each have a predecessor that is also
+// left.  Walking back along predecessors from any of them must repeat a vertex, and the
+// vertices between the two visits are a cycle.
+func (tt *DirectedAcyclicGraph[T]) nlFindCycle(inDegree map[*DirectedAcyclicGraphNode[T]]int) error {
+	var cur *DirectedAcyclicGraphNode[T]
+	for _, nn := range tt.nodes {
+		if inDegree[nn] > 0 {
+			cur = nn
+			break
+		}
+	}
+
+	seenAt := make(map[*DirectedAcyclicGraphNode[T]]int)
+	var path []*DirectedAcyclicGraphNode[T]
+	for {
+		if pos, seen := seenAt[cur]; seen {
+			path = path[pos:]
+			break
+		}
+		seenAt[cur] = len(path)
+		path = append(path, cur)
+		for _, pp := range cur.predecessors {
+			if inDegree[pp] > 0 {
+				cur = pp
+				break
+			}
+		}
+	}
+
+	// The path was found walking backwards, put it in edge order.
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return &CycleError[T]{Vertices: nodeData(path)}
+}
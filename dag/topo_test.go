@@ -0,0 +1,107 @@
+package dag
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// buildTestDag creates a graph with the listed vertices and edges.
+func buildTestDag(t *testing.T, vertices []string, edges [][2]string) (dd *DirectedAcyclicGraph[TestTreeNode]) {
+	dd = NewDirectedAcyclicGraph[TestTreeNode]()
+	for _, vv := range vertices {
+		dd.Insert(TestTreeNode{S: vv})
+	}
+	for _, ee := range edges {
+		if err := dd.AddEdge(TestTreeNode{S: ee[0]}, TestTreeNode{S: ee[1]}); err != nil {
+			t.Fatalf("Unexpected error adding %s -> %s: %s", ee[0], ee[1], err)
+		}
+	}
+	return
+}
+
+func toStrings(list []*TestTreeNode) (rv []string) {
+	rv = []string{}
+	for _, pp := range list {
+		rv = append(rv, pp.S)
+	}
+	return
+}
+
+func TestTopologicalSort(t *testing.T) {
+	// "compile" and "lint" both depend on "fetch", "test" depends on "compile", "pkg" on "test" and "lint".
+	dd := buildTestDag(t,
+		[]string{"test", "pkg", "lint", "fetch", "compile", "docs"},
+		[][2]string{{"fetch", "compile"}, {"fetch", "lint"}, {"compile", "test"}, {"test", "pkg"}, {"lint", "pkg"}},
+	)
+
+	order, err := dd.TopologicalSort()
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expect := []string{"docs", "fetch", "compile", "lint", "test", "pkg"}
+	if got := toStrings(order); !reflect.DeepEqual(got, expect) {
+		t.Errorf("TopologicalSort error, expcted %s got %s", expect, got)
+	}
+
+	var empty DirectedAcyclicGraph[TestTreeNode]
+	order, err = empty.TopologicalSort()
+	if err != nil || len(order) != 0 {
+		t.Errorf("Expected empty order got %v %v", order, err)
+	}
+}
+
+func TestLayers(t *testing.T) {
+	dd := buildTestDag(t,
+		[]string{"test", "pkg", "lint", "fetch", "compile", "docs"},
+		[][2]string{{"fetch", "compile"}, {"fetch", "lint"}, {"compile", "test"}, {"test", "pkg"}, {"lint", "pkg"}},
+	)
+
+	layers, err := dd.Layers()
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	var got [][]string
+	for _, ll := range layers {
+		got = append(got, toStrings(ll))
+	}
+	expect := [][]string{{"docs", "fetch"}, {"compile", "lint"}, {"test"}, {"pkg"}}
+	if !reflect.DeepEqual(got, expect) {
+		t.Errorf("Layers error, expcted %s got %s", expect, got)
+	}
+
+	if n := dd.Depth(); n != 4 {
+		t.Errorf("Expected depth 4 got %d", n)
+	}
+}
+
+func TestTopologicalSortCycle(t *testing.T) {
+	dd := buildTestDag(t, []string{"a", "b", "c", "d"}, [][2]string{{"a", "b"}, {"b", "c"}, {"c", "d"}})
+
+	// AddEdge will not make a cycle so link c -> b by hand.
+	bb, cc := dd.nlNode(TestTreeNode{S: "b"}), dd.nlNode(TestTreeNode{S: "c"})
+	cc.successors, _ = insertNode(cc.successors, bb)
+	bb.predecessors, _ = insertNode(bb.predecessors, cc)
+
+	_, err := dd.TopologicalSort()
+	if !errors.Is(err, ErrCycle) {
+		t.Fatalf("Expected ErrCycle got %v", err)
+	}
+	var ce *CycleError[TestTreeNode]
+	if !errors.As(err, &ce) {
+		t.Fatalf("Expected a *CycleError got %T", err)
+	}
+	if got, expect := toStrings(ce.Vertices), []string{"c", "b"}; !reflect.DeepEqual(got, expect) {
+		t.Errorf("Cycle error, expcted %s got %s", expect, got)
+	}
+
+	if _, err = dd.Layers(); !errors.Is(err, ErrCycle) {
+		t.Errorf("Expected ErrCycle from Layers got %v", err)
+	}
+}
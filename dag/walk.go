@@ -0,0 +1,128 @@
+package dag
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"iter"
+
+	"github.com/pschlump/pluto/queue"
+	"github.com/pschlump/pluto/stack"
+)
+
+// walkItem is a vertex waiting to be visited and the number of edges from the start to it.
+type walkItem[T any] struct {
+	node  T
+	depth int
+}
+
+// WalkDepthFirst calls `fx` on each vertex that can be reached from `start`, including
+// `start`, in depth-first pre-order.  Successors are visited in Compare order.  The depth
+// passed to `fx` is the number of edges from `start` on the path the walk took.  Each
+// vertex is visited once.  The walk stops if `fx` returns false.
+//
+// The walk is not recursive.  It uses a map to mark the visited vertices and the ../stack
+// to hold the vertices still to be visited, so it works on graphs of any depth.
+// ErrNotFound is returned if `start` is not in the graph.
+func (tt *DirectedAcyclicGraph[T]) WalkDepthFirst(start T, fx ApplyFunction[T], userData interface{}) error {
+	if tt == nil {
+		panic("graph sholud not be a nil")
+	}
+	node := tt.nlNode(start)
+	if node == nil {
+		return ErrNotFound
+	}
+
+	visited := make(map[*DirectedAcyclicGraphNode[T]]bool)
+	var stk stack.Stack[walkItem[*DirectedAcyclicGraphNode[T]]]
+	stk.Push(walkItem[*DirectedAcyclicGraphNode[T]]{node: node})
+	p := 0
+	for !stk.IsEmpty() {
+		cur, _ := stk.Pop()
+		if visited[cur.node] {
+			continue
+		}
+		visited[cur.node] = true
+		// ----------------------------------------------------------------------
+		if !fx(p, cur.depth, cur.node.data, userData) {
+			return nil
+		}
+		p++
+		// ----------------------------------------------------------------------
+		// Push in reverse so that the smallest successor is on top of the stack.
+		for i := len(cur.node.successors) - 1; i >= 0; i-- {
+			if ss := cur.node.successors[i]; !visited[ss] {
+				stk.Push(walkItem[*DirectedAcyclicGraphNode[T]]{node: ss, depth: cur.depth + 1})
+			}
+		}
+	}
+	return nil
+}
+
+// WalkBreadthFirst calls `fx` on each vertex that can be reached from `start`, including
+// `start`, closest first.  Vertices at the same distance are visited in the order they
+// were found, successors in Compare order.  The depth passed to `fx` is the smallest number
+// of edges from `start` to the vertex.  Each vertex is visited once.  The walk stops if
+// `fx` returns false.
+//
+// The walk is not recursive.  It uses a map to mark the visited vertices and the ../queue
+// to hold the vertices still to be visited.  ErrNotFound is returned if `start` is not in
+// the graph.
+func (tt *DirectedAcyclicGraph[T]) WalkBreadthFirst(start T, fx ApplyFunction[T], userData interface{}) error {
+	if tt == nil {
+		panic("graph sholud not be a nil")
+	}
+	node := tt.nlNode(start)
+	if node == nil {
+		return ErrNotFound
+	}
+
+	visited := make(map[*DirectedAcyclicGraphNode[T]]bool)
+	var que queue.Queue[walkItem[*DirectedAcyclicGraphNode[T]]]
+	que.Enqueue(walkItem[*DirectedAcyclicGraphNode[T]]{node: node})
+	visited[node] = true
+	p := 0
+	for !que.IsEmpty() {
+		cur, _ := que.Dequeue()
+		// ----------------------------------------------------------------------
+		if !fx(p, cur.depth, cur.node.data, userData) {
+			return nil
+		}
+		p++
+		// ----------------------------------------------------------------------
+		for _, ss := range cur.node.successors {
+			if !visited[ss] {
+				visited[ss] = true
+				que.Enqueue(walkItem[*DirectedAcyclicGraphNode[T]]{node: ss, depth: cur.depth + 1})
+			}
+		}
+	}
+	return nil
+}
+
+// DepthFirst returns an iterator over the vertices that can be reached from `start` in the
+// same order as WalkDepthFirst.  If `start` is not in the graph nothing is returned.
+//
+//	for vv := range dd.DepthFirst(start) { ... }
+func (tt *DirectedAcyclicGraph[T]) DepthFirst(start T) iter.Seq[*T] {
+	return func(yield func(*T) bool) {
+		tt.WalkDepthFirst(start, func(pos, depth int, data *T, userData interface{}) bool {
+			return yield(data)
+		}, nil)
+	}
+}
+
+// BreadthFirst returns an iterator over the vertices that can be reached from `start` in
+// the same order as WalkBreadthFirst.  If `start` is not in the graph nothing is returned.
+//
+//	for vv := range dd.BreadthFirst(start) { ... }
+func (tt *DirectedAcyclicGraph[T]) BreadthFirst(start T) iter.Seq[*T] {
+	return func(yield func(*T) bool) {
+		tt.WalkBreadthFirst(start, func(pos, depth int, data *T, userData interface{}) bool {
+			return yield(data)
+		}, nil)
+	}
+}
@@ -0,0 +1,125 @@
+package dag
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// walkTestDag builds this graph, all edges point down:
+//
+//	  a
+//	 / \
+//	b   c
+//	|  / \
+//	d e   f
+//	 \|
+//	  g
+func walkTestDag(t *testing.T) *DirectedAcyclicGraph[TestTreeNode] {
+	return buildTestDag(t,
+		[]string{"a", "b", "c", "d", "e", "f", "g"},
+		[][2]string{{"a", "b"}, {"a", "c"}, {"b", "d"}, {"c", "e"}, {"c", "f"}, {"d", "g"}, {"e", "g"}},
+	)
+}
+
+func TestWalkDepthFirst(t *testing.T) {
+	dd := walkTestDag(t)
+
+	var x []string
+	err := dd.WalkDepthFirst(TestTreeNode{S: "a"}, func(pos, depth int, data *TestTreeNode, y interface{}) bool {
+		x = append(x, fmt.Sprintf("%s%d", data.S, depth))
+		return true
+	}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expect := []string{"a0", "b1", "d2", "g3", "c1", "e2", "f2"}
+	if !reflect.DeepEqual(x, expect) {
+		t.Errorf("DepthFirst error, expcted %s got %s", expect, x)
+	}
+
+	// Early stop.
+	x = nil
+	dd.WalkDepthFirst(TestTreeNode{S: "a"}, func(pos, depth int, data *TestTreeNode, y interface{}) bool {
+		x = append(x, data.S)
+		return pos < 2
+	}, nil)
+	expect = []string{"a", "b", "d"}
+	if !reflect.DeepEqual(x, expect) {
+		t.Errorf("DepthFirst early stop error, expcted %s got %s", expect, x)
+	}
+
+	if err = dd.WalkDepthFirst(TestTreeNode{S: "z"}, nil, nil); err != ErrNotFound {
+		t.Errorf("Expected ErrNotFound got %v", err)
+	}
+}
+
+func TestWalkBreadthFirst(t *testing.T) {
+	dd := walkTestDag(t)
+
+	var x []string
+	err := dd.WalkBreadthFirst(TestTreeNode{S: "a"}, func(pos, depth int, data *TestTreeNode, y interface{}) bool {
+		x = append(x, fmt.Sprintf("%s%d", data.S, depth))
+		return true
+	}, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expect := []string{"a0", "b1", "c1", "d2", "e2", "f2", "g3"}
+	if !reflect.DeepEqual(x, expect) {
+		t.Errorf("BreadthFirst error, expcted %s got %s", expect, x)
+	}
+
+	x = nil
+	for vv := range dd.BreadthFirst(TestTreeNode{S: "c"}) {
+		x = append(x, vv.S)
+	}
+	expect = []string{"c", "e", "f", "g"}
+	if !reflect.DeepEqual(x, expect) {
+		t.Errorf("BreadthFirst iterator error, expcted %s got %s", expect, x)
+	}
+}
+
+func TestDepthFirstIter(t *testing.T) {
+	dd := walkTestDag(t)
+
+	var x []string
+	for vv := range dd.DepthFirst(TestTreeNode{S: "a"}) {
+		if vv.S == "c" {
+			break
+		}
+		x = append(x, vv.S)
+	}
+	expect := []string{"a", "b", "d", "g"}
+	if !reflect.DeepEqual(x, expect) {
+		t.Errorf("DepthFirst iterator error, expcted %s got %s", expect, x)
+	}
+}
+
+func TestWalkDeepGraph(t *testing.T) {
+	// A chain far deeper than a recursive walk would be happy with.
+	const n = 200000
+	dd := NewDirectedAcyclicGraph[TestTreeNode]()
+	for i := 0; i < n; i++ {
+		dd.Insert(TestTreeNode{S: fmt.Sprintf("%07d", i)})
+	}
+	for i := 1; i < n; i++ {
+		if err := dd.AddEdge(TestTreeNode{S: fmt.Sprintf("%07d", i-1)}, TestTreeNode{S: fmt.Sprintf("%07d", i)}); err != nil {
+			t.Fatalf("Unexpected error %s", err)
+		}
+	}
+	maxDepth := 0
+	dd.WalkDepthFirst(TestTreeNode{S: "0000000"}, func(pos, depth int, data *TestTreeNode, y interface{}) bool {
+		maxDepth = depth
+		return true
+	}, nil)
+	if maxDepth != n-1 {
+		t.Errorf("Expected depth %d got %d", n-1, maxDepth)
+	}
+}
@@ -24,6 +24,7 @@ This list has head-and-tail pointers.
 *	ReverseWalk - Iterate from tail to head of list. 											O(n)
 *	Search — Returns the given element from a linked list.  Search is from head to tail.		O(n) n/2
 *	Truncate - Delete all the nodes in list. 													O(1)
+*	ConvertToSlice - Return the data from head to tail as a slice.								O(n)
 *	Walk - Iterate from head to tail of list. 													O(n)
 
 With the basic stack operations it also can be used as a stack:
@@ -40,6 +41,19 @@ With the use of Enque can be used as a Queue.  This is a synonym for AppendAtTai
 * 	PopTail - Remvoe the element at the end of the DLL.											O(1)
 *	Enque - add to the tail so that DLL can be used as a Queue.									O(1)
 
+With an iterator from Front, Rear or Current the list can be changed at the current element
+(see cursor.go):
+
+*	InsertBefore - Inserts a new element before the current element.							O(1)
+*	InsertAfter - Inserts a new element after the current element.								O(1)
+*	Remove - Deletes the current element and moves on to the next one.							O(1)
+*	MoveToFront - Moves the current element to the head and moves on to the next one.			O(1)
+*	MoveToBack - Moves the current element to the tail and moves on to the next one.			O(1)
+*	Splice - Moves all the elements of another list in after the current element.				O(1)
+
+Sort, MergeSorted, SplitAt, SpliceAfter and Dedup re-link the nodes of lists without copying
+them, see listops.go.
+
 Additional go1.22 Functionality (replacements for Walk, ReverseWalk)
 All of this code can be found at the very bottom of this file. (except the type DllDeq)
 This replaces the DllIter type and Front/Done/Next/Value.
@@ -58,6 +72,7 @@ import (
 	"iter"
 
 	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/iface_list"
 )
 
 // To iterate over a list (where e is a *dll.Dll):
@@ -82,6 +97,11 @@ type Dll[T comparable.Equality] struct {
 	length     int
 }
 
+// At compile time verify that Dll can be used behind the iface_list interfaces.
+var _ iface_list.LinearDataType[comparable.Int] = (*Dll[comparable.Int])(nil)
+var _ iface_list.StackDataType[comparable.Int] = (*Dll[comparable.Int])(nil)
+var _ iface_list.QueueDataType[comparable.Int] = (*Dll[comparable.Int])(nil)
+
 // An iteration type that allows a for loop to walk the list.
 type DllIter[T comparable.Equality] struct {
 	cur *DllElement[T]
@@ -251,6 +271,8 @@ func (ns *Dll[T]) Pop() (rv *T, err error) {
 	(*ns).head = (*ns).head.next
 	if (*ns).head != nil {
 		(*ns).head.prev = nil
+	} else {
+		(*ns).tail = nil
 	}
 	(*ns).length--
 	return
@@ -265,6 +287,8 @@ func (ns *Dll[T]) PopTail() (rv *T, err error) {
 	(*ns).tail = (*ns).tail.prev
 	if (*ns).tail != nil {
 		(*ns).tail.next = nil
+	} else {
+		(*ns).head = nil
 	}
 	(*ns).length--
 	return
@@ -320,6 +344,8 @@ func (ns *Dll[T]) DeleteAtTail() (err error) {
 	(*ns).tail = (*ns).tail.prev
 	if (*ns).tail != nil {
 		(*ns).tail.next = nil
+	} else {
+		(*ns).head = nil
 	}
 	(*ns).length--
 	return
@@ -484,6 +510,15 @@ func (ns *Dll[T]) Reverse() {
 
 }
 
+// ConvertToSlice returns the data in the list from head to tail.		O(n)
+func (ns *Dll[T]) ConvertToSlice() (rv []*T) {
+	rv = make([]*T, 0, (*ns).length)
+	for p := (*ns).head; p != nil; p = p.next {
+		rv = append(rv, p.Data)
+	}
+	return
+}
+
 // -----------------------------------------------------------------------------------------------------------
 // Go1.22 Iterator stuff
 
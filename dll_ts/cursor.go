@@ -0,0 +1,218 @@
+package dll_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import "errors"
+
+// Errors from the cursor operations on a DllIter.
+var ErrNoCurrent = errors.New("Iterator has no Current Element")
+var ErrSameList = errors.New("Can not Splice a List into Itself")
+
+/*
+
+The cursor operations change the list at the current element of an iterator.  They hold the
+list's write lock while they run.  A change made by the iterator's own methods keeps the
+iterator valid, a change made any other way - by another iterator, by a list method or from
+another goroutine - makes the iterator fail fast: Done returns true, Value returns nil and the
+cursor operations return ErrListChanged, see Err.
+
+*/
+
+// noLockUnlink takes `el` out of the list.  It must be in this list.
+func (ns *Dll[T]) noLockUnlink(el *DllElement[T]) {
+	if el.prev != nil {
+		el.prev.next = el.next
+	} else {
+		ns.head = el.next
+	}
+	if el.next != nil {
+		el.next.prev = el.prev
+	} else {
+		ns.tail = el.prev
+	}
+	el.next, el.prev = nil, nil
+	ns.length--
+	ns.version++
+}
+
+// noLockLinkAfter puts `el` in the list after `mark`, or at the head if `mark` is nil.
+func (ns *Dll[T]) noLockLinkAfter(mark, el *DllElement[T]) {
+	el.prev = mark
+	if mark != nil {
+		el.next = mark.next
+		mark.next = el
+	} else {
+		el.next = ns.head
+		ns.head = el
+	}
+	if el.next != nil {
+		el.next.prev = el
+	} else {
+		ns.tail = el
+	}
+	ns.length++
+	ns.version++
+}
+
+// noLockStart checks that the iterator can change the list.  The caller must hold the
+// iterator lock and the list's write lock.
+func (iter *DllIter[T]) noLockStart() error {
+	if err := iter.noLockCheck(); err != nil {
+		return err
+	}
+	if iter.cur == nil {
+		return ErrNoCurrent
+	}
+	return nil
+}
+
+// InsertBefore adds `t` to the list before the current element.  The iterator stays on the
+// current element, so its Pos goes up by one.
+// Complexity is O(1).
+func (iter *DllIter[T]) InsertBefore(t *T) error {
+	(*iter).iterLock.Lock()
+	defer (*iter).iterLock.Unlock()
+	iter.dll.mu.Lock()
+	defer iter.dll.mu.Unlock()
+	if err := iter.noLockStart(); err != nil {
+		return err
+	}
+	iter.dll.noLockLinkAfter(iter.cur.prev, &DllElement[T]{Data: t})
+	iter.pos++
+	iter.version = iter.dll.version
+	return nil
+}
+
+// InsertAfter adds `t` to the list after the current element, so it is the next one that Next
+// will move to.  The iterator stays on the current element.
+// Complexity is O(1).
+func (iter *DllIter[T]) InsertAfter(t *T) error {
+	(*iter).iterLock.Lock()
+	defer (*iter).iterLock.Unlock()
+	iter.dll.mu.Lock()
+	defer iter.dll.mu.Unlock()
+	if err := iter.noLockStart(); err != nil {
+		return err
+	}
+	iter.dll.noLockLinkAfter(iter.cur, &DllElement[T]{Data: t})
+	iter.version = iter.dll.version
+	return nil
+}
+
+// Remove deletes the current element from the list and returns its data.  The iterator moves
+// on to the element that was after it, which now has the same Pos, so a loop that removes
+// should only call Next when it does not remove:
+//
+//	for ii := list.Front(); !ii.Done(); {
+//		if remove(ii.Value()) {
+//			ii.Remove()
+//		} else {
+//			ii.Next()
+//		}
+//	}
+//
+// Complexity is O(1).
+func (iter *DllIter[T]) Remove() (rv *T, err error) {
+	(*iter).iterLock.Lock()
+	defer (*iter).iterLock.Unlock()
+	iter.dll.mu.Lock()
+	defer iter.dll.mu.Unlock()
+	if err = iter.noLockStart(); err != nil {
+		return nil, err
+	}
+	el := iter.cur
+	iter.cur = el.next
+	iter.dll.noLockUnlink(el)
+	iter.version = iter.dll.version
+	return el.Data, nil
+}
+
+// MoveToFront moves the current element to the head of the list.  The element keeps its
+// DllElement, so a pointer to it from Search is still good.  The iterator moves on to the
+// element that was after it, which is now 1 further from the head because the moved element
+// is in front of it, so Pos goes up by 1.
+// Complexity is O(1).
+func (iter *DllIter[T]) MoveToFront() error {
+	(*iter).iterLock.Lock()
+	defer (*iter).iterLock.Unlock()
+	iter.dll.mu.Lock()
+	defer iter.dll.mu.Unlock()
+	if err := iter.noLockStart(); err != nil {
+		return err
+	}
+	el := iter.cur
+	iter.cur = el.next
+	iter.dll.noLockUnlink(el)
+	iter.dll.noLockLinkAfter(nil, el)
+	iter.pos++
+	iter.version = iter.dll.version
+	return nil
+}
+
+// MoveToBack moves the current element to the tail of the list.  Like Remove the iterator moves
+// on to the element that was after it, which has the same Pos.  A walk from the head will get
+// to the moved element again at the end of the list.
+// Complexity is O(1).
+func (iter *DllIter[T]) MoveToBack() error {
+	(*iter).iterLock.Lock()
+	defer (*iter).iterLock.Unlock()
+	iter.dll.mu.Lock()
+	defer iter.dll.mu.Unlock()
+	if err := iter.noLockStart(); err != nil {
+		return err
+	}
+	el := iter.cur
+	iter.cur = el.next
+	iter.dll.noLockUnlink(el)
+	iter.dll.noLockLinkAfter(iter.dll.tail, el)
+	iter.version = iter.dll.version
+	return nil
+}
+
+// Splice moves all the elements of `other` into the list after the current element, in order,
+// and leaves `other` empty.  The elements are moved, not copied, so pointers to them are still
+// good.  The iterator stays on the current element and Next will move to the first of the
+// spliced elements.  Both lists are locked, in a fixed order, see lockLists.  Iterators on
+// `other` will fail with ErrListChanged.
+// Complexity is O(1).
+func (iter *DllIter[T]) Splice(other *Dll[T]) error {
+	if other == iter.dll {
+		return ErrSameList
+	}
+	if other == nil {
+		return nil
+	}
+
+	(*iter).iterLock.Lock()
+	defer (*iter).iterLock.Unlock()
+	unlock := lockLists([]*Dll[T]{iter.dll, other}, nil)
+	defer unlock()
+
+	if err := iter.noLockStart(); err != nil {
+		return err
+	}
+	if other.length == 0 {
+		return nil
+	}
+	ns, first, last := iter.dll, other.head, other.tail
+	first.prev = iter.cur
+	last.next = iter.cur.next
+	if last.next != nil {
+		last.next.prev = last
+	} else {
+		ns.tail = last
+	}
+	iter.cur.next = first
+	ns.length += other.length
+	ns.version++
+	other.head, other.tail, other.length = nil, nil, 0
+	other.version++
+	iter.version = ns.version
+	return nil
+}
+
+/* vim: set noai ts=4 sw=4: */
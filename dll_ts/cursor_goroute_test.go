@@ -0,0 +1,76 @@
+package dll_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+// This test is meant to be run with the race detector, `go test -race`.
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestIterGoroutines(t *testing.T) {
+	var Dll1 Dll[TestDemo]
+
+	// Writers append while a remover walks the list and removes what it sees, starting the walk
+	// again each time a writer changes the list under it.  Two splicers move lists in to and
+	// out of each other at the same time, in opposite orders, which must not deadlock.
+	const nWriters, nItems = 2, 500
+	var wg sync.WaitGroup
+	for ww := 0; ww < nWriters; ww++ {
+		wg.Add(1)
+		go func(ww int) {
+			defer wg.Done()
+			for k := 0; k < nItems; k++ {
+				Dll1.AppendAtTail(&TestDemo{S: fmt.Sprintf("%d-%04d", ww, k)})
+			}
+		}(ww)
+	}
+
+	removed, restarts := 0, 0
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for removed < nWriters*nItems {
+			ii := Dll1.Front()
+			for !ii.Done() {
+				if _, err := ii.Remove(); err == nil {
+					removed++
+				}
+			}
+			if ii.Err() == ErrListChanged {
+				restarts++
+			}
+		}
+	}()
+
+	Dll2, Dll3 := newList("a", "b"), newList("c", "d")
+	for ss := 0; ss < 2; ss++ {
+		wg.Add(1)
+		go func(ss int) {
+			defer wg.Done()
+			from, to := Dll2, Dll3
+			if ss == 1 {
+				from, to = Dll3, Dll2
+			}
+			for k := 0; k < 200; k++ {
+				if ii := to.Front(); !ii.Done() {
+					ii.Splice(from)
+				}
+			}
+		}(ss)
+	}
+	wg.Wait()
+
+	if removed != nWriters*nItems || !Dll1.IsEmpty() {
+		t.Errorf("Expected to remove %d got %d, %d left", nWriters*nItems, removed, Dll1.Length())
+	}
+	if Dll2.Length()+Dll3.Length() != 4 {
+		t.Errorf("Expected 4 elements in the spliced lists got %d", Dll2.Length()+Dll3.Length())
+	}
+}
@@ -0,0 +1,263 @@
+package dll_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"strings"
+	"testing"
+)
+
+// checkList verifies the list from head to tail, from tail to head and its length.
+func checkList(t *testing.T, ns *Dll[TestDemo], want string) {
+	t.Helper()
+	var fwd, rev []string
+	for p := ns.head; p != nil; p = p.next {
+		if p.next != nil && p.next.prev != p {
+			t.Errorf("Bad prev link at %s", p.Data.S)
+		}
+		fwd = append(fwd, p.Data.S)
+	}
+	for p := ns.tail; p != nil; p = p.prev {
+		rev = append([]string{p.Data.S}, rev...)
+	}
+	if got := strings.Join(fwd, " "); got != want {
+		t.Errorf("Expcted ->%s<- got ->%s<-", want, got)
+	}
+	if got := strings.Join(rev, " "); got != want {
+		t.Errorf("From the tail expcted ->%s<- got ->%s<-", want, got)
+	}
+	if ns.Length() != len(fwd) {
+		t.Errorf("Expected length %d got %d", len(fwd), ns.Length())
+	}
+}
+
+func newList(data ...string) *Dll[TestDemo] {
+	ns := NewDll[TestDemo]()
+	for _, s := range data {
+		ns.AppendAtTail(&TestDemo{S: s})
+	}
+	return ns
+}
+
+func TestIterInsert(t *testing.T) {
+	Dll1 := newList("a", "b", "c")
+
+	ii := Dll1.Front()
+	ii.Next() // at b
+	if err := ii.InsertBefore(&TestDemo{S: "x"}); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+	if ii.Pos() != 2 || ii.Value().S != "b" {
+		t.Errorf("Expected to stay on b at 2 got %s at %d", ii.Value().S, ii.Pos())
+	}
+	if err := ii.InsertAfter(&TestDemo{S: "y"}); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+	checkList(t, Dll1, "a x b y c")
+	ii.Next()
+	if ii.Pos() != 3 || ii.Value().S != "y" {
+		t.Errorf("Expected y at 3 got %s at %d", ii.Value().S, ii.Pos())
+	}
+
+	// At the ends of the list.
+	ii = Dll1.Front()
+	ii.InsertBefore(&TestDemo{S: "h"})
+	ii = Dll1.Rear()
+	ii.InsertAfter(&TestDemo{S: "t"})
+	checkList(t, Dll1, "h a x b y c t")
+
+	for ; !ii.Done(); ii.Next() {
+	}
+	if ii.InsertBefore(&TestDemo{S: "z"}) != ErrNoCurrent || ii.InsertAfter(&TestDemo{S: "z"}) != ErrNoCurrent {
+		t.Errorf("Expected ErrNoCurrent when the iterator is done")
+	}
+}
+
+func TestIterRemove(t *testing.T) {
+	Dll1 := newList("0", "1", "2", "3", "4", "5")
+
+	// Remove the odd ones in a walk from the head.
+	var seen []string
+	for ii := Dll1.Front(); !ii.Done(); {
+		seen = append(seen, ii.Value().S)
+		if ii.Value().S[0]%2 == 1 {
+			pos := ii.Pos()
+			if rv, err := ii.Remove(); err != nil || rv == nil {
+				t.Errorf("Unexpected error %s", err)
+			}
+			if ii.Pos() != pos {
+				t.Errorf("Expected Pos to stay at %d got %d", pos, ii.Pos())
+			}
+		} else {
+			ii.Next()
+		}
+	}
+	if strings.Join(seen, " ") != "0 1 2 3 4 5" {
+		t.Errorf("Expected to see every element got %v", seen)
+	}
+	checkList(t, Dll1, "0 2 4")
+
+	// Remove the head and tail, then the last one.
+	ii := Dll1.Front()
+	ii.Remove()
+	if ii.Value().S != "2" || ii.Pos() != 0 {
+		t.Errorf("Expected 2 at 0 got %s at %d", ii.Value().S, ii.Pos())
+	}
+	checkList(t, Dll1, "2 4")
+	ii = Dll1.Rear()
+	ii.Remove()
+	if !ii.Done() {
+		t.Errorf("Expected to be done after removing the tail")
+	}
+	checkList(t, Dll1, "2")
+	ii = Dll1.Front()
+	ii.Remove()
+	checkList(t, Dll1, "")
+	if _, err := ii.Remove(); err != ErrNoCurrent {
+		t.Errorf("Expected ErrNoCurrent got %v", err)
+	}
+}
+
+func TestIterMove(t *testing.T) {
+	Dll1 := newList("a", "b", "c", "d")
+
+	ii := Dll1.Front()
+	ii.Next()
+	ii.Next() // at c
+	el := ii.cur
+	if err := ii.MoveToFront(); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+	checkList(t, Dll1, "c a b d")
+	if ii.Value().S != "d" || ii.Pos() != 3 {
+		t.Errorf("Expected d at 3 got %s at %d", ii.Value().S, ii.Pos())
+	}
+	if Dll1.head != el {
+		t.Errorf("Expected the element to be moved, not copied")
+	}
+
+	ii = Dll1.Front() // at c
+	ii.MoveToBack()
+	checkList(t, Dll1, "a b d c")
+	if ii.Value().S != "a" || ii.Pos() != 0 {
+		t.Errorf("Expected a at 0 got %s at %d", ii.Value().S, ii.Pos())
+	}
+
+	// Moving the tail to the back, or the head to the front, leaves the order alone.
+	ii = Dll1.Rear()
+	ii.MoveToBack()
+	ii = Dll1.Front()
+	ii.MoveToFront()
+	checkList(t, Dll1, "a b d c")
+	if ii.Value().S != "b" || ii.Pos() != 1 {
+		t.Errorf("Expected b at 1 got %s at %d", ii.Value().S, ii.Pos())
+	}
+
+	// A scheduler style pass, each element that is not done goes to the back once.
+	Dll2 := newList("1", "2", "3")
+	ii = Dll2.Front()
+	for n := Dll2.Length(); n > 0; n-- {
+		ii.MoveToBack()
+	}
+	checkList(t, Dll2, "1 2 3")
+
+	Dll3 := newList("x")
+	ii = Dll3.Front()
+	ii.MoveToBack()
+	checkList(t, Dll3, "x")
+	if ii.MoveToFront() != ErrNoCurrent || ii.MoveToBack() != ErrNoCurrent {
+		t.Errorf("Expected ErrNoCurrent when the iterator is done")
+	}
+}
+
+func TestIterSplice(t *testing.T) {
+	Dll1 := newList("a", "b", "c")
+	Dll2 := newList("x", "y")
+
+	ii := Dll1.Front() // at a
+	if err := ii.Splice(Dll2); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+	checkList(t, Dll1, "a x y b c")
+	checkList(t, Dll2, "")
+	ii.Next()
+	if ii.Value().S != "x" || ii.Pos() != 1 {
+		t.Errorf("Expected x at 1 got %s at %d", ii.Value().S, ii.Pos())
+	}
+
+	// Splice at the tail, and the spliced list can be used again.
+	Dll2.AppendAtTail(&TestDemo{S: "z"})
+	ii = Dll1.Rear()
+	ii.Splice(Dll2)
+	checkList(t, Dll1, "a x y b c z")
+	checkList(t, Dll2, "")
+	Dll2.AppendAtTail(&TestDemo{S: "w"})
+	checkList(t, Dll2, "w")
+
+	if err := ii.Splice(NewDll[TestDemo]()); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+	if err := ii.Splice(Dll1); err != ErrSameList {
+		t.Errorf("Expected ErrSameList got %v", err)
+	}
+	checkList(t, Dll1, "a x y b c z")
+}
+
+func TestIterFailFast(t *testing.T) {
+	Dll1 := newList("a", "b", "c")
+
+	// A change by one iterator makes the other one fail, but not itself.
+	ii := Dll1.Front()
+	jj := Dll1.Front()
+	ii.Remove()
+	if ii.Err() != nil || ii.Done() || ii.Value().S != "b" {
+		t.Errorf("Expected the iterator that made the change to still be good")
+	}
+	if !jj.Done() || jj.Err() != ErrListChanged || jj.Value() != nil {
+		t.Errorf("Expected the other iterator to fail with ErrListChanged")
+	}
+	if jj.InsertAfter(&TestDemo{S: "x"}) != ErrListChanged {
+		t.Errorf("Expected a cursor operation to fail with ErrListChanged")
+	}
+	jj.Next()
+	if jj.Err() != ErrListChanged {
+		t.Errorf("Expected the iterator to stay failed")
+	}
+	checkList(t, Dll1, "b c")
+
+	// A change with a list method in the middle of a walk stops the walk.
+	n := 0
+	for ii = Dll1.Front(); !ii.Done(); ii.Next() {
+		if n == 0 {
+			Dll1.AppendAtTail(&TestDemo{S: "d"})
+		}
+		n++
+	}
+	if n != 1 || ii.Err() != ErrListChanged {
+		t.Errorf("Expected the walk to stop after 1 with ErrListChanged, got %d %v", n, ii.Err())
+	}
+
+	// Changing the data of an element does not change the list, and a walk that finishes
+	// has no error.
+	for ii = Dll1.Front(); !ii.Done(); ii.Next() {
+		ii.cur.SetData(&TestDemo{S: ii.Value().S + "1"})
+	}
+	if ii.Err() != nil {
+		t.Errorf("Unexpected error %s", ii.Err())
+	}
+	checkList(t, Dll1, "b1 c1 d1")
+
+	// Splice changes both lists.
+	Dll2 := newList("x")
+	kk := Dll2.Front()
+	ii = Dll1.Front()
+	ii.Splice(Dll2)
+	if kk.Err() != ErrListChanged {
+		t.Errorf("Expected an iterator on the spliced list to fail")
+	}
+	checkList(t, Dll1, "b1 x c1 d1")
+}
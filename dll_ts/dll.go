@@ -26,9 +26,11 @@ This list has head-and-tail pointers.
 *	ReverseWalk - Iterate from tail to head of list. 											O(n)
 *	Search — Returns the given element from a linked list.  Search is from head to tail.		O(n) n/2
 *	Truncate - Delete all the nodes in list. 													O(1)
+*	ConvertToSlice - Return the data from head to tail as a slice.								O(n)
 *	Walk - Iterate from head to tail of list. 													O(n)
 *	Trim - Cut list to specified length - list is unchanged if longer than this length.			O(n) n passed
 *	DeleteSearch — Deletes a specified element from the linked list Search from Head to Tail 	O(n)
+*	Concat - Appends the data from another list to the end of this list.						O(m)
 
 With the basic stack operations it also can be used as a stack:
 *	Push — Inserts an element at the top														O(1)
@@ -44,6 +46,23 @@ With the use of Enque can be used as a Queue.  This is a synonym for AppendAtTai
 * 	PopTail - Remvoe the element at the end of the DLL.											O(1)
 *	Enque - add to the tail so that DLL can be used as a Queue.									O(1)
 
+With an iterator from Front, Rear or Current the list can be changed at the current element
+(see cursor.go):
+
+*	InsertBefore - Inserts a new element before the current element.							O(1)
+*	InsertAfter - Inserts a new element after the current element.								O(1)
+*	Remove - Deletes the current element and moves on to the next one.							O(1)
+*	MoveToFront - Moves the current element to the head and moves on to the next one.			O(1)
+*	MoveToBack - Moves the current element to the tail and moves on to the next one.			O(1)
+*	Splice - Moves all the elements of another list in after the current element.				O(1)
+*	Err - ErrListChanged if the list was changed other than by this iterator.					O(1)
+
+Sort, MergeSorted, SplitAt, SpliceAfter and Dedup re-link the nodes of lists without copying
+them, see listops.go.
+
+An iterator fails fast: if the list is changed while it is in use, other than by its own
+cursor methods, then it is Done and Err returns ErrListChanged.
+
 This version of the DLL is not suitable for concurrnet usage but ../DLLTs has mutex
 locks so that it is thread safe.  It has the exact same interface.
 
@@ -53,9 +72,11 @@ import (
 	"errors"
 	"iter"
 	"sync"
+	"sync/atomic"
 
 	"github.com/pschlump/pluto/comparable"
 	"github.com/pschlump/pluto/g_lib"
+	"github.com/pschlump/pluto/iface_list"
 )
 
 // A node in the doubly linked list
@@ -68,14 +89,23 @@ type DllElement[T comparable.Equality] struct {
 type Dll[T comparable.Equality] struct {
 	head, tail *DllElement[T]
 	length     int
+	version    int           // changed on each change to the list, so an iterator can tell
+	id         atomic.Uint64 // orders the locks when more than one list is locked, see lockLists
 	mu         sync.RWMutex
 }
 
+// At compile time verify that Dll can be used behind the iface_list interfaces.
+var _ iface_list.LinearDataType[comparable.Int] = (*Dll[comparable.Int])(nil)
+var _ iface_list.StackDataType[comparable.Int] = (*Dll[comparable.Int])(nil)
+var _ iface_list.QueueDataType[comparable.Int] = (*Dll[comparable.Int])(nil)
+
 // An iteration type that allows a for loop to walk the list.
 type DllIter[T comparable.Equality] struct {
 	cur      *DllElement[T]
 	dll      *Dll[T]
 	pos      int
+	version  int   // the version of the list this iterator has seen
+	err      error // ErrListChanged if the list was changed by someone else
 	iterLock sync.RWMutex
 }
 
@@ -105,18 +135,24 @@ func (ee *DllElement[T]) SetData(d *T) {
 
 // Front will start at the beginning of a list for iteration over list.
 func (ns *Dll[T]) Front() *DllIter[T] {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
 	return &DllIter[T]{
-		cur: ns.head,
-		dll: ns,
+		cur:     ns.head,
+		dll:     ns,
+		version: ns.version,
 	}
 }
 
 // Rear will start at the end of a list for iteration over list.
 func (ns *Dll[T]) Rear() *DllIter[T] {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
 	return &DllIter[T]{
-		cur: ns.tail,
-		dll: ns,
-		pos: ns.length - 1,
+		cur:     ns.tail,
+		dll:     ns,
+		pos:     ns.length - 1,
+		version: ns.version,
 	}
 }
 
@@ -126,19 +162,26 @@ func (ns *Dll[T]) Rear() *DllIter[T] {
 //
 // and allow you to start an iteration process from that point.
 func (ns *Dll[T]) Current(el *DllElement[T], pos int) *DllIter[T] {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
 	return &DllIter[T]{
-		cur: el,
-		dll: ns,
-		pos: pos,
+		cur:     el,
+		dll:     ns,
+		pos:     pos,
+		version: ns.version,
 	}
 }
 
 // Value returns the current data for this element in the list.
+// If the list was changed by something other than this iterator then nil is returned.
 func (iter *DllIter[T]) Value() *T {
-	(*iter).iterLock.RLock()
-	defer (*iter).iterLock.RUnlock()
+	(*iter).iterLock.Lock()
+	defer (*iter).iterLock.Unlock()
 	iter.dll.mu.RLock()
 	defer iter.dll.mu.RUnlock()
+	if iter.noLockCheck() != nil {
+		return nil
+	}
 	if iter.cur != nil {
 		return iter.cur.Data
 	}
@@ -151,7 +194,7 @@ func (iter *DllIter[T]) Next() {
 	defer (*iter).iterLock.Unlock()
 	iter.dll.mu.RLock()
 	defer iter.dll.mu.RUnlock()
-	if iter.cur == nil {
+	if iter.noLockCheck() != nil || iter.cur == nil {
 		return
 	}
 	iter.cur = iter.cur.next
@@ -164,18 +207,50 @@ func (iter *DllIter[T]) Prev() {
 	defer (*iter).iterLock.Unlock()
 	iter.dll.mu.RLock()
 	defer iter.dll.mu.RUnlock()
-	if iter.cur == nil {
+	if iter.noLockCheck() != nil || iter.cur == nil {
 		return
 	}
 	iter.cur = iter.cur.prev
 	iter.pos--
 }
 
-// Done returns true if the end of the list has been reached.
+// Done returns true if the end of the list has been reached, or if the list was changed by
+// something other than this iterator, see Err.
 func (iter *DllIter[T]) Done() bool {
+	(*iter).iterLock.Lock()
+	defer (*iter).iterLock.Unlock()
+	iter.dll.mu.RLock()
+	defer iter.dll.mu.RUnlock()
+	return iter.noLockCheck() != nil || iter.cur == nil
+}
+
+// Err returns ErrListChanged if the list was changed while the iterator was in use by
+// something other than the iterator's own methods, nil otherwise.  Once the list is
+// changed the iterator is Done and stays that way, it has to be made again with Front,
+// Rear or Current.
+//
+//	ii := list.Front()
+//	for ; !ii.Done(); ii.Next() {
+//		...
+//	}
+//	if err := ii.Err(); err != nil {
+//		// the walk did not finish
+//	}
+func (iter *DllIter[T]) Err() error {
+	(*iter).iterLock.Lock()
+	defer (*iter).iterLock.Unlock()
 	iter.dll.mu.RLock()
 	defer iter.dll.mu.RUnlock()
-	return iter.cur == nil
+	return iter.noLockCheck()
+}
+
+// noLockCheck sets and returns ErrListChanged if the list is not at the version the iterator
+// last saw.  The caller must hold the iterator lock and a lock on the list.
+func (iter *DllIter[T]) noLockCheck() error {
+	if iter.err == nil && iter.version != iter.dll.version {
+		iter.err = ErrListChanged
+	}
+	return iter.err
 }
 
 // Pos returns the current "index" of the elemnt being iterated on.  So if the list has 3 elements, a, b, c and we
@@ -196,6 +271,7 @@ func (ns *Dll[T]) IsEmpty() bool {
 
 func (ns *Dll[T]) noLockInsertBeforeHead(t *T) {
 	x := DllElement[T]{Data: t} // Create the node
+	ns.version++
 	if (*ns).head == nil {
 		(*ns).head = &x
 		(*ns).tail = &x
@@ -223,9 +299,14 @@ func (ns *Dll[T]) Push(t *T) {
 
 // Push will append a new node to the end of the list.
 func (ns *Dll[T]) AppendAtTail(t *T) {
-	x := DllElement[T]{Data: t} // Create the node
 	ns.mu.Lock()
 	defer ns.mu.Unlock()
+	ns.noLockAppendAtTail(t)
+}
+
+func (ns *Dll[T]) noLockAppendAtTail(t *T) {
+	x := DllElement[T]{Data: t} // Create the node
+	ns.version++
 	if ns.head == nil {
 		ns.head = &x
 		ns.tail = &x
@@ -254,6 +335,7 @@ var ErrNotFound = errors.New("Not Found")
 var ErrEmptyDll = errors.New("Empty Dll")
 var ErrInteralDll = errors.New("Interal Dll")
 var ErrOutOfRange = errors.New("Subscript Out of Range")
+var ErrListChanged = errors.New("List Changed during Iteration")
 
 // Pop will remove the top element from the DLL.  An error is returned if the stack is empty.
 func (ns *Dll[T]) Pop() (rv *T, err error) {
@@ -277,10 +359,13 @@ func (ns *Dll[T]) noLockPop() (rv *T, err error) {
 	if ns.length == 0 {
 		return nil, ErrEmptyDll
 	}
+	ns.version++
 	rv = ns.head.Data
 	ns.head = ns.head.next
 	if ns.head != nil {
 		ns.head.prev = nil
+	} else {
+		ns.tail = nil
 	}
 	ns.length--
 	return
@@ -292,10 +377,13 @@ func (ns *Dll[T]) noLockPopTail() (rv *T, err error) {
 	if ns.length == 0 {
 		return nil, ErrEmptyDll
 	}
+	ns.version++
 	rv = ns.tail.Data
 	ns.tail = ns.tail.prev
 	if ns.tail != nil {
 		ns.tail.next = nil
+	} else {
+		ns.head = nil
 	}
 	ns.length--
 	return
@@ -308,6 +396,7 @@ func (ns *Dll[T]) Delete(it *DllElement[T]) (err error) {
 }
 
 func (ns *Dll[T]) noLockDelete(it *DllElement[T]) (err error) {
+	ns.version++
 	if ns.head == it && ns.tail == it {
 		ns.head = nil
 		ns.tail = nil
@@ -345,9 +434,12 @@ func (ns *Dll[T]) DeleteAtTail() (err error) {
 	if ns.length == 0 {
 		return ErrEmptyDll
 	}
+	ns.version++
 	ns.tail = ns.tail.prev
 	if ns.tail != nil {
 		ns.tail.next = nil
+	} else {
+		ns.head = nil
 	}
 	ns.length--
 	return
@@ -381,6 +473,7 @@ func (ns *Dll[T]) PeekTail() (rv *T, err error) {
 func (ns *Dll[T]) Truncate() {
 	ns.mu.Lock()
 	defer ns.mu.Unlock()
+	ns.version++
 	ns.head = nil
 	ns.tail = nil
 	ns.length = 0
@@ -505,6 +598,7 @@ func (ns *Dll[T]) ReverseList() {
 	}
 	ns.head = tmp.head
 	ns.tail = tmp.tail
+	ns.version++
 }
 
 // Index will return the Nth item from the list.
@@ -586,6 +680,7 @@ func (ns *Dll[T]) noLockTrim(n int) (err error) {
 	if ns.length <= n { // Truncate
 		return
 	}
+	ns.version++
 	n-- // convert from Length to index
 	tmp := ns.head
 	for i := 0; i < n && tmp != nil; i++ {
@@ -616,6 +711,7 @@ func (ns *Dll[T]) noLockTrimTail(n int) (err error) {
 	if ns.length <= n { // Truncate
 		return
 	}
+	ns.version++
 	n-- // convert from Length to index
 	tmp := ns.tail
 	for i := 0; i < n && tmp != nil; i++ {
@@ -627,22 +723,29 @@ func (ns *Dll[T]) noLockTrimTail(n int) (err error) {
 	return
 }
 
-// xyzzy TODO - concat (which way, concat 2 into a new one? //
+// Concat appends the data from `yy` to the end of this list, `yy` is not changed.  The new
+// elements point to the same data as the elements of `yy`.  Both lists are locked, in a fixed
+// order, see lockLists.  A list can be appended to itself, ns.Concat(ns), which doubles it.
+// To move the elements of a list, not copy them, use SpliceAfter.
+// Complexity is O(m), m the length of `yy`.
 func (ns *Dll[T]) Concat(yy *Dll[T]) {
 	if ns == nil {
 		panic("list sholud not be a nil")
 	}
+	if yy == nil {
+		return
+	}
 
-	ns.mu.Lock()
-	yy.mu.RLock()
-	defer yy.mu.RUnlock()
-	defer ns.mu.Unlock()
+	unlock := lockLists([]*Dll[T]{ns}, []*Dll[T]{yy})
+	defer unlock()
 
-	// Walk list and add to end of ns
-	for ptr := yy.head; ptr != nil; ptr = ptr.next {
-		ns.AppendAtTail(ptr.Data)
+	// Walk list and add to end of ns, only the elements that were there at the start in case
+	// yy is ns.
+	ptr := yy.head
+	for n := yy.length; n > 0; n-- {
+		ns.noLockAppendAtTail(ptr.Data)
+		ptr = ptr.next
 	}
-
 }
 
 // Reverse - effeciently reverse direciotn on a list.  O(n) with storage O(1)
@@ -656,6 +759,7 @@ func (ns *Dll[T]) Reverse() {
 	ns.mu.Lock()
 	defer ns.mu.Unlock()
 
+	ns.version++
 	for cp := ns.head; cp != nil; cp = next {
 		next = cp.next // save next pointer at beginning
 		cp.next, cp.prev = cp.prev, cp.next
@@ -665,6 +769,17 @@ func (ns *Dll[T]) Reverse() {
 
 }
 
+// ConvertToSlice returns the data in the list from head to tail.		O(n)
+func (ns *Dll[T]) ConvertToSlice() (rv []*T) {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
+	rv = make([]*T, 0, ns.length)
+	for p := ns.head; p != nil; p = p.next {
+		rv = append(rv, p.Data)
+	}
+	return
+}
+
 func (ns *Dll[T]) Lock() {
 	ns.mu.Lock()
 }
@@ -0,0 +1,53 @@
+package dll_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+// This test is meant to be run with the race detector, `go test -race`.
+
+import (
+	"testing"
+)
+
+func TestListOpsGoroutines(t *testing.T) {
+	// Each goroutine works on both lists, in opposite orders, which must not deadlock.  The
+	// elements move between the lists but none are lost or copied except by Concat.
+	List1, List2 := newList("1", "3", "5"), newList("0", "2", "4")
+	done := make(chan bool)
+	for gg := 0; gg < 2; gg++ {
+		go func(gg int) {
+			aa, bb := List1, List2
+			if gg == 1 {
+				aa, bb = List2, List1
+			}
+			for k := 0; k < 200; k++ {
+				aa.MergeSorted(bb, byString)
+				aa.Sort(byString)
+				front, back := aa.SplitAt(aa.Length() / 2)
+				aa.SpliceAfter(nil, front)
+				bb.SpliceAfter(nil, back)
+			}
+			done <- true
+		}(gg)
+	}
+	<-done
+	<-done
+
+	List1.MergeSorted(List2, byString)
+	checkList(t, List1, "0 1 2 3 4 5")
+
+	List3 := newList("x")
+	go func() {
+		List1.Concat(List3)
+		done <- true
+	}()
+	List3.Concat(List1)
+	<-done
+	// Either Concat can go first, 6+1 then 1+7, or 1+6 then 6+7.
+	if n1, n3 := List1.Length(), List3.Length(); !(n1 == 7 && n3 == 8) && !(n1 == 13 && n3 == 7) {
+		t.Errorf("Unexpected lengths %d %d", List1.Length(), List3.Length())
+	}
+}
@@ -0,0 +1,147 @@
+package dot
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+Graphviz DOT support shared by the data structures.  See https://graphviz.org/Gallery/directed/datastruct.html
+for what graphviz can do with the output.
+
+Each data structure has a `WriteDOT(fo io.Writer, opts *dot.Options) error` method that writes
+a `digraph`.  The output can be turned into a picture with
+
+	dot -Tsvg -o tree.svg tree.dot
+
+Parse reads a simple `digraph` back in.  It is used to build a ../dag from a file.
+
+*/
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Options controls how a data structure is written as DOT.  A nil *Options is the same as
+// the zero value.
+type Options struct {
+	Name      string             // Name of the graph, "G" if empty.
+	RankDir   string             // Graphviz rankdir, "TB" (the default), "LR", "BT" or "RL".
+	Label     func(x any) string // Label for the data in a node, fmt "%v" if nil.
+	Attrs     map[string]string  // Extra attributes for the graph, written in sorted order.
+	NodeAttrs map[string]string  // Extra attributes for every node, written in sorted order.
+}
+
+// GetName returns the name of the graph.
+func (opts *Options) GetName() string {
+	if opts == nil || opts.Name == "" {
+		return "G"
+	}
+	return opts.Name
+}
+
+// NodeLabel returns the label for the data `x`.
+func (opts *Options) NodeLabel(x any) string {
+	if opts == nil || opts.Label == nil {
+		return fmt.Sprintf("%v", x)
+	}
+	return opts.Label(x)
+}
+
+// Quote returns `s` as a DOT double quoted string.
+func Quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, c := range s {
+		switch c {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// FormatAttrs returns `attrs` as a DOT attribute list, `[a="b", c="d"]`, with the
+// attributes in sorted order.  An empty string is returned if there are no attributes.
+func FormatAttrs(attrs map[string]string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	keys := sortedKeys(attrs)
+	ss := make([]string, 0, len(keys))
+	for _, kk := range keys {
+		ss = append(ss, fmt.Sprintf("%s=%s", kk, Quote(attrs[kk])))
+	}
+	return "[" + strings.Join(ss, ", ") + "]"
+}
+
+// WriteHeader writes the start of a digraph with the graph and node attributes from `opts`.
+func WriteHeader(fo io.Writer, opts *Options) (err error) {
+	if _, err = fmt.Fprintf(fo, "digraph %s {\n", Quote(opts.GetName())); err != nil {
+		return
+	}
+	if opts != nil && opts.RankDir != "" {
+		if _, err = fmt.Fprintf(fo, "\trankdir=%s;\n", Quote(opts.RankDir)); err != nil {
+			return
+		}
+	}
+	if opts != nil && len(opts.Attrs) > 0 {
+		if _, err = fmt.Fprintf(fo, "\tgraph %s;\n", FormatAttrs(opts.Attrs)); err != nil {
+			return
+		}
+	}
+	if opts != nil && len(opts.NodeAttrs) > 0 {
+		if _, err = fmt.Fprintf(fo, "\tnode %s;\n", FormatAttrs(opts.NodeAttrs)); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WriteNode writes a node statement.
+func WriteNode(fo io.Writer, id string, attrs map[string]string) (err error) {
+	if len(attrs) == 0 {
+		_, err = fmt.Fprintf(fo, "\t%s;\n", Quote(id))
+	} else {
+		_, err = fmt.Fprintf(fo, "\t%s %s;\n", Quote(id), FormatAttrs(attrs))
+	}
+	return
+}
+
+// WriteEdge writes an edge statement.
+func WriteEdge(fo io.Writer, from, to string, attrs map[string]string) (err error) {
+	if len(attrs) == 0 {
+		_, err = fmt.Fprintf(fo, "\t%s -> %s;\n", Quote(from), Quote(to))
+	} else {
+		_, err = fmt.Fprintf(fo, "\t%s -> %s %s;\n", Quote(from), Quote(to), FormatAttrs(attrs))
+	}
+	return
+}
+
+// WriteFooter writes the end of a digraph.
+func WriteFooter(fo io.Writer) (err error) {
+	_, err = fmt.Fprintf(fo, "}\n")
+	return
+}
+
+// sortedKeys returns the keys of `mm` in sorted order.
+func sortedKeys(mm map[string]string) (keys []string) {
+	keys = make([]string, 0, len(mm))
+	for kk := range mm {
+		keys = append(keys, kk)
+	}
+	sort.Strings(keys)
+	return
+}
@@ -0,0 +1,379 @@
+package dot
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Edge is an edge read by Parse.
+type Edge struct {
+	From, To string
+	Attrs    map[string]string
+}
+
+// Graph is what Parse read from a digraph.  Nodes are in the order they were first seen,
+// an edge adds its nodes if they have not been seen yet.
+type Graph struct {
+	Name      string
+	Nodes     []string
+	NodeAttrs map[string]map[string]string
+	Edges     []Edge
+}
+
+// An error to indicate that the input is not a digraph that Parse can read.
+var ErrSyntax = errors.New("DOT syntax error")
+
+// Parse reads a simple digraph:
+//
+//	digraph name {
+//		a;
+//		b [label="B"];
+//		a -> b -> c [weight=3];
+//	}
+//
+// Node and edge statements with attribute lists, chains of edges, `graph`/`node`/`edge`
+// default attribute statements and `id = id` graph attributes are read.  The last two are
+// skipped.  Comments (`//`, `#`, `/* */`) are allowed.  Subgraphs, ports and undirected
+// graphs are not supported and give an error that wraps ErrSyntax.
+func Parse(fi io.Reader) (gr *Graph, err error) {
+	buf, err := io.ReadAll(fi)
+	if err != nil {
+		return nil, err
+	}
+	ps := &parser{lx: lexer{src: []rune(string(buf)), line: 1}}
+	return ps.parseGraph()
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+type tokenType int
+
+const (
+	tokEOF tokenType = iota
+	tokID
+	tokPunct // one of { } [ ] ; , =  or the edge operator ->
+)
+
+type token struct {
+	typ  tokenType
+	val  string
+	line int
+}
+
+type lexer struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+func (lx *lexer) peekRune(n int) rune {
+	if lx.pos+n < len(lx.src) {
+		return lx.src[lx.pos+n]
+	}
+	return 0
+}
+
+// skipSpace skips white space and comments.
+func (lx *lexer) skipSpace() {
+	for lx.pos < len(lx.src) {
+		c := lx.src[lx.pos]
+		switch {
+		case c == '\n':
+			lx.line++
+			lx.pos++
+		case unicode.IsSpace(c):
+			lx.pos++
+		case c == '#' || (c == '/' && lx.peekRune(1) == '/'):
+			for lx.pos < len(lx.src) && lx.src[lx.pos] != '\n' {
+				lx.pos++
+			}
+		case c == '/' && lx.peekRune(1) == '*':
+			lx.pos += 2
+			for lx.pos < len(lx.src) && !(lx.src[lx.pos] == '*' && lx.peekRune(1) == '/') {
+				if lx.src[lx.pos] == '\n' {
+					lx.line++
+				}
+				lx.pos++
+			}
+			lx.pos += 2
+		default:
+			return
+		}
+	}
+}
+
+func (lx *lexer) next() (tok token, err error) {
+	lx.skipSpace()
+	tok.line = lx.line
+	if lx.pos >= len(lx.src) {
+		tok.typ = tokEOF
+		return
+	}
+	c := lx.src[lx.pos]
+	switch {
+	case c == '-' && lx.peekRune(1) == '>':
+		lx.pos += 2
+		tok.typ, tok.val = tokPunct, "->"
+	case c == '-' && lx.peekRune(1) == '-':
+		return tok, fmt.Errorf("%w: line %d: undirected edge '--' is not supported", ErrSyntax, lx.line)
+	case strings.ContainsRune("{}[];,=", c):
+		lx.pos++
+		tok.typ, tok.val = tokPunct, string(c)
+	case c == '"':
+		lx.pos++
+		var b strings.Builder
+		for {
+			if lx.pos >= len(lx.src) {
+				return tok, fmt.Errorf("%w: line %d: string not terminated", ErrSyntax, tok.line)
+			}
+			c = lx.src[lx.pos]
+			lx.pos++
+			if c == '"' {
+				break
+			}
+			if c == '\n' {
+				lx.line++
+			}
+			if c == '\\' && lx.pos < len(lx.src) {
+				switch e := lx.src[lx.pos]; e {
+				case '"', '\\':
+					c = e
+					lx.pos++
+				case 'n':
+					c = '\n'
+					lx.pos++
+				}
+			}
+			b.WriteRune(c)
+		}
+		tok.typ, tok.val = tokID, b.String()
+	case c == '_' || c == '.' || c == '-' || unicode.IsLetter(c) || unicode.IsDigit(c):
+		st := lx.pos
+		for lx.pos < len(lx.src) {
+			c = lx.src[lx.pos]
+			if !(c == '_' || c == '.' || unicode.IsLetter(c) || unicode.IsDigit(c)) && !(c == '-' && lx.pos == st) {
+				break
+			}
+			lx.pos++
+		}
+		tok.typ, tok.val = tokID, string(lx.src[st:lx.pos])
+	default:
+		return tok, fmt.Errorf("%w: line %d: unexpected character %q", ErrSyntax, lx.line, c)
+	}
+	return
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+type parser struct {
+	lx     lexer
+	tok    token
+	peeked bool
+	gr     *Graph
+}
+
+func (ps *parser) peek() (token, error) {
+	if !ps.peeked {
+		tok, err := ps.lx.next()
+		if err != nil {
+			return tok, err
+		}
+		ps.tok, ps.peeked = tok, true
+	}
+	return ps.tok, nil
+}
+
+func (ps *parser) next() (token, error) {
+	tok, err := ps.peek()
+	ps.peeked = false
+	return tok, err
+}
+
+func (ps *parser) expect(val string) error {
+	tok, err := ps.next()
+	if err != nil {
+		return err
+	}
+	if tok.typ != tokPunct || tok.val != val {
+		return fmt.Errorf("%w: line %d: expected '%s' found '%s'", ErrSyntax, tok.line, val, tok.val)
+	}
+	return nil
+}
+
+func isKeyword(tok token, kw string) bool {
+	return tok.typ == tokID && strings.EqualFold(tok.val, kw)
+}
+
+func (ps *parser) parseGraph() (*Graph, error) {
+	ps.gr = &Graph{NodeAttrs: make(map[string]map[string]string)}
+	tok, err := ps.next()
+	if err != nil {
+		return nil, err
+	}
+	if isKeyword(tok, "strict") {
+		if tok, err = ps.next(); err != nil {
+			return nil, err
+		}
+	}
+	if !isKeyword(tok, "digraph") {
+		return nil, fmt.Errorf("%w: line %d: expected 'digraph' found '%s'", ErrSyntax, tok.line, tok.val)
+	}
+	if tok, err = ps.peek(); err != nil {
+		return nil, err
+	}
+	if tok.typ == tokID {
+		ps.gr.Name = tok.val
+		ps.next()
+	}
+	if err = ps.expect("{"); err != nil {
+		return nil, err
+	}
+	for {
+		if tok, err = ps.peek(); err != nil {
+			return nil, err
+		}
+		switch {
+		case tok.typ == tokEOF:
+			return nil, fmt.Errorf("%w: line %d: missing '}'", ErrSyntax, tok.line)
+		case tok.typ == tokPunct && tok.val == "}":
+			ps.next()
+			return ps.gr, nil
+		case tok.typ == tokPunct && tok.val == ";":
+			ps.next()
+		default:
+			if err = ps.parseStmt(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (ps *parser) parseStmt() error {
+	tok, err := ps.next()
+	if err != nil {
+		return err
+	}
+	if tok.typ != tokID {
+		return fmt.Errorf("%w: line %d: unexpected '%s'", ErrSyntax, tok.line, tok.val)
+	}
+	if isKeyword(tok, "subgraph") {
+		return fmt.Errorf("%w: line %d: subgraph is not supported", ErrSyntax, tok.line)
+	}
+
+	nx, err := ps.peek()
+	if err != nil {
+		return err
+	}
+	// Default attributes, `graph [...]`, `node [...]`, `edge [...]` are skipped.
+	if (isKeyword(tok, "graph") || isKeyword(tok, "node") || isKeyword(tok, "edge")) && nx.typ == tokPunct && nx.val == "[" {
+		_, err = ps.parseAttrs()
+		return err
+	}
+	// Graph attribute, `id = id`, is skipped.
+	if nx.typ == tokPunct && nx.val == "=" {
+		ps.next()
+		if tok, err = ps.next(); err != nil {
+			return err
+		}
+		if tok.typ != tokID {
+			return fmt.Errorf("%w: line %d: expected a value after '='", ErrSyntax, tok.line)
+		}
+		return nil
+	}
+	// Node or a chain of edges.
+	ids := []string{tok.val}
+	for nx.typ == tokPunct && nx.val == "->" {
+		ps.next()
+		if tok, err = ps.next(); err != nil {
+			return err
+		}
+		if tok.typ != tokID {
+			return fmt.Errorf("%w: line %d: expected a node after '->' found '%s'", ErrSyntax, tok.line, tok.val)
+		}
+		ids = append(ids, tok.val)
+		if nx, err = ps.peek(); err != nil {
+			return err
+		}
+	}
+	var attrs map[string]string
+	if nx.typ == tokPunct && nx.val == "[" {
+		if attrs, err = ps.parseAttrs(); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range ids {
+		ps.addNode(id)
+	}
+	if len(ids) == 1 {
+		for kk, vv := range attrs {
+			ps.gr.NodeAttrs[ids[0]][kk] = vv
+		}
+		return nil
+	}
+	for i := 1; i < len(ids); i++ {
+		ea := make(map[string]string, len(attrs))
+		for kk, vv := range attrs {
+			ea[kk] = vv
+		}
+		ps.gr.Edges = append(ps.gr.Edges, Edge{From: ids[i-1], To: ids[i], Attrs: ea})
+	}
+	return nil
+}
+
+func (ps *parser) addNode(id string) {
+	if _, found := ps.gr.NodeAttrs[id]; !found {
+		ps.gr.Nodes = append(ps.gr.Nodes, id)
+		ps.gr.NodeAttrs[id] = make(map[string]string)
+	}
+}
+
+// parseAttrs reads one or more `[ a=b, c=d; e=f ]` lists.
+func (ps *parser) parseAttrs() (attrs map[string]string, err error) {
+	attrs = make(map[string]string)
+	for {
+		tok, err := ps.peek()
+		if err != nil {
+			return nil, err
+		}
+		if tok.typ != tokPunct || tok.val != "[" {
+			return attrs, nil
+		}
+		ps.next()
+		for {
+			if tok, err = ps.next(); err != nil {
+				return nil, err
+			}
+			if tok.typ == tokPunct && tok.val == "]" {
+				break
+			}
+			if tok.typ == tokPunct && (tok.val == "," || tok.val == ";") {
+				continue
+			}
+			if tok.typ != tokID {
+				return nil, fmt.Errorf("%w: line %d: expected an attribute name found '%s'", ErrSyntax, tok.line, tok.val)
+			}
+			key := tok.val
+			if err = ps.expect("="); err != nil {
+				return nil, err
+			}
+			val, err := ps.next()
+			if err != nil {
+				return nil, err
+			}
+			if val.typ != tokID {
+				return nil, fmt.Errorf("%w: line %d: expected a value for '%s'", ErrSyntax, val.line, key)
+			}
+			attrs[key] = val.val
+		}
+	}
+}
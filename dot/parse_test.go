@@ -0,0 +1,95 @@
+package dot
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	src := `
+// Build steps
+digraph "build" {
+	rankdir=LR;
+	node [shape=box];
+	fetch;
+	"compile step" [label="Compile"];   # a comment
+	fetch -> "compile step" -> test [weight=3];
+	/* a
+	   block comment */
+	lint -> pkg
+	test -> pkg;
+}
+`
+	gr, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if gr.Name != "build" {
+		t.Errorf("Expected name build got %s", gr.Name)
+	}
+	expect := []string{"fetch", "compile step", "test", "lint", "pkg"}
+	if !reflect.DeepEqual(gr.Nodes, expect) {
+		t.Errorf("Nodes error, expcted %s got %s", expect, gr.Nodes)
+	}
+	if gr.NodeAttrs["compile step"]["label"] != "Compile" {
+		t.Errorf("Expected label Compile got %v", gr.NodeAttrs["compile step"])
+	}
+	if len(gr.Edges) != 4 {
+		t.Fatalf("Expected 4 edges got %d", len(gr.Edges))
+	}
+	if ee := gr.Edges[1]; ee.From != "compile step" || ee.To != "test" || ee.Attrs["weight"] != "3" {
+		t.Errorf("Unexpected edge %+v", ee)
+	}
+	if ee := gr.Edges[2]; ee.From != "lint" || ee.To != "pkg" || len(ee.Attrs) != 0 {
+		t.Errorf("Unexpected edge %+v", ee)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{
+		`graph g { a -- b }`,
+		`digraph g { a -- b }`,
+		`digraph g { subgraph x { a } }`,
+		`digraph g { a -> }`,
+		`digraph g { a [label=] }`,
+		`digraph g { a -> b`,
+		`digraph g { "a -> b }`,
+		`digraph g { a:p1 -> b }`,
+	} {
+		if _, err := Parse(strings.NewReader(src)); !errors.Is(err, ErrSyntax) {
+			t.Errorf("Expected ErrSyntax for %s got %v", src, err)
+		}
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	opts := &Options{Name: "t", RankDir: "LR", NodeAttrs: map[string]string{"shape": "box"}}
+	WriteHeader(&buf, opts)
+	WriteNode(&buf, `say "hi"`, map[string]string{"label": "a\nb"})
+	WriteEdge(&buf, `say "hi"`, "x", nil)
+	WriteFooter(&buf)
+	if !strings.Contains(buf.String(), "\trankdir=\"LR\";\n") {
+		t.Errorf("Expected a quoted rankdir got %s", buf.String())
+	}
+
+	gr, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if expect := []string{`say "hi"`, "x"}; !reflect.DeepEqual(gr.Nodes, expect) {
+		t.Errorf("Nodes error, expcted %q got %q", expect, gr.Nodes)
+	}
+	if gr.NodeAttrs[`say "hi"`]["label"] != "a\nb" {
+		t.Errorf("Label did not round trip, got %q", gr.NodeAttrs[`say "hi"`]["label"])
+	}
+}
@@ -0,0 +1,43 @@
+package hash_grow_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/pschlump/pluto/containertest"
+	"github.com/pschlump/pluto/hashing"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// testItem makes the data for the shared TestHasher.
+var testItem = containertest.Item[TestData]{
+	New: func(n int) *TestData { return &TestData{S: fmt.Sprintf("%04d", n)} },
+	Key: func(x *TestData) int { n, _ := strconv.Atoi(x.S); return n },
+}
+
+// bucketOf returns the bucket that the hash of `x` picks in the current table, the table it
+// is in can be the old one during a resize.
+func bucketOf(st iface_list.SetDataType[TestData], x *TestData) int {
+	ht := st.(*HashTab[TestData])
+	for _, tb := range []*table[TestData]{&ht.cur, &ht.old} {
+		for h, item := range tb.buckets {
+			if item == x {
+				return tb.originalHash[h] % ht.cur.size
+			}
+		}
+	}
+	return -1
+}
+
+func TestHasher(t *testing.T) {
+	containertest.TestHasher(t, func(hh hashing.Hasher[TestData]) iface_list.SetDataType[TestData] {
+		return NewHashTab[TestData](7, 0, hashing.WithHasher(hh))
+	}, testItem, bucketOf)
+}
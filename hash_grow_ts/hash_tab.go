@@ -10,8 +10,8 @@ BSD 3 Clause Licensed. See ../LICENSE
 
 Basic operations on a Hash Table.
 
-* 	Insert - create a new element in tree.														O(log|2(n))
-*  	Delete — Deletes a specified element from the linked list (Element can be fond via Search). O(log|2(n))
+* 	Insert - create a new element in tree.														O(1) average
+*  	Delete — Deletes a specified element from the linked list (Element can be fond via Search). O(1) average
 * 	IsEmpty — Returns true if the linked list is empty											O(1)
 * 	Length — Returns number of elements in the list.  0 length is an empty list.				O(1)
 * 	Search — Returns the given element from a linked list.  Search is from head to tail.		O(n/k) where k is # of buckets.
@@ -19,8 +19,18 @@ Basic operations on a Hash Table.
 *	Walk - Walk the table																		O(n)
 *	Print - Using Walk to print out the contents of the table.									O(n)
 
-Possibly change to an extensible size with layers, so max dups in a layer or saturation causes
-geneation of a new layer - and not a re-hash of all existing keys.
+The table is resized without a re-hash of all existing keys in one call.  When it passes the
+saturation threshold a new table of twice the size is made, and when a Delete takes it below a
+low-water mark (1/4 of the saturation threshold) a new table of half the size is made, but
+never smaller than the size it was created with.  Until the resize is done there are 2 tables:
+new items go in the new one and each Insert and Delete moves the items from a few more buckets
+of the old one.  Search looks in both.  So no single call has to move all the items, the cost
+of the resize is spread over the calls that follow it.
+
+NewHashTabProbe picks the way a slot is found for an item when the one it hashes to is in use,
+linear (the default), quadratic or Robin Hood probing, see table.go.  Deletes with linear and
+quadratic probing leave tombstones, when there are too many the table is compacted with the
+same incremental resize but to a table of the same size.
 
 */
 
@@ -36,37 +46,64 @@ import (
 	"github.com/pschlump/pluto/binary_tree_ts"
 	"github.com/pschlump/pluto/comparable"
 	"github.com/pschlump/pluto/g_lib"
+	"github.com/pschlump/pluto/hashing"
+	"github.com/pschlump/pluto/iface_list"
 )
 
 // HashTab is a generic hash table that grows the underlying ttable when the number of
-// entries exceeds a threshold.    The table is doulbed in size.
+// entries exceeds a threshold.    The table is doulbed in size.  While it is being resized
+// the items are in 2 tables, see moveSome.
 type HashTab[T comparable.Comparable] struct {
-	buckets             []*T  // the table
-	originalHash        []int // the original hash values (used during delete, search)
-	size                int   // Modulo size for table	Current Size!
+	cur                 table[T]          // the table, new items go in this one
+	old                 table[T]          // the table that items are being moved out of, empty if not resizing
+	movePos             int               // the next bucket in old to move
+	minSize             int               // the table is not made smaller than this
+	probe               Probe             // how the next slot is found, see table.go
+	saturationThreshold float64           // Proportion before grow of table. (default 0.5)
+	lowWaterThreshold   float64           // Proportion before shrink of table. (saturationThreshold/4)
+	compactThreshold    float64           // Proportion of items and tombstones before compact of table. ((1+saturationThreshold)/2)
+	hasher              hashing.Hasher[T] // from hashing.WithHasher, nil to use HashKey or String()
 	lock                sync.RWMutex
-	length              int     // # of elements in table
-	saturationThreshold float64 // Proportion before grow of table. (default 0.5)
 }
 
+// At compile time verify that HashTab can be used behind the iface_list interfaces.
+var _ iface_list.SetDataType[comparable.Int] = (*HashTab[comparable.Int])(nil)
+
+// The number of buckets of the old table moved on each Insert or Delete during a resize.  With
+// 8 a grow is done before the new table is 1/3 full.
+const moveBuckets = 8
+
 type Hashable interface {
 	HashKey(x interface{}) int
 }
 
+// NewHashTab creates a hash table with `n` buckets that uses linear probing.  The items are
+// hashed with the Hasher from the hashing.WithHasher option, without one they have to be
+// Hashable or a fmt.Stringer.
+// Complexity is O(1).
+func NewHashTab[T comparable.Comparable](n int, saturation float64, opts ...hashing.Option[T]) *HashTab[T] {
+	return NewHashTabProbe[T](n, saturation, LinearProbe, opts...)
+}
+
+// NewHashTabProbe creates a hash table with `n` buckets that uses `probe` to find a slot.  With
+// QuadraticProbe `n` is rounded up to a power of 2.
 // Complexity is O(1).
-func NewHashTab[T comparable.Comparable](n int, saturation float64) *HashTab[T] {
+func NewHashTabProbe[T comparable.Comparable](n int, saturation float64, probe Probe, opts ...hashing.Option[T]) *HashTab[T] {
 	if n < 5 {
 		panic("n too small")
 	}
 	if saturation == 0 {
 		saturation = 0.5
 	}
+	n = tableSize(n, probe)
 	return &HashTab[T]{
-		length:              0,
-		size:                n,
+		cur:                 newTable[T](n, probe),
+		minSize:             n,
+		probe:               probe,
 		saturationThreshold: saturation,
-		buckets:             make([]*T, n, n),
-		originalHash:        make([]int, n, n),
+		lowWaterThreshold:   saturation / 4,
+		compactThreshold:    (1 + saturation) / 2,
+		hasher:              hashing.NewConfig(opts...).Hasher,
 	}
 }
 
@@ -75,114 +112,74 @@ func NewHashTab[T comparable.Comparable](n int, saturation float64) *HashTab[T]
 func (tt *HashTab[T]) IsEmpty() bool {
 	tt.lock.RLock()
 	defer tt.lock.RUnlock()
-	return tt.length == 0
+	return tt.nlIsEmpty()
 }
 
 func (tt *HashTab[T]) nlIsEmpty() bool {
-	return tt.length == 0
+	return tt.cur.length+tt.old.length == 0
 }
 
-// Truncate removes all data from the tree.
+// Truncate removes all data from the tree.  If a resize was in progress it is dropped, the
+// table keeps its current size.
 // Complexity is O(1).
 func (tt *HashTab[T]) Truncate() {
 	tt.lock.Lock()
 	defer tt.lock.Unlock()
-	for i := 0; i < tt.size; i++ {
-		tt.buckets[i] = nil
-	}
-	tt.length = 0
+	tt.cur.truncate()
+	tt.old = table[T]{}
 }
 
 // Insert will add a new item to the tree.  If it is a duplicate of an exiting
 // item the new item will replace the existing one.
-// Complexity is O(log n)/k.
+// Complexity is O(1) average, a resize adds O(1) to each of the following calls.
 func (tt *HashTab[T]) Insert(item *T) {
 	tt.lock.Lock()
 	defer tt.lock.Unlock()
-	rh := hash(item)
-
-	// Increment a position in table modulo the size of the table.
-	var incSize = func(xx int) (rv int) {
-		rv = xx + 1
-		if rv >= tt.size {
-			rv = 0
-		}
-		return
-	}
+	rh := tt.hashOf(item)
 
 	if db4 {
-		dbgo.Fprintf(os.Stderr, "%(cyan)AT:%(LF)\n")
+		dbgo.Fprintf(os.Stderr, "%(cyan)AT:%(LF), rh=%d tt.cur.size=%d\n", rh, tt.cur.size)
 	}
-	var insertNewItem = func(rh int, itemx *T, buckets []*T, originalHash []int) {
-		hh := rh % tt.size
-		if buckets[hh] == nil {
-			if db4 {
-				dbgo.Fprintf(os.Stderr, "%(cyan)AT:%(LF)\n")
-			}
-			buckets[hh] = itemx
-			originalHash[hh] = rh
-			tt.length++
-		} else if (*itemx).Compare(*buckets[hh]) == 0 {
-			if db4 {
-				dbgo.Fprintf(os.Stderr, "%(cyan)AT:%(LF)\n")
-			}
-			buckets[hh] = itemx // Replace, This means that you don't have a new key.
-			originalHash[hh] = rh
-		} else {
-			if db4 {
-				dbgo.Fprintf(os.Stderr, "%(cyan)AT:%(LF) -- walk down table looking for empty slot (modulo size of table)\n")
-			}
-			// collision, something already at tt.buckets[hh] (original)
-			for np := incSize(hh); np < tt.size; np = incSize(np) {
-				if db4 {
-					dbgo.Fprintf(os.Stderr, "%(cyan)AT:%(LF)\n")
-				}
-				if buckets[np] == nil { // Found an empty, so put it in and leave loop
-					if db4 {
-						dbgo.Fprintf(os.Stderr, "%(cyan)AT:%(LF)\n")
-					}
-					buckets[np] = itemx
-					originalHash[np] = rh
-					tt.length++
-					break
-				} else if (*itemx).Compare(*buckets[np]) == 0 {
-					buckets[np] = itemx
-					originalHash[np] = rh
-					break
-				}
-			}
-		}
+	tt.moveSome(moveBuckets)
+	if h := tt.old.search(item, rh); h >= 0 {
+		tt.old.clear(h) // It is replaced, in the new table.
 	}
+	tt.cur.insert(rh, item)
 
-	insertNewItem(rh, item, tt.buckets, tt.originalHash)
-
-	if db4 {
-		dbgo.Fprintf(os.Stderr, "%(cyan)AT:%(LF)\n")
-	}
-	if (((float64)(tt.length)) / ((float64)(tt.size))) > tt.saturationThreshold {
+	if (((float64)(tt.nlLength())) / ((float64)(tt.cur.size))) > tt.saturationThreshold {
 		if db4 {
 			dbgo.Fprintf(os.Stderr, "%(yellow)Passed Threshold for size, will double.......................................................\n")
 		}
-		originalSize := tt.size
-		n := tt.size * 2 // Double the size
+		tt.resize(tt.cur.size * 2) // Double the size
+	} else if (((float64)(tt.cur.length + tt.cur.deleted)) / ((float64)(tt.cur.size))) > tt.compactThreshold {
 		if db4 {
-			dbgo.Fprintf(os.Stderr, "%(yellow)    new size(n) = %d\n", n)
-		}
-		oldBuckets, oldOriginal := tt.buckets, tt.originalHash
-		tt.size = n
-		tt.length = 0
-		tt.buckets = make([]*T, n, n)
-		tt.originalHash = make([]int, n, n)
-		for i := 0; i < originalSize; i++ {
-			if oldBuckets[i] != nil {
-				item, rh := oldBuckets[i], oldOriginal[i]
-				insertNewItem(rh, item, tt.buckets, tt.originalHash)
-			}
+			dbgo.Fprintf(os.Stderr, "%(yellow)Passed Threshold for tombstones, will compact.......................................................\n")
 		}
-		if db4 {
-			dbgo.Fprintf(os.Stderr, "%(cyan)AT:%(LF)\n")
+		tt.resize(tt.cur.size) // Same size, without the tombstones
+	}
+}
+
+// resize starts moving the items to a new table with `n` buckets.  If a resize is in progress
+// it is finished first.
+func (tt *HashTab[T]) resize(n int) {
+	tt.moveSome(tt.old.size)
+	tt.old, tt.cur, tt.movePos = tt.cur, newTable[T](n, tt.probe), 0
+	tt.moveSome(moveBuckets)
+}
+
+// moveSome moves the items in the next `nBuckets` buckets of the old table to the current one.
+// When the last bucket has been moved the old table is dropped.  A moved bucket is left as a
+// tombstone, so a Search of the old table still walks past it.
+func (tt *HashTab[T]) moveSome(nBuckets int) {
+	for ; nBuckets > 0 && tt.movePos < tt.old.size; nBuckets, tt.movePos = nBuckets-1, tt.movePos+1 {
+		if item := tt.old.buckets[tt.movePos]; item != nil {
+			tt.cur.insert(tt.old.originalHash[tt.movePos], item)
+			tt.old.clear(tt.movePos)
 		}
 	}
+	if tt.movePos >= tt.old.size {
+		tt.old = table[T]{}
+	}
 }
 
 // Length returns the number of elements in the list.
@@ -190,12 +187,16 @@ func (tt *HashTab[T]) Insert(item *T) {
 func (tt *HashTab[T]) Len() int {
 	tt.lock.RLock()
 	defer tt.lock.RUnlock()
-	return tt.length
+	return tt.nlLength()
 }
 func (tt *HashTab[T]) Length() int {
 	tt.lock.RLock()
 	defer tt.lock.RUnlock()
-	return tt.length
+	return tt.nlLength()
+}
+
+func (tt *HashTab[T]) nlLength() int {
+	return tt.cur.length + tt.old.length
 }
 
 // Search will walk the tree looking for `find` and retrn the found item
@@ -209,30 +210,24 @@ func (tt *HashTab[T]) Search(find *T) (rv *T) {
 
 // Search will walk the tree looking for `find` and retrn the found item
 // if it is in the tree. If it is not found then `nil` will be returned.
+// During a resize both tables are searched.  Search does not move any items, so it can be
+// done with only a read lock.
 // Complexity is O(log n)/k.
 func (tt *HashTab[T]) NlSearch(find *T) (rv *T) {
 	if tt.nlIsEmpty() {
 		return nil
 	}
-	h := g_lib.Abs(hash(find) % tt.size)
+	rh := tt.hashOf(find)
 	if db1 {
-		fmt.Printf("%sh=%d - for ->%+v<-%s\n", MiscLib.ColorYellow, h, find, MiscLib.ColorReset)
+		fmt.Printf("%sh=%d - for ->%+v<-%s\n", MiscLib.ColorYellow, rh%tt.cur.size, find, MiscLib.ColorReset)
 	}
-	for {
-		// if tt.buckets[h] == nil { 			// Delete of duplicates overlaping with duplicates fix.
-		if tt.originalHash[h] == 0 {
-			return // not found
-			// } else if (*find).Compare(*tt.buckets[h]) == 0 { 			// Delete of duplicates overlaping with duplicates fix.
-		} else if tt.buckets[h] != nil && (*find).Compare(*tt.buckets[h]) == 0 {
-			rv = tt.buckets[h] // found
-			return
-		}
-		h++
-		if h >= tt.size {
-			h = 0 // wrap back to top
-		}
+	if h := tt.cur.search(find, rh); h >= 0 {
+		return tt.cur.buckets[h] // found
 	}
-	// return	-- detected as unrachable as of Go 1.23, before this missing return
+	if h := tt.old.search(find, rh); h >= 0 {
+		return tt.old.buckets[h] // found, not moved yet
+	}
+	return // not found
 }
 
 // ht.WriteLock()
@@ -252,14 +247,17 @@ func (tt *HashTab[T]) ReadUnlock() {
 	tt.lock.RUnlock()
 }
 
-// Dump will print out the hash table to the file `fo`.
+// Dump will print out the hash table to the file `fo`.  During a resize the old table is
+// printed after the current one.
 // Complexity is O(n).
 func (tt *HashTab[T]) Dump(fo io.Writer) {
 	tt.lock.RLock()
 	defer tt.lock.RUnlock()
-	fmt.Printf("Elements: %d, mod size:%d\n", tt.length, tt.size)
-	for i, v := range tt.buckets {
-		fmt.Fprintf(fo, "bucket [%04d] h=%d h%%size=%d = %v\n", i, tt.originalHash[i], tt.originalHash[i]%tt.size, v) // v.Dump(fo) // Xyzzy TODO - fix
+	fmt.Fprintf(fo, "Elements: %d, mod size:%d\n", tt.nlLength(), tt.cur.size)
+	tt.cur.dump(fo)
+	if tt.old.size > 0 {
+		fmt.Fprintf(fo, "Resizing from mod size:%d, next bucket to move:%d\n", tt.old.size, tt.movePos)
+		tt.old.dump(fo)
 	}
 }
 
@@ -276,79 +274,31 @@ func (tt *HashTab[T]) NlDelete(find *T) (found bool) {
 	if find == nil || tt.nlIsEmpty() {
 		return false
 	}
-	rh := hash(find)
-	h := rh % tt.size
-
-	// Increment a position in table modulo the size of the table.
-	var incSize = func(xx int) (rv int) {
-		rv = xx + 1
-		if rv >= tt.size {
-			rv = 0
-		}
-		return
-	}
-
+	rh := tt.hashOf(find)
 	if db1 {
-		fmt.Printf("%sh=%d - for ->%+v<-%s $(LF)\n", MiscLib.ColorYellow, h, find, MiscLib.ColorReset)
+		fmt.Printf("%sh=%d - for ->%+v<-%s $(LF)\n", MiscLib.ColorYellow, rh%tt.cur.size, find, MiscLib.ColorReset)
+	}
+	tt.moveSome(moveBuckets)
+	if h := tt.cur.search(find, rh); h >= 0 {
+		tt.cur.delete(h)
+	} else if h := tt.old.search(find, rh); h >= 0 {
+		tt.old.clear(h) // Only a tombstone in the old table, a shift could move an item into a bucket that has been moved.
+	} else {
+		return false
+	}
+	if db4 {
+		dbgo.Printf("%(LF)%(green) We Fond and Deleted It:  tt.length=%d \n", tt.nlLength())
 	}
-	for {
-		// if tt.buckets[h] == nil {
-		if tt.originalHash[h] == 0 {
-			return false
-			// } else if (*find).Compare(*tt.buckets[h]) == 0 {
-		} else if tt.buckets[h] != nil && (*find).Compare(*tt.buckets[h]) == 0 {
-			tt.buckets[h] = nil // found, delete the node we want to et rid of.
-			tt.length--         // one less node
-			found = true        // we found it
-			if db4 {
-				dbgo.Printf("%(LF)%(green) We Fond and Deleted It:  h=%d, tt.length=%d \n", h, tt.length)
-			}
-
-			// now we need to cleanup the empty stpot at tt.buckets[h]
-			// h -->> deleted slot, now nil.
-
-			// Must move up - and re-hash stuff ! unilt NIL found. ( 2nd loop ! )
-			// Find the "end" where our duplicates end.
-			h2 := h // To Locaiton in buckets
-			hf := h
-			oh := h
-			if db4 {
-				dbgo.Printf("%(LF) h2=%d hf=%d oh=%d\n", h2, hf, oh)
-			}
 
-			// xyzzy TODO -------------------------------- <<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<<
-			//               +--------------------- oh
-			//               |                +---- he
-			//               |                |
-			//               v                v
-			// Before:    A1 A2 b A3 b b c A4 __
-			// Delete '2nd' A
-			// Before:    A1 __ b A3 b c c A4 __
-			// Move Up:   A1 A3 b A4 b b c __ __
-
-			for {
-				hf = incSize(hf)
-				if db4 {
-					dbgo.Printf("%(LF) h2=%d hf=%d\n", h2, hf)
-				}
-				if tt.buckets[hf] == nil {
-					break
-				}
-				// if (tt.originalHash[h] % tt.size) == (tt.originalHash[hf] % tt.size) {
-				if oh == (tt.originalHash[hf] % tt.size) {
-					tt.buckets[h2] = tt.buckets[hf]
-					tt.originalHash[h2] = tt.originalHash[hf]
-					tt.buckets[hf] = nil
-					h2 = hf
-				}
-			}
-			return
-		}
-		h = incSize(h)
+	if tt.old.size == 0 && tt.cur.size/2 >= tt.minSize &&
+		(((float64)(tt.nlLength()))/((float64)(tt.cur.size))) < tt.lowWaterThreshold {
+		tt.resize(tt.cur.size / 2) // Halve the size
 	}
-	// return	-- detected as unrachable as of Go 1.23, before this missing return
+	return true
 }
 
+// Walk calls `fx` on each item in the table.  During a resize the items that have not been
+// moved yet are walked last, with `pos` after the end of the current table.
 func (tt *HashTab[T]) Walk(fx binary_tree_ts.ApplyFunction[T], userData interface{}) (b bool) {
 	tt.lock.RLock()
 	defer tt.lock.RUnlock()
@@ -356,7 +306,7 @@ func (tt *HashTab[T]) Walk(fx binary_tree_ts.ApplyFunction[T], userData interfac
 	if tt.nlIsEmpty() {
 		return
 	}
-	for ii, vv := range tt.buckets {
+	for ii, vv := range tt.cur.buckets {
 		if vv != nil {
 			b = b && fx(ii, 0, vv, userData)
 			if !b {
@@ -364,33 +314,49 @@ func (tt *HashTab[T]) Walk(fx binary_tree_ts.ApplyFunction[T], userData interfac
 			}
 		}
 	}
+	for ii, vv := range tt.old.buckets {
+		if vv != nil {
+			b = b && fx(tt.cur.size+ii, 0, vv, userData)
+			if !b {
+				return
+			}
+		}
+	}
 	return
 }
 
+// hashOf returns the hash of `item`, with the Hasher from hashing.WithHasher if there is one.
+func (tt *HashTab[T]) hashOf(item *T) int {
+	if tt.hasher != nil {
+		return hashing.Index(tt.hasher.Hash(*item))
+	}
+	return hash(item)
+}
+
 func hash(x interface{}) (rv int) {
 	hashstr := func(s string) int {
 		h := fnv.New32a()
 		h.Write([]byte(s))
-		return int(h.Sum32())
+		return g_lib.Abs(int(h.Sum32()))
 	}
 	if v, ok := x.(Hashable); ok {
 		h := v.HashKey(x)
-		return int(h)
+		return g_lib.Abs(int(h))
 	}
 	if v, ok := x.(string); ok {
 		h := hashstr(v)
-		return h
+		return g_lib.Abs(h)
 	}
 	if v, ok := x.(fmt.Stringer); ok {
 		h := hashstr(v.String())
-		return int(h)
+		return g_lib.Abs(int(h))
 	}
 	panic(fmt.Sprintf("Invalid type, %T needs to be Stringer or Hashable interface\n", x))
 }
 
 func (tt *HashTab[T]) Print(out io.Writer) {
 	fx := func(pos, depth int, data *T, y interface{}) bool {
-		fmt.Fprintf(out, "%v\n", *tt.buckets[pos])
+		fmt.Fprintf(out, "%v\n", *data)
 		return true
 	}
 	tt.Walk(fx, nil)
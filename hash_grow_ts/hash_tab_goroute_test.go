@@ -0,0 +1,67 @@
+package hash_grow_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+// This test is meant to be run with the race detector, `go test -race`.
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestHashTabGoroutines(t *testing.T) {
+	ht := NewHashTab[TestData](7, 0)
+
+	// The "base" keys are never deleted so they must always be found, even while the table is
+	// part way through a grow or a shrink.
+	const nKeys = 200
+	for k := 0; k < nKeys; k++ {
+		ht.Insert(&TestData{S: fmt.Sprintf("base%04d", k)})
+	}
+
+	var wg sync.WaitGroup
+	for ww := 0; ww < 2; ww++ {
+		wg.Add(1)
+		go func(ww int) {
+			defer wg.Done()
+			for jj := 0; jj < 3; jj++ {
+				for k := 0; k < 1000; k++ {
+					ht.Insert(&TestData{S: fmt.Sprintf("w%d-%04d", ww, k)})
+				}
+				for k := 0; k < 1000; k++ {
+					if !ht.Delete(&TestData{S: fmt.Sprintf("w%d-%04d", ww, k)}) {
+						t.Errorf("Expected to delete w%d-%04d", ww, k)
+					}
+				}
+			}
+		}(ww)
+	}
+	for rd := 0; rd < 4; rd++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ii := 0; ii < 20; ii++ {
+				for k := 0; k < nKeys; k += 7 {
+					if ht.Search(&TestData{S: fmt.Sprintf("base%04d", k)}) == nil {
+						t.Errorf("Expected to find base%04d", k)
+					}
+				}
+				n := 0
+				ht.Walk(func(pos, depth int, data *TestData, userData interface{}) bool { n++; return true }, nil)
+				if n < nKeys {
+					t.Errorf("Expected Walk to see at least %d items got %d", nKeys, n)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if ht.Length() != nKeys {
+		t.Errorf("Expected length %d got %d", nKeys, ht.Length())
+	}
+}
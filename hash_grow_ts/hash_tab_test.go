@@ -240,7 +240,9 @@ func TestTest2(t *testing.T) {
 		t.Errorf("Expected to delete it, did not")
 	}
 
-	// xyzzy TODO - check the tt.bucket[93] should be nil, with tt.originalHahs[93] == 93
+	if err := validate(ht); err != nil {
+		t.Errorf("After Delete of '  13': %s", err)
+	}
 
 	if db3 {
 		fmt.Printf("------------- after Delete of '  13' no move, no dup ---------------------------\n")
@@ -253,7 +255,9 @@ func TestTest2(t *testing.T) {
 		t.Errorf("Expected to delete it, did not")
 	}
 
-	// xyzzy TODO - check the tt.bucket[93] ...  see output and validate.
+	if err := validate(ht); err != nil {
+		t.Errorf("After Delete of '   6': %s", err)
+	}
 
 	if db3 {
 		fmt.Printf("------------- after Delete of '   6' move up ---------------------------\n")
@@ -268,6 +272,7 @@ func TestTest2(t *testing.T) {
 }
 
 func TestTestPrint(t *testing.T) {
+	// 7 and 29 collide, which is first depends on when each was moved to the new table.
 	expect := `{   3}
 {  10}
 {  26}
@@ -296,8 +301,8 @@ func TestTestPrint(t *testing.T) {
 {  32}
 {  23}
 {  15}
-{   7}
 {  29}
+{   7}
 {  14}
 {  13}
 {  18}
@@ -329,5 +334,86 @@ func TestTestPrint(t *testing.T) {
 
 }
 
+// checkAll verifies that the keys from `lo` up to `hi` are all in the table and the length.
+func checkAll(t *testing.T, ht *HashTab[TestData], lo, hi int, when string) {
+	for i := lo; i < hi; i++ {
+		if ht.Search(&TestData{S: fmt.Sprintf("%5d", i)}) == nil {
+			t.Fatalf("%s: Expected to find %d, did not", when, i)
+		}
+	}
+	if ht.Length() != hi-lo {
+		t.Fatalf("%s: Expected length of %d, got %d", when, hi-lo, ht.Length())
+	}
+}
+
+func TestIncrementalResize(t *testing.T) {
+	ht := NewHashTab[TestData](7, 0)
+
+	// No Insert moves more than moveBuckets buckets of the old table, so there is no one call
+	// that re-hashes everything.
+	nResize := 0
+	for i := 0; i < 3000; i++ {
+		oldSize, oldPos := ht.old.size, ht.movePos
+		ht.Insert(&TestData{S: fmt.Sprintf("%5d", i)})
+		if ht.old.size != oldSize && ht.old.size != 0 {
+			nResize++
+			if oldSize != 0 {
+				t.Fatalf("Insert %d: a resize was started before the last one was done", i)
+			}
+		} else if ht.old.size != 0 && ht.movePos-oldPos > moveBuckets {
+			t.Fatalf("Insert %d: moved %d buckets, expected at most %d", i, ht.movePos-oldPos, moveBuckets)
+		}
+		if ht.old.size != 0 && i%7 == 0 {
+			checkAll(t, ht, 0, i+1, "during grow")
+		}
+	}
+	if nResize < 8 {
+		t.Errorf("Expected the table to grow at least 8 times, got %d", nResize)
+	}
+	checkAll(t, ht, 0, 3000, "after grow")
+	grown := ht.cur.size
+
+	// Delete all but the last 20 and the table gets smaller, but not below the starting size.
+	for i := 0; i < 2980; i++ {
+		if !ht.Delete(&TestData{S: fmt.Sprintf("%5d", i)}) {
+			t.Fatalf("Expected to delete %d, did not", i)
+		}
+		if ht.old.size != 0 && i%7 == 0 {
+			checkAll(t, ht, i+1, 3000, "during shrink")
+		}
+	}
+	checkAll(t, ht, 2980, 3000, "after deletes")
+	if ht.cur.size >= grown {
+		t.Errorf("Expected the table to shrink from %d, got %d", grown, ht.cur.size)
+	}
+	// Each shrink is done a few buckets at a time, so it takes more calls to get down to size.
+	for j := 0; j < 200; j++ {
+		for i := 2980; i < 3000; i++ {
+			ht.Delete(&TestData{S: fmt.Sprintf("%5d", i)})
+			ht.Insert(&TestData{S: fmt.Sprintf("%5d", i)})
+		}
+	}
+	checkAll(t, ht, 2980, 3000, "after shrink")
+	if ht.cur.size != 112 {
+		t.Errorf("Expected the table to shrink to 112 (20 items are over the low-water mark of 1/8), got %d", ht.cur.size)
+	}
+
+	// Replacing an item that has not been moved yet leaves only one copy.
+	ht.Truncate()
+	for i := 0; i < 100 && ht.old.size == 0; i++ {
+		ht.Insert(&TestData{S: fmt.Sprintf("%5d", i)})
+	}
+	n := ht.Length()
+	for i := 0; i < n; i++ {
+		ht.Insert(&TestData{S: fmt.Sprintf("%5d", i)})
+	}
+	checkAll(t, ht, 0, n, "after replace")
+	nWalk := 0
+	ht.Walk(func(pos, depth int, data *TestData, userData interface{}) bool { nWalk++; return true }, nil)
+	if nWalk != n {
+		t.Errorf("Expected Walk to see %d items, got %d", n, nWalk)
+	}
+}
+
 const db2 = false
 const db3 = false
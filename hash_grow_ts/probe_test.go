@@ -0,0 +1,204 @@
+package hash_grow_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/pschlump/pluto/comparable"
+)
+
+// probeData has a hash that is set by the test, so there can be a hash of 0 and lots of
+// collisions.
+type probeData struct {
+	K, H int
+}
+
+var _ comparable.Comparable = (*probeData)(nil)
+var _ Hashable = (*probeData)(nil)
+
+func (aa probeData) Compare(x comparable.Comparable) int {
+	var bb probeData
+	switch v := x.(type) {
+	case probeData:
+		bb = v
+	case *probeData:
+		bb = *v
+	default:
+		panic(fmt.Sprintf("Passed invalid type %T to a Compare function.", x))
+	}
+	return aa.K - bb.K
+}
+
+func (aa probeData) HashKey(x interface{}) int {
+	if v, ok := x.(*probeData); ok {
+		return v.H
+	}
+	return x.(probeData).H
+}
+
+var probes = []Probe{LinearProbe, QuadraticProbe, RobinHoodProbe}
+
+// validate checks that the counts and states of both tables agree, that every item is found by
+// a search from the slot it hashes to, that an item is in only one table, and for Robin Hood
+// probing that the current table has no tombstones and no item is further from its slot than
+// the one before it plus 1.
+func validate[T comparable.Comparable](tt *HashTab[T]) error {
+	for ti, tb := range []*table[T]{&tt.cur, &tt.old} {
+		nOccupied, nDeleted := 0, 0
+		for h := 0; h < tb.size; h++ {
+			switch tb.state[h] {
+			case slotOccupied:
+				nOccupied++
+				if tb.buckets[h] == nil {
+					return fmt.Errorf("table %d slot %d is occupied with no item", ti, h)
+				}
+				if tb.originalHash[h] != tt.hashOf(tb.buckets[h]) {
+					return fmt.Errorf("table %d slot %d has the wrong hash", ti, h)
+				}
+				if at := tb.search(tb.buckets[h], tb.originalHash[h]); at != h {
+					return fmt.Errorf("table %d slot %d is found at %d", ti, h, at)
+				}
+				other := &tt.old
+				if ti == 1 {
+					other = &tt.cur
+				}
+				if other.search(tb.buckets[h], tb.originalHash[h]) >= 0 {
+					return fmt.Errorf("table %d slot %d is in both tables", ti, h)
+				}
+				if ti == 0 && tb.probe == RobinHoodProbe && tb.state[tb.next(h)] == slotOccupied && tb.dist(tb.next(h)) > tb.dist(h)+1 {
+					return fmt.Errorf("slot %d is further from its slot than %d", tb.next(h), h)
+				}
+			case slotDeleted:
+				nDeleted++
+				if ti == 0 && tb.probe == RobinHoodProbe {
+					return fmt.Errorf("slot %d is a tombstone with Robin Hood probing", h)
+				}
+				fallthrough
+			default:
+				if tb.buckets[h] != nil {
+					return fmt.Errorf("table %d slot %d is not occupied but has an item", ti, h)
+				}
+			}
+		}
+		if nOccupied != tb.length || nDeleted != tb.deleted {
+			return fmt.Errorf("table %d has %d items and %d tombstones, expected %d and %d", ti, nOccupied, nDeleted, tb.length, tb.deleted)
+		}
+	}
+	return nil
+}
+
+func TestHashZero(t *testing.T) {
+	for _, probe := range probes {
+		ht := NewHashTabProbe[probeData](8, 0, probe)
+		ht.Insert(&probeData{K: 1, H: 0})
+		ht.Insert(&probeData{K: 2, H: 0})
+		ht.Insert(&probeData{K: 3, H: 8}) // the same slot as 0
+		for k := 1; k <= 3; k++ {
+			if ht.Search(&probeData{K: k, H: []int{0, 0, 8}[k-1]}) == nil {
+				t.Errorf("%s: Expected to find %d, did not", probe, k)
+			}
+		}
+		if !ht.Delete(&probeData{K: 1, H: 0}) || ht.Search(&probeData{K: 2, H: 0}) == nil {
+			t.Errorf("%s: Expected to delete 1 and still find 2", probe)
+		}
+		if err := validate(ht); err != nil {
+			t.Errorf("%s: %s", probe, err)
+		}
+	}
+}
+
+func TestQuadraticSize(t *testing.T) {
+	ht := NewHashTabProbe[probeData](7, 0, QuadraticProbe)
+	if ht.cur.size != 8 {
+		t.Errorf("Expected size 8 got %d", ht.cur.size)
+	}
+	// Every slot is used by the probe before it repeats, so a full table can be filled.
+	tb := newTable[probeData](16, QuadraticProbe)
+	for k := 0; k < 16; k++ {
+		tb.insert(5, &probeData{K: k, H: 5})
+	}
+	if tb.length != 16 {
+		t.Errorf("Expected 16 items got %d", tb.length)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	for _, probe := range []Probe{LinearProbe, QuadraticProbe} {
+		ht := NewHashTabProbe[probeData](64, 0, probe)
+		// Keep 10 keys and move on through the slots, the deletes leave tombstones behind so the
+		// table does not grow but it has to be compacted.
+		nCompact := 0
+		for k := 0; k < 1000; k++ {
+			resizing := ht.old.size != 0
+			ht.Insert(&probeData{K: k, H: k})
+			if !resizing && ht.old.size == ht.cur.size {
+				nCompact++
+			}
+			if k >= 10 {
+				ht.Delete(&probeData{K: k - 10, H: k - 10})
+			}
+			if ht.cur.length+ht.cur.deleted > ht.cur.size {
+				t.Fatalf("%s: More items and tombstones than slots", probe)
+			}
+		}
+		if nCompact == 0 || ht.cur.size != 64 {
+			t.Errorf("%s: Expected a compact of the 64 slot table, got %d compacts, size %d", probe, nCompact, ht.cur.size)
+		}
+		if err := validate(ht); err != nil {
+			t.Errorf("%s: %s", probe, err)
+		}
+	}
+}
+
+// TestProbeProperty does random inserts, deletes and searches with each probe and checks the
+// table against a map.  The hashes have lots of collisions, including hash 0, and the table
+// grows, shrinks and is compacted along the way.
+func TestProbeProperty(t *testing.T) {
+	for _, probe := range probes {
+		ht := NewHashTabProbe[probeData](8, 0, probe)
+		model := make(map[int]bool)
+		rnd := rand.New(rand.NewSource(1001))
+		nKeys := 50
+		for ii := 0; ii < 40000; ii++ {
+			if ii%10000 == 0 {
+				nKeys = []int{50, 400, 20, 300}[ii/10000] // grow then shrink
+			}
+			k := rnd.Intn(nKeys)
+			item := &probeData{K: k, H: k % 17 * 1000}
+			switch rnd.Intn(3) {
+			case 0:
+				ht.Insert(item)
+				model[k] = true
+			case 1:
+				if found := ht.Delete(item); found != model[k] {
+					t.Fatalf("%s: Step %d, Delete(%d) expcted %v got %v", probe, ii, k, model[k], found)
+				}
+				delete(model, k)
+			case 2:
+				if found := ht.Search(item) != nil; found != model[k] {
+					t.Fatalf("%s: Step %d, Search(%d) expcted %v got %v", probe, ii, k, model[k], found)
+				}
+			}
+			if ht.Length() != len(model) {
+				t.Fatalf("%s: Step %d, expected length %d got %d", probe, ii, len(model), ht.Length())
+			}
+			if ii%97 == 0 {
+				if err := validate(ht); err != nil {
+					t.Fatalf("%s: Step %d, %s", probe, ii, err)
+				}
+			}
+		}
+		for k := 0; k < 400; k++ {
+			if found := ht.Search(&probeData{K: k, H: k % 17 * 1000}) != nil; found != model[k] {
+				t.Errorf("%s: Search(%d) expcted %v got %v", probe, k, model[k], found)
+			}
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package hash_grow_ts
+
+/*
+Copyright (C) Philip Schlump, 2023.
+
+BSD 3 Clause Licensed. See ../LICENSE
+*/
+
+/*
+
+One open addressed table.  Each slot has a state:
+
+*	empty - never used since the table was made, a search stops here.
+*	occupied - holds an item.
+*	deleted - a tombstone, the item was deleted but a search has to go on past it because an
+		item that hashed to an earlier slot may be after it.
+
+The state is kept apart from the hash, so an item whose hash is 0 is found like any other.
+
+How the next slot is picked when the one an item hashes to is in use is set by the Probe:
+
+*	LinearProbe - the next slot, then the one after that ...
+*	QuadraticProbe - 1, 3, 6, 10 ... slots on (the triangular numbers).  This spreads out the
+		items that hash to slots near each other.  The table size is a power of 2 so that the
+		probe visits every slot.
+*	RobinHoodProbe - linear, but an item that is further from the slot it hashed to takes the
+		place of an item that is closer to its slot, and that one moves on.  This keeps all
+		the probe lengths short, and a search can stop as soon as it gets to an item that is
+		closer to its slot than the item being looked for would be.  Delete shifts the items
+		after it back one slot, so there are no tombstones.
+
+With linear and quadratic probing a delete leaves a tombstone.  For linear probing a tombstone
+just before an empty slot is made empty, the rest stay until the table is compacted: when the
+items and the tombstones pass the compact threshold the items are moved to a new table of the
+same size, see HashTab.Insert.
+
+*/
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pschlump/pluto/comparable"
+)
+
+// Probe is the way a table looks for the next slot when the one an item hashes to is in use.
+type Probe int
+
+const (
+	LinearProbe    Probe = iota // the next slot, the default
+	QuadraticProbe              // 1, 3, 6, 10 ... slots on, the table size is rounded up to a power of 2
+	RobinHoodProbe              // linear, an item far from its slot takes the place of one that is closer
+)
+
+func (pp Probe) String() string {
+	switch pp {
+	case LinearProbe:
+		return "Linear"
+	case QuadraticProbe:
+		return "Quadratic"
+	case RobinHoodProbe:
+		return "RobinHood"
+	}
+	return "Unknown"
+}
+
+// slotState is the state of one slot in a table.
+type slotState uint8
+
+const (
+	slotEmpty    slotState = iota // never used, a search stops here
+	slotOccupied                  // holds an item
+	slotDeleted                   // a tombstone, a search goes on past it
+)
+
+// table is one open addressed table.
+type table[T comparable.Comparable] struct {
+	buckets      []*T        // the table
+	originalHash []int       // the original hash values (used during delete, search, resize)
+	state        []slotState // empty, occupied or deleted
+	size         int         // Modulo size for table	Current Size!
+	length       int         // # of elements in this table
+	deleted      int         // # of tombstones in this table
+	probe        Probe
+}
+
+func newTable[T comparable.Comparable](n int, probe Probe) table[T] {
+	return table[T]{
+		size:         n,
+		buckets:      make([]*T, n, n),
+		originalHash: make([]int, n, n),
+		state:        make([]slotState, n, n),
+		probe:        probe,
+	}
+}
+
+// tableSize returns the size to use for a table of at least `n` slots.  For quadratic probing
+// it is the next power of 2.
+func tableSize(n int, probe Probe) int {
+	if probe != QuadraticProbe {
+		return n
+	}
+	rv := 1
+	for rv < n {
+		rv *= 2
+	}
+	return rv
+}
+
+// slot returns the `i`th slot of the probe sequence that starts at `home`.
+func (tb *table[T]) slot(home, i int) int {
+	if tb.probe == QuadraticProbe {
+		return (home + i*(i+1)/2) % tb.size
+	}
+	return (home + i) % tb.size
+}
+
+// next returns the position after `h` in the table, modulo the size of the table.
+func (tb *table[T]) next(h int) int {
+	h++
+	if h >= tb.size {
+		h = 0 // wrap back to top
+	}
+	return h
+}
+
+// dist returns how far the item at `h` is from the slot it hashed to, for Robin Hood probing.
+func (tb *table[T]) dist(h int) int {
+	return (h - tb.originalHash[h]%tb.size + tb.size) % tb.size
+}
+
+// set puts `item` with the hash `rh` in slot `h`.
+func (tb *table[T]) set(h, rh int, item *T) {
+	tb.buckets[h] = item
+	tb.originalHash[h] = rh
+	tb.state[h] = slotOccupied
+}
+
+// search returns the position of `find`, with the hash `rh`, in the table or -1 if it is not
+// in the table.
+func (tb *table[T]) search(find *T, rh int) int {
+	if tb.length == 0 {
+		return -1
+	}
+	home := rh % tb.size
+	for i := 0; i < tb.size; i++ {
+		h := tb.slot(home, i)
+		switch tb.state[h] {
+		case slotEmpty:
+			return -1 // not found
+		case slotOccupied:
+			if tb.probe == RobinHoodProbe && tb.dist(h) < i {
+				return -1 // `find` would have taken this slot
+			}
+			if tb.originalHash[h] == rh && (*find).Compare(*tb.buckets[h]) == 0 {
+				return h // found
+			}
+		}
+	}
+	return -1 // every slot has been used, not found
+}
+
+// insert puts `item` with the hash `rh` in the table.  If the item is already in the table it
+// is replaced, else it goes in the first empty or deleted slot of its probe sequence.
+func (tb *table[T]) insert(rh int, item *T) {
+	if tb.probe == RobinHoodProbe {
+		if h := tb.search(item, rh); h >= 0 {
+			tb.set(h, rh, item) // Replace, This means that you don't have a new key.
+			return
+		}
+		tb.robinHoodInsert(rh, item)
+		return
+	}
+	free, home := -1, rh%tb.size
+probe:
+	for i := 0; i < tb.size; i++ {
+		h := tb.slot(home, i)
+		switch tb.state[h] {
+		case slotEmpty:
+			if free < 0 {
+				free = h
+			}
+			break probe // end of the run, it is not in the table
+		case slotDeleted:
+			if free < 0 {
+				free = h
+			}
+		case slotOccupied:
+			if tb.originalHash[h] == rh && (*item).Compare(*tb.buckets[h]) == 0 {
+				tb.set(h, rh, item) // Replace, This means that you don't have a new key.
+				return
+			}
+		}
+	}
+	if free < 0 {
+		panic("hash table is full")
+	}
+	if tb.state[free] == slotDeleted {
+		tb.deleted--
+	}
+	tb.set(free, rh, item)
+	tb.length++
+}
+
+// robinHoodInsert puts a new item in the table.  Going down from the slot it hashed to, the
+// item being placed takes the slot of the first item that is closer to its own slot, and then
+// that item is placed the same way.
+func (tb *table[T]) robinHoodInsert(rh int, item *T) {
+	h, d := rh%tb.size, 0
+	for n := 0; n < tb.size; n++ {
+		if tb.state[h] != slotOccupied {
+			if tb.state[h] == slotDeleted {
+				tb.deleted--
+			}
+			tb.set(h, rh, item)
+			tb.length++
+			return
+		}
+		if hd := tb.dist(h); hd < d {
+			item, tb.buckets[h] = tb.buckets[h], item
+			rh, tb.originalHash[h] = tb.originalHash[h], rh
+			d = hd
+		}
+		h, d = tb.next(h), d+1
+	}
+	panic("hash table is full")
+}
+
+// delete removes the item at `h`.  With Robin Hood probing the items after it are shifted back
+// one slot until one is found that is in its own slot, else it leaves a tombstone.
+func (tb *table[T]) delete(h int) {
+	if tb.probe != RobinHoodProbe {
+		tb.clear(h)
+		if tb.probe == LinearProbe && tb.state[tb.next(h)] == slotEmpty {
+			// Nothing can be after a tombstone that is just before an empty slot.
+			for tb.state[h] == slotDeleted {
+				tb.state[h] = slotEmpty
+				tb.deleted--
+				h = (h - 1 + tb.size) % tb.size
+			}
+		}
+		return
+	}
+	for {
+		nx := tb.next(h)
+		if tb.state[nx] != slotOccupied || tb.dist(nx) == 0 {
+			tb.buckets[h] = nil
+			tb.state[h] = slotEmpty
+			break
+		}
+		tb.set(h, tb.originalHash[nx], tb.buckets[nx])
+		h = nx
+	}
+	tb.length--
+}
+
+// clear makes the slot at `h` a tombstone.  This is the only delete used on the old table during
+// a resize, it does not move any other item.
+func (tb *table[T]) clear(h int) {
+	tb.buckets[h] = nil
+	tb.state[h] = slotDeleted
+	tb.length--
+	tb.deleted++
+}
+
+// truncate makes every slot empty.
+func (tb *table[T]) truncate() {
+	for i := 0; i < tb.size; i++ {
+		tb.buckets[i] = nil
+		tb.originalHash[i] = 0
+		tb.state[i] = slotEmpty
+	}
+	tb.length, tb.deleted = 0, 0
+}
+
+func (tb *table[T]) dump(fo io.Writer) {
+	for i, v := range tb.buckets {
+		fmt.Fprintf(fo, "bucket [%04d] %s h=%d h%%size=%d = %v\n", i, [...]string{"empty", "used", "deleted"}[tb.state[i]], tb.originalHash[i], tb.originalHash[i]%tb.size, v) // v.Dump(fo) // Xyzzy TODO - fix
+	}
+}
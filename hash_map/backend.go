@@ -0,0 +1,154 @@
+package hash_map
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/hash_grow"
+	hash_tab_bt "github.com/pschlump/pluto/hash_tab_bt"
+	hash_tab_dll "github.com/pschlump/pluto/hash_tab_dll"
+)
+
+// entry is the key and value stored in the hash table.  It carries the hash of the key and the
+// key functions, so that the hash tables can use it with their Hashable, Equality and
+// Comparable interfaces.
+type entry[K any, V any] struct {
+	key   K
+	value V
+	hash  int // the hash of key, not negative
+	fns   *keyFuncs[K]
+}
+
+// At compile time verify that entry can be stored in each of the hash tables.
+var _ comparable.Equality = (*entry[int, int])(nil)
+var _ comparable.Comparable = (*entry[int, int])(nil)
+var _ hash_tab_dll.Hashable = (*entry[int, int])(nil)
+var _ hash_grow.Hashable = (*entry[int, int])(nil)
+
+// toEntry returns the entry in `x`, which can be an entry or a pointer to one.
+func toEntry[K any, V any](x interface{}) *entry[K, V] {
+	switch bb := x.(type) {
+	case entry[K, V]:
+		return &bb
+	case *entry[K, V]:
+		return bb
+	}
+	panic(fmt.Sprintf("Passed invalid type %T to a Compare function.", x))
+}
+
+// HashKey returns the hash of the key, this is the Hashable interface.
+func (ee entry[K, V]) HashKey(x interface{}) int {
+	return toEntry[K, V](x).hash
+}
+
+// IsEqual compares the keys with the equal function.
+func (ee entry[K, V]) IsEqual(x comparable.Equality) bool {
+	bb := toEntry[K, V](x)
+	return ee.hash == bb.hash && ee.fns.equal(ee.key, bb.key)
+}
+
+// Compare orders the entries by hash and then by the compare function if there is one.  Without
+// one only a result of 0 means anything, that is all the open addressing table uses.
+func (ee entry[K, V]) Compare(x comparable.Comparable) int {
+	bb := toEntry[K, V](x)
+	switch {
+	case ee.hash < bb.hash:
+		return -1
+	case ee.hash > bb.hash:
+		return 1
+	case ee.fns.cmp != nil:
+		return ee.fns.cmp(ee.key, bb.key)
+	case ee.fns.equal(ee.key, bb.key):
+		return 0
+	}
+	return 1
+}
+
+// backend is the hash table that a HashMap keeps its entries in.
+type backend[K any, V any] interface {
+	search(find *entry[K, V]) *entry[K, V] // the stored entry with the same key, nil if none
+	insert(ee *entry[K, V])                // add an entry for a key that is not stored
+	delete(find *entry[K, V]) bool         // remove the entry with the same key
+	length() int
+	truncate()
+	walk(fx func(ee *entry[K, V]) bool) // call fx on each entry until it returns false
+}
+
+// openBackend is an open addressing hash_grow table.
+type openBackend[K any, V any] struct {
+	ht *hash_grow.HashTab[entry[K, V]]
+}
+
+func newOpenBackend[K any, V any](n int, saturation float64, probe hash_grow.Probe) *openBackend[K, V] {
+	return &openBackend[K, V]{ht: hash_grow.NewHashTabProbe[entry[K, V]](n, saturation, probe)}
+}
+
+func (bb *openBackend[K, V]) search(find *entry[K, V]) *entry[K, V] { return bb.ht.Search(find) }
+func (bb *openBackend[K, V]) insert(ee *entry[K, V])                { bb.ht.Insert(ee) }
+func (bb *openBackend[K, V]) delete(find *entry[K, V]) bool         { return bb.ht.Delete(find) }
+func (bb *openBackend[K, V]) length() int                           { return bb.ht.Length() }
+func (bb *openBackend[K, V]) truncate()                             { bb.ht.Truncate() }
+
+func (bb *openBackend[K, V]) walk(fx func(ee *entry[K, V]) bool) {
+	bb.ht.Walk(func(pos, depth int, data *entry[K, V], userData interface{}) bool {
+		return fx(data)
+	}, nil)
+}
+
+// chainBackend is a hash_tab_dll table, a list in each bucket.
+type chainBackend[K any, V any] struct {
+	ht *hash_tab_dll.HashTab[entry[K, V]]
+}
+
+func newChainBackend[K any, V any](n int) *chainBackend[K, V] {
+	return &chainBackend[K, V]{ht: hash_tab_dll.NewHashTab[entry[K, V]](n)}
+}
+
+func (bb *chainBackend[K, V]) search(find *entry[K, V]) *entry[K, V] {
+	if el := bb.ht.Search(find); el != nil {
+		return el.Data
+	}
+	return nil
+}
+func (bb *chainBackend[K, V]) insert(ee *entry[K, V])        { bb.ht.Insert(ee) }
+func (bb *chainBackend[K, V]) delete(find *entry[K, V]) bool { return bb.ht.Delete(find) }
+func (bb *chainBackend[K, V]) length() int                   { return bb.ht.Length() }
+func (bb *chainBackend[K, V]) truncate()                     { bb.ht.Truncate() }
+
+func (bb *chainBackend[K, V]) walk(fx func(ee *entry[K, V]) bool) {
+	// The dll Walk stops when the function returns true, found.
+	bb.ht.Walk(func(pos int, data entry[K, V], userData interface{}) bool {
+		return !fx(&data)
+	}, nil)
+}
+
+// treeBackend is a hash_tab_bt table, a binary tree in each bucket.
+type treeBackend[K any, V any] struct {
+	ht *hash_tab_bt.HashTab[entry[K, V]]
+}
+
+func newTreeBackend[K any, V any](n int) *treeBackend[K, V] {
+	return &treeBackend[K, V]{ht: hash_tab_bt.NewHashTab[entry[K, V]](n)}
+}
+
+func (bb *treeBackend[K, V]) search(find *entry[K, V]) *entry[K, V] { return bb.ht.Search(find) }
+func (bb *treeBackend[K, V]) insert(ee *entry[K, V])                { bb.ht.Insert(ee) }
+func (bb *treeBackend[K, V]) delete(find *entry[K, V]) bool         { return bb.ht.Delete(find) }
+func (bb *treeBackend[K, V]) length() int                           { return bb.ht.Length() }
+func (bb *treeBackend[K, V]) truncate()                             { bb.ht.Truncate() }
+
+func (bb *treeBackend[K, V]) walk(fx func(ee *entry[K, V]) bool) {
+	// WalkFunc can not be stopped, so after fx returns false the rest are skipped.
+	done := false
+	bb.ht.WalkFunc(func(data *entry[K, V]) {
+		if !done {
+			done = !fx(data)
+		}
+	})
+}
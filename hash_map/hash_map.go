@@ -0,0 +1,241 @@
+package hash_map
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+A map from keys to values, with the hash and the equality of the keys supplied by the user.
+The hash tables in ../hash_tab_dll, ../hash_tab_bt and ../hash_grow store a *T and find it
+with Compare or IsEqual, so the key and the value have to be in one struct and a lookup needs a
+dummy value.  HashMap does that wrapping inside, so:
+
+	hm := hash_map.NewHashMap[string, int](hashString, func(a, b string) bool { return a == b })
+	hm.Put("abc", 12)
+	v, found := hm.Get("abc")
+
+*	Put - set the value for a key.															O(1)
+*	Get - the value for a key.																O(1)
+*	Delete - remove a key, returns true if it was in the map.								O(1)
+*	GetOrCompute - the value for a key, if it is not in the map call a function to make it.	O(1)
+*	Update - call a function with the value for a key to make a new value or remove it.		O(1)
+*	IsEmpty - true if there are no keys.													O(1)
+*	Length - the number of keys.															O(1)
+*	Truncate - remove all the keys.															O(n)
+*	IterateOver - an iter.Seq2[K, V] over the keys and values, in no order.					O(n)
+
+The costs are the average, for the open addressing table.  The same API has 3 backends:
+
+*	NewHashMap / NewHashMapOpen - open addressing, ../hash_grow.  The table grows and shrinks
+		with the number of keys.
+*	NewHashMapChain - buckets each with a doubly linked list, ../hash_tab_dll.  The number of
+		buckets doubles when there are 2 items per bucket.  O(1)
+*	NewHashMapTree - a fixed number of buckets each with a binary tree, ../hash_tab_bt.
+		O(log(n/k)).  The tree needs an order on the keys, so this takes a compare function
+		in place of equality.
+
+The hash function can return any uint64, it is made a non-negative int with hashing.Index.
+
+A map is not safe for concurrent use.  A Put, Delete, GetOrCompute or Update must not be done
+inside an IterateOver loop, other than a Put of a key that is already in the map.
+
+*/
+
+import (
+	"iter"
+
+	"github.com/pschlump/pluto/hash_grow"
+	"github.com/pschlump/pluto/hashing"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// HashMap is a map from K to V that uses a hash and an equality function on the keys.
+type HashMap[K any, V any] struct {
+	fns   *keyFuncs[K]  // hash, equality and compare of keys, shared with each entry
+	store backend[K, V] // the hash table that holds the entries
+}
+
+// At compile time verify that HashMap can be used behind the iface_list interfaces.
+var _ iface_list.Container = (*HashMap[string, int])(nil)
+
+// keyFuncs are the functions on the keys that were passed to the constructor.
+type keyFuncs[K any] struct {
+	hash  func(key K) uint64
+	equal func(a, b K) bool
+	cmp   func(a, b K) int // only for NewHashMapTree
+}
+
+// NewHashMap creates a map with an open addressing table that grows as needed.
+// Complexity is O(1).
+func NewHashMap[K any, V any](hash func(key K) uint64, equal func(a, b K) bool) *HashMap[K, V] {
+	return NewHashMapOpen[K, V](16, 0, hash_grow.LinearProbe, hash, equal)
+}
+
+// NewHashMapOpen creates a map with an open addressing table that starts with `n` slots.
+// `saturation` and `probe` are passed to hash_grow.NewHashTabProbe.
+// Complexity is O(1).
+func NewHashMapOpen[K any, V any](n int, saturation float64, probe hash_grow.Probe, hash func(key K) uint64, equal func(a, b K) bool) *HashMap[K, V] {
+	if hash == nil || equal == nil {
+		panic("hash and equal functions are required")
+	}
+	return &HashMap[K, V]{
+		fns:   &keyFuncs[K]{hash: hash, equal: equal},
+		store: newOpenBackend[K, V](n, saturation, probe),
+	}
+}
+
+// NewHashMapChain creates a map that starts with `n` buckets, each bucket is a doubly linked list.
+// Complexity is O(n).
+func NewHashMapChain[K any, V any](n int, hash func(key K) uint64, equal func(a, b K) bool) *HashMap[K, V] {
+	if hash == nil || equal == nil {
+		panic("hash and equal functions are required")
+	}
+	return &HashMap[K, V]{
+		fns:   &keyFuncs[K]{hash: hash, equal: equal},
+		store: newChainBackend[K, V](n),
+	}
+}
+
+// NewHashMapTree creates a map with `n` buckets, each bucket is a binary tree.  `cmp` returns
+// < 0, 0 or > 0 like strings.Compare, it is used for equality too.
+// Complexity is O(n).
+func NewHashMapTree[K any, V any](n int, hash func(key K) uint64, cmp func(a, b K) int) *HashMap[K, V] {
+	if hash == nil || cmp == nil {
+		panic("hash and cmp functions are required")
+	}
+	return &HashMap[K, V]{
+		fns:   &keyFuncs[K]{hash: hash, equal: func(a, b K) bool { return cmp(a, b) == 0 }, cmp: cmp},
+		store: newTreeBackend[K, V](n),
+	}
+}
+
+// newEntry returns an entry for `key` with its hash set.
+func (hm *HashMap[K, V]) newEntry(key K) *entry[K, V] {
+	return &entry[K, V]{key: key, hash: hashing.Index(hm.fns.hash(key)), fns: hm.fns}
+}
+
+// Put sets the value for `key`, replacing the value if the key is already in the map.
+// Complexity is O(1).
+func (hm *HashMap[K, V]) Put(key K, value V) {
+	if hm == nil {
+		panic("map sholud not be a nil")
+	}
+	ee := hm.newEntry(key)
+	if found := hm.store.search(ee); found != nil {
+		found.value = value
+		return
+	}
+	ee.value = value
+	hm.store.insert(ee)
+}
+
+// Get returns the value for `key`.  If the key is not in the map then found is false.
+// Complexity is O(1).
+func (hm *HashMap[K, V]) Get(key K) (value V, found bool) {
+	if hm == nil {
+		panic("map sholud not be a nil")
+	}
+	if ee := hm.store.search(hm.newEntry(key)); ee != nil {
+		return ee.value, true
+	}
+	return
+}
+
+// Delete removes `key` from the map and returns true if it was there.
+// Complexity is O(1).
+func (hm *HashMap[K, V]) Delete(key K) bool {
+	if hm == nil {
+		panic("map sholud not be a nil")
+	}
+	return hm.store.delete(hm.newEntry(key))
+}
+
+// GetOrCompute returns the value for `key`.  If the key is not in the map then `fx` is called to
+// make the value, it is put in the map and returned with found false.
+// Complexity is O(1).
+func (hm *HashMap[K, V]) GetOrCompute(key K, fx func(key K) V) (value V, found bool) {
+	if hm == nil {
+		panic("map sholud not be a nil")
+	}
+	ee := hm.newEntry(key)
+	if old := hm.store.search(ee); old != nil {
+		return old.value, true
+	}
+	ee.value = fx(key)
+	hm.store.insert(ee)
+	return ee.value, false
+}
+
+// Update calls `fx` with the value for `key`, and found false and the zero value if it is not in
+// the map.  If `fx` returns keep true then the value it returns is put in the map, else the key
+// is removed from the map.  Update returns the new value and keep.
+// Complexity is O(1).
+func (hm *HashMap[K, V]) Update(key K, fx func(value V, found bool) (newValue V, keep bool)) (V, bool) {
+	if hm == nil {
+		panic("map sholud not be a nil")
+	}
+	ee := hm.newEntry(key)
+	old := hm.store.search(ee)
+	if old != nil {
+		ee.value = old.value
+	}
+	newValue, keep := fx(ee.value, old != nil)
+	switch {
+	case keep && old != nil:
+		old.value = newValue
+	case keep:
+		ee.value = newValue
+		hm.store.insert(ee)
+	case old != nil:
+		hm.store.delete(ee)
+	}
+	return newValue, keep
+}
+
+// IsEmpty returns true if there are no keys in the map.
+// Complexity is O(1).
+func (hm *HashMap[K, V]) IsEmpty() bool {
+	if hm == nil {
+		panic("map sholud not be a nil")
+	}
+	return hm.store.length() == 0
+}
+
+// Length returns the number of keys in the map.
+// Complexity is O(1).
+func (hm *HashMap[K, V]) Length() int {
+	if hm == nil {
+		panic("map sholud not be a nil")
+	}
+	return hm.store.length()
+}
+
+// Truncate removes all the keys from the map.
+// Complexity is O(n), n the size of the table.
+func (hm *HashMap[K, V]) Truncate() {
+	if hm == nil {
+		panic("map sholud not be a nil")
+	}
+	hm.store.truncate()
+}
+
+// IterateOver returns an iterator over the keys and values in the map, in no order.
+//
+//	for key, value := range hm.IterateOver() {
+//		...
+//	}
+//
+// Complexity is O(n).
+func (hm *HashMap[K, V]) IterateOver() iter.Seq2[K, V] {
+	if hm == nil {
+		panic("map sholud not be a nil")
+	}
+	return func(yield func(K, V) bool) {
+		hm.store.walk(func(ee *entry[K, V]) bool {
+			return yield(ee.key, ee.value)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package hash_map
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pschlump/pluto/hash_grow"
+	"github.com/pschlump/pluto/hashing"
+)
+
+func hashString(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// hashWeak puts all the keys in a few buckets, so that there are lots of collisions.
+func hashWeak(s string) uint64 {
+	return uint64(len(s) % 3)
+}
+
+func equalString(a, b string) bool { return a == b }
+
+var mapTypes = []struct {
+	name  string
+	newFn func(hash func(string) uint64) *HashMap[string, int]
+}{
+	{name: "Open", newFn: func(hash func(string) uint64) *HashMap[string, int] {
+		return NewHashMap[string, int](hash, equalString)
+	}},
+	{name: "RobinHood", newFn: func(hash func(string) uint64) *HashMap[string, int] {
+		return NewHashMapOpen[string, int](8, 0, hash_grow.RobinHoodProbe, hash, equalString)
+	}},
+	{name: "Chain", newFn: func(hash func(string) uint64) *HashMap[string, int] {
+		return NewHashMapChain[string, int](7, hash, equalString)
+	}},
+	{name: "Tree", newFn: func(hash func(string) uint64) *HashMap[string, int] {
+		return NewHashMapTree[string, int](7, hash, strings.Compare)
+	}},
+}
+
+// contents returns the keys and values in the map as a sorted string, and checks that no key is
+// seen twice.
+func contents(t *testing.T, hm *HashMap[string, int]) string {
+	var rv []string
+	seen := make(map[string]bool)
+	for k, v := range hm.IterateOver() {
+		if seen[k] {
+			t.Errorf("IterateOver returned %s twice", k)
+		}
+		seen[k] = true
+		rv = append(rv, fmt.Sprintf("%s=%d", k, v))
+	}
+	sort.Strings(rv)
+	return strings.Join(rv, " ")
+}
+
+func TestHashMap(t *testing.T) {
+	for _, mt := range mapTypes {
+		hm := mt.newFn(hashString)
+		if !hm.IsEmpty() {
+			t.Errorf("%s: Expected empty map", mt.name)
+		}
+		hm.Put("a", 1)
+		hm.Put("b", 2)
+		hm.Put("c", 3)
+		hm.Put("a", 10)
+		if v, found := hm.Get("a"); !found || v != 10 {
+			t.Errorf("%s: Get(a) expcted 10 got %d %v", mt.name, v, found)
+		}
+		if _, found := hm.Get("zz"); found {
+			t.Errorf("%s: Expected not to find zz", mt.name)
+		}
+		if hm.Length() != 3 {
+			t.Errorf("%s: Expected length 3 got %d", mt.name, hm.Length())
+		}
+		if !hm.Delete("b") || hm.Delete("b") {
+			t.Errorf("%s: Expected the first Delete of b to find it and the second not to", mt.name)
+		}
+		if got := contents(t, hm); got != "a=10 c=3" {
+			t.Errorf("%s: Expected a=10 c=3 got %s", mt.name, got)
+		}
+		hm.Truncate()
+		if hm.Length() != 0 || contents(t, hm) != "" {
+			t.Errorf("%s: Expected empty after Truncate", mt.name)
+		}
+	}
+}
+
+func TestGetOrComputeUpdate(t *testing.T) {
+	for _, mt := range mapTypes {
+		hm := mt.newFn(hashString)
+		nCalls := 0
+		compute := func(key string) int { nCalls++; return len(key) }
+		if v, found := hm.GetOrCompute("abc", compute); found || v != 3 {
+			t.Errorf("%s: GetOrCompute expcted 3 false got %d %v", mt.name, v, found)
+		}
+		if v, found := hm.GetOrCompute("abc", compute); !found || v != 3 || nCalls != 1 {
+			t.Errorf("%s: GetOrCompute expcted 3 true and 1 call got %d %v %d", mt.name, v, found, nCalls)
+		}
+
+		// A counter: add one, starting from 0 if it is not there.
+		inc := func(value int, found bool) (int, bool) { return value + 1, true }
+		hm.Update("n", inc)
+		hm.Update("n", inc)
+		if v, _ := hm.Get("n"); v != 2 {
+			t.Errorf("%s: Expected n to be 2 got %d", mt.name, v)
+		}
+		// Remove it when it gets to 0.
+		dec := func(value int, found bool) (int, bool) { return value - 1, value > 1 }
+		hm.Update("n", dec)
+		if v, keep := hm.Update("n", dec); keep || v != 0 {
+			t.Errorf("%s: Expected Update to remove n, got %d %v", mt.name, v, keep)
+		}
+		if _, found := hm.Get("n"); found || hm.Length() != 1 {
+			t.Errorf("%s: Expected n to be removed", mt.name)
+		}
+		// Not there and not kept is a no-op.
+		hm.Update("x", func(value int, found bool) (int, bool) {
+			if found {
+				t.Errorf("%s: Expected x not to be found", mt.name)
+			}
+			return 0, false
+		})
+		if hm.Length() != 1 {
+			t.Errorf("%s: Expected length 1 got %d", mt.name, hm.Length())
+		}
+	}
+}
+
+func TestIterateOverBreak(t *testing.T) {
+	for _, mt := range mapTypes {
+		hm := mt.newFn(hashString)
+		for i := 0; i < 50; i++ {
+			hm.Put(fmt.Sprintf("k%d", i), i)
+		}
+		n := 0
+		for range hm.IterateOver() {
+			n++
+			if n == 10 {
+				break
+			}
+		}
+		if n != 10 {
+			t.Errorf("%s: Expected the loop to stop at 10 got %d", mt.name, n)
+		}
+	}
+}
+
+// TestHashMapModel does random operations on each kind of map, with a good hash and with a
+// hash that has lots of collisions, and checks them against a Go map.
+func TestHashMapModel(t *testing.T) {
+	for _, mt := range mapTypes {
+		for _, hash := range []func(string) uint64{hashString, hashWeak, hashing.NewMapHash[string]().Hash} {
+			hm := mt.newFn(hash)
+			model := make(map[string]int)
+			rnd := rand.New(rand.NewSource(1001))
+			for ii := 0; ii < 5000; ii++ {
+				key := strings.Repeat("x", rnd.Intn(4)) + fmt.Sprint(rnd.Intn(150))
+				switch rnd.Intn(4) {
+				case 0:
+					hm.Put(key, ii)
+					model[key] = ii
+				case 1:
+					_, want := model[key]
+					if found := hm.Delete(key); found != want {
+						t.Fatalf("%s: Step %d, Delete(%s) expcted %v got %v", mt.name, ii, key, want, found)
+					}
+					delete(model, key)
+				case 2:
+					want, wantFound := model[key]
+					if v, found := hm.Get(key); found != wantFound || v != want {
+						t.Fatalf("%s: Step %d, Get(%s) expcted %d %v got %d %v", mt.name, ii, key, want, wantFound, v, found)
+					}
+				case 3:
+					v, _ := hm.Update(key, func(value int, found bool) (int, bool) { return value + 1, true })
+					model[key]++
+					if v != model[key] {
+						t.Fatalf("%s: Step %d, Update(%s) expcted %d got %d", mt.name, ii, key, model[key], v)
+					}
+				}
+				if hm.Length() != len(model) {
+					t.Fatalf("%s: Step %d, expected length %d got %d", mt.name, ii, len(model), hm.Length())
+				}
+			}
+			var want []string
+			for k, v := range model {
+				want = append(want, fmt.Sprintf("%s=%d", k, v))
+			}
+			sort.Strings(want)
+			if got := contents(t, hm); got != strings.Join(want, " ") {
+				t.Errorf("%s: IterateOver does not match the model", mt.name)
+			}
+		}
+	}
+}
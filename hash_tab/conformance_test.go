@@ -0,0 +1,44 @@
+package hash_tab
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/pschlump/pluto/containertest"
+	"github.com/pschlump/pluto/hashing"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// testItem makes the data for the shared conformance tests.
+var testItem = containertest.Item[TestData]{
+	New: func(n int) *TestData { return &TestData{S: fmt.Sprintf("%04d", n)} },
+	Key: func(x *TestData) int { n, _ := strconv.Atoi(x.S); return n },
+}
+
+func TestConformance(t *testing.T) {
+	containertest.TestSet(t, func() iface_list.SetDataType[TestData] { return NewHashTab[TestData](7) }, testItem)
+}
+
+// bucketOf returns the bucket that `x` is in.
+func bucketOf(st iface_list.SetDataType[TestData], x *TestData) int {
+	ht := st.(*HashTab[TestData])
+	for i, bk := range ht.buckets {
+		if _, pos := bk.Search(x); pos >= 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestHasher(t *testing.T) {
+	containertest.TestHasher(t, func(hh hashing.Hasher[TestData]) iface_list.SetDataType[TestData] {
+		return NewHashTab[TestData](7, hashing.WithHasher(hh))
+	}, testItem, bucketOf)
+}
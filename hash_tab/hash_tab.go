@@ -25,28 +25,37 @@ import (
 
 	"github.com/pschlump/pluto/comparable"
 	"github.com/pschlump/pluto/g_lib"
+	"github.com/pschlump/pluto/hashing"
+	"github.com/pschlump/pluto/iface_list"
 	"github.com/pschlump/pluto/sll"
 )
 
 // HashTab is a generic binary tree
 type HashTab[T comparable.Equality] struct {
-	buckets [](*sll.Sll[T]) // the table
-	length  int             // # of elements in table
-	size    int             // Modulo size for table
+	buckets [](*sll.Sll[T])   // the table
+	length  int               // # of elements in table
+	size    int               // Modulo size for table
+	hasher  hashing.Hasher[T] // from hashing.WithHasher, nil to use HashKey or String()
 }
 
+// At compile time verify that HashTab can be used behind the iface_list interfaces.
+var _ iface_list.SetDataType[comparable.Int] = (*HashTab[comparable.Int])(nil)
+
 type Hashable interface {
 	HashKey(x interface{}) int
 }
 
+// NewHashTab creates a hash table with `n` buckets.  The items are hashed with the Hasher from
+// the hashing.WithHasher option, without one they have to be Hashable or a fmt.Stringer.
 // Complexity is O(1).
-func NewHashTab[T comparable.Equality](n int) *HashTab[T] {
+func NewHashTab[T comparable.Equality](n int, opts ...hashing.Option[T]) *HashTab[T] {
 	if n < 5 {
 		panic("n too small")
 	}
 	r := HashTab[T]{
 		length: 0,
 		size:   n,
+		hasher: hashing.NewConfig(opts...).Hasher,
 	}
 	r.buckets = make([](*sll.Sll[T]), n, n)
 	for i := 0; i < n; i++ {
@@ -75,6 +84,10 @@ func (tt *HashTab[T]) Truncate() {
 // Complexity is O(log n)/k.
 func (tt *HashTab[T]) Insert(item *T) {
 	h := g_lib.Abs(tt.hash(item) % tt.size)
+	if it, pos := tt.buckets[h].Search(item); pos >= 0 {
+		tt.buckets[h].DeleteFound(it) // Replace the existing item
+		(*tt).length--
+	}
 	tt.buckets[h].InsertBeforeHead(item)
 	(*tt).length++
 }
@@ -134,6 +147,9 @@ func (tt *HashTab[T]) Delete(find *T) (found bool) {
 }
 
 func (tt *HashTab[T]) hash(x interface{}) (rv int) {
+	if v, ok := x.(*T); ok && tt.hasher != nil {
+		return hashing.Index(tt.hasher.Hash(*v))
+	}
 	hashstr := func(s string) int {
 		h := fnv.New32a()
 		h.Write([]byte(s))
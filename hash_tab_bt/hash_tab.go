@@ -30,6 +30,8 @@ import (
 	"github.com/pschlump/pluto/binary_tree"
 	"github.com/pschlump/pluto/comparable"
 	"github.com/pschlump/pluto/g_lib"
+	"github.com/pschlump/pluto/hashing"
+	"github.com/pschlump/pluto/iface_list"
 )
 
 // HashTab is a generic binary tree
@@ -37,20 +39,27 @@ type HashTab[T comparable.Comparable] struct {
 	buckets [](*binary_tree.BinaryTree[T]) // the table
 	length  int                            // # of elements in table
 	size    int                            // Modulo size for table
+	hasher  hashing.Hasher[T]              // from hashing.WithHasher, nil to use HashKey or String()
 }
 
+// At compile time verify that HashTab can be used behind the iface_list interfaces.
+var _ iface_list.SetDataType[comparable.Int] = (*HashTab[comparable.Int])(nil)
+
 type Hashable interface {
 	HashKey(x interface{}) int
 }
 
+// NewHashTab creates a hash table with `n` buckets.  The items are hashed with the Hasher from
+// the hashing.WithHasher option, without one they have to be Hashable or a fmt.Stringer.
 // Complexity is O(1).
-func NewHashTab[T comparable.Comparable](n int) *HashTab[T] {
+func NewHashTab[T comparable.Comparable](n int, opts ...hashing.Option[T]) *HashTab[T] {
 	if n < 5 {
 		panic("n too small")
 	}
 	r := HashTab[T]{
 		length: 0,
 		size:   n,
+		hasher: hashing.NewConfig(opts...).Hasher,
 	}
 	r.buckets = make([](*binary_tree.BinaryTree[T]), n, n)
 	for i := 0; i < n; i++ {
@@ -78,7 +87,7 @@ func (tt *HashTab[T]) Truncate() {
 // item the new item will replace the existing one.
 // Complexity is O(log n)/k.
 func (tt *HashTab[T]) Insert(item *T) {
-	h := g_lib.Abs(hash(item) % tt.size)
+	h := g_lib.Abs(tt.hashOf(item) % tt.size)
 	isNew := tt.buckets[h].Insert(item)
 	if isNew {
 		(*tt).length++
@@ -101,7 +110,7 @@ func (tt *HashTab[T]) Search(find *T) (rv *T) {
 	if (*tt).IsEmpty() {
 		return nil
 	}
-	h := g_lib.Abs(hash(find) % tt.size)
+	h := g_lib.Abs(tt.hashOf(find) % tt.size)
 	if db1 {
 		fmt.Printf("%sh=%d - for ->%+v<-%s\n", MiscLib.ColorYellow, h, find, MiscLib.ColorReset)
 	}
@@ -131,7 +140,7 @@ func (tt *HashTab[T]) Delete(find *T) (found bool) {
 	if find == nil || (*tt).IsEmpty() {
 		return false
 	}
-	h := g_lib.Abs(hash(find) % tt.size)
+	h := g_lib.Abs(tt.hashOf(find) % tt.size)
 	found = tt.buckets[h].Delete(find)
 	if found {
 		(*tt).length--
@@ -139,6 +148,14 @@ func (tt *HashTab[T]) Delete(find *T) (found bool) {
 	return
 }
 
+// hashOf returns the hash of `item`, with the Hasher from hashing.WithHasher if there is one.
+func (tt *HashTab[T]) hashOf(item *T) int {
+	if tt.hasher != nil {
+		return hashing.Index(tt.hasher.Hash(*item))
+	}
+	return hash(item)
+}
+
 func hash(x interface{}) (rv int) {
 	hashstr := func(s string) int {
 		h := fnv.New32a()
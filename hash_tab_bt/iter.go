@@ -1,7 +1,7 @@
 package hash_tab
 
 func (tt *HashTab[T]) WalkFunc(Fx func(a *T)) {
-	for i := 0; i < tt.length; i++ {
+	for i := 0; i < tt.size; i++ {
 		if tt.buckets[i] != nil {
 			tt.buckets[i].WalkFunc(Fx)
 		}
@@ -14,12 +14,18 @@ Basic operations on a Hash Table.
 * 	Delete — Deletes a specified element from the linked list (Element can be fond via Search). O(1)
 * 	IsEmpty — Returns true if the linked list is empty											O(1)
 * 	Length — Returns number of elements in the list.  0 length is an empty list.				O(1)
-* 	Search — Returns the given element from a linked list.  Search is from head to tail.		O(n/k) where k is # of buckets.
+* 	Search — Returns the given element from a linked list.  Search is from head to tail.		O(1)
 * 	Truncate - Delete all the nodes in list. 													O(1)
 
 	Walk - Walk the table
 	Print - Using Walk to print out the contents of the table.
 
+When an Insert takes the table over maxLoad items per bucket a new table with twice the number of
+buckets is made and all of the items are moved to it, so a bucket stays short and the lookups
+are O(1) on average.  The move is O(n) but it is done each time the table doubles, so an Insert
+is O(1) amortized.  An element returned by Search can not be passed to DeleteFound after an
+Insert, the Insert may have moved it.
+
 */
 
 import (
@@ -30,27 +36,35 @@ import (
 	"github.com/pschlump/pluto/comparable"
 	"github.com/pschlump/pluto/dll"
 	"github.com/pschlump/pluto/g_lib"
+	"github.com/pschlump/pluto/hashing"
 )
 
 // HashTab is a generic binary tree
 type HashTab[T comparable.Equality] struct {
-	buckets [](*dll.Dll[T]) // the table
-	length  int             // # of elements in table
-	size    int             // Modulo size for table
+	buckets [](*dll.Dll[T])   // the table
+	length  int               // # of elements in table
+	size    int               // Modulo size for table
+	hasher  hashing.Hasher[T] // from hashing.WithHasher, nil to use HashKey or String()
 }
 
+// maxLoad is the average number of items in a bucket above which the table grows.
+const maxLoad = 2
+
 type Hashable interface {
 	HashKey(x interface{}) int
 }
 
+// NewHashTab creates a hash table with `n` buckets.  The items are hashed with the Hasher from
+// the hashing.WithHasher option, without one they have to be Hashable or a fmt.Stringer.
 // Complexity is O(1).
-func NewHashTab[T comparable.Equality](n int) *HashTab[T] {
+func NewHashTab[T comparable.Equality](n int, opts ...hashing.Option[T]) *HashTab[T] {
 	if n < 5 {
 		panic("n too small")
 	}
 	r := HashTab[T]{
 		length: 0,
 		size:   n,
+		hasher: hashing.NewConfig(opts...).Hasher,
 	}
 	r.buckets = make([](*dll.Dll[T]), n, n)
 	for i := 0; i < n; i++ {
@@ -77,13 +91,33 @@ func (tt *HashTab[T]) Truncate() {
 // Insert will add a new item to the tree.  If it is a duplicate of an exiting
 // item the new item will (*old: replace the existing one.*) be inserted before
 // the old one - hiding it (it will act like a stack).
-// Complexity is O(log n)/k.
+// Complexity is O(1) amortized.
 func (tt *HashTab[T]) Insert(item *T) {
 	h := g_lib.Abs(tt.hash(item) % tt.size)
 	is_new := tt.buckets[h].InsertBeforeHead(item)
 	if is_new {
 		(*tt).length++
 	}
+	if tt.length > maxLoad*tt.size {
+		tt.resize(2*tt.size + 1)
+	}
+}
+
+// resize moves all the items to a new table with `n` buckets.  A bucket is moved from tail to
+// head so that a duplicate is still in front of the item that it hides.
+// Complexity is O(n).
+func (tt *HashTab[T]) resize(n int) {
+	buckets := make([](*dll.Dll[T]), n, n)
+	for i := 0; i < n; i++ {
+		buckets[i] = dll.NewDll[T]()
+	}
+	for _, bk := range tt.buckets {
+		for ii := bk.Rear(); !ii.Done(); ii.Prev() {
+			item := ii.Value()
+			buckets[g_lib.Abs(tt.hash(item)%n)].InsertBeforeHead(item)
+		}
+	}
+	tt.buckets, tt.size = buckets, n
 }
 
 // Length returns the number of elements in the list.
@@ -97,7 +131,7 @@ func (tt *HashTab[T]) Length() int {
 
 // Search will walk the tree looking for `find` and retrn the found item
 // if it is in the tree. If it is not found then `nil` will be returned.
-// Complexity is O(log n)/k.
+// Complexity is O(1).
 func (tt *HashTab[T]) Search(find *T) (rv *dll.DllElement[T]) {
 	if (*tt).IsEmpty() {
 		return nil
@@ -190,6 +224,9 @@ func (ns *HashTab[T]) Walk(fx dll.ApplyFunction[T], userData interface{}) (rv *d
 }
 
 func (tt *HashTab[T]) hash(x interface{}) (rv int) {
+	if v, ok := x.(*T); ok && tt.hasher != nil {
+		return hashing.Index(tt.hasher.Hash(*v))
+	}
 	hashstr := func(s string) int {
 		h := fnv.New32a()
 		h.Write([]byte(s))
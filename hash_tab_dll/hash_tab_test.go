@@ -156,3 +156,30 @@ func TestTest(t *testing.T) {
 }
 
 const db8 = false
+
+func TestGrow(t *testing.T) {
+	ht := NewHashTab[TestData](7)
+	for i := 0; i < 200; i++ {
+		ht.Insert(&TestData{S: fmt.Sprintf("%4d", i)})
+	}
+	// a duplicate of an item that is already in the table
+	ht.Insert(&TestData{S: "   5"})
+
+	if ht.size <= 7 {
+		t.Errorf("Expected the table to grow, size is %d", ht.size)
+	}
+	if ht.Length() > maxLoad*ht.size {
+		t.Errorf("Expected at most %d items per bucket, got %d items in %d buckets", maxLoad, ht.Length(), ht.size)
+	}
+	if ht.Length() != 201 {
+		t.Errorf("Expected length of 201, got %d", ht.Length())
+	}
+	for i := 0; i < 200; i++ {
+		if ht.Search(&TestData{S: fmt.Sprintf("%4d", i)}) == nil {
+			t.Errorf("Expected to find %d after grow, did not", i)
+		}
+	}
+	if !ht.Delete(&TestData{S: "   5"}) || !ht.Delete(&TestData{S: "   5"}) || ht.Delete(&TestData{S: "   5"}) {
+		t.Errorf("Expected to delete the 2 copies of 5")
+	}
+}
@@ -0,0 +1,101 @@
+package hash_tab_shard
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+// Benchmarks of the sharded table against ../hash_tab_bt_ts, one lock for the whole table, and
+// sync.Map, with all the goroutines doing the same mix of calls:
+//
+//	make bench
+//	make bench_race		// the same with the race detector
+//
+// Read is all Search, Mixed is 90% Search and 10% Insert or Delete, Write is half Insert and
+// half Delete.
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	hash_tab_bt_ts "github.com/pschlump/pluto/hash_tab_bt_ts"
+)
+
+// benchTable is the calls that the benchmarks make.
+type benchTable interface {
+	Insert(item *TestData)
+	Search(find *TestData) *TestData
+	Delete(find *TestData) bool
+}
+
+// syncMap is a sync.Map as a benchTable.
+type syncMap struct {
+	mm sync.Map
+}
+
+func (sm *syncMap) Insert(item *TestData) { sm.mm.Store(item.S, item) }
+func (sm *syncMap) Delete(find *TestData) bool {
+	_, found := sm.mm.LoadAndDelete(find.S)
+	return found
+}
+func (sm *syncMap) Search(find *TestData) *TestData {
+	if v, ok := sm.mm.Load(find.S); ok {
+		return v.(*TestData)
+	}
+	return nil
+}
+
+const benchBuckets, benchKeys = 1024, 4096
+
+var benchTables = []struct {
+	name  string
+	newFn func() benchTable
+}{
+	{"Sharded", func() benchTable { return NewHashTab[TestData](benchBuckets) }},
+	{"OneLock", func() benchTable { return hash_tab_bt_ts.NewHashTab[TestData](benchBuckets) }},
+	{"SyncMap", func() benchTable { return &syncMap{} }},
+}
+
+var benchItems = func() (rv []*TestData) {
+	for i := 0; i < benchKeys; i++ {
+		rv = append(rv, &TestData{S: fmt.Sprintf("key%05d", i), N: i})
+	}
+	return
+}()
+
+// runBench runs `pctWrite` percent Insert and Delete, the rest Search, on each of the tables
+// from all the goroutines.  Half the keys are in the table at the start.
+func runBench(b *testing.B, pctWrite int) {
+	for _, bt := range benchTables {
+		b.Run(bt.name, func(b *testing.B) {
+			tab := bt.newFn()
+			for i := 0; i < benchKeys; i += 2 {
+				tab.Insert(benchItems[i])
+			}
+			var seed atomic.Int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				r := uint32(seed.Add(1) * 7919)
+				for pb.Next() {
+					r = r*1664525 + 1013904223
+					item := benchItems[(r>>8)%benchKeys]
+					switch op := int(r>>24) % 100; {
+					case op >= pctWrite:
+						tab.Search(item)
+					case op%2 == 0:
+						tab.Insert(item)
+					default:
+						tab.Delete(item)
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkRead(b *testing.B)  { runBench(b, 0) }
+func BenchmarkMixed(b *testing.B) { runBench(b, 10) }
+func BenchmarkWrite(b *testing.B) { runBench(b, 100) }
@@ -0,0 +1,390 @@
+package hash_tab_shard
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+A concurrent hash table split into shards.  ../hash_tab_bt_ts has one lock for the whole table
+so 2 writers wait for each other even when they use different buckets.  Here the table is N
+shards, each with its own read/write lock and its own buckets, and the hash of an item picks
+the shard.  Calls on items in different shards do not wait for each other.
+
+* 	Insert - create a new element in the table, or replace the one with the same key.			O(log n/k)
+* 	Delete — Deletes the item with the same key.												O(log n/k)
+* 	IsEmpty — Returns true if the table is empty												O(1)
+* 	Length — Returns number of elements in the table.											O(1)
+* 	Search — Returns the item with the same key, nil if it is not in the table.				O(log n/k)
+* 	ItemExists — Returns true if there is an item with the same key.							O(log n/k)
+* 	Truncate - Delete all the items.															O(k)
+*	LoadOrStore - Return the item with the same key, if there is none insert this one.			O(log n/k)
+*	CompareAndSwap - Replace an item only if it is the one that was read.						O(log n/k)
+*	Compute - Call a function with the item for a key to make a new item or remove it.		O(log n/k)
+*	Walk - Walk the table																		O(n)
+*	Dump - print out the table																	O(n)
+
+k is the number of buckets, each bucket is a binary tree.  LoadOrStore, CompareAndSwap and
+Compute each hold the shard's lock for the whole call, so no other call can change the item
+between the read and the write.  They replace the WriteLock/ReadLock calls that are used with
+../hash_tab_bt_ts to do a read and a write as one step.
+
+Walk locks one shard at a time, it is not a snapshot of the whole table.  The function passed to
+Walk, WalkFunc or Compute must not call a method of the same table, the shard is locked.
+
+*/
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pschlump/pluto/binary_tree"
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/hashing"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// HashTab is a generic hash table made of shards that are locked on their own.
+type HashTab[T comparable.Comparable] struct {
+	shards    []shard[T]
+	shardBits int               // there are 1<<shardBits shards
+	length    atomic.Int64      // # of elements in all the shards
+	hasher    hashing.Hasher[T] // from hashing.WithHasher, nil to use HashKey or String()
+}
+
+// At compile time verify that HashTab can be used behind the iface_list interfaces.
+var _ iface_list.SetDataType[comparable.Int] = (*HashTab[comparable.Int])(nil)
+
+// shard is one part of the table, with its own lock and buckets.
+type shard[T comparable.Comparable] struct {
+	lock    sync.RWMutex
+	buckets [](*binary_tree.BinaryTree[T]) // the table
+	length  int                            // # of elements in this shard
+	_       [64]byte                       // keep the locks of 2 shards off the same cache line
+}
+
+type Hashable interface {
+	HashKey(x interface{}) int
+}
+
+// DefaultShards is the number of shards used by NewHashTab.
+const DefaultShards = 32
+
+// NewHashTab creates a hash table with about `n` buckets in DefaultShards shards.  The items are
+// hashed with the Hasher from the hashing.WithHasher option, without one they have to be
+// Hashable or a fmt.Stringer.
+// Complexity is O(n).
+func NewHashTab[T comparable.Comparable](n int, opts ...hashing.Option[T]) *HashTab[T] {
+	return NewHashTabShards[T](n, DefaultShards, opts...)
+}
+
+// NewHashTabShards creates a hash table with about `n` buckets in `nShards` shards.  `nShards`
+// is rounded up to a power of 2, and each shard has at least 1 bucket.
+// Complexity is O(n).
+func NewHashTabShards[T comparable.Comparable](n, nShards int, opts ...hashing.Option[T]) *HashTab[T] {
+	if n < 5 {
+		panic("n too small")
+	}
+	if nShards < 1 {
+		panic("nShards too small")
+	}
+	r := HashTab[T]{hasher: hashing.NewConfig(opts...).Hasher}
+	for 1<<r.shardBits < nShards {
+		r.shardBits++
+	}
+	nShards = 1 << r.shardBits
+	perShard := (n + nShards - 1) / nShards
+	r.shards = make([]shard[T], nShards, nShards)
+	for i := range r.shards {
+		r.shards[i].buckets = make([](*binary_tree.BinaryTree[T]), perShard, perShard)
+		for j := 0; j < perShard; j++ {
+			r.shards[i].buckets[j] = binary_tree.NewBinaryTree[T]()
+		}
+	}
+	return &r
+}
+
+// IsEmpty will return true if the hash table is empty
+// Complexity is O(1).
+func (tt *HashTab[T]) IsEmpty() bool {
+	if tt == nil {
+		panic("hash table sholud not be a nil")
+	}
+	return tt.length.Load() == 0
+}
+
+// Truncate removes all data from the table.  Each shard is emptied in turn, an Insert into a
+// shard that is already done is kept.
+// Complexity is O(k).
+func (tt *HashTab[T]) Truncate() {
+	if tt == nil {
+		panic("hash table sholud not be a nil")
+	}
+	for i := range tt.shards {
+		ss := &tt.shards[i]
+		ss.lock.Lock()
+		for _, v := range ss.buckets {
+			v.Truncate()
+		}
+		tt.length.Add(int64(-ss.length))
+		ss.length = 0
+		ss.lock.Unlock()
+	}
+}
+
+// Insert will add a new item to the table.  If it is a duplicate of an exiting
+// item the new item will replace the existing one.
+// Complexity is O(log n)/k.
+func (tt *HashTab[T]) Insert(item *T) {
+	if tt == nil {
+		panic("hash table sholud not be a nil")
+	}
+	ss, bucket := tt.locate(item)
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+	tt.nlInsert(ss, bucket, item)
+}
+
+func (tt *HashTab[T]) nlInsert(ss *shard[T], bucket *binary_tree.BinaryTree[T], item *T) {
+	if bucket.Insert(item) {
+		ss.length++
+		tt.length.Add(1)
+	}
+}
+
+// Len returns the number of elements in the table.
+// Complexity is O(1).
+func (tt *HashTab[T]) Len() int {
+	return tt.Length()
+}
+
+// Length returns the number of elements in the table.
+// Complexity is O(1).
+func (tt *HashTab[T]) Length() int {
+	if tt == nil {
+		panic("hash table sholud not be a nil")
+	}
+	return int(tt.length.Load())
+}
+
+// Search will look for `find` and retrn the found item if it is in the table. If it is not
+// found then `nil` will be returned.
+// Complexity is O(log n)/k.
+func (tt *HashTab[T]) Search(find *T) (rv *T) {
+	if tt == nil {
+		panic("hash table sholud not be a nil")
+	}
+	ss, bucket := tt.locate(find)
+	ss.lock.RLock()
+	defer ss.lock.RUnlock()
+	return bucket.Search(find)
+}
+
+// ItemExists returns true if there is an item with the same key as `find`.
+//
+//	if ok := ht.ItemExists(&DefinedItem{Name: in}); !ok {
+//
+// Complexity is O(log n)/k.
+func (tt *HashTab[T]) ItemExists(find *T) (rv bool) {
+	return tt.Search(find) != nil
+}
+
+// Delete removes the item with the same key as `find`, it returns true if it was found.
+// Complexity is O(log n)/k.
+func (tt *HashTab[T]) Delete(find *T) (found bool) {
+	if tt == nil {
+		panic("hash table sholud not be a nil")
+	}
+	if find == nil {
+		return false
+	}
+	ss, bucket := tt.locate(find)
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+	return tt.nlDelete(ss, bucket, find)
+}
+
+func (tt *HashTab[T]) nlDelete(ss *shard[T], bucket *binary_tree.BinaryTree[T], find *T) (found bool) {
+	if found = bucket.Delete(find); found {
+		ss.length--
+		tt.length.Add(-1)
+	}
+	return
+}
+
+// LoadOrStore returns the item with the same key as `item` and loaded true.  If there is none
+// then `item` is inserted and returned with loaded false.
+// Complexity is O(log n)/k.
+func (tt *HashTab[T]) LoadOrStore(item *T) (actual *T, loaded bool) {
+	if tt == nil {
+		panic("hash table sholud not be a nil")
+	}
+	ss, bucket := tt.locate(item)
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+	if actual = bucket.Search(item); actual != nil {
+		return actual, true
+	}
+	tt.nlInsert(ss, bucket, item)
+	return item, false
+}
+
+// CompareAndSwap replaces the item with the same key as `newItem` if it is `old`, the same
+// pointer that was returned by Search.  It returns true if it was replaced.  `old` and
+// `newItem` have to have the same key.
+//
+//	for {
+//		old := ht.Search(&Item{Key: "k"})
+//		if ht.CompareAndSwap(old, &Item{Key: "k", Count: old.Count + 1}) {
+//			break
+//		}
+//	}
+//
+// Complexity is O(log n)/k.
+func (tt *HashTab[T]) CompareAndSwap(old, newItem *T) (swapped bool) {
+	if tt == nil {
+		panic("hash table sholud not be a nil")
+	}
+	if old == nil || newItem == nil {
+		return false
+	}
+	if (*old).Compare(*newItem) != 0 {
+		panic("old and new items have different keys")
+	}
+	ss, bucket := tt.locate(newItem)
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+	if bucket.Search(newItem) != old {
+		return false
+	}
+	bucket.Insert(newItem) // Replace, the key is the same.
+	return true
+}
+
+// Compute calls `fx` with the item that has the same key as `find` and found true, or nil and
+// found false if there is none.  If `fx` returns keep true then the item it returns is put in
+// the table, it has to have the same key as `find`, else the item is removed.  The shard is
+// locked while `fx` runs.  Compute returns the new item and keep.
+// Complexity is O(log n)/k.
+func (tt *HashTab[T]) Compute(find *T, fx func(item *T, found bool) (newItem *T, keep bool)) (*T, bool) {
+	if tt == nil {
+		panic("hash table sholud not be a nil")
+	}
+	ss, bucket := tt.locate(find)
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+	old := bucket.Search(find)
+	newItem, keep := fx(old, old != nil)
+	switch {
+	case keep:
+		if newItem == nil || (*find).Compare(*newItem) != 0 {
+			panic("Compute must keep an item with the same key")
+		}
+		tt.nlInsert(ss, bucket, newItem)
+	case old != nil:
+		tt.nlDelete(ss, bucket, find)
+	}
+	return newItem, keep
+}
+
+// Dump will print out the hash table to the file `fo`.
+// Complexity is O(n).
+func (tt *HashTab[T]) Dump(fo io.Writer) {
+	fmt.Fprintf(fo, "Elements: %d, shards:%d\n", tt.length.Load(), len(tt.shards))
+	for i := range tt.shards {
+		ss := &tt.shards[i]
+		ss.lock.RLock()
+		for j, v := range ss.buckets {
+			if v.Length() > 0 {
+				fmt.Fprintf(fo, "shard [%03d] bucket [%04d] = \n", i, j)
+				v.Dump(fo)
+			}
+		}
+		ss.lock.RUnlock()
+	}
+}
+
+// Walk calls `fx` on each item, one shard at a time, until it returns false.
+// Complexity is O(n).
+func (tt *HashTab[T]) Walk(fx binary_tree.ApplyFunction[T], userData interface{}) {
+	if tt == nil {
+		panic("hash table sholud not be a nil")
+	}
+	more := true
+	for i := 0; i < len(tt.shards) && more; i++ {
+		ss := &tt.shards[i]
+		ss.lock.RLock()
+		for _, v := range ss.buckets {
+			if v.Length() > 0 && more {
+				v.WalkInOrder(func(pos, depth int, data *T, userData interface{}) bool {
+					more = fx(pos, depth, data, userData)
+					return more
+				}, userData)
+			}
+		}
+		ss.lock.RUnlock()
+	}
+}
+
+// WalkFunc calls `Fx` on each item, one shard at a time.
+// Complexity is O(n).
+func (tt *HashTab[T]) WalkFunc(Fx func(a *T)) {
+	tt.Walk(func(pos, depth int, data *T, userData interface{}) bool {
+		Fx(data)
+		return true
+	}, nil)
+}
+
+// locate returns the shard and the bucket for `item`.  The top bits of the hash times a large
+// odd number pick the shard and the hash modulo the number of buckets picks the bucket, so
+// the items in a shard are spread over all of its buckets.
+func (tt *HashTab[T]) locate(item *T) (*shard[T], *binary_tree.BinaryTree[T]) {
+	h := tt.hashOf(item)
+	s := 0
+	if tt.shardBits > 0 {
+		s = int((uint64(h) * 0x9E3779B97F4A7C15) >> (64 - tt.shardBits))
+	}
+	ss := &tt.shards[s]
+	return ss, ss.buckets[h%len(ss.buckets)]
+}
+
+// hashOf returns the hash of `item`, not negative, with the Hasher from hashing.WithHasher if
+// there is one.
+func (tt *HashTab[T]) hashOf(item *T) int {
+	if tt.hasher != nil {
+		return hashing.Index(tt.hasher.Hash(*item))
+	}
+	h := hash(item)
+	if h < 0 {
+		h = -(h + 1)
+	}
+	return h
+}
+
+func hash(x interface{}) (rv int) {
+	hashstr := func(s string) int {
+		h := fnv.New32a()
+		h.Write([]byte(s))
+		return int(h.Sum32())
+	}
+	if v, ok := x.(Hashable); ok {
+		h := v.HashKey(x)
+		return int(h)
+	}
+	if v, ok := x.(string); ok {
+		h := hashstr(v)
+		return h
+	}
+	if v, ok := x.(fmt.Stringer); ok {
+		h := hashstr(v.String())
+		return int(h)
+	}
+	panic(fmt.Sprintf("Invalid type, %T needs to be Stringer or Hashable interface\n", x))
+}
+
+/* vim: set noai ts=4 sw=4: */
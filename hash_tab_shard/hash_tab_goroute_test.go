@@ -0,0 +1,82 @@
+package hash_tab_shard
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+// This test is meant to be run with the race detector, `go test -race`.
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestHashTabGoroutines(t *testing.T) {
+	ht := NewHashTabShards[TestData](64, 8)
+
+	const nWorkers, nKeys, nIncr = 8, 50, 200
+	var wg sync.WaitGroup
+	for ww := 0; ww < nWorkers; ww++ {
+		wg.Add(1)
+		go func(ww int) {
+			defer wg.Done()
+			for ii := 0; ii < nIncr; ii++ {
+				// Shared counters, each Compute adds 1.
+				key := fmt.Sprintf("c%02d", ii%nKeys)
+				ht.Compute(&TestData{S: key}, func(item *TestData, found bool) (*TestData, bool) {
+					if !found {
+						return &TestData{S: key, N: 1}, true
+					}
+					return &TestData{S: key, N: item.N + 1}, true
+				})
+				// A CompareAndSwap loop on one key.
+				for {
+					old, _ := ht.LoadOrStore(&TestData{S: "cas"})
+					if ht.CompareAndSwap(old, &TestData{S: "cas", N: old.N + 1}) {
+						break
+					}
+				}
+				// Keys that only this worker uses.
+				mine := &TestData{S: fmt.Sprintf("w%d-%d", ww, ii)}
+				ht.Insert(mine)
+				if !ht.ItemExists(mine) {
+					t.Errorf("Expected to find %s", mine.S)
+				}
+				if ii%2 == 0 && !ht.Delete(mine) {
+					t.Errorf("Expected to delete %s", mine.S)
+				}
+			}
+		}(ww)
+	}
+	for rd := 0; rd < 2; rd++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ii := 0; ii < 20; ii++ {
+				n := 0
+				ht.Walk(func(pos, depth int, data *TestData, userData interface{}) bool { n++; return true }, nil)
+				if n > ht.Length()+nWorkers*nIncr {
+					t.Errorf("Walk saw %d items", n)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	for k := 0; k < nKeys; k++ {
+		total += ht.Search(&TestData{S: fmt.Sprintf("c%02d", k)}).N
+	}
+	if total != nWorkers*nIncr {
+		t.Errorf("Expected the counters to add up to %d got %d", nWorkers*nIncr, total)
+	}
+	if x := ht.Search(&TestData{S: "cas"}); x.N != nWorkers*nIncr {
+		t.Errorf("Expected cas to be %d got %d", nWorkers*nIncr, x.N)
+	}
+	if want := nKeys + 1 + nWorkers*nIncr/2; ht.Length() != want {
+		t.Errorf("Expected length %d got %d", want, ht.Length())
+	}
+}
@@ -0,0 +1,262 @@
+package hash_tab_shard
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pschlump/HashStr"
+	"github.com/pschlump/pluto/comparable"
+)
+
+// TestData is an Inteface Matcing data type for the Nodes that supports the Comparable
+// interface.  This means that it has a Compare fucntion.  N is not part of the key.
+type TestData struct {
+	S string
+	N int
+}
+
+// At compile time verify that this is a correct type/interface setup.
+var _ comparable.Comparable = (*TestData)(nil)
+var _ Hashable = (*TestData)(nil)
+
+// Compare implements the Compare function to satisfy the interface requirements.
+func (aa TestData) Compare(x comparable.Comparable) int {
+	if bb, ok := x.(TestData); ok {
+		return strings.Compare(aa.S, bb.S)
+	} else if bb, ok := x.(*TestData); ok {
+		return strings.Compare(aa.S, bb.S)
+	}
+	panic(fmt.Sprintf("Passed invalid type %T to a Compare function.", x))
+}
+
+func (aa TestData) HashKey(x interface{}) (rv int) {
+	if v, ok := x.(*TestData); ok {
+		rv = HashStr.HashStr([]byte(v.S))
+		return
+	}
+	if v, ok := x.(TestData); ok {
+		rv = HashStr.HashStr([]byte(v.S))
+		return
+	}
+	return
+}
+
+func TestTest(t *testing.T) {
+	ht := NewHashTab[TestData](7)
+	if !ht.IsEmpty() {
+		t.Errorf("Expected empty hash-tab after decleration, failed to get one.")
+	}
+	if len(ht.shards) != DefaultShards || len(ht.shards[0].buckets) != 1 {
+		t.Errorf("Expected %d shards of 1 bucket got %d of %d", DefaultShards, len(ht.shards), len(ht.shards[0].buckets))
+	}
+	for i := 0; i < 40; i++ {
+		ht.Insert(&TestData{S: fmt.Sprintf("%4d", i)})
+	}
+	for i := 0; i < 40; i++ {
+		ht.Insert(&TestData{S: fmt.Sprintf("%4d", i)})
+	}
+	if ht.Len() != 40 || ht.Length() != 40 || ht.IsEmpty() {
+		t.Errorf("Expected length of 40, got %d", ht.Len())
+	}
+	if !ht.ItemExists(&TestData{S: "   8"}) {
+		t.Errorf("Expected to find it, did not")
+	}
+	if !ht.Delete(&TestData{S: "   8"}) || ht.Delete(&TestData{S: "   8"}) {
+		t.Errorf("Expected the first Delete to find it and the second not to")
+	}
+	if ht.ItemExists(&TestData{S: "   8"}) || ht.Length() != 39 {
+		t.Errorf("Expected to NOT find it and length 39, got %d", ht.Length())
+	}
+
+	var buf bytes.Buffer
+	ht.Dump(&buf)
+	if !strings.HasPrefix(buf.String(), "Elements: 39, shards:32\n") {
+		t.Errorf("Unexpected Dump %q", buf.String())
+	}
+
+	ht.Truncate()
+	if ht.Length() != 0 || ht.Search(&TestData{S: "   9"}) != nil {
+		t.Errorf("Expected empty after Truncate, got %d", ht.Length())
+	}
+}
+
+func TestShards(t *testing.T) {
+	ht := NewHashTabShards[TestData](100, 5)
+	if len(ht.shards) != 8 || len(ht.shards[0].buckets) != 13 {
+		t.Errorf("Expected 8 shards of 13 buckets got %d of %d", len(ht.shards), len(ht.shards[0].buckets))
+	}
+	for i := 0; i < 800; i++ {
+		ht.Insert(&TestData{S: fmt.Sprintf("k%d", i)})
+	}
+	// The items are spread over all the shards and all the buckets in each shard.
+	total := 0
+	for i := range ht.shards {
+		ss := &ht.shards[i]
+		total += ss.length
+		if ss.length < 50 || ss.length > 150 {
+			t.Errorf("Shard %d has %d items", i, ss.length)
+		}
+		for j, v := range ss.buckets {
+			if v.Length() == 0 {
+				t.Errorf("Shard %d bucket %d is empty", i, j)
+			}
+		}
+	}
+	if total != 800 {
+		t.Errorf("Expected 800 items in the shards got %d", total)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	ht := NewHashTabShards[TestData](16, 4)
+	var want []string
+	for i := 0; i < 50; i++ {
+		ht.Insert(&TestData{S: fmt.Sprintf("%4d", i)})
+		want = append(want, fmt.Sprintf("%4d", i))
+	}
+	var got []string
+	ht.WalkFunc(func(a *TestData) { got = append(got, a.S) })
+	sort.Strings(got)
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("WalkFunc expcted %v got %v", want, got)
+	}
+	n := 0
+	ht.Walk(func(pos, depth int, data *TestData, userData interface{}) bool {
+		n++
+		return n < 10
+	}, nil)
+	if n != 10 {
+		t.Errorf("Expected Walk to stop at 10 got %d", n)
+	}
+}
+
+func TestLoadOrStore(t *testing.T) {
+	ht := NewHashTab[TestData](7)
+	a := &TestData{S: "a", N: 1}
+	if actual, loaded := ht.LoadOrStore(a); loaded || actual != a {
+		t.Errorf("Expected a to be stored")
+	}
+	if actual, loaded := ht.LoadOrStore(&TestData{S: "a", N: 2}); !loaded || actual != a {
+		t.Errorf("Expected a to be loaded")
+	}
+	if ht.Length() != 1 || ht.Search(&TestData{S: "a"}).N != 1 {
+		t.Errorf("Expected only the first a")
+	}
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	ht := NewHashTab[TestData](7)
+	a := &TestData{S: "a", N: 1}
+	ht.Insert(a)
+	b := &TestData{S: "a", N: 2}
+	if !ht.CompareAndSwap(a, b) {
+		t.Errorf("Expected a to be swapped for b")
+	}
+	if ht.CompareAndSwap(a, &TestData{S: "a", N: 3}) {
+		t.Errorf("Expected the swap from a stale item to fail")
+	}
+	if x := ht.Search(&TestData{S: "a"}); x != b || ht.Length() != 1 {
+		t.Errorf("Expected b got %v", x)
+	}
+	if ht.CompareAndSwap(&TestData{S: "zz"}, &TestData{S: "zz"}) || ht.Length() != 1 {
+		t.Errorf("Expected no swap for a missing key")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for different keys")
+		}
+	}()
+	ht.CompareAndSwap(b, &TestData{S: "b"})
+}
+
+func TestCompute(t *testing.T) {
+	ht := NewHashTab[TestData](7)
+	// A counter: add one, starting from 0 if it is not there.
+	inc := func(item *TestData, found bool) (*TestData, bool) {
+		if !found {
+			return &TestData{S: "n", N: 1}, true
+		}
+		return &TestData{S: "n", N: item.N + 1}, true
+	}
+	ht.Compute(&TestData{S: "n"}, inc)
+	if x, keep := ht.Compute(&TestData{S: "n"}, inc); !keep || x.N != 2 {
+		t.Errorf("Expected n to be 2 got %v", x)
+	}
+	// Remove it.
+	ht.Compute(&TestData{S: "n"}, func(item *TestData, found bool) (*TestData, bool) { return nil, false })
+	if ht.ItemExists(&TestData{S: "n"}) || ht.Length() != 0 {
+		t.Errorf("Expected n to be removed")
+	}
+	// Not there and not kept is a no-op.
+	ht.Compute(&TestData{S: "x"}, func(item *TestData, found bool) (*TestData, bool) {
+		if found || item != nil {
+			t.Errorf("Expected x not to be found")
+		}
+		return nil, false
+	})
+	if ht.Length() != 0 {
+		t.Errorf("Expected length 0 got %d", ht.Length())
+	}
+}
+
+// TestModel does random operations, including the atomic ones, and checks them against a Go map.
+func TestModel(t *testing.T) {
+	ht := NewHashTabShards[TestData](20, 4)
+	model := make(map[string]int)
+	rnd := rand.New(rand.NewSource(1001))
+	for ii := 0; ii < 5000; ii++ {
+		key := fmt.Sprint(rnd.Intn(150))
+		want, wantFound := model[key]
+		switch rnd.Intn(5) {
+		case 0:
+			ht.Insert(&TestData{S: key, N: ii})
+			model[key] = ii
+		case 1:
+			if found := ht.Delete(&TestData{S: key}); found != wantFound {
+				t.Fatalf("Step %d, Delete(%s) expcted %v got %v", ii, key, wantFound, found)
+			}
+			delete(model, key)
+		case 2:
+			actual, loaded := ht.LoadOrStore(&TestData{S: key, N: ii})
+			if loaded != wantFound || (loaded && actual.N != want) {
+				t.Fatalf("Step %d, LoadOrStore(%s) expcted %d %v got %v %v", ii, key, want, wantFound, actual, loaded)
+			}
+			if !loaded {
+				model[key] = ii
+			}
+		case 3:
+			ht.Compute(&TestData{S: key}, func(item *TestData, found bool) (*TestData, bool) {
+				if found != wantFound || (found && item.N != want) {
+					t.Fatalf("Step %d, Compute(%s) expcted %d %v got %v %v", ii, key, want, wantFound, item, found)
+				}
+				return &TestData{S: key, N: want + 1}, true
+			})
+			model[key] = want + 1
+		case 4:
+			if old := ht.Search(&TestData{S: key}); old != nil {
+				if !ht.CompareAndSwap(old, &TestData{S: key, N: -ii}) {
+					t.Fatalf("Step %d, CompareAndSwap(%s) failed", ii, key)
+				}
+				model[key] = -ii
+			}
+		}
+		if ht.Length() != len(model) {
+			t.Fatalf("Step %d, expected length %d got %d", ii, len(model), ht.Length())
+		}
+	}
+	for key, n := range model {
+		if x := ht.Search(&TestData{S: key}); x == nil || x.N != n {
+			t.Errorf("Search(%s) expcted %d got %v", key, n, x)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package hashing
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// FNV64 is FNV-1a 64 bit of a string or []byte.  If Seed is not 0 its 8 bytes are hashed
+// before the key, with a 0 Seed it is the same as hash/fnv New64a.
+type FNV64[T Bytes] struct {
+	Seed uint64
+}
+
+// Hash returns the hash of `x`.
+// Complexity is O(n), n the length of x.
+func (hh FNV64[T]) Hash(x T) uint64 {
+	return fnv64(fnvSeed(hh.Seed), x)
+}
+
+// IntFNV64 is FNV-1a 64 bit of the 8 bytes of an integer, see FNV64.
+type IntFNV64[T Integer] struct {
+	Seed uint64
+}
+
+// Hash returns the hash of `x`.
+// Complexity is O(1).
+func (hh IntFNV64[T]) Hash(x T) uint64 {
+	b := intBytes(x)
+	return fnv64(fnvSeed(hh.Seed), b[:])
+}
+
+// fnvSeed returns the starting state for `seed`.
+func fnvSeed(seed uint64) uint64 {
+	if seed == 0 {
+		return fnvOffset64
+	}
+	b := intBytes(seed)
+	return fnv64(fnvOffset64, b[:])
+}
+
+// fnv64 hashes the bytes of `x` on from the state `h`.
+func fnv64[T Bytes](h uint64, x T) uint64 {
+	for i := 0; i < len(x); i++ {
+		h ^= uint64(x[i])
+		h *= fnvPrime64
+	}
+	return h
+}
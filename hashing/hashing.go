@@ -0,0 +1,104 @@
+package hashing
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+Hash functions that can be passed to the hash tables.  Without one each table uses its own
+hash(): the HashKey method if the item is Hashable, else FNV-32a of the item if it is a string
+or of String() if it is a fmt.Stringer.  That is a fixed hash, anybody that can pick the keys
+can pick keys that all go in one bucket, and String() makes a new string on each lookup.
+
+A Hasher is passed to a table with WithHasher:
+
+	hh := hashing.HasherFunc[Item](func(x Item) uint64 { return hashing.XXH64[string]{Seed: seed}.Hash(x.Key) })
+	ht := hash_tab_dll.NewHashTab[Item](101, hashing.WithHasher[Item](hh))
+
+Hashers for strings and []byte (Bytes) and for the integer types:
+
+*	FNV64 / IntFNV64 - FNV-1a 64 bit, with an optional seed mixed in first.  Small and fast on
+		short keys, not safe for keys chosen by somebody else.								O(n)
+*	XXH64 / IntXXH64 - the XXH64 hash with a seed.  Fast on long keys, a secret random seed
+		makes the buckets hard to guess but it is not a keyed hash.							O(n)
+*	MapHash / IntMapHash - hash/maphash with a random seed made by NewMapHash and
+		NewIntMapHash.  Use this for keys from a request or a file.							O(n)
+
+n is the length of the key.  All of the hashers are safe for concurrent use.
+
+The Hash method of any of these can be used as the hash function of a ../hash_map HashMap:
+
+	hm := hash_map.NewHashMap[string, int](hashing.NewMapHash[string]().Hash, equal)
+
+*/
+
+import (
+	"golang.org/x/exp/constraints"
+)
+
+// Hasher returns the hash of a value of type T.
+type Hasher[T any] interface {
+	Hash(x T) uint64
+}
+
+// HasherFunc makes a function a Hasher.
+type HasherFunc[T any] func(x T) uint64
+
+// Hash calls ff.
+func (ff HasherFunc[T]) Hash(x T) uint64 {
+	return ff(x)
+}
+
+// Bytes are the types that are hashed as a sequence of bytes.
+type Bytes interface {
+	~string | ~[]byte
+}
+
+// Integer are the types that are hashed as the 8 bytes of their value.
+type Integer interface {
+	constraints.Integer
+}
+
+// Config is the set of options that a hash table constructor takes.
+type Config[T any] struct {
+	Hasher Hasher[T] // nil to use the table's own hash()
+}
+
+// Option sets one field of a Config.
+type Option[T any] func(cfg *Config[T])
+
+// WithHasher makes a table use `hh` to hash its items.
+func WithHasher[T any](hh Hasher[T]) Option[T] {
+	return func(cfg *Config[T]) {
+		cfg.Hasher = hh
+	}
+}
+
+// NewConfig returns the Config with each of `opts` applied in order.
+// Complexity is O(1).
+func NewConfig[T any](opts ...Option[T]) (cfg Config[T]) {
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return
+}
+
+// Index returns `h` as a non-negative int, for use as a bucket index with `% size`.  The
+// hash is reduced to the width of uint before it is converted, so the result is not negative
+// where int is 32 bits.
+// Complexity is O(1).
+func Index(h uint64) int {
+	return int(uint(h) >> 1)
+}
+
+// intBytes returns the 8 bytes of `x`, low byte first.
+func intBytes[T Integer](x T) (rv [8]byte) {
+	v := uint64(x)
+	for i := range rv {
+		rv[i] = byte(v >> (8 * i))
+	}
+	return
+}
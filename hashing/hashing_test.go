@@ -0,0 +1,171 @@
+package hashing
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"hash/fnv"
+	"testing"
+)
+
+// At compile time verify that each hasher is a Hasher.
+var _ Hasher[string] = FNV64[string]{}
+var _ Hasher[[]byte] = XXH64[[]byte]{}
+var _ Hasher[string] = MapHash[string]{}
+var _ Hasher[int] = IntFNV64[int]{}
+var _ Hasher[uint32] = IntXXH64[uint32]{}
+var _ Hasher[int64] = IntMapHash[int64]{}
+var _ Hasher[string] = HasherFunc[string](nil)
+
+type myKey string
+
+func TestFNV64(t *testing.T) {
+	for _, s := range []string{"", "a", "abc", "Nobody inspects the spammish repetition"} {
+		h := fnv.New64a()
+		h.Write([]byte(s))
+		if got := (FNV64[string]{}).Hash(s); got != h.Sum64() {
+			t.Errorf("FNV64(%q) expcted %x got %x", s, h.Sum64(), got)
+		}
+		if (FNV64[string]{}).Hash(s) != (FNV64[[]byte]{}).Hash([]byte(s)) {
+			t.Errorf("FNV64(%q) of a string and a []byte are different", s)
+		}
+		if (FNV64[string]{Seed: 1}).Hash(s) == h.Sum64() {
+			t.Errorf("FNV64(%q) with a seed expcted a different hash", s)
+		}
+	}
+}
+
+func TestXXH64(t *testing.T) {
+	// Values from the reference implementation.
+	tests := []struct {
+		s    string
+		want uint64
+	}{
+		{"", 0xef46db3751d8e999},
+		{"a", 0xd24ec4f1a98c6e5b},
+		{"abc", 0x44bc2cf5ad770999},
+		{"Nobody inspects the spammish repetition", 0xfbcea83c8a378bf1},
+	}
+	for _, test := range tests {
+		if got := (XXH64[string]{}).Hash(test.s); got != test.want {
+			t.Errorf("XXH64(%q) expcted %x got %x", test.s, test.want, got)
+		}
+		if got := (XXH64[myKey]{}).Hash(myKey(test.s)); got != test.want {
+			t.Errorf("XXH64(myKey(%q)) expcted %x got %x", test.s, test.want, got)
+		}
+		if (XXH64[string]{Seed: 1}).Hash(test.s) == test.want {
+			t.Errorf("XXH64(%q) with a seed expcted a different hash", test.s)
+		}
+	}
+	// Every length up to 2 stripes, so each of the tail loops is used.
+	var b []byte
+	for i := 0; i < 70; i++ {
+		if (XXH64[[]byte]{Seed: 5}).Hash(b) != (XXH64[string]{Seed: 5}).Hash(string(b)) {
+			t.Errorf("XXH64 of a string and a []byte of length %d are different", i)
+		}
+		b = append(b, byte(i*7))
+	}
+}
+
+func TestMapHash(t *testing.T) {
+	h1, h2 := NewMapHash[string](), NewMapHash[string]()
+	if h1.Hash("abc") != h1.Hash("abc") {
+		t.Errorf("MapHash expcted the same hash for the same key")
+	}
+	if h1.Hash("abc") == h2.Hash("abc") {
+		t.Errorf("MapHash expcted different seeds to give different hashes")
+	}
+	if NewMapHash[myKey]().Hash("abc") == 0 || NewMapHash[[]byte]().Hash([]byte("abc")) == 0 {
+		t.Errorf("MapHash expcted a hash")
+	}
+}
+
+func TestIntHashers(t *testing.T) {
+	hashers := []Hasher[int]{IntFNV64[int]{}, IntXXH64[int]{Seed: 3}, NewIntMapHash[int]()}
+	for _, hh := range hashers {
+		seen := make(map[uint64]int)
+		for i := -500; i < 500; i++ {
+			h := hh.Hash(i)
+			if h != hh.Hash(i) {
+				t.Errorf("%T expcted the same hash for %d", hh, i)
+			}
+			if j, ok := seen[h]; ok {
+				t.Errorf("%T has the same hash for %d and %d", hh, i, j)
+			}
+			seen[h] = i
+		}
+	}
+	// The same value in a different integer type is the same 8 bytes.
+	if (IntXXH64[int]{}).Hash(12) != (IntXXH64[uint8]{}).Hash(12) {
+		t.Errorf("IntXXH64 expcted the same hash for 12 as an int and a uint8")
+	}
+}
+
+// TestSpread checks that each hasher puts similar keys in the buckets of a small table about
+// evenly, none has more than twice its share.
+func TestSpread(t *testing.T) {
+	const nBuckets, nKeys = 31, 31 * 100
+	hashers := map[string]Hasher[string]{
+		"FNV64":   FNV64[string]{Seed: 77},
+		"XXH64":   XXH64[string]{Seed: 77},
+		"MapHash": NewMapHash[string](),
+	}
+	for name, hh := range hashers {
+		var count [nBuckets]int
+		for i := 0; i < nKeys; i++ {
+			count[(hh.Hash(fmt.Sprintf("key%d", i))>>1)%nBuckets]++
+		}
+		for b, n := range count {
+			if n > 2*nKeys/nBuckets {
+				t.Errorf("%s: bucket %d has %d keys", name, b, n)
+			}
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	for _, h := range []uint64{0, 1, 1 << 31, 0xffffffff, 0xffffffff80000000, 1<<63 | 1<<31, ^uint64(0)} {
+		if x := Index(h); x < 0 {
+			t.Errorf("Index(%x) expcted a non-negative int got %d", h, x)
+		}
+	}
+	if x := Index(0x1234 << 1); x != 0x1234 {
+		t.Errorf("Index expcted %x got %x", 0x1234, x)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	if cfg := NewConfig[string](); cfg.Hasher != nil {
+		t.Errorf("Expected no Hasher")
+	}
+	cfg := NewConfig(WithHasher[string](HasherFunc[string](func(x string) uint64 { return uint64(len(x)) })))
+	if cfg.Hasher == nil || cfg.Hasher.Hash("abcd") != 4 {
+		t.Errorf("Expected the Hasher from WithHasher")
+	}
+}
+
+func BenchmarkHashers(b *testing.B) {
+	hashers := []struct {
+		name string
+		hh   Hasher[string]
+	}{
+		{"FNV64", FNV64[string]{}},
+		{"XXH64", XXH64[string]{}},
+		{"MapHash", NewMapHash[string]()},
+	}
+	for _, size := range []int{8, 64, 1024} {
+		key := string(make([]byte, size))
+		for _, hs := range hashers {
+			b.Run(fmt.Sprintf("%s/%d", hs.name, size), func(b *testing.B) {
+				b.SetBytes(int64(size))
+				for i := 0; i < b.N; i++ {
+					hs.hh.Hash(key)
+				}
+			})
+		}
+	}
+}
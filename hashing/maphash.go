@@ -0,0 +1,53 @@
+package hashing
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import "hash/maphash"
+
+// MapHash is hash/maphash of a string or []byte.  The seed is random, so the hash of a key is
+// different in each MapHash and in each run of the program.  The zero value can not be used,
+// make one with NewMapHash.
+type MapHash[T Bytes] struct {
+	seed maphash.Seed
+}
+
+// NewMapHash returns a MapHash with a new random seed.
+// Complexity is O(1).
+func NewMapHash[T Bytes]() MapHash[T] {
+	return MapHash[T]{seed: maphash.MakeSeed()}
+}
+
+// Hash returns the hash of `x`.
+// Complexity is O(n), n the length of x.
+func (hh MapHash[T]) Hash(x T) uint64 {
+	switch v := any(x).(type) {
+	case string:
+		return maphash.String(hh.seed, v)
+	case []byte:
+		return maphash.Bytes(hh.seed, v)
+	}
+	return maphash.String(hh.seed, string(x)) // a named string or []byte type
+}
+
+// IntMapHash is hash/maphash of the 8 bytes of an integer, see MapHash.  Make one with
+// NewIntMapHash.
+type IntMapHash[T Integer] struct {
+	seed maphash.Seed
+}
+
+// NewIntMapHash returns an IntMapHash with a new random seed.
+// Complexity is O(1).
+func NewIntMapHash[T Integer]() IntMapHash[T] {
+	return IntMapHash[T]{seed: maphash.MakeSeed()}
+}
+
+// Hash returns the hash of `x`.
+// Complexity is O(1).
+func (hh IntMapHash[T]) Hash(x T) uint64 {
+	b := intBytes(x)
+	return maphash.Bytes(hh.seed, b[:])
+}
@@ -0,0 +1,119 @@
+package hashing
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+The XXH64 hash, see https://github.com/Cyan4973/xxHash/blob/dev/doc/xxhash_spec.md
+
+The key is hashed 32 bytes at a time with 4 accumulators, then the rest 8, 4 and 1 bytes at a
+time, and then the bits are mixed (the avalanche).  It is written over the Bytes type set so
+that a string is hashed without a copy to a []byte.
+
+*/
+
+import "math/bits"
+
+const (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+// XXH64 is the XXH64 hash of a string or []byte with Seed.
+type XXH64[T Bytes] struct {
+	Seed uint64
+}
+
+// Hash returns the hash of `x`.
+// Complexity is O(n), n the length of x.
+func (hh XXH64[T]) Hash(x T) uint64 {
+	return xxh64(hh.Seed, x)
+}
+
+// IntXXH64 is the XXH64 hash of the 8 bytes of an integer with Seed.
+type IntXXH64[T Integer] struct {
+	Seed uint64
+}
+
+// Hash returns the hash of `x`.
+// Complexity is O(1).
+func (hh IntXXH64[T]) Hash(x T) uint64 {
+	b := intBytes(x)
+	return xxh64(hh.Seed, b[:])
+}
+
+func xxh64[T Bytes](seed uint64, x T) uint64 {
+	n := len(x)
+	p := 0
+	var h uint64
+	if n >= 32 {
+		v1 := seed + xxPrime1 + xxPrime2
+		v2 := seed + xxPrime2
+		v3 := seed
+		v4 := seed - xxPrime1
+		for ; p+32 <= n; p += 32 {
+			v1 = xxRound(v1, le64(x, p))
+			v2 = xxRound(v2, le64(x, p+8))
+			v3 = xxRound(v3, le64(x, p+16))
+			v4 = xxRound(v4, le64(x, p+24))
+		}
+		h = bits.RotateLeft64(v1, 1) + bits.RotateLeft64(v2, 7) + bits.RotateLeft64(v3, 12) + bits.RotateLeft64(v4, 18)
+		h = xxMerge(h, v1)
+		h = xxMerge(h, v2)
+		h = xxMerge(h, v3)
+		h = xxMerge(h, v4)
+	} else {
+		h = seed + xxPrime5
+	}
+	h += uint64(n)
+
+	for ; p+8 <= n; p += 8 {
+		h ^= xxRound(0, le64(x, p))
+		h = bits.RotateLeft64(h, 27)*xxPrime1 + xxPrime4
+	}
+	if p+4 <= n {
+		h ^= uint64(le32(x, p)) * xxPrime1
+		h = bits.RotateLeft64(h, 23)*xxPrime2 + xxPrime3
+		p += 4
+	}
+	for ; p < n; p++ {
+		h ^= uint64(x[p]) * xxPrime5
+		h = bits.RotateLeft64(h, 11) * xxPrime1
+	}
+
+	h ^= h >> 33
+	h *= xxPrime2
+	h ^= h >> 29
+	h *= xxPrime3
+	h ^= h >> 32
+	return h
+}
+
+func xxRound(acc, input uint64) uint64 {
+	acc += input * xxPrime2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * xxPrime1
+}
+
+func xxMerge(h, v uint64) uint64 {
+	h ^= xxRound(0, v)
+	return h*xxPrime1 + xxPrime4
+}
+
+// le64 returns the 8 bytes of `x` at `p`, low byte first.
+func le64[T Bytes](x T, p int) uint64 {
+	return uint64(x[p]) | uint64(x[p+1])<<8 | uint64(x[p+2])<<16 | uint64(x[p+3])<<24 |
+		uint64(x[p+4])<<32 | uint64(x[p+5])<<40 | uint64(x[p+6])<<48 | uint64(x[p+7])<<56
+}
+
+// le32 returns the 4 bytes of `x` at `p`, low byte first.
+func le32[T Bytes](x T, p int) uint32 {
+	return uint32(x[p]) | uint32(x[p+1])<<8 | uint32(x[p+2])<<16 | uint32(x[p+3])<<24
+}
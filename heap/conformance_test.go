@@ -0,0 +1,24 @@
+package heap
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"testing"
+
+	"github.com/pschlump/pluto/containertest"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// testItem makes the data for the shared conformance tests.
+var testItem = containertest.Item[myHeap]{
+	New: func(n int) *myHeap { hv := myHeap(n); return &hv },
+	Key: func(x *myHeap) int { return int(*x) },
+}
+
+func TestConformance(t *testing.T) {
+	containertest.TestPriorityQueue(t, func() iface_list.PriorityQueueDataType[myHeap] { return NewHeap[myHeap]() }, testItem)
+}
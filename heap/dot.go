@@ -0,0 +1,35 @@
+package heap
+
+// Copyright (C) 2021 Philip Schlump. All rights reserved.
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pschlump/pluto/dot"
+)
+
+// WriteDOT writes the heap as a tree in graphviz DOT format to `fo`.  The node for index
+// `ii` is named "n<ii>" and has the children 2*ii+1 and 2*ii+2.  Each node is labeled with
+// its data, from opts.Label, and its index in the heap.
+// Complexity is O(n).
+func (hp *Heap[T]) WriteDOT(fo io.Writer, opts *dot.Options) (err error) {
+	if err = dot.WriteHeader(fo, opts); err != nil {
+		return
+	}
+	n := len(hp.data)
+	for ii := 0; ii < n; ii++ {
+		label := fmt.Sprintf("%s\n[%d]", opts.NodeLabel(*(hp.data[ii])), ii)
+		if err = dot.WriteNode(fo, fmt.Sprintf("n%d", ii), map[string]string{"label": label}); err != nil {
+			return
+		}
+		for _, cc := range []int{2*ii + 1, 2*ii + 2} {
+			if cc < n {
+				if err = dot.WriteEdge(fo, fmt.Sprintf("n%d", ii), fmt.Sprintf("n%d", cc), nil); err != nil {
+					return
+				}
+			}
+		}
+	}
+	return dot.WriteFooter(fo)
+}
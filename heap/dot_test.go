@@ -0,0 +1,38 @@
+// Copyright (C) 2021 Philip Schlump. All rights reserved.
+
+package heap
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pschlump/pluto/dot"
+)
+
+func TestWriteDOT(t *testing.T) {
+	h := NewHeap[myHeap]()
+	for _, v := range []myHeap{3, 1, 2} {
+		hv := v
+		h.Push(&hv)
+	}
+
+	var buf bytes.Buffer
+	if err := h.WriteDOT(&buf, &dot.Options{Name: "heap"}); err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	expect := `digraph "heap" {
+	"n0" [label="1\n[0]"];
+	"n0" -> "n1";
+	"n0" -> "n2";
+	"n1" [label="3\n[1]"];
+	"n2" [label="2\n[2]"];
+}
+`
+	if got := buf.String(); got != expect {
+		t.Errorf("WriteDOT error, expcted\n%s\ngot\n%s", expect, got)
+	}
+
+	if _, err := dot.Parse(&buf); err != nil {
+		t.Errorf("Output is not valid DOT: %s", err)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/pschlump/MiscLib"
 	"github.com/pschlump/dbgo"
 	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/iface_list"
 )
 
 //
@@ -28,6 +29,9 @@ type Heap[T comparable.Comparable] struct {
 	data []*T
 }
 
+// At compile time verify that Heap implements the priority queue interface.
+var _ iface_list.PriorityQueueDataType[comparable.Int] = (*Heap[comparable.Int])(nil)
+
 // Create a new heap and return it.
 // Complexity is O(1).
 func NewHeap[T comparable.Comparable]() *Heap[T] {
@@ -42,6 +46,12 @@ func (hp *Heap[T]) Push(x *T) {
 	hp.up(len(hp.data) - 1)      // Reorder to fix heap
 }
 
+// Insert is the same as Push, so a heap can be used as a priority queue.
+// Complexity is O(log n).
+func (hp *Heap[T]) Insert(x *T) {
+	hp.Push(x)
+}
+
 // Pop removes and returns the minimum element (using comparable.Compare).
 // Pop is the same as hp.Remove(0).
 // Complexity is O(log n).
@@ -129,6 +139,12 @@ func (hp *Heap[T]) Length() int {
 	return len(hp.data)
 }
 
+// IsEmpty returns true if there is no data in the heap.
+// Complexity is O(1).
+func (hp *Heap[T]) IsEmpty() bool {
+	return len(hp.data) == 0
+}
+
 // Complexity is O(n).
 func (hp *Heap[T]) Search(cmpVal *T) (rv *T, pos int, err error) {
 	for ii := 0; ii < len(hp.data); ii++ {
@@ -13,6 +13,7 @@ import (
 	// "github.com/pschlump/dbgo"
 	// "github.com/pschlump/MiscLib"
 	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/iface_list"
 )
 
 // Create a "heap of int" type called myHeap
@@ -314,3 +315,29 @@ func TestFix(t *testing.T) {
 */
 
 const db12 = false
+
+func TestPriorityQueueDataType(t *testing.T) {
+	var pq iface_list.PriorityQueueDataType[myHeap] = NewHeap[myHeap]()
+
+	if !pq.IsEmpty() {
+		t.Errorf("Expected empty heap")
+	}
+	for _, v := range []int{5, 1, 4, 2, 3} {
+		hv := myHeap(v)
+		pq.Insert(&hv)
+	}
+	if pq.IsEmpty() || pq.Length() != 5 {
+		t.Errorf("Invalid length, expected 5, got %d", pq.Length())
+	}
+	if x := pq.Peek(); x == nil || *x != 1 {
+		t.Errorf("Peek error, expected 1 got %v", x)
+	}
+	for i := 1; i <= 5; i++ {
+		if x := pq.Pop(); x == nil || int(*x) != i {
+			t.Errorf("%d.th Pop() got %v; expected %d", i, x, i)
+		}
+	}
+	if !pq.IsEmpty() || pq.Pop() != nil {
+		t.Errorf("Expected empty heap")
+	}
+}
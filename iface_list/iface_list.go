@@ -1,82 +1,96 @@
 package iface_list
 
-// sll
-// sll_ts
-// dll
-// dll_ts
+/*
+Copyright (C) Philip Schlump, 2012-2023.
 
-type LinearDataType[T any] interface {
-	Insert(data *T)                  // same as InsertBeforeHead
-	InsertBeforeHead(data *T)        //
-	Append(data *T)                  // same as InsertAfterTail
-	InsertAfterTail(data *T)         //
-	Push(data *T)                    // same as Insert, InsertBeforeHead
-	DeleteAt(int pos) (err error)    //
-	Delete(data *T) (err error)      //
-	DeleteFound(data *T) (err error) //
-	IsEmpty() bool                   //
-	Peek() (data *T, err erorr)      //
-	Pop() (data *T, err erorr)       //
-	Reverse()                        //
-	Length() int                     //
-	Truncate()                       //
-
-	EnQueue(data *T)               // same as InsertAfterTail, sometimes called Q.Push (PushTail)
-	PushTail(data *T)              // same as Append
-	PopTail() (data *T, err error) // o(n) on SLL, o(1) on DLL
-	ConvertToSlice() (data []*T)   // Convert to a Slice
-
-	// InsertBeforPos
-	// InsertAfterPos
-
-	// JSON Interface Functions
+BSD 3 Clause Licensed.
+
+Interfaces that are implemented by the containers in this module.  Code that is written
+against one of these can have the implementation swapped (for example sll for dll_ts)
+without other changes.
+
+*	Container — IsEmpty, Length and Truncate, implemented by all of the containers.
+*	StackDataType — Push/Peek/Pop at the top.  Implemented by sll, sll_ts, dll, dll_ts, stack_sll_ts.
+*	QueueDataType — Enque at the tail, Peek/Pop at the head.  Implemented by sll, sll_ts, dll, dll_ts.
+*	LinearDataType — A list that is both a stack and a queue.  Implemented by sll, sll_ts, dll, dll_ts.
+*	TreeDataType — An ordered set.  Implemented by binary_tree, binary_tree_ts, avl_tree, avl_tree_ts, rb_tree,
+		rb_tree_ts, treap, treap_ts, btree, skiplist, skiplist_ts.
+*	PriorityQueueDataType — Insert and Pop the minimum.  Implemented by heap, priority_queue.
+*	SetDataType — An unordered set.  Implemented by hash_tab, hash_tab_bt, hash_tab_bt_ts, hash_tab_shard, hash_grow, hash_grow_ts.
+
+Each package has a compile time check next to the type, so go build catches a method that no
+longer matches, like:
+
+	var _ iface_list.LinearDataType[comparable.Int] = (*Sll[comparable.Int])(nil)
+
+and runs the shared conformance tests in ../containertest against its implementation.
+
+*/
+
+// Implemented by all the containers.
+type Container interface {
+	IsEmpty() bool // True if there is no data
+	Length() int   // Number of items
+	Truncate()     // Remove all the data
+}
+
+// Implemented by sll, sll_ts, dll, dll_ts, stack_sll_ts
+type StackDataType[T any] interface {
+	Container
+	Push(data *T)               // same as InsertBeforeHead
+	Peek() (data *T, err error) // top of stack, error if empty
+	Pop() (data *T, err error)  // remove top of stack, error if empty
+	ConvertToSlice() []*T       // Convert to a Slice, top of stack first
 }
 
 // Implemented by sll, sll_ts, dll, dll_ts
-type StackDataType interface {
-	IsEmpty() bool
-	Push(data *T) // same as Insert, InsertBeforeHead
-	Peek() (data *T, err erorr)
-	Pop() (data *T, err erorr)
-	Length() int
-	Truncate()
-	ConvertToSlice() (data []*T) // Convert to a Slice
+type QueueDataType[T any] interface {
+	Container
+	Enque(data *T)              // same as InsertAfterTail, sometimes called Q.Push
+	Peek() (data *T, err error) // head of queue, error if empty
+	Pop() (data *T, err error)  // remove head of queue, error if empty
+	ConvertToSlice() []*T       // Convert to a Slice, head of queue first
 }
 
 // Implemented by sll, sll_ts, dll, dll_ts
-type QueueDataType interface {
-	IsEmpty() bool
-	EnQueue(data *T) // same as InsertAfterTail, sometimes called Q.Push
-	Peek() (data *T, err erorr)
-	Pop() (data *T, err erorr)
-	Length() int
-	Truncate()
-	ConvertToSlice() (data []*T) // Convert to a Slice
+type LinearDataType[T any] interface {
+	StackDataType[T]
+	QueueDataType[T]
+	PeekTail() (data *T, err error) // last item, error if empty
+	PopTail() (data *T, err error)  // O(n) on SLL, O(1) on DLL
+	Reverse()                       // Reverse the order of the list
+}
+
+// Implemented by binary_tree, binary_tree_ts, avl_tree, avl_tree_ts, rb_tree, rb_tree_ts, treap,
+// treap_ts, btree, skiplist, skiplist_ts
+type TreeDataType[T any] interface {
+	Container
+	Insert(data *T) (isNew bool) // Replace if already in tree, true if a new item
+	Delete(data *T) (found bool) //
+	Search(data *T) (item *T)    // Item will be a different pointer from data, nil if not found
+	FindMin() (item *T)          // nil if empty
+	FindMax() (item *T)          // nil if empty
+	DeleteAtHead() (found bool)  // Delete the minimum
+	DeleteAtTail() (found bool)  // Delete the maximum
+	Index(pos int) (item *T)     // The pos'th item in order, nil if out of range
+	Depth() int                  // Deepest part of tree
+	ConvertToSlice() (data []*T) // Convert to a Slice, in order
 }
 
-// Implemented binary_tree, binary_tree_ts, avl_tree, avl_tree_ts
-type TreeDataType interface {
-	Insert(data *T)                       // same as InsertBeforeHead
-	Delete(data *T) (err error)           //
-	HasItem(data *T) (found bool)         //
-	Search(data *T) (item *T, found bool) // Item will be a different pointer from data, that has IsEqual() to data
-	IsEmpty() bool
-	Length() int
-	Truncate()
-	ConvertToSlice() (data []*T) // Convert to a Slice
-	FindMin() (data *T, err error)
-	FindMax() (data *T, err error)
-	Depth() int                //  int to get deepest part of tree
-	Pop() (data *T, err error) // FindMin -> DeleteAt(0)
+// Implemented by heap, priority_queue
+type PriorityQueueDataType[T any] interface {
+	Container
+	Insert(data *T)  // Add an item
+	Peek() (item *T) // Smallest item, nil if empty
+	Pop() (item *T)  // Remove the smallest item, nil if empty
 }
 
-type PriorityQueueDataType interface {
-	Insert(data *T)            // same as InsertBeforeHead
-	Depth() int                //  int to get deepest part of tree
-	Pop() (data *T, err error) // FindMin -> DeleteAt(0)
-	IsEmpty() bool
-	Length() int
-	Truncate()
+// Implemented by hash_tab, hash_tab_bt, hash_tab_bt_ts, hash_tab_shard, hash_grow
+type SetDataType[T any] interface {
+	Container
+	Insert(data *T)              // Replace if already in set
+	Delete(data *T) (found bool) //
+	Search(data *T) (item *T)    // Item will be a different pointer from data, nil if not found
 }
 
 /*
@@ -0,0 +1,393 @@
+package pavl
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+A persistent AVL tree.  A tree is never changed after it is made.  Insert and Delete return a
+new version of the tree that shares all the nodes that are not on the path to the change with
+the old version, the path is copied.  Old versions stay valid and can be read from any number
+of goroutines with no locks.  See Ref for holding the current version of a tree that is shared
+between goroutines.
+
+The zero value of AvlTree is an empty tree.
+
+* 	Insert - return a new version with the item added.  Duplicates replace the item.			O(log|2(n))
+* 	Delete — return a new version without the item.												O(log|2(n))
+* 	Search — Returns the matching item or nil.													O(log|2(n))
+* 	Index - return the Nth item in order.														O(log|2(n))
+* 	Rank - the number of items less than an item.												O(log|2(n))
+* 	IsEmpty — Returns true if the tree is empty													O(1)
+* 	Length — Returns number of items in the tree.												O(1)
+* 	Depth - the height of the tree.																O(1)
+*	FindMin - the smallest item.																O(log|2(n))
+*	FindMax - the largest item.																	O(log|2(n))
+* 	ConvertToSlice - return the data in order as a slice.										O(n)
+*	WalkInOrder - Apply a function to all the items in order.									O(n)
+* 	Range — an iter.Seq over the items from lo to hi.											O(log|2(n)+k)
+* 	All — an iter.Seq over all the items.														O(n)
+
+*/
+
+import (
+	"iter"
+
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/g_lib"
+	"github.com/pschlump/pluto/stack"
+)
+
+// AvlTreeElement is a node in the tree.  Nodes are shared between versions of the tree so
+// they are never modified after they are made.
+type AvlTreeElement[T comparable.Comparable] struct {
+	data        *T
+	height      int
+	size        int // number of nodes in this sub-tree
+	left, right *AvlTreeElement[T]
+}
+
+// AvlTree is one version of a persistent AVL tree.
+type AvlTree[T comparable.Comparable] struct {
+	root *AvlTreeElement[T]
+}
+
+// NewAvlTree returns an empty tree.
+// Complexity is O(1).
+func NewAvlTree[T comparable.Comparable]() *AvlTree[T] {
+	return &AvlTree[T]{}
+}
+
+// Complexity is O(1).
+func (ee *AvlTreeElement[T]) GetData() *T {
+	return ee.data
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// height returns the saved height of the sub-tree at `e`.
+func height[T comparable.Comparable](e *AvlTreeElement[T]) int {
+	if e == nil {
+		return 0
+	}
+	return e.height
+}
+
+// size returns the number of nodes in the sub-tree at `e`.
+func size[T comparable.Comparable](e *AvlTreeElement[T]) int {
+	if e == nil {
+		return 0
+	}
+	return e.size
+}
+
+// newNode makes a new node from the parts.  `l` and `r` must already be balanced and differ in
+// height by no more than 1.
+func newNode[T comparable.Comparable](l *AvlTreeElement[T], data *T, r *AvlTreeElement[T]) *AvlTreeElement[T] {
+	return &AvlTreeElement[T]{
+		data:   data,
+		height: g_lib.Max(height(l), height(r)) + 1,
+		size:   size(l) + size(r) + 1,
+		left:   l,
+		right:  r,
+	}
+}
+
+// balance makes a new node from the parts, doing the AVL rotation if `l` and `r` differ in
+// height by 2.  The rotations build new nodes, the nodes in `l` and `r` may be in use by
+// other versions of the tree.
+func balance[T comparable.Comparable](l *AvlTreeElement[T], data *T, r *AvlTreeElement[T]) *AvlTreeElement[T] {
+	hl, hr := height(l), height(r)
+	if hl > hr+1 {
+		if height(l.left) >= height(l.right) {
+			// Left Left Case - Right Rotate
+			return newNode(l.left, l.data, newNode(l.right, data, r))
+		}
+		// Left Right Case - Left Rotate the left, Right Rotate
+		x := l.right
+		return newNode(newNode(l.left, l.data, x.left), x.data, newNode(x.right, data, r))
+	} else if hr > hl+1 {
+		if height(r.right) >= height(r.left) {
+			// Right Right Case - Left Rotate
+			return newNode(newNode(l, data, r.left), r.data, r.right)
+		}
+		// Right Left Case - Right Rotate the right, Left Rotate
+		x := r.left
+		return newNode(newNode(l, data, x.left), x.data, newNode(x.right, r.data, r.right))
+	}
+	return newNode(l, data, r)
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// IsEmpty will return true if the tree is empty
+// Complexity is O(1).
+func (tt *AvlTree[T]) IsEmpty() bool {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	return tt.root == nil
+}
+
+// Length returns the number of items in the tree.
+// Complexity is O(1).
+func (tt *AvlTree[T]) Length() int {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	return size(tt.root)
+}
+
+// Depth returns the height of the tree.
+// Complexity is O(1).
+func (tt *AvlTree[T]) Depth() int {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	return height(tt.root)
+}
+
+// Insert returns a new version of the tree with `item` added.  If it is a duplicate of an
+// existing item the new item replaces it in the new version.  True is returned if the item
+// is new, false if it replaced an existing one.  The tree `tt` is not changed.
+// Complexity is O(log|2(n)).
+func (tt *AvlTree[T]) Insert(item *T) (nt *AvlTree[T], isNew bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// Recursive, each node on the path is copied on the way back up.
+	var insert func(e *AvlTreeElement[T]) *AvlTreeElement[T]
+	insert = func(e *AvlTreeElement[T]) *AvlTreeElement[T] {
+		if e == nil {
+			isNew = true
+			return newNode(nil, item, nil)
+		} else if c := (*item).Compare(*e.data); c == 0 {
+			return newNode(e.left, item, e.right)
+		} else if c < 0 {
+			return balance(insert(e.left), e.data, e.right)
+		}
+		return balance(e.left, e.data, insert(e.right))
+	}
+
+	return &AvlTree[T]{root: insert(tt.root)}, isNew
+}
+
+// Delete returns a new version of the tree without the item that matches `find`.  If there is
+// no match then `tt` itself is returned with found false.  The tree `tt` is not changed.
+// Complexity is O(log|2(n)).
+func (tt *AvlTree[T]) Delete(find *T) (nt *AvlTree[T], found bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	// removeMin returns the sub-tree without its smallest item, and that item.
+	var removeMin func(e *AvlTreeElement[T]) (*AvlTreeElement[T], *T)
+	removeMin = func(e *AvlTreeElement[T]) (*AvlTreeElement[T], *T) {
+		if e.left == nil {
+			return e.right, e.data
+		}
+		rest, min := removeMin(e.left)
+		return balance(rest, e.data, e.right), min
+	}
+
+	// Recursive, only the nodes on the path to a found item are copied.
+	var remove func(e *AvlTreeElement[T]) *AvlTreeElement[T]
+	remove = func(e *AvlTreeElement[T]) *AvlTreeElement[T] {
+		if e == nil {
+			return nil
+		}
+		c := (*find).Compare(*e.data)
+		if c < 0 {
+			if l := remove(e.left); found {
+				return balance(l, e.data, e.right)
+			}
+			return e
+		} else if c > 0 {
+			if r := remove(e.right); found {
+				return balance(e.left, e.data, r)
+			}
+			return e
+		}
+		found = true
+		if e.left == nil {
+			return e.right
+		} else if e.right == nil {
+			return e.left
+		}
+		rest, min := removeMin(e.right) // promote the left most of the right sub-tree
+		return balance(e.left, min, rest)
+	}
+
+	root := remove(tt.root)
+	if !found {
+		return tt, false
+	}
+	return &AvlTree[T]{root: root}, true
+}
+
+// Search will walk the tree looking for `find` and retrn the found item
+// if it is in the tree. If it is not found then `nil` will be returned.
+// Complexity is O(log|2(n)).
+func (tt *AvlTree[T]) Search(find *T) (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	for cur := tt.root; cur != nil; {
+		c := (*find).Compare(*cur.data)
+		if c == 0 {
+			return cur.data
+		} else if c < 0 {
+			cur = cur.left
+		} else {
+			cur = cur.right
+		}
+	}
+	return nil
+}
+
+// FindMin returns the smallest item in the tree, nil if the tree is empty.
+// Complexity is O(log|2(n)).
+func (tt *AvlTree[T]) FindMin() (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	for cur := tt.root; cur != nil; cur = cur.left {
+		item = cur.data
+	}
+	return
+}
+
+// FindMax returns the largest item in the tree, nil if the tree is empty.
+// Complexity is O(log|2(n)).
+func (tt *AvlTree[T]) FindMax() (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	for cur := tt.root; cur != nil; cur = cur.right {
+		item = cur.data
+	}
+	return
+}
+
+// Index returns the N-th item in the tree, 0 based, in order.
+// Complexity is O(log|2(n)).
+func (tt *AvlTree[T]) Index(pos int) (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	if pos < 0 || pos >= size(tt.root) {
+		return nil
+	}
+	cur := tt.root
+	for cur != nil {
+		n := size(cur.left)
+		if pos < n {
+			cur = cur.left
+		} else if pos > n {
+			pos -= n + 1 // skip the left sub-tree and this node
+			cur = cur.right
+		} else {
+			return cur.data
+		}
+	}
+	return nil
+}
+
+// Rank returns the number of items in the tree that are less than `find`.
+// Complexity is O(log|2(n)).
+func (tt *AvlTree[T]) Rank(find *T) (n int) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	for cur := tt.root; cur != nil; {
+		if (*find).Compare(*cur.data) > 0 {
+			n += size(cur.left) + 1
+			cur = cur.right
+		} else {
+			cur = cur.left
+		}
+	}
+	return
+}
+
+// ConvertToSlice returns the data in the tree in order.
+// Complexity is O(n).
+func (tt *AvlTree[T]) ConvertToSlice() (rv []*T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	rv = make([]*T, 0, size(tt.root))
+	for item := range tt.All() {
+		rv = append(rv, item)
+	}
+	return
+}
+
+type ApplyFunction[T comparable.Comparable] func(pos, depth int, data *T, userData interface{}) bool
+
+// WalkInOrder calls `fx` for each item in order.  The walk stops if `fx` returns false.
+// Complexity is O(n).
+func (tt *AvlTree[T]) WalkInOrder(fx ApplyFunction[T], userData interface{}) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	p := 0
+	b := true
+	var inorderTraversal func(cur *AvlTreeElement[T], n int)
+	inorderTraversal = func(cur *AvlTreeElement[T], n int) {
+		if cur == nil || !b {
+			return
+		}
+		inorderTraversal(cur.left, n+1)
+		if b {
+			b = fx(p, n, cur.data, userData)
+			p++
+		}
+		inorderTraversal(cur.right, n+1)
+	}
+	inorderTraversal(tt.root, 0)
+}
+
+// Range returns an iterator over the items from `lo` to `hi`, both included, in order.  A nil
+// `lo` or `hi` leaves that end of the range open.
+// Complexity is O(log|2(n)+k) where k is the number of items in the range.
+func (tt *AvlTree[T]) Range(lo, hi *T) iter.Seq[*T] {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	root := tt.root
+	return func(yield func(*T) bool) {
+		var stk stack.Stack[*AvlTreeElement[T]]
+		pushLeft := func(cur *AvlTreeElement[T]) {
+			for cur != nil {
+				if lo != nil && (*lo).Compare(*cur.data) > 0 {
+					cur = cur.right // cur and everything to its left is below lo
+				} else {
+					stk.Push(cur)
+					cur = cur.left
+				}
+			}
+		}
+		pushLeft(root)
+		for !stk.IsEmpty() {
+			cur, _ := stk.Pop()
+			if hi != nil && (*hi).Compare(*cur.data) < 0 {
+				return
+			}
+			if !yield(cur.data) {
+				return
+			}
+			pushLeft(cur.right)
+		}
+	}
+}
+
+// All returns an iterator over all the items in the tree in order.
+// Complexity is O(n).
+func (tt *AvlTree[T]) All() iter.Seq[*T] {
+	return tt.Range(nil, nil)
+}
@@ -0,0 +1,223 @@
+package pavl
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/g_lib"
+)
+
+// TestTreeNode is an Inteface Matcing data type for the Nodes that supports the Comparable
+// interface.  This means that it has a Compare fucntion.
+type TestTreeNode struct {
+	S string
+}
+
+// At compile time verify that this is a correct type/interface setup.
+var _ comparable.Comparable = (*TestTreeNode)(nil)
+
+// Compare implements the Compare function to satisfy the interface requirements.
+func (aa TestTreeNode) Compare(x comparable.Comparable) int {
+	if bb, ok := x.(TestTreeNode); ok {
+		if aa.S < bb.S {
+			return -1
+		} else if aa.S > bb.S {
+			return 1
+		}
+	} else if bb, ok := x.(*TestTreeNode); ok {
+		if aa.S < bb.S {
+			return -1
+		} else if aa.S > bb.S {
+			return 1
+		}
+	} else {
+		panic(fmt.Sprintf("Passed invalid type %T to a Compare function.", x))
+	}
+	return 0
+}
+
+func key(k int) *TestTreeNode {
+	return &TestTreeNode{S: fmt.Sprintf("%04d", k)}
+}
+
+// avlValidate recursively checks that every node has the correct height and size, that the
+// balance is -1, 0 or 1 and that the items are in order.
+func avlValidate(t *testing.T, tt *AvlTree[TestTreeNode]) {
+	t.Helper()
+	var check func(cur *AvlTreeElement[TestTreeNode], lo, hi *TestTreeNode) (height, size int)
+	check = func(cur *AvlTreeElement[TestTreeNode], lo, hi *TestTreeNode) (height, size int) {
+		if cur == nil {
+			return 0, 0
+		}
+		if (lo != nil && lo.S >= cur.data.S) || (hi != nil && hi.S <= cur.data.S) {
+			t.Errorf("Item %s is out of order", cur.data.S)
+		}
+		lh, ls := check(cur.left, lo, cur.data)
+		rh, rs := check(cur.right, cur.data, hi)
+		height = g_lib.Max(lh, rh) + 1
+		size = ls + rs + 1
+		if cur.height != height || cur.size != size {
+			t.Errorf("Item %s expected height %d size %d got %d %d", cur.data.S, height, size, cur.height, cur.size)
+		}
+		if b := lh - rh; b < -1 || b > 1 {
+			t.Errorf("Item %s is out of balance, %d", cur.data.S, b)
+		}
+		return
+	}
+	check(tt.root, nil, nil)
+}
+
+// treeKeys returns the items in the tree as a string.
+func treeKeys(tt *AvlTree[TestTreeNode]) string {
+	var got []string
+	for _, item := range tt.ConvertToSlice() {
+		got = append(got, item.S)
+	}
+	return fmt.Sprint(got)
+}
+
+// modelKeys returns the keys in the model as a string in the same form as treeKeys.
+func modelKeys(model map[int]bool) string {
+	var want []string
+	for k := range model {
+		want = append(want, fmt.Sprintf("%04d", k))
+	}
+	sort.Strings(want)
+	return fmt.Sprint(want)
+}
+
+func TestTreeInsertSearch(t *testing.T) {
+	var empty AvlTree[TestTreeNode]
+	if !empty.IsEmpty() || empty.Length() != 0 || empty.Search(key(1)) != nil {
+		t.Errorf("Expected an empty tree")
+	}
+
+	t1, isNew := empty.Insert(key(5))
+	if !isNew {
+		t.Errorf("Expected a new item")
+	}
+	t2, _ := t1.Insert(key(3))
+	t3, isNew := t2.Insert(&TestTreeNode{S: "0005"})
+	if isNew {
+		t.Errorf("Expected a replaced item")
+	}
+
+	if !empty.IsEmpty() {
+		t.Errorf("Expected the empty version to stay empty")
+	}
+	if t1.Length() != 1 || t2.Length() != 2 || t3.Length() != 2 {
+		t.Errorf("Expected lengths 1 2 2 got %d %d %d", t1.Length(), t2.Length(), t3.Length())
+	}
+	if t1.Search(key(3)) != nil || t2.Search(key(3)) == nil {
+		t.Errorf("Expected 3 only in the second version")
+	}
+	if t2.Search(key(5)) == t3.Search(key(5)) {
+		t.Errorf("Expected the third version to have the replaced item")
+	}
+	if s := t3.FindMin().S; s != "0003" {
+		t.Errorf("FindMin expcted 0003 got %s", s)
+	}
+	if s := t3.FindMax().S; s != "0005" {
+		t.Errorf("FindMax expcted 0005 got %s", s)
+	}
+}
+
+func TestTreeDelete(t *testing.T) {
+	var tt AvlTree[TestTreeNode]
+	t1 := &tt
+	for k := 0; k < 10; k++ {
+		t1, _ = t1.Insert(key(k))
+	}
+
+	t2, found := t1.Delete(key(20))
+	if found || t2 != t1 {
+		t.Errorf("Expected the same version back for a missing item")
+	}
+	t3, found := t1.Delete(key(4))
+	if !found || t3.Length() != 9 || t3.Search(key(4)) != nil {
+		t.Errorf("Expected 4 to be deleted")
+	}
+	if t1.Length() != 10 || t1.Search(key(4)) == nil {
+		t.Errorf("Expected the old version to still have 4")
+	}
+	avlValidate(t, t1)
+	avlValidate(t, t3)
+}
+
+func TestTreeVersions(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1001))
+	var versions []*AvlTree[TestTreeNode]
+	var models []string
+
+	cur := NewAvlTree[TestTreeNode]()
+	model := make(map[int]bool)
+	for ii := 0; ii < 1000; ii++ {
+		k := rnd.Intn(150)
+		if rnd.Intn(3) == 0 {
+			var found bool
+			cur, found = cur.Delete(key(k))
+			if found != model[k] {
+				t.Fatalf("Step %d, Delete(%d) expected %v", ii, k, model[k])
+			}
+			delete(model, k)
+		} else {
+			var isNew bool
+			cur, isNew = cur.Insert(key(k))
+			if isNew == model[k] {
+				t.Fatalf("Step %d, Insert(%d) expected isNew %v", ii, k, !model[k])
+			}
+			model[k] = true
+		}
+		if cur.Length() != len(model) {
+			t.Fatalf("Step %d, expected length %d got %d", ii, len(model), cur.Length())
+		}
+		if ii%10 == 0 {
+			versions = append(versions, cur)
+			models = append(models, modelKeys(model))
+		}
+	}
+
+	// Every old version still has exactly what it had when it was made.
+	for ii, vv := range versions {
+		avlValidate(t, vv)
+		if got := treeKeys(vv); got != models[ii] {
+			t.Errorf("Version %d expcted %s got %s", ii, models[ii], got)
+		}
+	}
+
+	keys := cur.ConvertToSlice()
+	for pos, item := range keys {
+		if got := cur.Index(pos); got != item {
+			t.Errorf("Index(%d) expcted %s got %v", pos, item.S, got)
+		}
+		if r := cur.Rank(item); r != pos {
+			t.Errorf("Rank(%s) expcted %d got %d", item.S, pos, r)
+		}
+	}
+
+	n := 0
+	for item := range cur.Range(key(20), key(40)) {
+		if item.S < "0020" || item.S > "0040" {
+			t.Errorf("Range(20,40) returned %s", item.S)
+		}
+		n++
+	}
+	want := 0
+	for k := range model {
+		if k >= 20 && k <= 40 {
+			want++
+		}
+	}
+	if n != want {
+		t.Errorf("Range(20,40) expcted %d items got %d", want, n)
+	}
+}
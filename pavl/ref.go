@@ -0,0 +1,96 @@
+package pavl
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+Ref holds the current version of a tree that is shared between goroutines.  Readers take a
+Snapshot, an O(1) atomic load, and can then walk that version for as long as they like with
+no locks.  Writers build a new version and swap it in with a compare-and-swap, retrying if
+another writer got there first.
+
+* 	Snapshot — the current version of the tree.												O(1)
+* 	Insert — add an item to the current version.												O(log|2(n))
+* 	Delete — remove an item from the current version.											O(log|2(n))
+* 	Update — replace the current version with one built from it.								depends on fx
+
+*/
+
+import (
+	"sync/atomic"
+
+	"github.com/pschlump/pluto/comparable"
+)
+
+// Ref is a shared, updatable reference to a version of a persistent tree.
+// The zero value of Ref holds an empty tree.
+type Ref[T comparable.Comparable] struct {
+	cur atomic.Pointer[AvlTree[T]]
+}
+
+// NewRef returns a Ref that holds `tt`, or an empty tree if `tt` is nil.
+func NewRef[T comparable.Comparable](tt *AvlTree[T]) *Ref[T] {
+	rr := &Ref[T]{}
+	if tt != nil {
+		rr.cur.Store(tt)
+	}
+	return rr
+}
+
+// Snapshot returns the current version of the tree.  It will not change, later updates to
+// the Ref make new versions.
+// Complexity is O(1).
+func (rr *Ref[T]) Snapshot() *AvlTree[T] {
+	if rr == nil {
+		panic("ref sholud not be a nil")
+	}
+	if tt := rr.cur.Load(); tt != nil {
+		return tt
+	}
+	return &AvlTree[T]{}
+}
+
+// Update replaces the current version with fx(current).  If another goroutine updates the
+// Ref first then `fx` is called again with the newer version, so `fx` should have no side
+// effects.
+func (rr *Ref[T]) Update(fx func(tt *AvlTree[T]) *AvlTree[T]) {
+	if rr == nil {
+		panic("ref sholud not be a nil")
+	}
+	for {
+		old := rr.cur.Load()
+		cur := old
+		if cur == nil {
+			cur = &AvlTree[T]{}
+		}
+		if rr.cur.CompareAndSwap(old, fx(cur)) {
+			return
+		}
+	}
+}
+
+// Insert adds `item` to the current version.  True is returned if the item is new, false if
+// it replaced an existing one.
+// Complexity is O(log|2(n)).
+func (rr *Ref[T]) Insert(item *T) (isNew bool) {
+	rr.Update(func(tt *AvlTree[T]) (nt *AvlTree[T]) {
+		nt, isNew = tt.Insert(item)
+		return
+	})
+	return
+}
+
+// Delete removes the item that matches `find` from the current version.  True is returned
+// if an item was removed.
+// Complexity is O(log|2(n)).
+func (rr *Ref[T]) Delete(find *T) (found bool) {
+	rr.Update(func(tt *AvlTree[T]) (nt *AvlTree[T]) {
+		nt, found = tt.Delete(find)
+		return
+	})
+	return
+}
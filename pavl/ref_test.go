@@ -0,0 +1,70 @@
+package pavl
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+// This test is meant to be run with the race detector, `go test -race`.
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRefConcurrent(t *testing.T) {
+	var rr Ref[TestTreeNode]
+	if !rr.Snapshot().IsEmpty() {
+		t.Errorf("Expected the zero Ref to hold an empty tree")
+	}
+
+	const nWriters, nPerWriter = 4, 100
+	var wg sync.WaitGroup
+	for ww := 0; ww < nWriters; ww++ {
+		wg.Add(1)
+		go func(ww int) {
+			defer wg.Done()
+			for ii := 0; ii < nPerWriter; ii++ {
+				if !rr.Insert(key(ww*nPerWriter + ii)) {
+					t.Errorf("Expected %d to be new", ww*nPerWriter+ii)
+				}
+			}
+		}(ww)
+	}
+
+	// Readers walk snapshots while the writers run, every snapshot must be a valid tree that
+	// only grows.
+	for rd := 0; rd < 4; rd++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			last := 0
+			for ii := 0; ii < 200; ii++ {
+				snap := rr.Snapshot()
+				n := 0
+				for range snap.All() {
+					n++
+				}
+				if n != snap.Length() || n < last {
+					t.Errorf("Snapshot walk got %d items, length %d, last %d", n, snap.Length(), last)
+				}
+				last = n
+			}
+		}()
+	}
+	wg.Wait()
+
+	snap := rr.Snapshot()
+	if snap.Length() != nWriters*nPerWriter {
+		t.Errorf("Expected %d items got %d", nWriters*nPerWriter, snap.Length())
+	}
+	avlValidate(t, snap)
+
+	if !rr.Delete(key(0)) || rr.Delete(key(0)) {
+		t.Errorf("Expected the first Delete to find 0 and the second not to")
+	}
+	if snap.Search(key(0)) == nil {
+		t.Errorf("Expected the older snapshot to still have 0")
+	}
+}
@@ -0,0 +1,25 @@
+package priority_queue
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pschlump/pluto/containertest"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// testItem makes the data for the shared conformance tests.
+var testItem = containertest.Item[PqTest]{
+	New: func(n int) *PqTest { return &PqTest{value: fmt.Sprintf("%d", n), priority: n} },
+	Key: func(x *PqTest) int { return x.priority },
+}
+
+func TestConformance(t *testing.T) {
+	containertest.TestPriorityQueue(t, func() iface_list.PriorityQueueDataType[PqTest] { return NewPriorityQueue[PqTest]() }, testItem)
+}
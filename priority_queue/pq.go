@@ -9,6 +9,8 @@ package priority_queue
 4. Pop - (Peek+Delete)
 5. UpdatePriority ( element )
 6. Search
+7. IsEmpty
+8. Length
 */
 
 import (
@@ -18,42 +20,58 @@ import (
 	// "github.com/pschlump/MiscLib"
 	"github.com/pschlump/pluto/comparable"
 	"github.com/pschlump/pluto/heap"
+	"github.com/pschlump/pluto/iface_list"
 )
 
-type priority_queue[T comparable.Comparable] struct {
+// PriorityQueue is a min priority queue built on top of a heap.
+type PriorityQueue[T comparable.Comparable] struct {
 	theHeap *heap.Heap[T]
 }
 
-// Create a new priority_queue and return it.
+// At compile time verify that PriorityQueue implements the priority queue interface.
+var _ iface_list.PriorityQueueDataType[comparable.Int] = (*PriorityQueue[comparable.Int])(nil)
+
+// Create a new PriorityQueue and return it.
 // Complexity is O(1).
-func NewPriorityQueue[T comparable.Comparable]() (rv *priority_queue[T]) {
+func NewPriorityQueue[T comparable.Comparable]() (rv *PriorityQueue[T]) {
 	// We don't have to "heapify" at this point becasue we start all heaps with an empty set of data.
-	rv.theHeap = heap.NewHeap[T]()
-	return
+	return &PriorityQueue[T]{theHeap: heap.NewHeap[T]()}
 }
 
 // Complexity O(1)
-func (pq *priority_queue[T]) Peek() (rv *T) {
+func (pq *PriorityQueue[T]) Peek() (rv *T) {
 	return pq.theHeap.Peek()
 }
 
+// IsEmpty returns true if there is no data in the queue.
+// Complexity O(1)
+func (pq *PriorityQueue[T]) IsEmpty() bool {
+	return pq.theHeap.IsEmpty()
+}
+
+// Length returns the number of items in the queue.
+// Complexity O(1)
+func (pq *PriorityQueue[T]) Length() int {
+	return pq.theHeap.Length()
+}
+
 // Complexity O(n log n)
-func (pq *priority_queue[T]) Insert(n *T) {
+func (pq *PriorityQueue[T]) Insert(n *T) {
 	pq.theHeap.Push(n)
 }
 
-func (pq *priority_queue[T]) Pop() (rv *T) {
+func (pq *PriorityQueue[T]) Pop() (rv *T) {
 	return pq.theHeap.Pop()
 }
 
 // O(n log n)
-func (pq *priority_queue[T]) Search(cmpVal *T) (rv *T, pos int, err error) {
+func (pq *PriorityQueue[T]) Search(cmpVal *T) (rv *T, pos int, err error) {
 	// Binary tree search to find matching node.
 	return pq.theHeap.Search(cmpVal)
 }
 
 // Complexity O(n)
-func (pq *priority_queue[T]) UpdatePriority(pos int, newVal *T) (found bool) {
+func (pq *PriorityQueue[T]) UpdatePriority(pos int, newVal *T) (found bool) {
 	// check pos in range
 	// update node at [pos]
 	// re-heap-ify (down from pos)
@@ -62,7 +80,7 @@ func (pq *priority_queue[T]) UpdatePriority(pos int, newVal *T) (found bool) {
 }
 
 // Complexity O(n log n)
-func (pq *priority_queue[T]) Delete(pos int) (err error) {
+func (pq *PriorityQueue[T]) Delete(pos int) (err error) {
 	// swap in node from leaf (last) to this potion
 	// set last to nil
 	// re-heap-ify (down from pos)
@@ -75,6 +93,6 @@ func (pq *priority_queue[T]) Delete(pos int) (err error) {
 
 // Truncate removes all data from the heap.
 // Complexity is O(1).
-func (pq *priority_queue[T]) Truncate() {
+func (pq *PriorityQueue[T]) Truncate() {
 	pq.theHeap = heap.NewHeap[T]()
 }
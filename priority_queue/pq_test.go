@@ -8,6 +8,7 @@ import (
 	// "github.com/pschlump/MiscLib"
 	"github.com/pschlump/pluto/comparable"
 	"github.com/pschlump/pluto/heap"
+	"github.com/pschlump/pluto/iface_list"
 )
 
 // Create a "heap of int" type called PqTest
@@ -37,3 +38,34 @@ func TestCreateHeap(t *testing.T) {
 	// xyzzy - Implement test - TODO
 	return
 }
+
+func TestPriorityQueue(t *testing.T) {
+	var pq iface_list.PriorityQueueDataType[PqTest] = NewPriorityQueue[PqTest]()
+
+	if !pq.IsEmpty() {
+		t.Errorf("Expected empty queue")
+	}
+	pq.Insert(&PqTest{value: "c", priority: 3})
+	pq.Insert(&PqTest{value: "a", priority: 1})
+	pq.Insert(&PqTest{value: "b", priority: 2})
+	if pq.Length() != 3 {
+		t.Errorf("Invalid length, expected 3, got %d", pq.Length())
+	}
+	if x := pq.Peek(); x == nil || x.value != "a" {
+		t.Errorf("Peek error, expected a got %v", x)
+	}
+
+	got := ""
+	for !pq.IsEmpty() {
+		got += pq.Pop().value
+	}
+	if got != "abc" {
+		t.Errorf("Pop order error, expected %s got %s", "abc", got)
+	}
+
+	pq.Insert(&PqTest{value: "d", priority: 4})
+	pq.Truncate()
+	if !pq.IsEmpty() || pq.Pop() != nil {
+		t.Errorf("Expected empty queue after Truncate")
+	}
+}
@@ -10,6 +10,7 @@ Basic operations on a Queue
 	Enqueue() — Inserts an element to the end of the queue (Same as "Push")
 	Dequeue() — Removes an element from the start of the queue (Same as "Peek" then "Pop")
 	IsEmpty() — Returns true if the queue is empty
+	Truncate() — Removes all the elements from the queue
 	Top() — Returns the first element of the queue (Same as "Peek")
 
 */
@@ -75,3 +76,8 @@ func (ns *Queue[T]) Dequeue() (rv *T, err error) {
 	(*ns).data = (*ns).data[1:]
 	return
 }
+
+// Truncate removes all data from the queue.
+func (ns *Queue[T]) Truncate() {
+	(*ns).data = nil
+}
@@ -16,50 +16,48 @@ func TestStack(t *testing.T) {
 	var Que1 Queue[TestDemo]
 
 	if !Que1.IsEmpty() {
-		t.Errorf ( "Expected empty stack after decleration, failed to get one." )
+		t.Errorf("Expected empty stack after decleration, failed to get one.")
 	}
 
-	Que1.Push ( TestDemo{S:"hi"} )
+	Que1.Push(TestDemo{S: "hi"})
 
 	if Que1.IsEmpty() {
-		t.Errorf ( "Expected non-empty stack after 1st push, failed to get one." )
+		t.Errorf("Expected non-empty stack after 1st push, failed to get one.")
 	}
 
 	err := Que1.Pop()
 	if err != nil {
-		t.Errorf ( "Unexpectd empty stack error after 1 pop" )
+		t.Errorf("Unexpectd empty stack error after 1 pop")
 	}
 	err = Que1.Pop()
 	if err == nil {
-		t.Errorf ( "Unexpectd lack of error after pop on empty stack" )
+		t.Errorf("Unexpectd lack of error after pop on empty stack")
 	}
 
-	Que1.Push ( TestDemo{S:"hi2"} )
-	Que1.Push ( TestDemo{S:"hi3"} )
+	Que1.Push(TestDemo{S: "hi2"})
+	Que1.Push(TestDemo{S: "hi3"})
 
-	got := Que1.Length() 
+	got := Que1.Length()
 	expect := 2
 	if got != expect {
-		t.Errorf ( "Expected length of %d got %d", expect, got )
+		t.Errorf("Expected length of %d got %d", expect, got)
 	}
 
 	ss, err := Que1.Peek()
 	if err != nil {
-		t.Errorf ( "Unexpectd error on non-empty stack" )
+		t.Errorf("Unexpectd error on non-empty stack")
 	}
 	if ss.S != "hi2" {
-		t.Errorf ( "Expected %s got %s", "hi3", ss.S )
+		t.Errorf("Expected %s got %s", "hi3", ss.S)
 	}
 
 	_ = Que1.Pop()
 	ss, err = Que1.Peek()
 	if err != nil {
-		t.Errorf ( "Unexpectd error on non-empty stack" )
+		t.Errorf("Unexpectd error on non-empty stack")
 	}
 	if ss.S != "hi3" {
-		t.Errorf ( "Expected %s got %s", "hi3", ss.S )
+		t.Errorf("Expected %s got %s", "hi3", ss.S)
 	}
 
 }
-
-
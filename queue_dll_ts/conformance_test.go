@@ -0,0 +1,34 @@
+package queue_dll_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/pschlump/pluto/containertest"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// queueAdapter gives a Queue the QueueDataType names for its methods.
+type queueAdapter struct {
+	*Queue[TestDemo]
+}
+
+func (qa queueAdapter) Enque(data *TestDemo)             { qa.Enqueue(data) }
+func (qa queueAdapter) Pop() (data *TestDemo, err error) { return qa.Dequeue() }
+func (qa queueAdapter) ConvertToSlice() []*TestDemo      { return qa.data.ConvertToSlice() }
+
+var testItem = containertest.Item[TestDemo]{
+	New: func(n int) *TestDemo { return &TestDemo{S: fmt.Sprintf("%04d", n)} },
+	Key: func(x *TestDemo) int { n, _ := strconv.Atoi(x.S); return n },
+}
+
+func TestConformance(t *testing.T) {
+	containertest.TestQueue(t, func() iface_list.QueueDataType[TestDemo] { return queueAdapter{&Queue[TestDemo]{}} }, testItem)
+}
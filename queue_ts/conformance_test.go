@@ -0,0 +1,39 @@
+package queue
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"testing"
+
+	"github.com/pschlump/pluto/containertest"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// queueAdapter makes a Queue, which stores values, look like a QueueDataType, which passes
+// pointers.
+type queueAdapter[T any] struct {
+	*Queue[T]
+}
+
+func (qa queueAdapter[T]) Enque(data *T)             { qa.Enqueue(*data) }
+func (qa queueAdapter[T]) Pop() (data *T, err error) { return qa.Dequeue() }
+
+func (qa queueAdapter[T]) ConvertToSlice() (rv []*T) {
+	for i := range qa.data {
+		rv = append(rv, &qa.data[i])
+	}
+	return
+}
+
+var testItem = containertest.Item[int]{
+	New: func(n int) *int { return &n },
+	Key: func(x *int) int { return *x },
+}
+
+func TestConformance(t *testing.T) {
+	containertest.TestQueue(t, func() iface_list.QueueDataType[int] { return queueAdapter[int]{&Queue[int]{}} }, testItem)
+}
@@ -0,0 +1,427 @@
+package radix_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+A Radix Tree, a compressed trie.  A chain of nodes with one child each is kept as a single
+edge with a multi byte label, so the tree has at most 2 nodes per key and a lookup follows
+one pointer per branch in the keys, not one per byte.  The keys can be a string or a []byte.
+This is a thread safe version with a read/write lock, see ../radix for a no-lock version.
+It has the same methods as ../trie_ts.
+
+* 	Insert - set the value for a key, returns true if the key is new.							O(k)
+* 	Get - returns the value for a key and true, or false if the key is not in the tree.		O(k)
+* 	Delete — removes a key, returns true if it was in the tree.								O(k)
+* 	LongestPrefix - the longest key in the tree that is a prefix of a key.						O(k)
+* 	WalkPrefix - call a function for each key that starts with a prefix, in order.			O(k+m)
+* 	WithPrefix - an iter.Seq2 over the keys that start with a prefix, in order.				O(k+m)
+* 	All - an iter.Seq2 over all the keys in order.												O(n)
+* 	IsEmpty — Returns true if the tree is empty													O(1)
+* 	Length — Returns number of keys in the tree.												O(1)
+* 	Truncate - Delete all the keys in the tree. 												O(1)
+* 	Dump - print out the tree.																	O(n)
+
+k is the length of the key and m is the size of the part of the tree that is walked.  The
+order is lexical, byte by byte, so "ab" is before "abc" which is before "b".
+
+*/
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Key is the type of the keys, a string or a []byte.
+type Key interface {
+	~string | ~[]byte
+}
+
+// RadixNode is a node in the tree.  It has a value if a key ends at this node.  The root
+// has an empty label, every other node has a label that is not empty and no two children
+// of a node have labels that start with the same byte.
+type RadixNode[K Key, V any] struct {
+	label    string             // the part of the key on the edge in to this node
+	children []*RadixNode[K, V] // sorted by the first byte of the label
+	key      K                  // the full key, if hasValue
+	value    V
+	hasValue bool
+}
+
+// RadixTree is a generic radix tree from keys to values of type V.
+type RadixTree[K Key, V any] struct {
+	root   RadixNode[K, V]
+	length int
+	lock   sync.RWMutex
+}
+
+// NewRadixTree creates a new RadixTree and return it.
+// Complexity is O(1).
+func NewRadixTree[K Key, V any]() *RadixTree[K, V] {
+	return &RadixTree[K, V]{}
+}
+
+// cloneKey returns a copy of `key`, so that a []byte key can not be changed by the caller
+// after it is in the tree.
+func cloneKey[K Key](key K) K {
+	return K(string(key))
+}
+
+// commonPrefix returns the number of bytes at the start of `label` and `key` that are the same.
+func commonPrefix[K Key](label string, key K) (n int) {
+	for n < len(label) && n < len(key) && label[n] == key[n] {
+		n++
+	}
+	return
+}
+
+// find returns the position of the child with a label that starts with `first`, and true if
+// it is there.  If not the position is where it would be inserted.
+func (nd *RadixNode[K, V]) find(first byte) (pos int, found bool) {
+	lo, hi := 0, len(nd.children)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if nd.children[mid].label[0] < first {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, lo < len(nd.children) && nd.children[lo].label[0] == first
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// IsEmpty will return true if the tree is empty
+// Complexity is O(1).
+func (tt *RadixTree[K, V]) IsEmpty() bool {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return tt.length == 0
+}
+
+// Length returns the number of keys in the tree.
+// Complexity is O(1).
+func (tt *RadixTree[K, V]) Length() int {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return tt.length
+}
+
+// Truncate removes all the keys from the tree.
+// Complexity is O(1).
+func (tt *RadixTree[K, V]) Truncate() {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.Lock()
+	defer tt.lock.Unlock()
+
+	tt.root = RadixNode[K, V]{}
+	tt.length = 0
+}
+
+// Insert sets the value for `key`, replacing any current value.  It returns true if the key
+// was not already in the tree.
+// Complexity is O(k).
+func (tt *RadixTree[K, V]) Insert(key K, value V) (isNew bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.Lock()
+	defer tt.lock.Unlock()
+
+	return tt.NlInsert(key, value)
+}
+
+// NlInsert is Insert without the lock, for use between WriteLock and WriteUnlock.
+func (tt *RadixTree[K, V]) NlInsert(key K, value V) (isNew bool) {
+	nd, rest := &tt.root, key
+	for len(rest) > 0 {
+		pos, found := nd.find(rest[0])
+		if !found {
+			// No child shares a byte with the rest of the key, add a leaf for all of it.
+			nd.children = slices.Insert(nd.children, pos, &RadixNode[K, V]{label: string(rest), key: cloneKey(key), value: value, hasValue: true})
+			tt.length++
+			return true
+		}
+		child := nd.children[pos]
+		n := commonPrefix(child.label, rest)
+		if n < len(child.label) {
+			// The key leaves the label part way, split the edge with a node at that point.
+			mid := &RadixNode[K, V]{label: child.label[:n], children: []*RadixNode[K, V]{child}}
+			child.label = child.label[n:]
+			nd.children[pos] = mid
+			child = mid
+		}
+		nd, rest = child, rest[n:]
+	}
+	if !nd.hasValue {
+		nd.key, nd.hasValue = cloneKey(key), true
+		tt.length++
+		isNew = true
+	}
+	nd.value = value
+	return
+}
+
+// Get returns the value for `key`.  If the key is not in the tree then found is false.
+// Complexity is O(k).
+func (tt *RadixTree[K, V]) Get(key K) (value V, found bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return tt.NlGet(key)
+}
+
+// NlGet is Get without the lock, for use between ReadLock and ReadUnlock.
+func (tt *RadixTree[K, V]) NlGet(key K) (value V, found bool) {
+	nd, rest := &tt.root, key
+	for len(rest) > 0 {
+		pos, found := nd.find(rest[0])
+		if !found {
+			return value, false
+		}
+		nd = nd.children[pos]
+		if n := commonPrefix(nd.label, rest); n < len(nd.label) {
+			return value, false
+		}
+		rest = rest[len(nd.label):]
+	}
+	return nd.value, nd.hasValue
+}
+
+// Delete removes `key` from the tree.  It returns true if the key was in the tree.  A node
+// that is left with no value and only one child is merged with the child.
+// Complexity is O(k).
+func (tt *RadixTree[K, V]) Delete(key K) (found bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.Lock()
+	defer tt.lock.Unlock()
+
+	return tt.NlDelete(key)
+}
+
+// NlDelete is Delete without the lock, for use between WriteLock and WriteUnlock.
+func (tt *RadixTree[K, V]) NlDelete(key K) (found bool) {
+	var parent *RadixNode[K, V]
+	parentPos := 0
+	nd, rest := &tt.root, key
+	for len(rest) > 0 {
+		pos, found := nd.find(rest[0])
+		if !found {
+			return false
+		}
+		child := nd.children[pos]
+		if n := commonPrefix(child.label, rest); n < len(child.label) {
+			return false
+		}
+		parent, parentPos, nd, rest = nd, pos, child, rest[len(child.label):]
+	}
+	if !nd.hasValue {
+		return false
+	}
+	var zeroKey K
+	var zeroValue V
+	nd.key, nd.value, nd.hasValue = zeroKey, zeroValue, false
+	tt.length--
+
+	if parent == nil {
+		return true // the root, it stays even with no value
+	}
+	switch len(nd.children) {
+	case 0:
+		parent.children = slices.Delete(parent.children, parentPos, parentPos+1)
+		if parent != &tt.root && !parent.hasValue && len(parent.children) == 1 {
+			parent.mergeChild()
+		}
+	case 1:
+		nd.mergeChild()
+	}
+	return true
+}
+
+// mergeChild moves the only child of `nd` in to `nd`, joining the labels.
+func (nd *RadixNode[K, V]) mergeChild() {
+	child := nd.children[0]
+	nd.label += child.label
+	nd.children = child.children
+	nd.key, nd.value, nd.hasValue = child.key, child.value, child.hasValue
+}
+
+// LongestPrefix returns the longest key in the tree that is a prefix of `key`, or is equal to
+// it, and its value.  If there is no such key then found is false.
+//
+//	tree.Insert("/api/", apiHandler)
+//	prefix, handler, found := tree.LongestPrefix("/api/users/12")	// "/api/", apiHandler, true
+//
+// Complexity is O(k).
+func (tt *RadixTree[K, V]) LongestPrefix(key K) (prefix K, value V, found bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	nd, rest := &tt.root, key
+	for {
+		if nd.hasValue {
+			prefix, value, found = nd.key, nd.value, true
+		}
+		if len(rest) == 0 {
+			return
+		}
+		pos, ok := nd.find(rest[0])
+		if !ok {
+			return
+		}
+		nd = nd.children[pos]
+		if n := commonPrefix(nd.label, rest); n < len(nd.label) {
+			return
+		}
+		rest = rest[len(nd.label):]
+	}
+}
+
+// WalkPrefix calls `fx` in order for each key that starts with `prefix`, and its value.  An
+// empty prefix walks all the keys.  The walk stops if `fx` returns false.  The read lock is
+// held for the walk, so `fx` must not change the tree.
+// Complexity is O(k+m), m the size of the part of the tree under the prefix.
+func (tt *RadixTree[K, V]) WalkPrefix(prefix K, fx func(key K, value V) bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	tt.NlWalkPrefix(prefix, fx)
+}
+
+// NlWalkPrefix is WalkPrefix without the lock, for use between ReadLock and ReadUnlock.
+func (tt *RadixTree[K, V]) NlWalkPrefix(prefix K, fx func(key K, value V) bool) {
+	nd, rest := &tt.root, prefix
+	for len(rest) > 0 {
+		pos, found := nd.find(rest[0])
+		if !found {
+			return
+		}
+		nd = nd.children[pos]
+		n := commonPrefix(nd.label, rest)
+		if n == len(rest) {
+			break // the prefix ends on this edge, every key under it matches
+		}
+		if n < len(nd.label) {
+			return
+		}
+		rest = rest[n:]
+	}
+	nlWalk(nd, fx)
+}
+
+// nlWalk calls `fx` for the keys at and under `nd` in order.  It returns false if `fx` did.
+func nlWalk[K Key, V any](nd *RadixNode[K, V], fx func(key K, value V) bool) bool {
+	if nd.hasValue && !fx(nd.key, nd.value) {
+		return false
+	}
+	for _, child := range nd.children {
+		if !nlWalk(child, fx) {
+			return false
+		}
+	}
+	return true
+}
+
+// WithPrefix returns an iterator over the keys that start with `prefix` and their values,
+// in order.  The read lock is held while the loop runs, so the body of the loop
+// must not change the tree.
+//
+//	for key, value := range tree.WithPrefix("ab") {
+//		...
+//	}
+func (tt *RadixTree[K, V]) WithPrefix(prefix K) iter.Seq2[K, V] {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+	return func(yield func(K, V) bool) {
+		tt.WalkPrefix(prefix, yield)
+	}
+}
+
+// All returns an iterator over all the keys and their values in order.
+func (tt *RadixTree[K, V]) All() iter.Seq2[K, V] {
+	var empty K
+	return tt.WithPrefix(empty)
+}
+
+// tree.WriteLock()
+// tree.WriteUnlock()
+// tree.ReadLock()
+// tree.ReadUnlock()
+func (tt *RadixTree[K, V]) WriteLock() {
+	tt.lock.Lock()
+}
+func (tt *RadixTree[K, V]) WriteUnlock() {
+	tt.lock.Unlock()
+}
+func (tt *RadixTree[K, V]) ReadLock() {
+	tt.lock.RLock()
+}
+func (tt *RadixTree[K, V]) ReadUnlock() {
+	tt.lock.RUnlock()
+}
+
+// Dump will print out the tree to the file `fo`, one node per line indented by its depth.
+// Complexity is O(n).
+func (tt *RadixTree[K, V]) Dump(fo io.Writer) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	var dump func(nd *RadixNode[K, V], depth int)
+	dump = func(nd *RadixNode[K, V], depth int) {
+		for _, child := range nd.children {
+			fmt.Fprintf(fo, "%s%q", strings.Repeat(" ", 4*depth), child.label)
+			if child.hasValue {
+				fmt.Fprintf(fo, " = %v", child.value)
+			}
+			fmt.Fprintf(fo, "\n")
+			dump(child, depth+1)
+		}
+	}
+	if tt.root.hasValue {
+		fmt.Fprintf(fo, "\"\" = %v\n", tt.root.value)
+	}
+	dump(&tt.root, 0)
+}
@@ -0,0 +1,288 @@
+package radix_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestRadixTreeInsertGet(t *testing.T) {
+	var Tr1 RadixTree[string, int]
+
+	if !Tr1.IsEmpty() || Tr1.Length() != 0 {
+		t.Errorf("Expected empty")
+	}
+	if _, found := Tr1.Get("abc"); found {
+		t.Errorf("Expected abc not found")
+	}
+	for ii, key := range []string{"abc", "ab", "abd", "b", "", "abcde"} {
+		if !Tr1.Insert(key, ii) {
+			t.Errorf("Expected %q to be new", key)
+		}
+	}
+	if Tr1.Insert("ab", 10) {
+		t.Errorf("Expected ab to be replaced")
+	}
+	if Tr1.Length() != 6 {
+		t.Errorf("Expected length 6 got %d", Tr1.Length())
+	}
+	for key, want := range map[string]int{"abc": 0, "ab": 10, "abd": 2, "b": 3, "": 4, "abcde": 5} {
+		if got, found := Tr1.Get(key); !found || got != want {
+			t.Errorf("Get(%q) expcted %d got %d %v", key, want, got, found)
+		}
+	}
+	for _, key := range []string{"a", "abcd", "abce", "bc", "c"} {
+		if _, found := Tr1.Get(key); found {
+			t.Errorf("Get(%q) expected not found", key)
+		}
+	}
+
+	if Tr1.Delete("a") || Tr1.Delete("abcd") || Tr1.Delete("zz") {
+		t.Errorf("Expected Delete of a missing key to return false")
+	}
+	if !Tr1.Delete("abc") || Tr1.Delete("abc") {
+		t.Errorf("Expected the first Delete of abc to find it and the second not to")
+	}
+	if got, found := Tr1.Get("abcde"); !found || got != 5 {
+		t.Errorf("Expected abcde to still be there after deleting abc")
+	}
+	if !Tr1.Delete("") {
+		t.Errorf("Expected to delete the empty key")
+	}
+	if Tr1.Length() != 4 {
+		t.Errorf("Expected length 4 got %d", Tr1.Length())
+	}
+
+	var buf bytes.Buffer
+	Tr1.Dump(&buf)
+	if !strings.Contains(buf.String(), "= 10") {
+		t.Errorf("Expected Dump to show the value 10 got %s", buf.String())
+	}
+
+	Tr1.Truncate()
+	if !Tr1.IsEmpty() || Tr1.Length() != 0 {
+		t.Errorf("Expected empty after Truncate")
+	}
+	if _, found := Tr1.Get("ab"); found {
+		t.Errorf("Expected ab not found after Truncate")
+	}
+}
+
+func TestRadixTreeLongestPrefix(t *testing.T) {
+	Tr1 := NewRadixTree[string, string]()
+	for _, route := range []string{"/", "/api/", "/api/users/", "/api/users/admin", "/static/"} {
+		Tr1.Insert(route, "h:"+route)
+	}
+
+	tests := []struct {
+		path, prefix string
+		found        bool
+	}{
+		{path: "/api/users/12", prefix: "/api/users/", found: true},
+		{path: "/api/users/admin", prefix: "/api/users/admin", found: true},
+		{path: "/api/users/adm", prefix: "/api/users/", found: true},
+		{path: "/api/orders", prefix: "/api/", found: true},
+		{path: "/api", prefix: "/", found: true},
+		{path: "/index.html", prefix: "/", found: true},
+		{path: "", prefix: "", found: false},
+		{path: "api", prefix: "", found: false},
+	}
+	for _, tc := range tests {
+		prefix, handler, found := Tr1.LongestPrefix(tc.path)
+		if found != tc.found || prefix != tc.prefix || (found && handler != "h:"+tc.prefix) {
+			t.Errorf("LongestPrefix(%q) expcted %q %v got %q %q %v", tc.path, tc.prefix, tc.found, prefix, handler, found)
+		}
+	}
+}
+
+func TestRadixTreeWalkPrefix(t *testing.T) {
+	Tr1 := NewRadixTree[string, int]()
+	for ii, key := range []string{"car", "cart", "carbon", "cat", "dog", "ca", "c", "do"} {
+		Tr1.Insert(key, ii)
+	}
+
+	walk := func(prefix string) (got []string) {
+		Tr1.WalkPrefix(prefix, func(key string, value int) bool {
+			got = append(got, key)
+			return true
+		})
+		return
+	}
+	tests := map[string]string{
+		"":     "[c ca car carbon cart cat do dog]",
+		"ca":   "[ca car carbon cart cat]",
+		"car":  "[car carbon cart]",
+		"carb": "[carbon]",
+		"cab":  "[]",
+		"d":    "[do dog]",
+		"dogs": "[]",
+		"x":    "[]",
+	}
+	for prefix, want := range tests {
+		if got := fmt.Sprint(walk(prefix)); got != want {
+			t.Errorf("WalkPrefix(%q) expcted %s got %s", prefix, want, got)
+		}
+	}
+
+	var got []string
+	for key, value := range Tr1.WithPrefix("car") {
+		got = append(got, fmt.Sprintf("%s=%d", key, value))
+		if len(got) == 2 {
+			break
+		}
+	}
+	if fmt.Sprint(got) != "[car=0 carbon=2]" {
+		t.Errorf("WithPrefix(car) with a break got %v", got)
+	}
+
+	n := 0
+	for range Tr1.All() {
+		n++
+	}
+	if n != Tr1.Length() {
+		t.Errorf("All expcted %d keys got %d", Tr1.Length(), n)
+	}
+}
+
+func TestRadixTreeByteKeys(t *testing.T) {
+	Tr1 := NewRadixTree[[]byte, int]()
+	key := []byte("abc")
+	Tr1.Insert(key, 1)
+	Tr1.Insert([]byte("abd"), 2)
+	key[2] = 'x' // must not change the key in the tree
+
+	if _, found := Tr1.Get([]byte("abx")); found {
+		t.Errorf("Expected the tree to have its own copy of the key")
+	}
+	if got, found := Tr1.Get([]byte("abc")); !found || got != 1 {
+		t.Errorf("Get(abc) expcted 1 got %d %v", got, found)
+	}
+	var got []string
+	for key := range Tr1.All() {
+		got = append(got, string(key))
+	}
+	if fmt.Sprint(got) != "[abc abd]" {
+		t.Errorf("All expcted [abc abd] got %v", got)
+	}
+	if prefix, _, found := Tr1.LongestPrefix([]byte("abcdef")); !found || string(prefix) != "abc" {
+		t.Errorf("LongestPrefix(abcdef) expcted abc got %s %v", prefix, found)
+	}
+}
+
+func TestRadixTreeModel(t *testing.T) {
+	Tr1 := NewRadixTree[string, int]()
+	model := make(map[string]int)
+	rnd := rand.New(rand.NewSource(1001))
+	randKey := func() string {
+		b := make([]byte, rnd.Intn(6))
+		for ii := range b {
+			b[ii] = "abc"[rnd.Intn(3)]
+		}
+		return string(b)
+	}
+
+	for ii := 0; ii < 5000; ii++ {
+		key := randKey()
+		_, inModel := model[key]
+		if rnd.Intn(5) < 2 {
+			if found := Tr1.Delete(key); found != inModel {
+				t.Fatalf("Step %d, Delete(%q) expected %v", ii, key, inModel)
+			}
+			delete(model, key)
+		} else {
+			if isNew := Tr1.Insert(key, ii); isNew == inModel {
+				t.Fatalf("Step %d, Insert(%q) expected isNew %v", ii, key, !inModel)
+			}
+			model[key] = ii
+		}
+		if Tr1.Length() != len(model) {
+			t.Fatalf("Step %d, expected length %d got %d", ii, len(model), Tr1.Length())
+		}
+		if ii%100 == 0 {
+			validate(t, Tr1)
+			checkModel(t, Tr1, model, randKey())
+		}
+	}
+	validate(t, Tr1)
+	checkModel(t, Tr1, model, "")
+
+	for key := range model {
+		if !Tr1.Delete(key) {
+			t.Errorf("Expected to delete %q", key)
+		}
+	}
+	validate(t, Tr1)
+	if !Tr1.IsEmpty() {
+		t.Errorf("Expected empty")
+	}
+}
+
+// checkModel compares the tree to the model with Get, WalkPrefix and LongestPrefix.
+func checkModel(t *testing.T, tt *RadixTree[string, int], model map[string]int, prefix string) {
+	t.Helper()
+	var want []string
+	for key, value := range model {
+		if got, found := tt.Get(key); !found || got != value {
+			t.Errorf("Get(%q) expcted %d got %d %v", key, value, got, found)
+		}
+		if strings.HasPrefix(key, prefix) {
+			want = append(want, key)
+		}
+	}
+	sort.Strings(want)
+	var got []string
+	tt.WalkPrefix(prefix, func(key string, value int) bool {
+		got = append(got, key)
+		return true
+	})
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("WalkPrefix(%q) expcted %v got %v", prefix, want, got)
+	}
+
+	longest, found := "", false
+	for key := range model {
+		if strings.HasPrefix(prefix, key) && (!found || len(key) > len(longest)) {
+			longest, found = key, true
+		}
+	}
+	if key, value, ok := tt.LongestPrefix(prefix); ok != found || key != longest || (ok && value != model[key]) {
+		t.Errorf("LongestPrefix(%q) expcted %q %v got %q %v", prefix, longest, found, key, ok)
+	}
+}
+
+// validate checks that the children of each node are in order by their first byte, that no
+// label is empty and that no node other than the root is without a value and has less than
+// 2 children, so that Delete merged or removed it.
+func validate(t *testing.T, tt *RadixTree[string, int]) {
+	t.Helper()
+	var check func(nd *RadixNode[string, int], path string)
+	check = func(nd *RadixNode[string, int], path string) {
+		if nd.hasValue && nd.key != path {
+			t.Errorf("Node at %q has key %q", path, nd.key)
+		}
+		if nd != &tt.root {
+			if nd.label == "" {
+				t.Errorf("Node at %q has an empty label", path)
+			}
+			if !nd.hasValue && len(nd.children) < 2 {
+				t.Errorf("Node at %q has no value and %d children", path, len(nd.children))
+			}
+		}
+		for ii, child := range nd.children {
+			if ii > 0 && nd.children[ii-1].label[0] >= child.label[0] {
+				t.Errorf("Children at %q are out of order", path)
+			}
+			check(child, path+child.label)
+		}
+	}
+	check(&tt.root, "")
+}
@@ -0,0 +1,31 @@
+package rb_tree_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/pschlump/pluto/containertest"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// testItem makes the data for the shared conformance tests.
+var testItem = containertest.Item[TestTreeNode]{
+	New: func(n int) *TestTreeNode { return &TestTreeNode{S: fmt.Sprintf("%04d", n)} },
+	Key: func(x *TestTreeNode) int { n, _ := strconv.Atoi(x.S); return n },
+}
+
+func TestConformance(t *testing.T) {
+	containertest.TestTree(t, func() iface_list.TreeDataType[TestTreeNode] { return NewRbTree[TestTreeNode]() }, testItem)
+}
+
+func TestInvariant(t *testing.T) {
+	containertest.TestTreeInvariant(t, func() iface_list.TreeDataType[TestTreeNode] { return NewRbTree[TestTreeNode]() }, testItem,
+		func(t *testing.T, tr iface_list.TreeDataType[TestTreeNode]) { validate(t, tr.(*RbTree[TestTreeNode])) })
+}
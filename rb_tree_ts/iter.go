@@ -0,0 +1,40 @@
+package rb_tree_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"github.com/pschlump/pluto/bst"
+	"github.com/pschlump/pluto/comparable"
+)
+
+// An iteration type that allows a for loop to walk the tree inorder, or
+// in reverse order from Rear.
+//
+//	for it := tree.Front(); !it.Done(); it.Next() {
+//		item := it.Value()
+//		...
+//	}
+//
+// This is more moemory effecient than the Walk* functions becasue it
+// manages the stack interally.  The tree must not be modified while
+// an iterator is in use.  Value, Next and Done are from bst.Iter.
+type RbTreeIter[T comparable.Comparable] struct {
+	bst.Iter[T, *RbTreeElement[T]]
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// Front will start at the inorder traversal beginning of the tree for iteration over tree.
+func (tt *RbTree[T]) Front() (rv *RbTreeIter[T]) {
+	return &RbTreeIter[T]{bst.NewIter[T](tt.root, false)}
+}
+
+// Rear will start at the last inorder node of the tree for iteration over the tree in
+// reverse order.
+func (tt *RbTree[T]) Rear() (rv *RbTreeIter[T]) {
+	return &RbTreeIter[T]{bst.NewIter[T](tt.root, true)}
+}
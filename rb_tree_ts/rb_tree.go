@@ -0,0 +1,273 @@
+package rb_tree_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+A Red-Black balanced binary tree.  This version is thread safe, every method takes the tree's
+lock.  It has the same methods as the AVL tree in ../avl_tree_ts so they can be swapped.  A
+red-black tree is less strictly balanced than an AVL tree, the longest path is at most twice
+the shortest, but an insert does at most 2 rotations and a delete at most 3, so it does less
+work on write heavy loads.
+
+* 	ConvertToSlice - return the data in order as a slice.										O(n)
+* 	Insert - create a new element in tree.														O(log|2(n))
+*		Duplicates replace the current item - Insert returns false for a duplicate.
+* 	Delete — Deletes a specified element from the tree.										O(log|2(n))
+* 	Index - return the Nth item in order.														O(log|2(n))
+* 	Rank - the number of items less than an item, its position for Index.					O(log|2(n))
+* 	IsEmpty — Returns true if the tree is empty													O(1)
+* 	Length — Returns number of elements in the tree.											O(1)
+* 	Search — Returns the matching element from the tree.										O(log|2(n))
+* 	Truncate - Delete all the nodes in tree. 													O(1)
+*	FindMin - the smallest item.																O(log|2(n))
+*	FindMax - the largest item.																	O(log|2(n))
+*	Depth -> int to get deepest part of tree													O(n)
+* 	DeleteAtHead — Deletes the smallest element of the tree.  									O(log|2(n))
+* 	DeleteAtTail — Deletes the largest element of the tree. 									O(log|2(n))
+*	WalkInOrder, WalkPreOrder, WalkPostOrder - Apply a function to all the nodes.				O(n)
+*	Front, Rear - iterators in order and in reverse order.
+
+*/
+
+import (
+	"sync"
+
+	"github.com/pschlump/pluto/bst"
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// RbTreeElement is a node in the tree.
+type RbTreeElement[T comparable.Comparable] struct {
+	data                *T
+	isRed               bool
+	size                int // number of nodes in this sub-tree
+	left, right, parent *RbTreeElement[T]
+}
+
+// RbTree is a generic red-black balanced binary tree.
+type RbTree[T comparable.Comparable] struct {
+	root   *RbTreeElement[T]
+	length int
+	lock   sync.RWMutex
+}
+
+// At compile time verify that RbTree can be used behind the iface_list interfaces.
+var _ iface_list.TreeDataType[comparable.Int] = (*RbTree[comparable.Int])(nil)
+
+// NewRbTree creates a new RbTree and return it.
+// Complexity is O(1).
+func NewRbTree[T comparable.Comparable]() *RbTree[T] {
+	return &RbTree[T]{
+		root:   nil,
+		length: 0,
+	}
+}
+
+// isRed returns the color of `e`, a missing node is black.
+func (tt *RbTree[T]) isRed(e *RbTreeElement[T]) bool {
+	return e != nil && e.isRed
+}
+
+// replaceChild puts `newChild` where `old` was under `parent`, or at the root.
+func (tt *RbTree[T]) replaceChild(parent, old, newChild *RbTreeElement[T]) {
+	if parent == nil {
+		tt.root = newChild
+	} else if parent.left == old {
+		parent.left = newChild
+	} else {
+		parent.right = newChild
+	}
+	if newChild != nil {
+		newChild.parent = parent
+	}
+}
+
+// rotateLeft moves x.right up into the place of x.
+//
+//	  x                y
+//	 / \              / \
+//	a   y    -->     x   c
+//	   / \          / \
+//	  b   c        a   b
+func (tt *RbTree[T]) rotateLeft(x *RbTreeElement[T]) {
+	y := x.right
+	x.right = y.left
+	if y.left != nil {
+		y.left.parent = x
+	}
+	tt.replaceChild(x.parent, x, y)
+	y.left = x
+	x.parent = y
+	y.size = x.size
+	tt.setSize(x)
+}
+
+// rotateRight is the mirror of rotateLeft, x.left moves up into the place of x.
+func (tt *RbTree[T]) rotateRight(x *RbTreeElement[T]) {
+	y := x.left
+	x.left = y.right
+	if y.right != nil {
+		y.right.parent = x
+	}
+	tt.replaceChild(x.parent, x, y)
+	y.right = x
+	x.parent = y
+	y.size = x.size
+	tt.setSize(x)
+}
+
+// nlInsert is the no-lock insert.
+func (tt *RbTree[T]) nlInsert(item *T) (isNew bool) {
+	var parent *RbTreeElement[T]
+	cur := &tt.root
+	for *cur != nil {
+		c := (*item).Compare(*(*cur).data)
+		if c == 0 {
+			(*cur).data = item // Replace duplicate.
+			return false
+		}
+		parent = *cur
+		if c < 0 {
+			cur = &parent.left
+		} else {
+			cur = &parent.right
+		}
+	}
+
+	node := &RbTreeElement[T]{data: item, isRed: true, size: 1, parent: parent}
+	*cur = node
+	tt.length++
+	for p := parent; p != nil; p = p.parent {
+		p.size++
+	}
+
+	// Fix up the colors.  The only problem can be a red node with a red parent.
+	z := node
+	for tt.isRed(z.parent) {
+		zp := z.parent
+		g := zp.parent // zp is red so it is not the root and g is not nil.
+		if zp == g.left {
+			if u := g.right; tt.isRed(u) {
+				// Red uncle, re-color and move the problem up 2 levels.
+				zp.isRed, u.isRed, g.isRed = false, false, true
+				z = g
+				continue
+			}
+			if z == zp.right {
+				// Left Right - rotate to make it Left Left.
+				z = zp
+				tt.rotateLeft(z)
+				zp = z.parent
+			}
+			// Left Left
+			zp.isRed, g.isRed = false, true
+			tt.rotateRight(g)
+		} else {
+			if u := g.left; tt.isRed(u) {
+				zp.isRed, u.isRed, g.isRed = false, false, true
+				z = g
+				continue
+			}
+			if z == zp.left {
+				z = zp
+				tt.rotateRight(z)
+				zp = z.parent
+			}
+			zp.isRed, g.isRed = false, true
+			tt.rotateLeft(g)
+		}
+	}
+	tt.root.isRed = false
+	return true
+}
+
+// nlDelete is the no-lock delete.
+func (tt *RbTree[T]) nlDelete(find *T) (found bool) {
+	z := bst.Find(tt.root, find)
+	if z == nil {
+		return false
+	}
+	tt.length--
+
+	// With two children move the next item up into z and remove the node it was in.
+	if z.left != nil && z.right != nil {
+		s := z.right
+		for s.left != nil {
+			s = s.left
+		}
+		z.data = s.data
+		z = s
+	}
+
+	// z now has at most one child, replace z with it.
+	child := z.left
+	if child == nil {
+		child = z.right
+	}
+	parent := z.parent
+	for p := parent; p != nil; p = p.parent {
+		p.size--
+	}
+	tt.replaceChild(parent, z, child)
+	if z.isRed {
+		return true // removing a red node does not change the black height.
+	}
+
+	// Fix up, x carries an extra black.  x may be nil so keep its parent as well.
+	x := child
+	for x != tt.root && !tt.isRed(x) {
+		if x == parent.left {
+			w := parent.right
+			if tt.isRed(w) {
+				w.isRed, parent.isRed = false, true
+				tt.rotateLeft(parent)
+				w = parent.right
+			}
+			if !tt.isRed(w.left) && !tt.isRed(w.right) {
+				w.isRed = true
+				x = parent
+				parent = x.parent
+			} else {
+				if !tt.isRed(w.right) {
+					w.left.isRed, w.isRed = false, true
+					tt.rotateRight(w)
+					w = parent.right
+				}
+				w.isRed, parent.isRed, w.right.isRed = parent.isRed, false, false
+				tt.rotateLeft(parent)
+				x = tt.root
+			}
+		} else {
+			w := parent.left
+			if tt.isRed(w) {
+				w.isRed, parent.isRed = false, true
+				tt.rotateRight(parent)
+				w = parent.left
+			}
+			if !tt.isRed(w.left) && !tt.isRed(w.right) {
+				w.isRed = true
+				x = parent
+				parent = x.parent
+			} else {
+				if !tt.isRed(w.left) {
+					w.right.isRed, w.isRed = false, true
+					tt.rotateLeft(w)
+					w = parent.left
+				}
+				w.isRed, parent.isRed, w.left.isRed = parent.isRed, false, false
+				tt.rotateRight(parent)
+				x = tt.root
+			}
+		}
+	}
+	if x != nil {
+		x.isRed = false
+	}
+	return true
+}
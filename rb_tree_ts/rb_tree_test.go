@@ -0,0 +1,83 @@
+package rb_tree_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pschlump/pluto/comparable"
+)
+
+// TestTreeNode is an Inteface Matcing data type for the Nodes that supports the Comparable
+// interface.  This means that it has a Compare fucntion.
+type TestTreeNode struct {
+	S string
+}
+
+// At compile time verify that this is a correct type/interface setup.
+var _ comparable.Comparable = (*TestTreeNode)(nil)
+
+// Compare implements the Compare function to satisfy the interface requirements.
+func (aa TestTreeNode) Compare(x comparable.Comparable) int {
+	if bb, ok := x.(TestTreeNode); ok {
+		if aa.S < bb.S {
+			return -1
+		} else if aa.S > bb.S {
+			return 1
+		}
+	} else if bb, ok := x.(*TestTreeNode); ok {
+		if aa.S < bb.S {
+			return -1
+		} else if aa.S > bb.S {
+			return 1
+		}
+	} else {
+		panic(fmt.Sprintf("Passed invalid type %T to a Compare function.", x))
+	}
+	return 0
+}
+
+// validate checks the red-black rules, that the sizes and parent pointers are right, and that
+// the items are in order.
+func validate(t *testing.T, tt *RbTree[TestTreeNode]) {
+	t.Helper()
+	if tt.isRed(tt.root) {
+		t.Errorf("The root is red")
+	}
+	var check func(cur, parent *RbTreeElement[TestTreeNode], lo, hi *TestTreeNode) (blackHeight, size int)
+	check = func(cur, parent *RbTreeElement[TestTreeNode], lo, hi *TestTreeNode) (blackHeight, size int) {
+		if cur == nil {
+			return 1, 0
+		}
+		if cur.parent != parent {
+			t.Errorf("Item %s has the wrong parent", cur.data.S)
+		}
+		if (lo != nil && lo.S >= cur.data.S) || (hi != nil && hi.S <= cur.data.S) {
+			t.Errorf("Item %s is out of order", cur.data.S)
+		}
+		if cur.isRed && (tt.isRed(cur.left) || tt.isRed(cur.right)) {
+			t.Errorf("Red item %s has a red child", cur.data.S)
+		}
+		lb, ls := check(cur.left, cur, lo, cur.data)
+		rb, rs := check(cur.right, cur, cur.data, hi)
+		if lb != rb {
+			t.Errorf("Item %s has black heights %d and %d", cur.data.S, lb, rb)
+		}
+		size = ls + rs + 1
+		if cur.size != size {
+			t.Errorf("Item %s expected size %d got %d", cur.data.S, size, cur.size)
+		}
+		if !cur.isRed {
+			lb++
+		}
+		return lb, size
+	}
+	if _, size := check(tt.root, nil, nil, nil); size != tt.length {
+		t.Errorf("Expected length %d got %d", size, tt.length)
+	}
+}
@@ -0,0 +1,302 @@
+package rb_tree_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+// The methods that only read the tree.  The work is done by ../bst, which has the walks, the
+// iterator and the order statistics for any binary search tree that keeps the sub-tree size
+// in each node, these take the lock and pass it the root.
+
+import (
+	"io"
+
+	"github.com/pschlump/pluto/bst"
+	"github.com/pschlump/pluto/comparable"
+)
+
+type ApplyFunction[T comparable.Comparable] func(pos, depth int, data *T, userData interface{}) bool
+
+// Complexity is O(1).
+func (ee *RbTreeElement[T]) GetData() *T {
+	return ee.data
+}
+
+// Left returns the sub-tree of smaller items.
+// Complexity is O(1).
+func (ee *RbTreeElement[T]) Left() *RbTreeElement[T] {
+	return ee.left
+}
+
+// Right returns the sub-tree of larger items.
+// Complexity is O(1).
+func (ee *RbTreeElement[T]) Right() *RbTreeElement[T] {
+	return ee.right
+}
+
+// Size returns the number of nodes in the sub-tree at `ee`, 0 for a nil node.
+// Complexity is O(1).
+func (ee *RbTreeElement[T]) Size() int {
+	if ee == nil {
+		return 0
+	}
+	return ee.size
+}
+
+// setSize re-calculates the size of `e` from its children.
+// Complexity is O(1).
+func (tt *RbTree[T]) setSize(e *RbTreeElement[T]) {
+	e.size = e.left.Size() + e.right.Size() + 1
+}
+
+// IsEmpty will return true if the tree is empty
+// Complexity is O(1).
+func (tt *RbTree[T]) IsEmpty() bool {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return tt.root == nil
+}
+
+// Length returns the number of elements in the tree.
+// Complexity is O(1).
+func (tt *RbTree[T]) Length() int {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return tt.length
+}
+
+// Truncate removes all data from the tree.
+// Complexity is O(1).
+func (tt *RbTree[T]) Truncate() {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.Lock()
+	defer tt.lock.Unlock()
+
+	tt.root = nil
+	tt.length = 0
+}
+
+// Insert will add a new item to the tree.  If it is a duplicate of an exiting
+// item the new item will replace the existing one.  True is returned if the item
+// is new, false if it replaced an existing one.
+// Complexity is O(log|2(n)).
+func (tt *RbTree[T]) Insert(item *T) (isNew bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.Lock()
+	defer tt.lock.Unlock()
+
+	return tt.nlInsert(item)
+}
+
+// Delete removes the item that matches `find` from the tree.  True is returned if an item
+// was removed.
+// Complexity is O(log|2(n)).
+func (tt *RbTree[T]) Delete(find *T) (found bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.Lock()
+	defer tt.lock.Unlock()
+
+	return tt.nlDelete(find)
+}
+
+// DeleteAtHead removes the smallest item in the tree.
+// Complexity is O(log|2(n)).
+func (tt *RbTree[T]) DeleteAtHead() (found bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.Lock()
+	defer tt.lock.Unlock()
+
+	if tt.root == nil {
+		return false
+	}
+	return tt.nlDelete(bst.Min[T](tt.root))
+}
+
+// DeleteAtTail removes the largest item in the tree.
+// Complexity is O(log|2(n)).
+func (tt *RbTree[T]) DeleteAtTail() (found bool) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.Lock()
+	defer tt.lock.Unlock()
+
+	if tt.root == nil {
+		return false
+	}
+	return tt.nlDelete(bst.Max[T](tt.root))
+}
+
+// Search will walk the tree looking for `find` and retrn the found item
+// if it is in the tree. If it is not found then `nil` will be returned.
+// Complexity is O(log|2(n)).
+func (tt *RbTree[T]) Search(find *T) (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	if cur := bst.Find(tt.root, find); cur != nil {
+		return cur.data
+	}
+	return nil
+}
+
+// FindMin returns the smallest item in the tree, nil if the tree is empty.
+// Complexity is O(log|2(n)).
+func (tt *RbTree[T]) FindMin() (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return bst.Min[T](tt.root)
+}
+
+// FindMax returns the largest item in the tree, nil if the tree is empty.
+// Complexity is O(log|2(n)).
+func (tt *RbTree[T]) FindMax() (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return bst.Max[T](tt.root)
+}
+
+// Index returns the N-th item in the tree, 0 based, in order.
+// Complexity is O(log|2(n)).
+func (tt *RbTree[T]) Index(pos int) (item *T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return bst.Index[T](tt.root, pos)
+}
+
+// Rank returns the number of items in the tree that are less than `find`.  If `find` is in
+// the tree this is its position as used by Index.
+// Complexity is O(log|2(n)).
+func (tt *RbTree[T]) Rank(find *T) (n int) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return bst.Rank(tt.root, find)
+}
+
+// Depth returns the height of the tree, the number of nodes on the longest path from the root.
+// Complexity is O(n).
+func (tt *RbTree[T]) Depth() (d int) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return bst.Depth[T](tt.root)
+}
+
+// ConvertToSlice returns the data in the tree in order.
+// Complexity is O(n).
+func (tt *RbTree[T]) ConvertToSlice() (rv []*T) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	return bst.ConvertToSlice[T](tt.root)
+}
+
+// Dump will print out the tree to the file `fo`.
+func (tt *RbTree[T]) Dump(fo io.Writer) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	bst.Dump[T](fo, tt.root)
+}
+
+// WalkInOrder calls `fx` for each item in order.  The walk stops if `fx` returns false.
+// Complexity is O(n).
+func (tt *RbTree[T]) WalkInOrder(fx ApplyFunction[T], userData interface{}) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	bst.WalkInOrder(tt.root, fx, userData)
+}
+
+// WalkPreOrder calls `fx` for each node before its children.  The walk stops if `fx`
+// returns false.
+// Complexity is O(n).
+func (tt *RbTree[T]) WalkPreOrder(fx ApplyFunction[T], userData interface{}) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	bst.WalkPreOrder(tt.root, fx, userData)
+}
+
+// WalkPostOrder calls `fx` for each node after its children.  The walk stops if `fx`
+// returns false.
+// Complexity is O(n).
+func (tt *RbTree[T]) WalkPostOrder(fx ApplyFunction[T], userData interface{}) {
+	if tt == nil {
+		panic("tree sholud not be a nil")
+	}
+
+	tt.lock.RLock()
+	defer tt.lock.RUnlock()
+
+	bst.WalkPostOrder(tt.root, fx, userData)
+}
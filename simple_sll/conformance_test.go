@@ -0,0 +1,40 @@
+package sll
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/pschlump/pluto/containertest"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// sllAdapter adds the StackDataType and QueueDataType methods that Sll does not have.
+type sllAdapter struct {
+	*Sll[TestDemo]
+}
+
+func (sa sllAdapter) Enque(data *TestDemo) { sa.InsertAfterTail(data) }
+
+func (sa sllAdapter) ConvertToSlice() (rv []*TestDemo) {
+	for ii := sa.Front(); !ii.Done(); ii.Next() {
+		rv = append(rv, ii.Value())
+	}
+	return
+}
+
+var testItem = containertest.Item[TestDemo]{
+	New: func(n int) *TestDemo { return &TestDemo{S: fmt.Sprintf("%04d", n)} },
+	Key: func(x *TestDemo) int { n, _ := strconv.Atoi(x.S); return n },
+}
+
+func TestConformance(t *testing.T) {
+	containertest.TestStack(t, func() iface_list.StackDataType[TestDemo] { return sllAdapter{NewSll[TestDemo]()} }, testItem)
+	containertest.TestQueue(t, func() iface_list.QueueDataType[TestDemo] { return sllAdapter{NewSll[TestDemo]()} }, testItem)
+}
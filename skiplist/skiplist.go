@@ -0,0 +1,490 @@
+package skiplist
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+A Skip List, an ordered linked list with extra "express" links.  Every item is on level 0,
+about half of them are also on level 1, a quarter on level 2 and so on, so a search can skip
+over most of the list.  This is a no-lock, not thread safe version, see ../skiplist_ts for a
+version where readers do not lock.  It has the same methods as the AVL tree in ../avl_tree
+so they can be swapped.  Each link also keeps its span, the number of items it skips over,
+so Index and Rank do not need to walk the list.
+
+* 	ConvertToSlice - return the data in order as a slice.										O(n)
+* 	Insert - add an item in order.																O(log|2(n)) expected
+*		Duplicates replace the current item - Insert returns false for a duplicate.
+* 	Delete — Deletes a specified element from the list.										O(log|2(n)) expected
+* 	Index - return the Nth item in order.														O(log|2(n)) expected
+* 	Rank - the number of items less than an item, its position for Index.					O(log|2(n)) expected
+* 	IsEmpty — Returns true if the list is empty													O(1)
+* 	Length — Returns number of elements in the list.											O(1)
+* 	Search — Returns the matching element from the list.										O(log|2(n)) expected
+* 	Truncate - Delete all the nodes in list. 													O(1)
+*	FindMin - the smallest item.																O(1)
+*	FindMax - the largest item.																	O(log|2(n)) expected
+*	Depth -> int the number of levels in use.													O(1)
+* 	DeleteAtHead — Deletes the smallest element of the list.  									O(log|2(n)) expected
+* 	DeleteAtTail — Deletes the largest element of the list. 									O(log|2(n)) expected
+* 	RangeWalk — call a function in order for each item from lo to hi.							O(log|2(n)+k)
+* 	Range — an iter.Seq over the items from lo to hi.											O(log|2(n)+k)
+* 	All — an iter.Seq over all the items.														O(n)
+
+*/
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+	"strings"
+
+	// "sync"
+
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// MaxLevel is the most levels that a list can have, enough for 2**32 items.
+const MaxLevel = 32
+
+// skipLink is a forward link and the number of level 0 steps that it covers.
+type skipLink[T comparable.Comparable] struct {
+	node *SkipListElement[T]
+	span int
+}
+
+// SkipListElement is a node in the list, it is on levels 0 to len(next)-1.
+type SkipListElement[T comparable.Comparable] struct {
+	data *T
+	next []skipLink[T]
+}
+
+// SkipList is a generic ordered skip list.
+type SkipList[T comparable.Comparable] struct {
+	head   *SkipListElement[T] // has no data, it is on every level
+	level  int                 // number of levels in use
+	length int
+	// lock   sync.RWMutex
+}
+
+// At compile time verify that SkipList can be used behind the iface_list interfaces.
+var _ iface_list.TreeDataType[comparable.Int] = (*SkipList[comparable.Int])(nil)
+
+// NewSkipList creates a new SkipList and return it.
+// Complexity is O(1).
+func NewSkipList[T comparable.Comparable]() *SkipList[T] {
+	return &SkipList[T]{}
+}
+
+// randomLevel picks the number of levels for a new node, 1 with probability 1/2, 2 with
+// probability 1/4 and so on.
+func randomLevel() int {
+	return bits.TrailingZeros64(rand.Uint64()|(1<<(MaxLevel-1))) + 1
+}
+
+// nlHead returns the head node, making it for a zero value list.
+func (tt *SkipList[T]) nlHead() *SkipListElement[T] {
+	if tt.head == nil {
+		tt.head = &SkipListElement[T]{next: make([]skipLink[T], MaxLevel)}
+		tt.level = 1
+	}
+	return tt.head
+}
+
+// nlFindPath fills in `update` with the last node on each level that is before `find`, and
+// `rank` with the position of that node, counting the head as 0.
+func (tt *SkipList[T]) nlFindPath(find *T, update *[MaxLevel]*SkipListElement[T], rank *[MaxLevel]int) {
+	cur := tt.nlHead()
+	var stop *SkipListElement[T] // the node that ended the search on the level above
+	for ii := tt.level - 1; ii >= 0; ii-- {
+		if ii < tt.level-1 {
+			rank[ii] = rank[ii+1]
+		}
+		for nx := cur.next[ii]; nx.node != stop && (*nx.node.data).Compare(*find) < 0; nx = cur.next[ii] {
+			rank[ii] += nx.span
+			cur = nx.node
+		}
+		stop = cur.next[ii].node
+		update[ii] = cur
+	}
+}
+
+// GetData returns the data in the node.
+// Complexity is O(1).
+func (ee *SkipListElement[T]) GetData() *T {
+	return ee.data
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// IsEmpty will return true if the list is empty
+// Complexity is O(1).
+func (tt *SkipList[T]) IsEmpty() bool {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.length == 0
+}
+
+// Length returns the number of elements in the list.
+// Complexity is O(1).
+func (tt *SkipList[T]) Length() int {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.length
+}
+
+// Truncate removes all data from the list.
+// Complexity is O(1).
+func (tt *SkipList[T]) Truncate() {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.Lock()
+	// defer tt.lock.Unlock()
+
+	tt.head = nil
+	tt.level = 0
+	tt.length = 0
+}
+
+// Insert will add a new item to the list in order.  If it is a duplicate of an existing
+// item the new item will replace the existing one.  True is returned if the item
+// is new, false if it replaced an existing one.
+// Complexity is O(log|2(n)) expected.
+func (tt *SkipList[T]) Insert(item *T) (isNew bool) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.Lock()
+	// defer tt.lock.Unlock()
+
+	var update [MaxLevel]*SkipListElement[T]
+	var rank [MaxLevel]int
+	tt.nlFindPath(item, &update, &rank)
+
+	if nx := update[0].next[0].node; nx != nil && (*item).Compare(*nx.data) == 0 {
+		nx.data = item
+		return false
+	}
+
+	lvl := randomLevel()
+	if lvl > tt.level {
+		for ii := tt.level; ii < lvl; ii++ {
+			rank[ii] = 0
+			update[ii] = tt.head
+			tt.head.next[ii].span = tt.length
+		}
+		tt.level = lvl
+	}
+
+	node := &SkipListElement[T]{data: item, next: make([]skipLink[T], lvl)}
+	for ii := 0; ii < lvl; ii++ {
+		prev := &update[ii].next[ii]
+		node.next[ii] = skipLink[T]{node: prev.node, span: prev.span - (rank[0] - rank[ii])}
+		*prev = skipLink[T]{node: node, span: rank[0] - rank[ii] + 1}
+	}
+	for ii := lvl; ii < tt.level; ii++ {
+		update[ii].next[ii].span++ // these links now skip over the new node
+	}
+	tt.length++
+	return true
+}
+
+// Delete removes the item that matches `find` from the list.  True is returned if an item
+// was removed.
+// Complexity is O(log|2(n)) expected.
+func (tt *SkipList[T]) Delete(find *T) (found bool) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.Lock()
+	// defer tt.lock.Unlock()
+
+	return tt.nlDelete(find)
+}
+
+func (tt *SkipList[T]) nlDelete(find *T) (found bool) {
+	var update [MaxLevel]*SkipListElement[T]
+	var rank [MaxLevel]int
+	tt.nlFindPath(find, &update, &rank)
+
+	node := update[0].next[0].node
+	if node == nil || (*find).Compare(*node.data) != 0 {
+		return false
+	}
+	for ii := 0; ii < tt.level; ii++ {
+		prev := &update[ii].next[ii]
+		if prev.node == node {
+			*prev = skipLink[T]{node: node.next[ii].node, span: prev.span + node.next[ii].span - 1}
+		} else {
+			prev.span-- // this link skipped over the node
+		}
+	}
+	for tt.level > 1 && tt.head.next[tt.level-1].node == nil {
+		tt.level--
+	}
+	tt.length--
+	return true
+}
+
+// DeleteAtHead removes the smallest item in the list.
+// Complexity is O(log|2(n)) expected.
+func (tt *SkipList[T]) DeleteAtHead() (found bool) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.Lock()
+	// defer tt.lock.Unlock()
+
+	if tt.length == 0 {
+		return false
+	}
+	return tt.nlDelete(tt.nlFindMin())
+}
+
+// DeleteAtTail removes the largest item in the list.
+// Complexity is O(log|2(n)) expected.
+func (tt *SkipList[T]) DeleteAtTail() (found bool) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.Lock()
+	// defer tt.lock.Unlock()
+
+	if tt.length == 0 {
+		return false
+	}
+	return tt.nlDelete(tt.nlFindMax())
+}
+
+// Search will look for `find` in the list and retrn the found item if it is in the list.
+// If it is not found then `nil` will be returned.
+// Complexity is O(log|2(n)) expected.
+func (tt *SkipList[T]) Search(find *T) (item *T) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	if node := tt.nlSeek(find); node != nil && (*find).Compare(*node.data) == 0 {
+		return node.data
+	}
+	return nil
+}
+
+// nlSeek returns the first node that is greater than or equal to `find`, nil if there is none.
+func (tt *SkipList[T]) nlSeek(find *T) *SkipListElement[T] {
+	cur := tt.nlHead()
+	var stop *SkipListElement[T] // the node that ended the search on the level above
+	for ii := tt.level - 1; ii >= 0; ii-- {
+		for nx := cur.next[ii].node; nx != stop && (*nx.data).Compare(*find) < 0; nx = cur.next[ii].node {
+			cur = nx
+		}
+		stop = cur.next[ii].node
+	}
+	return stop
+}
+
+// FindMin returns the smallest item in the list, nil if the list is empty.
+// Complexity is O(1).
+func (tt *SkipList[T]) FindMin() (item *T) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.nlFindMin()
+}
+
+func (tt *SkipList[T]) nlFindMin() (item *T) {
+	if node := tt.nlHead().next[0].node; node != nil {
+		return node.data
+	}
+	return nil
+}
+
+// FindMax returns the largest item in the list, nil if the list is empty.
+// Complexity is O(log|2(n)) expected.
+func (tt *SkipList[T]) FindMax() (item *T) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.nlFindMax()
+}
+
+func (tt *SkipList[T]) nlFindMax() (item *T) {
+	cur := tt.nlHead()
+	for ii := tt.level - 1; ii >= 0; ii-- {
+		for cur.next[ii].node != nil {
+			cur = cur.next[ii].node
+		}
+	}
+	return cur.data
+}
+
+// Index returns the N-th item in the list, 0 based, in order.  It uses the spans of the
+// links to skip directly to the item.
+// Complexity is O(log|2(n)) expected.
+func (tt *SkipList[T]) Index(pos int) (item *T) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	if pos < 0 || pos >= tt.length {
+		return nil
+	}
+	pos++ // the head is at position 0
+	cur, at := tt.nlHead(), 0
+	for ii := tt.level - 1; ii >= 0; ii-- {
+		for nx := cur.next[ii]; nx.node != nil && at+nx.span <= pos; nx = cur.next[ii] {
+			at += nx.span
+			cur = nx.node
+		}
+		if at == pos {
+			return cur.data
+		}
+	}
+	return nil
+}
+
+// Rank returns the number of items in the list that are less than `find`.  If `find` is in
+// the list this is its position as used by Index.
+// Complexity is O(log|2(n)) expected.
+func (tt *SkipList[T]) Rank(find *T) (n int) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	var update [MaxLevel]*SkipListElement[T]
+	var rank [MaxLevel]int
+	tt.nlFindPath(find, &update, &rank)
+	return rank[0]
+}
+
+// Depth returns the number of levels in use, about log|2(n).
+// Complexity is O(1).
+func (tt *SkipList[T]) Depth() (d int) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	if tt.length == 0 {
+		return 0
+	}
+	return tt.level
+}
+
+// ConvertToSlice returns the data in the list in order.
+// Complexity is O(n).
+func (tt *SkipList[T]) ConvertToSlice() (rv []*T) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	rv = make([]*T, 0, tt.length)
+	for cur := tt.nlHead().next[0].node; cur != nil; cur = cur.next[0].node {
+		rv = append(rv, cur.data)
+	}
+	return
+}
+
+// Dump will print out the list to the file `fo`, one item per line with a # for each level
+// that the item is on.
+func (tt *SkipList[T]) Dump(fo io.Writer) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	for cur := tt.nlHead().next[0].node; cur != nil; cur = cur.next[0].node {
+		fmt.Fprintf(fo, "%-*s %v\n", MaxLevel, strings.Repeat("#", len(cur.next)), *cur.data)
+	}
+}
+
+// RangeWalk calls `fx` in order for each item from `lo` to `hi`, both included.  A nil `lo`
+// or `hi` leaves that end of the range open.  The walk stops if `fx` returns false.
+// Complexity is O(log|2(n)+k) expected, k is the number of items in the range.
+func (tt *SkipList[T]) RangeWalk(lo, hi *T, fx func(item *T) bool) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	cur := tt.nlHead().next[0].node
+	if lo != nil {
+		cur = tt.nlSeek(lo)
+	}
+	for ; cur != nil; cur = cur.next[0].node {
+		if hi != nil && (*hi).Compare(*cur.data) < 0 {
+			return
+		}
+		if !fx(cur.data) {
+			return
+		}
+	}
+}
+
+// Range returns an iterator over the items from `lo` to `hi`, both included, in order.  A nil
+// `lo` or `hi` leaves that end of the range open.
+//
+//	for item := range list.Range(&lo, &hi) {
+//		...
+//	}
+func (tt *SkipList[T]) Range(lo, hi *T) iter.Seq[*T] {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	return func(yield func(*T) bool) {
+		tt.RangeWalk(lo, hi, yield)
+	}
+}
+
+// All returns an iterator over all the items in the list in order.
+func (tt *SkipList[T]) All() iter.Seq[*T] {
+	return tt.Range(nil, nil)
+}
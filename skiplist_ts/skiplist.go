@@ -0,0 +1,529 @@
+package skiplist_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+A thread safe Skip List where nothing takes a lock.  It has the same methods as ../skiplist
+and the AVL tree in ../avl_tree so they can be swapped.  Every forward link is an atomic
+pointer.  Readers only load links, so they never wait for a writer or for each other.
+Writers add and remove links with compare-and-swap, and if another writer got there first
+they look again and retry.  This is the lock-free skip list of Herlihy and Shavit, "The Art
+of Multiprocessor Programming", chapter 14.
+
+A delete first marks the links out of the node, top level first, and the node is removed
+from the list when the mark on its level 0 link is set.  After that readers skip over it and
+any writer that passes it unlinks it.
+
+The methods that look at more than one item, Index, Rank, ConvertToSlice and the Range walks,
+see the list as it changes, an item inserted or deleted during the walk may or may not be
+seen.  Length is exact when nothing is being changed.
+
+* 	ConvertToSlice - return the data in order as a slice.										O(n)
+* 	Insert - add an item in order.																O(log|2(n)) expected
+*		Duplicates replace the current item - Insert returns false for a duplicate.
+* 	Delete — Deletes a specified element from the list.										O(log|2(n)) expected
+* 	Index - return the Nth item in order.														O(n)
+* 	Rank - the number of items less than an item, its position for Index.					O(n)
+* 	IsEmpty — Returns true if the list is empty													O(1)
+* 	Length — Returns number of elements in the list.											O(1)
+* 	Search — Returns the matching element from the list.										O(log|2(n)) expected
+* 	Truncate - Delete all the nodes in list. 													O(n)
+*	FindMin - the smallest item.																O(1)
+*	FindMax - the largest item.																	O(log|2(n)) expected
+*	Depth -> int the number of levels in use.													O(1)
+* 	DeleteAtHead — Deletes the smallest element of the list.  									O(1) expected
+* 	DeleteAtTail — Deletes the largest element of the list. 									O(log|2(n)) expected
+* 	RangeWalk — call a function in order for each item from lo to hi.							O(log|2(n)+k)
+* 	Range — an iter.Seq over the items from lo to hi.											O(log|2(n)+k)
+* 	All — an iter.Seq over all the items.														O(n)
+
+*/
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+	"strings"
+	"sync/atomic"
+
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// MaxLevel is the most levels that a list can have, enough for 2**32 items.
+const MaxLevel = 32
+
+// skipLink is a forward link and the mark that says the node it is in has been deleted.
+// A skipLink is never changed, a new one is swapped in so that the link and the mark change
+// together in one compare-and-swap.
+type skipLink[T comparable.Comparable] struct {
+	node   *SkipListElement[T]
+	marked bool
+}
+
+// SkipListElement is a node in the list, it is on levels 0 to len(next)-1.
+type SkipListElement[T comparable.Comparable] struct {
+	data atomic.Pointer[T] // replaced by an Insert of a duplicate
+	next []atomic.Pointer[skipLink[T]]
+}
+
+// SkipList is a generic ordered skip list that is safe to use from many go routines.
+type SkipList[T comparable.Comparable] struct {
+	head   atomic.Pointer[SkipListElement[T]] // has no data, it is on every level
+	level  atomic.Int32                       // highest level that has been used
+	length atomic.Int64
+}
+
+// At compile time verify that SkipList can be used behind the iface_list interfaces.
+var _ iface_list.TreeDataType[comparable.Int] = (*SkipList[comparable.Int])(nil)
+
+// NewSkipList creates a new SkipList and return it.
+// Complexity is O(1).
+func NewSkipList[T comparable.Comparable]() *SkipList[T] {
+	return &SkipList[T]{}
+}
+
+// randomLevel picks the number of levels for a new node, 1 with probability 1/2, 2 with
+// probability 1/4 and so on.
+func randomLevel() int {
+	return bits.TrailingZeros64(rand.Uint64()|(1<<(MaxLevel-1))) + 1
+}
+
+// newNode makes a node on len(succs) levels with links to `succs`.
+func newNode[T comparable.Comparable](item *T, succs []*SkipListElement[T]) *SkipListElement[T] {
+	node := &SkipListElement[T]{next: make([]atomic.Pointer[skipLink[T]], len(succs))}
+	node.data.Store(item)
+	for ii, succ := range succs {
+		node.next[ii].Store(&skipLink[T]{node: succ})
+	}
+	return node
+}
+
+// getHead returns the head node, making it for a zero value list.
+func (tt *SkipList[T]) getHead() *SkipListElement[T] {
+	if head := tt.head.Load(); head != nil {
+		return head
+	}
+	tt.head.CompareAndSwap(nil, newNode[T](nil, make([]*SkipListElement[T], MaxLevel)))
+	return tt.head.Load()
+}
+
+// less returns true if `node` is before `find`.  A nil node is the end of the list.
+func less[T comparable.Comparable](node *SkipListElement[T], find *T) bool {
+	return node != nil && (*node.data.Load()).Compare(*find) < 0
+}
+
+// find fills in `preds` and `succs` with the nodes on each level just before and just after
+// `find`.  Marked nodes that it passes are unlinked.  It returns true if succs[0] is `find`.
+func (tt *SkipList[T]) find(find *T, preds, succs *[MaxLevel]*SkipListElement[T]) bool {
+	head := tt.getHead()
+retry:
+	for {
+		pred := head
+		for ii := MaxLevel - 1; ii >= 0; ii-- {
+			predLink := pred.next[ii].Load()
+			cur := predLink.node
+			for cur != nil {
+				curLink := cur.next[ii].Load()
+				if curLink.marked {
+					// cur has been deleted, unlink it on this level.
+					newLink := &skipLink[T]{node: curLink.node}
+					if !pred.next[ii].CompareAndSwap(predLink, newLink) {
+						continue retry // pred has changed
+					}
+					predLink, cur = newLink, curLink.node
+					continue
+				}
+				if !less(cur, find) {
+					break
+				}
+				pred, predLink, cur = cur, curLink, curLink.node
+			}
+			preds[ii], succs[ii] = pred, cur
+		}
+		return succs[0] != nil && (*find).Compare(*succs[0].data.Load()) == 0
+	}
+}
+
+// topLevel returns the highest level to start a search on.
+func (tt *SkipList[T]) topLevel() int {
+	return max(int(tt.level.Load()), 1) - 1
+}
+
+// seek returns the first node that is not deleted and is greater than or equal to `find`,
+// nil if there is none.  It does not change the list.
+func (tt *SkipList[T]) seek(find *T) *SkipListElement[T] {
+	pred := tt.getHead()
+	var cur *SkipListElement[T]
+	for ii := tt.topLevel(); ii >= 0; ii-- {
+		cur = pred.next[ii].Load().node
+		for cur != nil {
+			curLink := cur.next[ii].Load()
+			if curLink.marked {
+				cur = curLink.node // skip the deleted node
+			} else if less(cur, find) {
+				pred, cur = cur, curLink.node
+			} else {
+				break
+			}
+		}
+	}
+	return cur
+}
+
+// GetData returns the data in the node.
+// Complexity is O(1).
+func (ee *SkipListElement[T]) GetData() *T {
+	return ee.data.Load()
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// IsEmpty will return true if the list is empty
+// Complexity is O(1).
+func (tt *SkipList[T]) IsEmpty() bool {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	return tt.length.Load() == 0
+}
+
+// Length returns the number of elements in the list.
+// Complexity is O(1).
+func (tt *SkipList[T]) Length() int {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	return int(tt.length.Load())
+}
+
+// Truncate removes all data from the list.  It deletes the items one at a time so that it
+// is safe with other changes at the same time, an item inserted during the Truncate may be
+// left in the list.
+// Complexity is O(n).
+func (tt *SkipList[T]) Truncate() {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	for tt.DeleteAtHead() {
+	}
+}
+
+// Insert will add a new item to the list in order.  If it is a duplicate of an existing
+// item the new item will replace the existing one.  True is returned if the item
+// is new, false if it replaced an existing one.
+// Complexity is O(log|2(n)) expected.
+func (tt *SkipList[T]) Insert(item *T) (isNew bool) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	var preds, succs [MaxLevel]*SkipListElement[T]
+	lvl := randomLevel()
+	var node *SkipListElement[T]
+	for {
+		if tt.find(item, &preds, &succs) {
+			succs[0].data.Store(item)
+			return false
+		}
+
+		// Link in on level 0, this is the point where the item is in the list.
+		node = newNode(item, succs[:lvl])
+		if predLink := preds[0].next[0].Load(); !predLink.marked && predLink.node == succs[0] &&
+			preds[0].next[0].CompareAndSwap(predLink, &skipLink[T]{node: node}) {
+			break
+		}
+	}
+	tt.length.Add(1)
+	for cur := tt.level.Load(); int(cur) < lvl && !tt.level.CompareAndSwap(cur, int32(lvl)); cur = tt.level.Load() {
+	}
+
+	// Link in on the upper levels, these only make searches faster.
+	for ii := 1; ii < lvl; ii++ {
+		for {
+			nodeLink := node.next[ii].Load()
+			if nodeLink.marked {
+				return true // deleted already, do not link in any more
+			}
+			if nodeLink.node != succs[ii] && !node.next[ii].CompareAndSwap(nodeLink, &skipLink[T]{node: succs[ii]}) {
+				continue
+			}
+			if predLink := preds[ii].next[ii].Load(); !predLink.marked && predLink.node == succs[ii] &&
+				preds[ii].next[ii].CompareAndSwap(predLink, &skipLink[T]{node: node}) {
+				break
+			}
+			tt.find(item, &preds, &succs)
+		}
+	}
+	return true
+}
+
+// Delete removes the item that matches `find` from the list.  True is returned if an item
+// was removed.
+// Complexity is O(log|2(n)) expected.
+func (tt *SkipList[T]) Delete(find *T) (found bool) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+
+	var preds, succs [MaxLevel]*SkipListElement[T]
+	if !tt.find(find, &preds, &succs) {
+		return false
+	}
+	return tt.remove(find, succs[0])
+}
+
+// remove deletes `node`.  It returns false if another go routine deleted it first.
+func (tt *SkipList[T]) remove(find *T, node *SkipListElement[T]) bool {
+	for ii := len(node.next) - 1; ii >= 1; ii-- {
+		for {
+			link := node.next[ii].Load()
+			if link.marked || node.next[ii].CompareAndSwap(link, &skipLink[T]{node: link.node, marked: true}) {
+				break
+			}
+		}
+	}
+	for {
+		link := node.next[0].Load()
+		if link.marked {
+			return false // another go routine deleted it
+		}
+		if node.next[0].CompareAndSwap(link, &skipLink[T]{node: link.node, marked: true}) {
+			tt.length.Add(-1)
+			var preds, succs [MaxLevel]*SkipListElement[T]
+			tt.find(find, &preds, &succs) // unlinks the node
+			return true
+		}
+	}
+}
+
+// DeleteAtHead removes the smallest item in the list.
+// Complexity is O(1) expected.
+func (tt *SkipList[T]) DeleteAtHead() (found bool) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	for {
+		node := tt.first()
+		if node == nil {
+			return false
+		}
+		if tt.remove(node.data.Load(), node) {
+			return true
+		}
+	}
+}
+
+// DeleteAtTail removes the largest item in the list.
+// Complexity is O(log|2(n)) expected.
+func (tt *SkipList[T]) DeleteAtTail() (found bool) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	for {
+		node := tt.last()
+		if node == nil {
+			return false
+		}
+		if tt.remove(node.data.Load(), node) {
+			return true
+		}
+	}
+}
+
+// Search will look for `find` in the list and retrn the found item if it is in the list.
+// If it is not found then `nil` will be returned.  Search does not wait for writers.
+// Complexity is O(log|2(n)) expected.
+func (tt *SkipList[T]) Search(find *T) (item *T) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	if node := tt.seek(find); node != nil {
+		if item = node.data.Load(); (*find).Compare(*item) == 0 {
+			return item
+		}
+	}
+	return nil
+}
+
+// first returns the first node that is not deleted, nil if the list is empty.
+func (tt *SkipList[T]) first() *SkipListElement[T] {
+	return tt.after(tt.getHead())
+}
+
+// after returns the first node after `node` on level 0 that is not deleted.
+func (tt *SkipList[T]) after(node *SkipListElement[T]) *SkipListElement[T] {
+	cur := node.next[0].Load().node
+	for cur != nil {
+		link := cur.next[0].Load()
+		if !link.marked {
+			return cur
+		}
+		cur = link.node
+	}
+	return nil
+}
+
+// last returns the last node that is not deleted, nil if the list is empty.
+func (tt *SkipList[T]) last() *SkipListElement[T] {
+	pred := tt.getHead()
+	for ii := tt.topLevel(); ii >= 0; ii-- {
+		for cur := pred.next[ii].Load().node; cur != nil; {
+			link := cur.next[ii].Load()
+			if !link.marked {
+				pred = cur
+			}
+			cur = link.node
+		}
+	}
+	if pred == tt.getHead() {
+		return nil
+	}
+	if pred.next[0].Load().marked {
+		// Deleted since it was passed, look again.
+		return tt.last()
+	}
+	return pred
+}
+
+// FindMin returns the smallest item in the list, nil if the list is empty.
+// Complexity is O(1).
+func (tt *SkipList[T]) FindMin() (item *T) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	if node := tt.first(); node != nil {
+		return node.data.Load()
+	}
+	return nil
+}
+
+// FindMax returns the largest item in the list, nil if the list is empty.
+// Complexity is O(log|2(n)) expected.
+func (tt *SkipList[T]) FindMax() (item *T) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	if node := tt.last(); node != nil {
+		return node.data.Load()
+	}
+	return nil
+}
+
+// Index returns the N-th item in the list, 0 based, in order.  The links do not keep spans
+// in this version so it walks level 0.
+// Complexity is O(n).
+func (tt *SkipList[T]) Index(pos int) (item *T) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	if pos < 0 {
+		return nil
+	}
+	for cur := tt.first(); cur != nil; cur = tt.after(cur) {
+		if pos == 0 {
+			return cur.data.Load()
+		}
+		pos--
+	}
+	return nil
+}
+
+// Rank returns the number of items in the list that are less than `find`.  If `find` is in
+// the list this is its position as used by Index.
+// Complexity is O(n).
+func (tt *SkipList[T]) Rank(find *T) (n int) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	for cur := tt.first(); less(cur, find); cur = tt.after(cur) {
+		n++
+	}
+	return
+}
+
+// Depth returns the number of levels in use, about log|2(n).  Levels are not given back
+// when items are deleted.
+// Complexity is O(1).
+func (tt *SkipList[T]) Depth() (d int) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	if tt.length.Load() == 0 {
+		return 0
+	}
+	return int(tt.level.Load())
+}
+
+// ConvertToSlice returns the data in the list in order.
+// Complexity is O(n).
+func (tt *SkipList[T]) ConvertToSlice() (rv []*T) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	rv = make([]*T, 0, tt.length.Load())
+	for cur := tt.first(); cur != nil; cur = tt.after(cur) {
+		rv = append(rv, cur.data.Load())
+	}
+	return
+}
+
+// Dump will print out the list to the file `fo`, one item per line with a # for each level
+// that the item is on.
+func (tt *SkipList[T]) Dump(fo io.Writer) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	for cur := tt.first(); cur != nil; cur = tt.after(cur) {
+		fmt.Fprintf(fo, "%-*s %v\n", MaxLevel, strings.Repeat("#", len(cur.next)), *cur.data.Load())
+	}
+}
+
+// RangeWalk calls `fx` in order for each item from `lo` to `hi`, both included.  A nil `lo`
+// or `hi` leaves that end of the range open.  The walk stops if `fx` returns false.
+// Complexity is O(log|2(n)+k) expected, k is the number of items in the range.
+func (tt *SkipList[T]) RangeWalk(lo, hi *T, fx func(item *T) bool) {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	cur := tt.first()
+	if lo != nil {
+		cur = tt.seek(lo)
+	}
+	for ; cur != nil; cur = tt.after(cur) {
+		item := cur.data.Load()
+		if hi != nil && (*hi).Compare(*item) < 0 {
+			return
+		}
+		if !fx(item) {
+			return
+		}
+	}
+}
+
+// Range returns an iterator over the items from `lo` to `hi`, both included, in order.  A nil
+// `lo` or `hi` leaves that end of the range open.
+//
+//	for item := range list.Range(&lo, &hi) {
+//		...
+//	}
+func (tt *SkipList[T]) Range(lo, hi *T) iter.Seq[*T] {
+	if tt == nil {
+		panic("list sholud not be a nil")
+	}
+	return func(yield func(*T) bool) {
+		tt.RangeWalk(lo, hi, yield)
+	}
+}
+
+// All returns an iterator over all the items in the list in order.
+func (tt *SkipList[T]) All() iter.Seq[*T] {
+	return tt.Range(nil, nil)
+}
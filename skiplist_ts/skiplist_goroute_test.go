@@ -0,0 +1,138 @@
+package skiplist_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+// This test is meant to be run with the race detector, `go test -race`.
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestListGoroutines(t *testing.T) {
+	var List1 SkipList[TestTreeNode]
+
+	// Every key is inserted by two writers at once and the odd keys are then deleted by two
+	// deleters at once, so the writers race on the same nodes.
+	const nKeys = 2000
+	var wg sync.WaitGroup
+	var deletes [2]int
+	for ww := 0; ww < 2; ww++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < nKeys; k++ {
+				List1.Insert(key(k))
+			}
+		}()
+	}
+	wg.Wait()
+
+	for dd := 0; dd < 2; dd++ {
+		wg.Add(1)
+		go func(dd int) {
+			defer wg.Done()
+			for k := 1; k < nKeys; k += 2 {
+				if List1.Delete(key(k)) {
+					deletes[dd]++
+				}
+			}
+		}(dd)
+	}
+
+	// Readers walk and search while the deletes run.  Even keys are never deleted so they
+	// must always be found, and every walk must be in order.
+	for rd := 0; rd < 4; rd++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ii := 0; ii < 20; ii++ {
+				for k := 0; k < nKeys; k += 50 {
+					if List1.Search(key(k)) == nil {
+						t.Errorf("Expected to find %d", k)
+					}
+				}
+				var prev *TestTreeNode
+				for item := range List1.All() {
+					if prev != nil && prev.S >= item.S {
+						t.Errorf("Walk is out of order at %s", item.S)
+					}
+					prev = item
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if deletes[0]+deletes[1] != nKeys/2 {
+		t.Errorf("Expected %d deletes got %d", nKeys/2, deletes[0]+deletes[1])
+	}
+	if List1.Length() != nKeys/2 {
+		t.Errorf("Expected length %d got %d", nKeys/2, List1.Length())
+	}
+	validate(t, &List1)
+	for pos, item := range List1.ConvertToSlice() {
+		if item.S != key(pos*2).S {
+			t.Errorf("At %d expcted %04d got %s", pos, pos*2, item.S)
+		}
+	}
+}
+
+func TestListGoroutinesMixed(t *testing.T) {
+	var List1 SkipList[TestTreeNode]
+
+	// Each go routine owns the keys k where k%4 is its number, it inserts and deletes them
+	// and keeps its own model, while the others do the same on the same list.
+	var wg sync.WaitGroup
+	models := make([]map[int]bool, 4)
+	for gg := 0; gg < 4; gg++ {
+		models[gg] = make(map[int]bool)
+		wg.Add(1)
+		go func(gg int) {
+			defer wg.Done()
+			model := models[gg]
+			for ii := 0; ii < 3000; ii++ {
+				k := (ii*7919)%400*4 + gg
+				if ii%3 == 0 {
+					if found := List1.Delete(key(k)); found != model[k] {
+						t.Errorf("Delete(%d) expected %v", k, model[k])
+					}
+					delete(model, k)
+				} else {
+					if isNew := List1.Insert(key(k)); isNew == model[k] {
+						t.Errorf("Insert(%d) expected isNew %v", k, !model[k])
+					}
+					model[k] = true
+				}
+			}
+		}(gg)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for ii := 0; ii < 200; ii++ {
+			List1.FindMin()
+			List1.FindMax()
+			List1.Index(ii)
+		}
+	}()
+	wg.Wait()
+
+	n := 0
+	for _, model := range models {
+		n += len(model)
+		for k := range model {
+			if List1.Search(key(k)) == nil {
+				t.Errorf("Expected to find %d", k)
+			}
+		}
+	}
+	if List1.Length() != n {
+		t.Errorf("Expected length %d got %d", n, List1.Length())
+	}
+	validate(t, &List1)
+}
@@ -0,0 +1,249 @@
+package skiplist_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pschlump/pluto/comparable"
+)
+
+// TestTreeNode is an Inteface Matcing data type for the Nodes that supports the Comparable
+// interface.  This means that it has a Compare fucntion.
+type TestTreeNode struct {
+	S string
+}
+
+// At compile time verify that this is a correct type/interface setup.
+var _ comparable.Comparable = (*TestTreeNode)(nil)
+
+// Compare implements the Compare function to satisfy the interface requirements.
+func (aa TestTreeNode) Compare(x comparable.Comparable) int {
+	if bb, ok := x.(TestTreeNode); ok {
+		if aa.S < bb.S {
+			return -1
+		} else if aa.S > bb.S {
+			return 1
+		}
+	} else if bb, ok := x.(*TestTreeNode); ok {
+		if aa.S < bb.S {
+			return -1
+		} else if aa.S > bb.S {
+			return 1
+		}
+	} else {
+		panic(fmt.Sprintf("Passed invalid type %T to a Compare function.", x))
+	}
+	return 0
+}
+
+func key(k int) *TestTreeNode {
+	return &TestTreeNode{S: fmt.Sprintf("%04d", k)}
+}
+
+func TestListInsertSearch(t *testing.T) {
+	var List1 SkipList[TestTreeNode]
+
+	if !List1.IsEmpty() || List1.Search(key(5)) != nil || List1.FindMin() != nil || List1.FindMax() != nil || List1.Depth() != 0 {
+		t.Errorf("Expected empty list")
+	}
+	for _, k := range []int{5, 2, 9, 0, 3} {
+		if !List1.Insert(key(k)) {
+			t.Errorf("Expected %d to be new", k)
+		}
+	}
+	dup := key(9)
+	if List1.Insert(dup) {
+		t.Errorf("Expected 9 to be a duplicate")
+	}
+	if List1.Length() != 5 {
+		t.Errorf("Expected length 5 got %d", List1.Length())
+	}
+	if x := List1.Search(key(9)); x != dup {
+		t.Errorf("Expected the duplicate to replace the item")
+	}
+	if x := List1.Search(key(4)); x != nil {
+		t.Errorf("Expected nil got %v", x)
+	}
+	if x := List1.FindMin(); x.S != "0000" {
+		t.Errorf("FindMin expcted 0000 got %s", x.S)
+	}
+	if x := List1.FindMax(); x.S != "0009" {
+		t.Errorf("FindMax expcted 0009 got %s", x.S)
+	}
+	if x := List1.Index(1); x.S != "0002" {
+		t.Errorf("Index(1) expcted 0002 got %s", x.S)
+	}
+	if n := List1.Rank(key(4)); n != 3 {
+		t.Errorf("Rank(4) expcted 3 got %d", n)
+	}
+
+	var buf bytes.Buffer
+	List1.Dump(&buf)
+	if n := strings.Count(buf.String(), "\n"); n != 5 {
+		t.Errorf("Expected 5 lines from Dump got %d", n)
+	}
+
+	if !List1.DeleteAtHead() || !List1.DeleteAtTail() {
+		t.Errorf("Expected DeleteAtHead and DeleteAtTail to remove an item")
+	}
+	if List1.Length() != 3 || List1.FindMin().S != "0002" || List1.FindMax().S != "0005" {
+		t.Errorf("Expected 0002 0003 0005 got %v", List1.ConvertToSlice())
+	}
+	List1.Truncate()
+	if !List1.IsEmpty() || List1.DeleteAtHead() || List1.DeleteAtTail() || List1.Depth() != 0 {
+		t.Errorf("Expected empty list after Truncate")
+	}
+	if !List1.Insert(key(1)) || List1.Length() != 1 {
+		t.Errorf("Expected the list to be usable after Truncate")
+	}
+}
+
+func TestListModel(t *testing.T) {
+	List1 := NewSkipList[TestTreeNode]()
+	model := make(map[int]bool)
+	rnd := rand.New(rand.NewSource(1001))
+
+	for ii := 0; ii < 5000; ii++ {
+		k := rnd.Intn(500)
+		if rnd.Intn(5) < 2 {
+			if found := List1.Delete(key(k)); found != model[k] {
+				t.Fatalf("Step %d, Delete(%d) expected %v", ii, k, model[k])
+			}
+			delete(model, k)
+		} else {
+			if isNew := List1.Insert(key(k)); isNew == model[k] {
+				t.Fatalf("Step %d, Insert(%d) expected isNew %v", ii, k, !model[k])
+			}
+			model[k] = true
+		}
+		if ii%250 == 0 {
+			validate(t, List1)
+		}
+	}
+	validate(t, List1)
+
+	keys := make([]int, 0, len(model))
+	for k := range model {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	if List1.Length() != len(keys) {
+		t.Errorf("Expected length %d got %d", len(keys), List1.Length())
+	}
+	for pos, k := range keys {
+		if x := List1.Index(pos); x == nil || x.S != key(k).S {
+			t.Errorf("Index(%d) expcted %04d got %v", pos, k, x)
+		}
+		if r := List1.Rank(key(k)); r != pos {
+			t.Errorf("Rank(%d) expcted %d got %d", k, pos, r)
+		}
+	}
+	if List1.Index(-1) != nil || List1.Index(len(keys)) != nil {
+		t.Errorf("Expected nil for an Index out of range")
+	}
+	if d := List1.Depth(); d < 2 || d > MaxLevel {
+		t.Errorf("Expected a depth from 2 to %d got %d", MaxLevel, d)
+	}
+
+	for _, k := range keys {
+		if !List1.Delete(key(k)) {
+			t.Errorf("Expected to delete %d", k)
+		}
+	}
+	validate(t, List1)
+	if !List1.IsEmpty() || List1.Length() != 0 {
+		t.Errorf("Expected empty list")
+	}
+}
+
+func TestListRange(t *testing.T) {
+	List1 := NewSkipList[TestTreeNode]()
+	for k := 0; k < 100; k += 2 {
+		List1.Insert(key(k))
+	}
+
+	var got []string
+	for item := range List1.Range(key(15), key(25)) {
+		got = append(got, item.S)
+	}
+	if fmt.Sprint(got) != "[0016 0018 0020 0022 0024]" {
+		t.Errorf("Range(15,25) got %v", got)
+	}
+
+	got = got[:0]
+	List1.RangeWalk(nil, key(4), func(item *TestTreeNode) bool {
+		got = append(got, item.S)
+		return true
+	})
+	if fmt.Sprint(got) != "[0000 0002 0004]" {
+		t.Errorf("RangeWalk(nil,4) got %v", got)
+	}
+
+	got = got[:0]
+	List1.RangeWalk(key(95), nil, func(item *TestTreeNode) bool {
+		got = append(got, item.S)
+		return true
+	})
+	if fmt.Sprint(got) != "[0096 0098]" {
+		t.Errorf("RangeWalk(95,nil) got %v", got)
+	}
+
+	n := 0
+	for range List1.All() {
+		n++
+		if n == 10 {
+			break
+		}
+	}
+	if n != 10 {
+		t.Errorf("Expected All to stop after 10 got %d", n)
+	}
+}
+
+// validate checks, when nothing is changing the list, that no deleted node is still linked
+// in, that each level is in order and that each level only has nodes that are on the level
+// below.
+func validate(t *testing.T, tt *SkipList[TestTreeNode]) {
+	t.Helper()
+	head := tt.getHead()
+	onLevel0 := make(map[*SkipListElement[TestTreeNode]]bool)
+	n := 0
+	for ii := 0; ii < MaxLevel; ii++ {
+		var prev *TestTreeNode
+		for cur := head.next[ii].Load(); ; cur = cur.node.next[ii].Load() {
+			if cur.marked {
+				t.Errorf("Level %d has a deleted node linked in", ii)
+			}
+			if cur.node == nil {
+				break
+			}
+			data := cur.node.data.Load()
+			if prev != nil && prev.S >= data.S {
+				t.Errorf("Level %d item %s is out of order", ii, data.S)
+			}
+			prev = data
+			if ii == 0 {
+				onLevel0[cur.node] = true
+				n++
+			} else if !onLevel0[cur.node] {
+				t.Errorf("Level %d item %s is not on level 0", ii, data.S)
+			}
+			if ii >= int(tt.level.Load()) {
+				t.Errorf("Level %d is above the levels in use, %d, but has items", ii, tt.level.Load())
+			}
+		}
+	}
+	if n != tt.Length() {
+		t.Errorf("Expected length %d got %d", n, tt.Length())
+	}
+}
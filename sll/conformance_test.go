@@ -0,0 +1,26 @@
+package sll
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/pschlump/pluto/containertest"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// testItem makes the data for the shared conformance tests.
+var testItem = containertest.Item[TestDemo]{
+	New: func(n int) *TestDemo { return &TestDemo{S: fmt.Sprintf("%04d", n)} },
+	Key: func(x *TestDemo) int { n, _ := strconv.Atoi(x.S); return n },
+}
+
+func TestConformance(t *testing.T) {
+	containertest.TestLinear(t, func() iface_list.LinearDataType[TestDemo] { return NewSll[TestDemo]() }, testItem)
+}
@@ -0,0 +1,202 @@
+package sll
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+Operations that re-link the nodes of lists.  No node is copied or allocated, so a *SllElement
+from Search, or held anywhere else, still points to the same data after the operation.
+
+*	Sort - stable merge sort of the list with a compare function.								O(n log n)
+*	MergeSorted - merge another sorted list into this sorted list, the other is left empty.		O(n+m)
+*	SplitAt - move the nodes before and after a position into 2 new lists.						O(n)
+*	SpliceAfter - move all the nodes of another list in after a node of this list.				O(1)
+*	Dedup - remove the nodes that compare equal to the node before them.						O(n)
+
+The compare function returns < 0 if a is before b, 0 if they are equal and > 0 if a is after
+b, like strings.Compare.
+
+*/
+
+import (
+	"errors"
+
+	"github.com/pschlump/pluto/comparable"
+)
+
+// An error to indicate that a list was passed to be merged or spliced into itself.
+var ErrSameList = errors.New("Can not Splice a List into Itself")
+
+// sortChain sorts the chain of nodes that starts at `head` with a bottom up merge sort, it
+// merges runs of 1, then 2, then 4 ... in place.  It returns the new head and tail.  When 2
+// nodes are equal the one that was first stays first.
+func sortChain[T comparable.Equality](head *SllElement[T], cmp func(a, b *T) int) (*SllElement[T], *SllElement[T]) {
+	if head == nil {
+		return nil, nil
+	}
+	for inSize := 1; ; inSize *= 2 {
+		var tail *SllElement[T]
+		pp := head
+		head = nil
+		nMerges := 0
+		for pp != nil {
+			nMerges++
+			qq, pSize := pp, 0
+			for ; pSize < inSize && qq != nil; pSize++ {
+				qq = qq.next
+			}
+			qSize := inSize
+			for pSize > 0 || (qSize > 0 && qq != nil) {
+				var el *SllElement[T]
+				if pSize == 0 || (qSize > 0 && qq != nil && cmp(qq.data, pp.data) < 0) {
+					el, qq = qq, qq.next
+					qSize--
+				} else {
+					el, pp = pp, pp.next
+					pSize--
+				}
+				if tail == nil {
+					head = el
+				} else {
+					tail.next = el
+				}
+				tail = el
+			}
+			pp = qq
+		}
+		tail.next = nil
+		if nMerges <= 1 {
+			return head, tail
+		}
+	}
+}
+
+// Sort sorts the list in the order given by `cmp`.  The sort is stable, elements that are equal
+// stay in the order they were in.  The nodes are re-linked, not copied, and nothing is allocated.
+// Complexity is O(n log n).
+func (ns *Sll[T]) Sort(cmp func(a, b *T) int) {
+	if ns == nil {
+		panic("list sholud not be a nil")
+	}
+	(*ns).head, (*ns).tail = sortChain((*ns).head, cmp)
+}
+
+// MergeSorted merges the elements of `other` into this list.  Both lists must already be sorted
+// by `cmp`, the result is sorted and `other` is left empty.  If an element of this list and one
+// of `other` are equal then the one from this list is first.
+// Complexity is O(n+m).
+func (ns *Sll[T]) MergeSorted(other *Sll[T], cmp func(a, b *T) int) error {
+	if ns == nil {
+		panic("list sholud not be a nil")
+	}
+	if other == ns {
+		return ErrSameList
+	}
+	if other == nil || (*other).length == 0 {
+		return nil
+	}
+	var head, tail *SllElement[T]
+	aa, bb := (*ns).head, (*other).head
+	for aa != nil || bb != nil {
+		var el *SllElement[T]
+		if aa == nil || (bb != nil && cmp(bb.data, aa.data) < 0) {
+			el, bb = bb, bb.next
+		} else {
+			el, aa = aa, aa.next
+		}
+		if tail == nil {
+			head = el
+		} else {
+			tail.next = el
+		}
+		tail = el
+	}
+	(*ns).head, (*ns).tail = head, tail
+	(*ns).length += (*other).length
+	other.Truncate()
+	return nil
+}
+
+// SplitAt moves the elements before `pos` into the first list returned and the elements from
+// `pos` on into the second one.  This list is left empty.  A `pos` of 0 or less puts all the
+// elements in the second list, a `pos` at or after the end puts them all in the first.
+// Complexity is O(n).
+func (ns *Sll[T]) SplitAt(pos int) (*Sll[T], *Sll[T]) {
+	if ns == nil {
+		panic("list sholud not be a nil")
+	}
+	front, back := NewSll[T](), NewSll[T]()
+	switch {
+	case pos <= 0:
+		back.head, back.tail, back.length = (*ns).head, (*ns).tail, (*ns).length
+	case pos >= (*ns).length:
+		front.head, front.tail, front.length = (*ns).head, (*ns).tail, (*ns).length
+	default:
+		last := (*ns).head // the last node of the front
+		for ii := 1; ii < pos; ii++ {
+			last = last.next
+		}
+		front.head, front.tail, front.length = (*ns).head, last, pos
+		back.head, back.tail, back.length = last.next, (*ns).tail, (*ns).length-pos
+		last.next = nil
+	}
+	ns.Truncate()
+	return front, back
+}
+
+// SpliceAfter moves all the elements of `other` into this list after `el`, in order, and leaves
+// `other` empty.  If `el` is nil they go in at the head of the list.  `el` must be an element
+// of this list.
+// Complexity is O(1).
+func (ns *Sll[T]) SpliceAfter(el *SllElement[T], other *Sll[T]) error {
+	if ns == nil {
+		panic("list sholud not be a nil")
+	}
+	if other == ns {
+		return ErrSameList
+	}
+	if other == nil || (*other).length == 0 {
+		return nil
+	}
+	first, last := (*other).head, (*other).tail
+	if el != nil {
+		last.next = el.next
+		el.next = first
+	} else {
+		last.next = (*ns).head
+		(*ns).head = first
+	}
+	if last.next == nil {
+		(*ns).tail = last
+	}
+	(*ns).length += (*other).length
+	other.Truncate()
+	return nil
+}
+
+// Dedup removes each element that is equal, by `cmp`, to the element before it, so after a Sort
+// only the first of each set of equal elements is left.  It returns the number of elements
+// removed.
+// Complexity is O(n).
+func (ns *Sll[T]) Dedup(cmp func(a, b *T) int) (nRemoved int) {
+	if ns == nil {
+		panic("list sholud not be a nil")
+	}
+	for p := (*ns).head; p != nil && p.next != nil; {
+		if cmp(p.data, p.next.data) == 0 {
+			if p.next == (*ns).tail {
+				(*ns).tail = p
+			}
+			p.next = p.next.next
+			(*ns).length--
+			nRemoved++
+		} else {
+			p = p.next
+		}
+	}
+	return
+}
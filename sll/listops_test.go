@@ -0,0 +1,185 @@
+package sll
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// byFirst compares on the first byte only, so "1a" and "1b" are equal, to test stability.
+func byFirst(a, b *TestDemo) int {
+	return int(a.S[0]) - int(b.S[0])
+}
+
+func byString(a, b *TestDemo) int {
+	return strings.Compare(a.S, b.S)
+}
+
+func TestListSort(t *testing.T) {
+	List1 := newList("3a", "1a", "2a", "1b", "3b", "0a", "1c")
+	el := List1.head
+	List1.Sort(byFirst)
+	checkList(t, List1, "0a 1a 1b 1c 2a 3a 3b")
+	found := false
+	for p := List1.head; p != nil; p = p.next {
+		found = found || p == el
+	}
+	if !found || el.data.S != "3a" {
+		t.Errorf("Expected the elements to be re-linked, not copied")
+	}
+
+	List2 := newList()
+	List2.Sort(byString)
+	checkList(t, List2, "")
+
+	// Random lists against sort.SliceStable.
+	rnd := rand.New(rand.NewSource(1001))
+	for ii := 0; ii < 200; ii++ {
+		n := rnd.Intn(50)
+		data := make([]string, n)
+		for jj := range data {
+			data[jj] = fmt.Sprintf("%d%02d", rnd.Intn(5), jj)
+		}
+		List3 := newList(data...)
+		sort.SliceStable(data, func(i, j int) bool { return data[i][0] < data[j][0] })
+		List3.Sort(byFirst)
+		checkList(t, List3, strings.Join(data, " "))
+	}
+}
+
+func TestListMergeSorted(t *testing.T) {
+	List1 := newList("1a", "3a", "5a", "5b")
+	List2 := newList("0b", "1b", "4b", "5c", "6b")
+	if err := List1.MergeSorted(List2, byFirst); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+	checkList(t, List1, "0b 1a 1b 3a 4b 5a 5b 5c 6b")
+	checkList(t, List2, "")
+
+	List3 := newList()
+	List3.MergeSorted(newList("1", "2"), byString)
+	checkList(t, List3, "1 2")
+	List3.MergeSorted(newList(), byString)
+	checkList(t, List3, "1 2")
+	if err := List3.MergeSorted(List3, byString); err != ErrSameList {
+		t.Errorf("Expected ErrSameList got %v", err)
+	}
+}
+
+func TestListSplitAt(t *testing.T) {
+	tests := []struct {
+		pos         int
+		front, back string
+	}{
+		{pos: -1, front: "", back: "a b c d"},
+		{pos: 0, front: "", back: "a b c d"},
+		{pos: 1, front: "a", back: "b c d"},
+		{pos: 3, front: "a b c", back: "d"},
+		{pos: 4, front: "a b c d", back: ""},
+		{pos: 9, front: "a b c d", back: ""},
+	}
+	for _, tc := range tests {
+		List1 := newList("a", "b", "c", "d")
+		front, back := List1.SplitAt(tc.pos)
+		checkList(t, front, tc.front)
+		checkList(t, back, tc.back)
+		checkList(t, List1, "")
+	}
+}
+
+func TestListSpliceAfter(t *testing.T) {
+	List1 := newList("a", "b", "c")
+
+	List1.SpliceAfter(nil, newList("x", "y"))
+	checkList(t, List1, "x y a b c")
+
+	el := find(List1, "a")
+	List2 := newList("m", "n")
+	if err := List1.SpliceAfter(el, List2); err != nil {
+		t.Errorf("Unexpected error %s", err)
+	}
+	checkList(t, List1, "x y a m n b c")
+	checkList(t, List2, "")
+
+	el = find(List1, "c")
+	List1.SpliceAfter(el, newList("z"))
+	List1.SpliceAfter(el, newList())
+	checkList(t, List1, "x y a m n b c z")
+
+	if err := List1.SpliceAfter(el, List1); err != ErrSameList {
+		t.Errorf("Expected ErrSameList got %v", err)
+	}
+
+	List3 := newList()
+	List3.SpliceAfter(nil, newList("1", "2"))
+	checkList(t, List3, "1 2")
+}
+
+func TestListDedup(t *testing.T) {
+	List1 := newList("b", "a", "b", "b", "c", "a", "c", "c")
+	if n := List1.Dedup(byString); n != 2 {
+		t.Errorf("Expected to remove 2 got %d", n)
+	}
+	checkList(t, List1, "b a b c a c")
+
+	List1.Sort(byString)
+	if n := List1.Dedup(byString); n != 3 {
+		t.Errorf("Expected to remove 3 got %d", n)
+	}
+	checkList(t, List1, "a b c")
+
+	List2 := newList("1a", "1b", "1c")
+	List2.Dedup(byFirst)
+	checkList(t, List2, "1a")
+
+	List3 := newList()
+	if n := List3.Dedup(byString); n != 0 {
+		t.Errorf("Expected to remove 0 got %d", n)
+	}
+}
+
+// checkList verifies the list from head to tail, the tail and the length.
+func checkList(t *testing.T, ns *Sll[TestDemo], want string) {
+	t.Helper()
+	var got []string
+	var last *SllElement[TestDemo]
+	for p := ns.head; p != nil; p = p.next {
+		got = append(got, p.data.S)
+		last = p
+	}
+	if strings.Join(got, " ") != want {
+		t.Errorf("Expcted ->%s<- got ->%s<-", want, strings.Join(got, " "))
+	}
+	if ns.tail != last {
+		t.Errorf("The tail is not the last element")
+	}
+	if ns.Length() != len(got) {
+		t.Errorf("Expected length %d got %d", len(got), ns.Length())
+	}
+}
+
+func newList(data ...string) *Sll[TestDemo] {
+	ns := NewSll[TestDemo]()
+	for _, s := range data {
+		ns.InsertAfterTail(&TestDemo{S: s})
+	}
+	return ns
+}
+
+// find returns the first element with data `s`.
+func find(ns *Sll[TestDemo], s string) *SllElement[TestDemo] {
+	for p := ns.head; p != nil; p = p.next {
+		if p.data.S == s {
+			return p
+		}
+	}
+	return nil
+}
@@ -10,6 +10,11 @@ Basic operations on a Singly Linked List (SLL)
 *	IsEmpty() — Returns true if the sll is empty
 	AppendSLL(t T) -
 * 	Length() int -
+*	ConvertToSlice — Returns the data from head to tail as a slice.	O(n)
+*	Enque — Inserts after the tail so the SLL can be used as a Queue.	O(1)
+*	PeekTail — Returns the last element.	O(1)
+*	PopTail — Removes and returns the last element.	O(n)
+*	Sort, MergeSorted, SplitAt, SpliceAfter, Dedup — re-link the nodes of lists, see listops.go.
 
 */
 
@@ -20,6 +25,7 @@ import (
 	"iter"
 
 	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/iface_list"
 )
 
 // A node in the singly linked list
@@ -34,6 +40,11 @@ type Sll[T comparable.Equality] struct {
 	length     int
 }
 
+// At compile time verify that Sll can be used behind the iface_list interfaces.
+var _ iface_list.LinearDataType[comparable.Int] = (*Sll[comparable.Int])(nil)
+var _ iface_list.StackDataType[comparable.Int] = (*Sll[comparable.Int])(nil)
+var _ iface_list.QueueDataType[comparable.Int] = (*Sll[comparable.Int])(nil)
+
 // An iteration type that allows a for loop to walk the list.
 type SllIter[T comparable.Equality] struct {
 	cur *SllElement[T]
@@ -165,6 +176,9 @@ func (ns *Sll[T]) Pop() (rv *T, err error) {
 	}
 	rv = (*ns).head.data
 	(*ns).head = (*ns).head.next
+	if (*ns).head == nil {
+		(*ns).tail = nil
+	}
 	(*ns).length--
 	return
 }
@@ -181,13 +195,14 @@ func (ns *Sll[T]) DeleteFound(t *SllElement[T]) (err error) {
 	if ns.IsEmpty() {
 		return ErrEmptySll
 	}
-	if (*((*ns.head).data)).IsEqual(*t.data) { // IsEqual(b Equality) bool
-		*ns.head = *ns.head.next
-		return
-	}
-	for pp := &((*ns.head).next); *pp != nil; pp = &((*pp).next) {
+	var prev *SllElement[T]
+	for pp := &((*ns).head); *pp != nil; prev, pp = *pp, &((*pp).next) {
 		if (*((*pp).data)).IsEqual(*t.data) { // IsEqual(b Equality) bool
+			if *pp == (*ns).tail {
+				(*ns).tail = prev
+			}
 			*pp = (*pp).next
+			(*ns).length--
 			return
 		}
 	}
@@ -219,6 +234,51 @@ func (ns *Sll[T]) Peek() (rv *T, err error) {
 	return
 }
 
+// Enque will append a new node to the end of the list so the SLL can be used as a Queue.
+// This is just an alias for InsertAfterTail()
+func (ns *Sll[T]) Enque(t *T) {
+	ns.InsertAfterTail(t)
+}
+
+// PeekTail returns the last element of the list or an error indicating that the list is empty.	O(1)
+func (ns *Sll[T]) PeekTail() (rv *T, err error) {
+	if ns.IsEmpty() {
+		return nil, ErrEmptySll
+	}
+	rv = (*ns).tail.data
+	return
+}
+
+// PopTail will remove the last element from the list.  An error is returned if the list is empty.
+// A SLL has no back pointers so this has to walk the list to find the new tail.		O(n)
+func (ns *Sll[T]) PopTail() (rv *T, err error) {
+	if ns.IsEmpty() {
+		return nil, ErrEmptySll
+	}
+	var prev *SllElement[T]
+	p := (*ns).head
+	for ; p.next != nil; prev, p = p, p.next {
+	}
+	rv = p.data
+	if prev == nil {
+		(*ns).head = nil
+	} else {
+		prev.next = nil
+	}
+	(*ns).tail = prev
+	(*ns).length--
+	return
+}
+
+// ConvertToSlice returns the data in the list from head to tail.		O(n)
+func (ns *Sll[T]) ConvertToSlice() (rv []*T) {
+	rv = make([]*T, 0, (*ns).length)
+	for p := (*ns).head; p != nil; p = p.next {
+		rv = append(rv, p.data)
+	}
+	return
+}
+
 // Truncate removes all data from the list. 		O(1)
 func (ns *Sll[T]) Truncate() {
 	(*ns).head = nil
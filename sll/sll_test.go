@@ -13,6 +13,7 @@ import (
 
 	"github.com/pschlump/dbgo"
 	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/iface_list"
 )
 
 type TestDemo struct {
@@ -247,3 +248,48 @@ var db6 = false
 var db7 = false
 var db8 = false
 var db9 = false
+
+func TestLinearDataType(t *testing.T) {
+	var ll iface_list.LinearDataType[TestDemo] = &Sll[TestDemo]{}
+
+	ll.Enque(&TestDemo{S: "02"})
+	ll.Enque(&TestDemo{S: "03"})
+	ll.Push(&TestDemo{S: "01"})
+
+	got := ""
+	for _, v := range ll.ConvertToSlice() {
+		got += v.S
+	}
+	if got != "010203" {
+		t.Errorf("ConvertToSlice error, expcted %s got %s", "010203", got)
+	}
+
+	if a, err := ll.PeekTail(); err != nil || a.S != "03" {
+		t.Errorf("PeekTail error, expcted 03 got %v %v", a, err)
+	}
+	for _, expect := range []string{"03", "02", "01"} {
+		a, err := ll.PopTail()
+		if err != nil {
+			t.Fatalf("Unexpectd error %s from PopTail", err)
+		}
+		if a.S != expect {
+			t.Errorf("PopTail error, expcted %s got %s", expect, a.S)
+		}
+	}
+	if _, err := ll.PopTail(); err == nil {
+		t.Errorf("Expected error from PopTail on empty list")
+	}
+	if !ll.IsEmpty() || ll.Length() != 0 {
+		t.Errorf("Expected empty list, got length %d", ll.Length())
+	}
+
+	// The list must still work after PopTail emptied it.
+	ll.Push(&TestDemo{S: "04"})
+	ll.Enque(&TestDemo{S: "05"})
+	if x := ll.ConvertToSlice(); len(x) != 2 || x[0].S != "04" || x[1].S != "05" {
+		t.Errorf("Expected [04 05] after refill got %v", x)
+	}
+	if a, err := ll.PeekTail(); err != nil || a.S != "05" {
+		t.Errorf("PeekTail error, expcted 05 got %v %v", a, err)
+	}
+}
@@ -0,0 +1,41 @@
+package sll_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+// This test is meant to be run with the race detector, `go test -race`.
+
+import (
+	"testing"
+)
+
+func TestListOpsGoroutines(t *testing.T) {
+	// Each goroutine works on both lists, in opposite orders, which must not deadlock.  The
+	// elements move between the lists but none are lost.
+	List1, List2 := newList("1", "3", "5"), newList("0", "2", "4")
+	done := make(chan bool)
+	for gg := 0; gg < 2; gg++ {
+		go func(gg int) {
+			aa, bb := List1, List2
+			if gg == 1 {
+				aa, bb = List2, List1
+			}
+			for k := 0; k < 200; k++ {
+				aa.MergeSorted(bb, byString)
+				aa.Sort(byString)
+				front, back := aa.SplitAt(aa.Length() / 2)
+				aa.SpliceAfter(nil, front)
+				bb.SpliceAfter(nil, back)
+			}
+			done <- true
+		}(gg)
+	}
+	<-done
+	<-done
+
+	List1.MergeSorted(List2, byString)
+	checkList(t, List1, "0 1 2 3 4 5")
+}
@@ -0,0 +1,71 @@
+package sll_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"sort"
+	"sync/atomic"
+)
+
+// listIDs hands out the IDs that fix the order in which lists are locked.
+var listIDs atomic.Uint64
+
+// lockID returns the ID of the list.  A list gets its ID the first time it is locked along
+// with other lists, so that a zero value Sll is still ready to use.
+func (ns *Sll[T]) lockID() uint64 {
+	if id := ns.id.Load(); id != 0 {
+		return id
+	}
+	ns.id.CompareAndSwap(0, listIDs.Add(1))
+	return ns.id.Load()
+}
+
+// lockLists locks the lists in `wr` for writing and the lists in `rd` for reading.  The locks
+// are always taken in order of the list IDs so that two goroutines that work on the same lists,
+// in any argument order, can not deadlock.  A list that is passed more than once is locked
+// once, for writing if it is in `wr`.  The returned function releases the locks.
+func lockLists[T any](wr []*Sll[T], rd []*Sll[T]) (unlock func()) {
+	type listLock struct {
+		list  *Sll[T]
+		write bool
+	}
+	var locks []listLock
+	add := func(list *Sll[T], write bool) {
+		for ii := range locks {
+			if locks[ii].list == list {
+				locks[ii].write = locks[ii].write || write
+				return
+			}
+		}
+		locks = append(locks, listLock{list: list, write: write})
+	}
+	for _, list := range wr {
+		add(list, true)
+	}
+	for _, list := range rd {
+		add(list, false)
+	}
+
+	sort.Slice(locks, func(i, j int) bool { return locks[i].list.lockID() < locks[j].list.lockID() })
+	for _, ll := range locks {
+		if ll.write {
+			ll.list.mu.Lock()
+		} else {
+			ll.list.mu.RLock()
+		}
+	}
+
+	return func() {
+		for ii := len(locks) - 1; ii >= 0; ii-- {
+			if locks[ii].write {
+				locks[ii].list.mu.Unlock()
+			} else {
+				locks[ii].list.mu.RUnlock()
+			}
+		}
+	}
+}
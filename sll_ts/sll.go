@@ -10,6 +10,11 @@ Basic operations on a Singly Linked List (SLL)
 	IsEmpty() — Returns true if the sll is empty
 	AppendSLL(t T) -
  	Length() int -
+	ConvertToSlice — Returns the data from head to tail as a slice.	O(n)
+	Enque — Inserts after the tail so the SLL can be used as a Queue.	O(1)
+	PeekTail — Returns the last element.	O(1)
+	PopTail — Removes and returns the last element.	O(n)
+	Sort, MergeSorted, SplitAt, SpliceAfter, Dedup — re-link the nodes of lists, see listops.go.
 
 */
 
@@ -19,6 +24,10 @@ import (
 	"io"
 	"iter"
 	"sync"
+	"sync/atomic"
+
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/iface_list"
 )
 
 // A node in the singly linked list
@@ -31,9 +40,15 @@ type SllElement[T any] struct {
 type Sll[T any] struct {
 	head, tail *SllElement[T]
 	length     int
+	id         atomic.Uint64 // orders the locks when more than one list is locked, see lockLists
 	mu         sync.RWMutex
 }
 
+// At compile time verify that Sll can be used behind the iface_list interfaces.
+var _ iface_list.LinearDataType[comparable.Int] = (*Sll[comparable.Int])(nil)
+var _ iface_list.StackDataType[comparable.Int] = (*Sll[comparable.Int])(nil)
+var _ iface_list.QueueDataType[comparable.Int] = (*Sll[comparable.Int])(nil)
+
 // An iteration type that allows a for loop to walk the list.
 type SllIter[T any] struct {
 	cur      *SllElement[T]
@@ -187,6 +202,9 @@ func (ns *Sll[T]) Pop() (rv *T, err error) {
 	}
 	rv = ns.head.data
 	ns.head = ns.head.next
+	if ns.head == nil {
+		ns.tail = nil
+	}
 	ns.length--
 	return
 }
@@ -203,6 +221,57 @@ func (ns *Sll[T]) Peek() (rv *T, err error) {
 	return
 }
 
+// Enque will append a new node to the end of the list so the SLL can be used as a Queue.
+// This is just an alias for InsertAfterTail()
+func (ns *Sll[T]) Enque(t *T) {
+	ns.InsertAfterTail(t)
+}
+
+// PeekTail returns the last element of the list or an error indicating that the list is empty.   O(1)
+func (ns *Sll[T]) PeekTail() (rv *T, err error) {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
+	if ns.length == 0 {
+		return nil, ErrEmptySll
+	}
+	rv = ns.tail.data
+	return
+}
+
+// PopTail will remove the last element from the list.  An error is returned if the list is empty.
+// A SLL has no back pointers so this has to walk the list to find the new tail.   O(n)
+func (ns *Sll[T]) PopTail() (rv *T, err error) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	if ns.length == 0 {
+		return nil, ErrEmptySll
+	}
+	var prev *SllElement[T]
+	p := ns.head
+	for ; p.next != nil; prev, p = p, p.next {
+	}
+	rv = p.data
+	if prev == nil {
+		ns.head = nil
+	} else {
+		prev.next = nil
+	}
+	ns.tail = prev
+	ns.length--
+	return
+}
+
+// ConvertToSlice returns the data in the list from head to tail.   O(n)
+func (ns *Sll[T]) ConvertToSlice() (rv []*T) {
+	ns.mu.RLock()
+	defer ns.mu.RUnlock()
+	rv = make([]*T, 0, ns.length)
+	for p := ns.head; p != nil; p = p.next {
+		rv = append(rv, p.data)
+	}
+	return
+}
+
 // Truncate removes all data from the list.   O(1)
 func (ns *Sll[T]) Truncate() {
 	ns.mu.Lock()
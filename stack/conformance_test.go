@@ -0,0 +1,47 @@
+package stack
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"testing"
+
+	"github.com/pschlump/pluto/containertest"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// stackAdapter makes a Stack, which stores values, look like a StackDataType, which passes
+// pointers.
+type stackAdapter[T any] struct {
+	*Stack[T]
+}
+
+func (sa stackAdapter[T]) Push(data *T) { sa.Stack.Push(*data) }
+
+func (sa stackAdapter[T]) Pop() (data *T, err error) {
+	rv, err := sa.Stack.Pop()
+	if err != nil {
+		return nil, err
+	}
+	return &rv, nil
+}
+
+// ConvertToSlice returns the data from the top of the stack down.
+func (sa stackAdapter[T]) ConvertToSlice() (rv []*T) {
+	for i := len(*sa.Stack) - 1; i >= 0; i-- {
+		rv = append(rv, &(*sa.Stack)[i])
+	}
+	return
+}
+
+var testItem = containertest.Item[int]{
+	New: func(n int) *int { return &n },
+	Key: func(x *int) int { return *x },
+}
+
+func TestConformance(t *testing.T) {
+	containertest.TestStack(t, func() iface_list.StackDataType[int] { return stackAdapter[int]{&Stack[int]{}} }, testItem)
+}
@@ -12,6 +12,7 @@ Basic operations on a stack:
 *	Pop - will remove the top element from the stack.  An error is returned if the stack is empty.
 *	IsEmpty — Returns true if the stack is empty
 *	Peek — Returns the top element without removing from the stack
+*	ConvertToSlice — Returns the data from the top of the stack down as a slice
 
 Note: This is a subset of the operations that happen on the `sll_ts` so you can just use the
 singly linked list (thread safe) instead.
@@ -20,6 +21,7 @@ singly linked list (thread safe) instead.
 
 import (
 	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/iface_list"
 	"github.com/pschlump/pluto/sll_ts"
 )
 
@@ -28,6 +30,9 @@ type Stack[T comparable.Equality] struct {
 	data sll_ts.Sll[T]
 }
 
+// At compile time verify that Stack can be used behind the iface_list interfaces.
+var _ iface_list.StackDataType[comparable.Int] = (*Stack[comparable.Int])(nil)
+
 // IsEmpty will return true if the stack is empty
 func (ns *Stack[T]) IsEmpty() bool {
 	return ns.data.IsEmpty()
@@ -59,4 +64,9 @@ func (ns *Stack[T]) Truncate() {
 	ns.data.Truncate()
 }
 
+// ConvertToSlice returns the data in the stack from the top down.
+func (ns *Stack[T]) ConvertToSlice() []*T {
+	return ns.data.ConvertToSlice()
+}
+
 /* vim: set noai ts=4 sw=4: */
@@ -0,0 +1,71 @@
+package treap
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pschlump/pluto/comparable"
+)
+
+// TestTreeNode is an Inteface Matcing data type for the Nodes that supports the Comparable
+// interface.  This means that it has a Compare fucntion.
+type TestTreeNode struct {
+	S string
+}
+
+// At compile time verify that this is a correct type/interface setup.
+var _ comparable.Comparable = (*TestTreeNode)(nil)
+
+// Compare implements the Compare function to satisfy the interface requirements.
+func (aa TestTreeNode) Compare(x comparable.Comparable) int {
+	if bb, ok := x.(TestTreeNode); ok {
+		if aa.S < bb.S {
+			return -1
+		} else if aa.S > bb.S {
+			return 1
+		}
+	} else if bb, ok := x.(*TestTreeNode); ok {
+		if aa.S < bb.S {
+			return -1
+		} else if aa.S > bb.S {
+			return 1
+		}
+	} else {
+		panic(fmt.Sprintf("Passed invalid type %T to a Compare function.", x))
+	}
+	return 0
+}
+
+// validate checks that the priorities are a heap, that the sizes are right, and that the
+// items are in order.
+func validate(t *testing.T, tt *Treap[TestTreeNode]) {
+	t.Helper()
+	var check func(cur *TreapElement[TestTreeNode], lo, hi *TestTreeNode) (size int)
+	check = func(cur *TreapElement[TestTreeNode], lo, hi *TestTreeNode) (size int) {
+		if cur == nil {
+			return 0
+		}
+		if (lo != nil && lo.S >= cur.data.S) || (hi != nil && hi.S <= cur.data.S) {
+			t.Errorf("Item %s is out of order", cur.data.S)
+		}
+		for _, child := range []*TreapElement[TestTreeNode]{cur.left, cur.right} {
+			if child != nil && child.priority > cur.priority {
+				t.Errorf("Item %s has a child with a larger priority", cur.data.S)
+			}
+		}
+		size = check(cur.left, lo, cur.data) + check(cur.right, cur.data, hi) + 1
+		if cur.size != size {
+			t.Errorf("Item %s expected size %d got %d", cur.data.S, size, cur.size)
+		}
+		return
+	}
+	if size := check(tt.root, nil, nil); size != tt.length {
+		t.Errorf("Expected length %d got %d", size, tt.length)
+	}
+}
@@ -0,0 +1,40 @@
+package treap_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"github.com/pschlump/pluto/bst"
+	"github.com/pschlump/pluto/comparable"
+)
+
+// An iteration type that allows a for loop to walk the tree inorder, or
+// in reverse order from Rear.
+//
+//	for it := tree.Front(); !it.Done(); it.Next() {
+//		item := it.Value()
+//		...
+//	}
+//
+// This is more moemory effecient than the Walk* functions becasue it
+// manages the stack interally.  The tree must not be modified while
+// an iterator is in use.  Value, Next and Done are from bst.Iter.
+type TreapIter[T comparable.Comparable] struct {
+	bst.Iter[T, *TreapElement[T]]
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// Front will start at the inorder traversal beginning of the tree for iteration over tree.
+func (tt *Treap[T]) Front() (rv *TreapIter[T]) {
+	return &TreapIter[T]{bst.NewIter[T](tt.root, false)}
+}
+
+// Rear will start at the last inorder node of the tree for iteration over the tree in
+// reverse order.
+func (tt *Treap[T]) Rear() (rv *TreapIter[T]) {
+	return &TreapIter[T]{bst.NewIter[T](tt.root, true)}
+}
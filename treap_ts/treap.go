@@ -0,0 +1,170 @@
+package treap_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+A Treap, a binary search tree on the items that is also a heap on a random priority given to
+each node.  This version is thread safe, every method takes the tree's lock.  It has the same
+methods as the AVL tree in ../avl_tree_ts so they can be swapped.  The random priorities keep
+the expected depth at O(log|2(n)) with no balance information to maintain, an insert or
+delete does on average less than 2 rotations.
+
+* 	ConvertToSlice - return the data in order as a slice.										O(n)
+* 	Insert - create a new element in tree.														O(log|2(n)) expected
+*		Duplicates replace the current item - Insert returns false for a duplicate.
+* 	Delete — Deletes a specified element from the tree.										O(log|2(n)) expected
+* 	Index - return the Nth item in order.														O(log|2(n)) expected
+* 	Rank - the number of items less than an item, its position for Index.					O(log|2(n)) expected
+* 	IsEmpty — Returns true if the tree is empty													O(1)
+* 	Length — Returns number of elements in the tree.											O(1)
+* 	Search — Returns the matching element from the tree.										O(log|2(n)) expected
+* 	Truncate - Delete all the nodes in tree. 													O(1)
+*	FindMin - the smallest item.																O(log|2(n)) expected
+*	FindMax - the largest item.																	O(log|2(n)) expected
+*	Depth -> int to get deepest part of tree													O(n)
+* 	DeleteAtHead — Deletes the smallest element of the tree.  									O(log|2(n)) expected
+* 	DeleteAtTail — Deletes the largest element of the tree. 									O(log|2(n)) expected
+*	WalkInOrder, WalkPreOrder, WalkPostOrder - Apply a function to all the nodes.				O(n)
+*	Front, Rear - iterators in order and in reverse order.
+
+*/
+
+import (
+	"math/rand/v2"
+	"sync"
+
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/iface_list"
+)
+
+// TreapElement is a node in the tree.
+type TreapElement[T comparable.Comparable] struct {
+	data        *T
+	priority    uint32 // a node's priority is larger than its children's
+	size        int    // number of nodes in this sub-tree
+	left, right *TreapElement[T]
+}
+
+// Treap is a generic randomized balanced binary tree.
+type Treap[T comparable.Comparable] struct {
+	root   *TreapElement[T]
+	length int
+	lock   sync.RWMutex
+}
+
+// At compile time verify that Treap can be used behind the iface_list interfaces.
+var _ iface_list.TreeDataType[comparable.Int] = (*Treap[comparable.Int])(nil)
+
+// NewTreap creates a new Treap and return it.
+// Complexity is O(1).
+func NewTreap[T comparable.Comparable]() *Treap[T] {
+	return &Treap[T]{
+		root:   nil,
+		length: 0,
+	}
+}
+
+// rotateRight moves the left child of *root up into its place.
+func (tt *Treap[T]) rotateRight(root **TreapElement[T]) {
+	l := (*root).left
+	(*root).left = l.right
+	tt.setSize(*root)
+	l.right = *root
+	tt.setSize(l)
+	*root = l
+}
+
+// rotateLeft moves the right child of *root up into its place.
+func (tt *Treap[T]) rotateLeft(root **TreapElement[T]) {
+	r := (*root).right
+	(*root).right = r.left
+	tt.setSize(*root)
+	r.left = *root
+	tt.setSize(r)
+	*root = r
+}
+
+// nlInsert is the no-lock insert.  The new node goes in as a leaf and is rotated up while
+// its priority is larger than its parent's.
+func (tt *Treap[T]) nlInsert(item *T) (isNew bool) {
+	var insert func(root **TreapElement[T])
+	insert = func(root **TreapElement[T]) {
+		if *root == nil {
+			*root = &TreapElement[T]{data: item, priority: rand.Uint32(), size: 1}
+			tt.length++
+			isNew = true
+			return
+		}
+		if c := (*item).Compare(*(*root).data); c == 0 {
+			(*root).data = item // Replace duplicate.
+			return
+		} else if c < 0 {
+			insert(&((*root).left))
+			if (*root).left.priority > (*root).priority {
+				tt.rotateRight(root)
+			}
+		} else {
+			insert(&((*root).right))
+			if (*root).right.priority > (*root).priority {
+				tt.rotateLeft(root)
+			}
+		}
+		tt.setSize(*root)
+	}
+	insert(&tt.root)
+	return
+}
+
+// nlDelete is the no-lock delete.  The node is rotated down, always lifting the child with
+// the larger priority, until it has only one child and can be cut out.
+func (tt *Treap[T]) nlDelete(find *T) (found bool) {
+	var removeNode func(root **TreapElement[T])
+	removeNode = func(root **TreapElement[T]) {
+		n := *root
+		if n.left == nil {
+			*root = n.right
+			return
+		} else if n.right == nil {
+			*root = n.left
+			return
+		}
+		if n.left.priority > n.right.priority {
+			tt.rotateRight(root)
+			removeNode(&((*root).right))
+		} else {
+			tt.rotateLeft(root)
+			removeNode(&((*root).left))
+		}
+		tt.setSize(*root)
+	}
+
+	var remove func(root **TreapElement[T])
+	remove = func(root **TreapElement[T]) {
+		if *root == nil {
+			return
+		}
+		if c := (*find).Compare(*(*root).data); c == 0 {
+			found = true
+			removeNode(root)
+			return
+		} else if c < 0 {
+			remove(&((*root).left))
+		} else {
+			remove(&((*root).right))
+		}
+		if found {
+			tt.setSize(*root)
+		}
+	}
+
+	remove(&tt.root)
+	if found {
+		tt.length--
+	}
+	return
+}
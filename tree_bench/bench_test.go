@@ -0,0 +1,238 @@
+package tree_bench
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+import (
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pschlump/pluto/avl_tree"
+	"github.com/pschlump/pluto/avl_tree_ts"
+	"github.com/pschlump/pluto/binary_tree"
+	"github.com/pschlump/pluto/binary_tree_ts"
+	"github.com/pschlump/pluto/btree"
+	"github.com/pschlump/pluto/comparable"
+	"github.com/pschlump/pluto/iface_list"
+	"github.com/pschlump/pluto/rb_tree"
+	"github.com/pschlump/pluto/rb_tree_ts"
+	"github.com/pschlump/pluto/skiplist"
+	"github.com/pschlump/pluto/skiplist_ts"
+	"github.com/pschlump/pluto/treap"
+	"github.com/pschlump/pluto/treap_ts"
+)
+
+// benchKey is the item that is stored in the trees.
+type benchKey int
+
+// At compile time verify that this is a correct type/interface setup.
+var _ comparable.Comparable = (*benchKey)(nil)
+
+// Compare implements the Compare function to satisfy the interface requirements.
+func (aa benchKey) Compare(x comparable.Comparable) int {
+	var bb benchKey
+	if b, ok := x.(benchKey); ok {
+		bb = b
+	} else if b, ok := x.(*benchKey); ok {
+		bb = *b
+	} else {
+		panic(fmt.Sprintf("Passed invalid type %T to a Compare function.", x))
+	}
+	if aa < bb {
+		return -1
+	} else if aa > bb {
+		return 1
+	}
+	return 0
+}
+
+// benchTree is one of the trees to compare.  Unbalanced trees are skipped on the sorted
+// workloads where they turn in to a list.  Only thread safe trees are used on the parallel
+// workloads.
+type benchTree struct {
+	name       string
+	unbalanced bool
+	threadSafe bool
+	newFn      func() iface_list.TreeDataType[benchKey]
+}
+
+var benchTrees = []benchTree{
+	{name: "binary_tree", unbalanced: true, newFn: func() iface_list.TreeDataType[benchKey] { return binary_tree.NewBinaryTree[benchKey]() }},
+	{name: "binary_tree_ts", threadSafe: true, unbalanced: true, newFn: func() iface_list.TreeDataType[benchKey] { return binary_tree_ts.NewBinaryTree[benchKey]() }},
+	{name: "avl_tree", newFn: func() iface_list.TreeDataType[benchKey] { return avl_tree.NewAvlTree[benchKey]() }},
+	{name: "avl_tree_ts", threadSafe: true, newFn: func() iface_list.TreeDataType[benchKey] { return avl_tree_ts.NewAvlTree[benchKey]() }},
+	{name: "rb_tree", newFn: func() iface_list.TreeDataType[benchKey] { return rb_tree.NewRbTree[benchKey]() }},
+	{name: "rb_tree_ts", threadSafe: true, newFn: func() iface_list.TreeDataType[benchKey] { return rb_tree_ts.NewRbTree[benchKey]() }},
+	{name: "treap", newFn: func() iface_list.TreeDataType[benchKey] { return treap.NewTreap[benchKey]() }},
+	{name: "treap_ts", threadSafe: true, newFn: func() iface_list.TreeDataType[benchKey] { return treap_ts.NewTreap[benchKey]() }},
+	{name: "btree", newFn: func() iface_list.TreeDataType[benchKey] { return btree.NewBTree[benchKey]() }},
+	{name: "skiplist", newFn: func() iface_list.TreeDataType[benchKey] { return skiplist.NewSkipList[benchKey]() }},
+	{name: "skiplist_ts", threadSafe: true, newFn: func() iface_list.TreeDataType[benchKey] { return skiplist_ts.NewSkipList[benchKey]() }},
+}
+
+const benchSize = 10000
+
+// benchKeys returns the keys 0 to n-1 as items, shuffled if `rnd` is not nil.
+func benchKeys(n int, rnd *rand.Rand) []*benchKey {
+	keys := make([]*benchKey, n)
+	for ii := range keys {
+		k := benchKey(ii)
+		keys[ii] = &k
+	}
+	if rnd != nil {
+		rnd.Shuffle(n, func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+	}
+	return keys
+}
+
+// benchFilled returns a new tree with `keys` inserted in order.
+func benchFilled(bt benchTree, keys []*benchKey) iface_list.TreeDataType[benchKey] {
+	tree := bt.newFn()
+	for _, k := range keys {
+		tree.Insert(k)
+	}
+	return tree
+}
+
+// TestBenchTrees checks that every tree in the table gives the same results, so the
+// benchmarks compare trees that do the same work.
+func TestBenchTrees(t *testing.T) {
+	keys := benchKeys(1000, rand.New(rand.NewSource(1001)))
+	for _, bt := range benchTrees {
+		tree := benchFilled(bt, keys)
+		for _, k := range keys[:500] {
+			if !tree.Delete(k) {
+				t.Errorf("%s: expected to delete %d", bt.name, *k)
+			}
+		}
+		if tree.Length() != 500 {
+			t.Errorf("%s: expcted length 500 got %d", bt.name, tree.Length())
+		}
+		for pos, item := range tree.ConvertToSlice() {
+			if pos > 0 && *tree.Index(pos - 1) >= *item {
+				t.Errorf("%s: items out of order at %d", bt.name, pos)
+			}
+		}
+	}
+}
+
+func BenchmarkRandomInsert(b *testing.B) {
+	keys := benchKeys(benchSize, rand.New(rand.NewSource(1001)))
+	for _, bt := range benchTrees {
+		b.Run(bt.name, func(b *testing.B) {
+			for ii := 0; ii < b.N; ii++ {
+				benchFilled(bt, keys)
+			}
+		})
+	}
+}
+
+func BenchmarkSequentialInsert(b *testing.B) {
+	keys := benchKeys(benchSize, nil)
+	for _, bt := range benchTrees {
+		if bt.unbalanced {
+			continue
+		}
+		b.Run(bt.name, func(b *testing.B) {
+			for ii := 0; ii < b.N; ii++ {
+				benchFilled(bt, keys)
+			}
+		})
+	}
+}
+
+func BenchmarkMixed(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1001))
+	keys := benchKeys(benchSize, rnd)
+	extra := benchKeys(2*benchSize, rnd)
+	for _, bt := range benchTrees {
+		b.Run(bt.name, func(b *testing.B) {
+			tree := benchFilled(bt, keys)
+			b.ResetTimer()
+			for ii := 0; ii < b.N; ii++ {
+				k := extra[ii%len(extra)]
+				if ii%2 == 0 {
+					tree.Delete(k)
+				} else {
+					tree.Insert(k)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDeleteAll(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1001))
+	keys := benchKeys(benchSize, rnd)
+	order := benchKeys(benchSize, rnd)
+	for _, bt := range benchTrees {
+		b.Run(bt.name, func(b *testing.B) {
+			for ii := 0; ii < b.N; ii++ {
+				b.StopTimer()
+				tree := benchFilled(bt, keys)
+				b.StartTimer()
+				for _, k := range order {
+					tree.Delete(k)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSearch(b *testing.B) {
+	keys := benchKeys(benchSize, rand.New(rand.NewSource(1001)))
+	for _, bt := range benchTrees {
+		b.Run(bt.name, func(b *testing.B) {
+			tree := benchFilled(bt, keys)
+			b.ResetTimer()
+			for ii := 0; ii < b.N; ii++ {
+				tree.Search(keys[ii%len(keys)])
+			}
+		})
+	}
+}
+
+// benchParallel runs a workload from many go routines at once where `writes` out of every 100
+// operations are an insert or a delete and the rest are a search.
+func benchParallel(b *testing.B, writes int) {
+	rnd := rand.New(rand.NewSource(1001))
+	keys := benchKeys(benchSize, rnd)
+	extra := benchKeys(2*benchSize, rnd)
+	for _, bt := range benchTrees {
+		if !bt.threadSafe {
+			continue
+		}
+		b.Run(bt.name, func(b *testing.B) {
+			tree := benchFilled(bt, keys)
+			var seed atomic.Int64
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				rnd := rand.New(rand.NewSource(seed.Add(1)))
+				for pb.Next() {
+					k := extra[rnd.Intn(len(extra))]
+					switch op := rnd.Intn(100); {
+					case op >= writes:
+						tree.Search(k)
+					case op%2 == 0:
+						tree.Delete(k)
+					default:
+						tree.Insert(k)
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkParallelReadMostly(b *testing.B) {
+	benchParallel(b, 10)
+}
+
+func BenchmarkParallelMixed(b *testing.B) {
+	benchParallel(b, 50)
+}
@@ -0,0 +1,27 @@
+package tree_bench
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+Benchmarks that compare the ordered set trees, binary_tree, avl_tree, rb_tree, treap, btree
+and skiplist and the thread safe versions, on the same workloads.  All of the trees are used through the
+iface_list.TreeDataType interface so they are compared on equal terms.
+
+* 	RandomInsert — insert items in a random order.
+* 	SequentialInsert — insert items in increasing order, the worst case for an unbalanced tree.
+* 	Mixed — delete and insert random items in a tree that already holds 10,000 items.
+* 	DeleteAll — insert items in a random order and then delete them all in a different order.
+* 	Search — look up random items in a tree that already holds 10,000 items.
+* 	ParallelReadMostly — 90% search and 10% insert or delete from many go routines at once.
+* 	ParallelMixed — 50% search and 50% insert or delete from many go routines at once.
+
+Run with:
+
+	go test -run NONE -bench . -benchmem
+
+*/
@@ -0,0 +1,358 @@
+package trie
+
+/*
+Copyright (C) Philip Schlump, 2012-2023.
+
+BSD 3 Clause Licensed.
+*/
+
+/*
+
+A Trie, a tree where each edge is one byte of the key, so all the keys that start with the
+same bytes share the same path from the root.  The keys can be a string or a []byte, a key is
+found in O(k), k the length of the key, no matter how many keys there are.  This is a no-lock,
+not thread safe version, see ../trie_ts for a thread safe version and ../radix for a
+compressed version that uses less memory.
+
+* 	Insert - set the value for a key, returns true if the key is new.							O(k)
+* 	Get - returns the value for a key and true, or false if the key is not in the trie.		O(k)
+* 	Delete — removes a key, returns true if it was in the trie.								O(k)
+* 	LongestPrefix - the longest key in the trie that is a prefix of a key.						O(k)
+* 	WalkPrefix - call a function for each key that starts with a prefix, in order.			O(k+m)
+* 	WithPrefix - an iter.Seq2 over the keys that start with a prefix, in order.				O(k+m)
+* 	All - an iter.Seq2 over all the keys in order.												O(n)
+* 	IsEmpty — Returns true if the trie is empty													O(1)
+* 	Length — Returns number of keys in the trie.												O(1)
+* 	Truncate - Delete all the keys in the trie. 												O(1)
+* 	Dump - print out the trie.																	O(n)
+
+k is the length of the key and m is the size of the part of the trie that is walked.  The
+order is lexical, byte by byte, so "ab" is before "abc" which is before "b".
+
+*/
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+	"strings"
+	// "sync"
+)
+
+// Key is the type of the keys, a string or a []byte.
+type Key interface {
+	~string | ~[]byte
+}
+
+// trieEdge is a link from a node to the child for one byte of the key.
+type trieEdge[K Key, V any] struct {
+	label byte
+	node  *TrieNode[K, V]
+}
+
+// TrieNode is a node in the trie.  It has a value if a key ends at this node.
+type TrieNode[K Key, V any] struct {
+	edges    []trieEdge[K, V] // sorted by label
+	key      K                // the full key, if hasValue
+	value    V
+	hasValue bool
+}
+
+// Trie is a generic trie from keys to values of type V.
+type Trie[K Key, V any] struct {
+	root   TrieNode[K, V]
+	length int
+	// lock   sync.RWMutex
+}
+
+// NewTrie creates a new Trie and return it.
+// Complexity is O(1).
+func NewTrie[K Key, V any]() *Trie[K, V] {
+	return &Trie[K, V]{}
+}
+
+// cloneKey returns a copy of `key`, so that a []byte key can not be changed by the caller
+// after it is in the trie.
+func cloneKey[K Key](key K) K {
+	return K(string(key))
+}
+
+// find returns the position of the edge for `label`, and true if it is there.  If not the
+// position is where it would be inserted.
+func (nd *TrieNode[K, V]) find(label byte) (pos int, found bool) {
+	lo, hi := 0, len(nd.edges)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if nd.edges[mid].label < label {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	return lo, lo < len(nd.edges) && nd.edges[lo].label == label
+}
+
+// child returns the child for `label`, nil if there is none.
+func (nd *TrieNode[K, V]) child(label byte) *TrieNode[K, V] {
+	if pos, found := nd.find(label); found {
+		return nd.edges[pos].node
+	}
+	return nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// IsEmpty will return true if the trie is empty
+// Complexity is O(1).
+func (tt *Trie[K, V]) IsEmpty() bool {
+	if tt == nil {
+		panic("trie sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.length == 0
+}
+
+// Length returns the number of keys in the trie.
+// Complexity is O(1).
+func (tt *Trie[K, V]) Length() int {
+	if tt == nil {
+		panic("trie sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.length
+}
+
+// Truncate removes all the keys from the trie.
+// Complexity is O(1).
+func (tt *Trie[K, V]) Truncate() {
+	if tt == nil {
+		panic("trie sholud not be a nil")
+	}
+
+	// tt.lock.Lock()
+	// defer tt.lock.Unlock()
+
+	tt.root = TrieNode[K, V]{}
+	tt.length = 0
+}
+
+// Insert sets the value for `key`, replacing any current value.  It returns true if the key
+// was not already in the trie.
+// Complexity is O(k).
+func (tt *Trie[K, V]) Insert(key K, value V) (isNew bool) {
+	if tt == nil {
+		panic("trie sholud not be a nil")
+	}
+
+	// tt.lock.Lock()
+	// defer tt.lock.Unlock()
+
+	return tt.nlInsert(key, value)
+}
+
+func (tt *Trie[K, V]) nlInsert(key K, value V) (isNew bool) {
+	nd := &tt.root
+	for ii := 0; ii < len(key); ii++ {
+		pos, found := nd.find(key[ii])
+		if !found {
+			nd.edges = slices.Insert(nd.edges, pos, trieEdge[K, V]{label: key[ii], node: &TrieNode[K, V]{}})
+		}
+		nd = nd.edges[pos].node
+	}
+	if !nd.hasValue {
+		nd.key, nd.hasValue = cloneKey(key), true
+		tt.length++
+		isNew = true
+	}
+	nd.value = value
+	return
+}
+
+// Get returns the value for `key`.  If the key is not in the trie then found is false.
+// Complexity is O(k).
+func (tt *Trie[K, V]) Get(key K) (value V, found bool) {
+	if tt == nil {
+		panic("trie sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	return tt.nlGet(key)
+}
+
+func (tt *Trie[K, V]) nlGet(key K) (value V, found bool) {
+	if nd := tt.nlFindNode(key); nd != nil && nd.hasValue {
+		return nd.value, true
+	}
+	return
+}
+
+// nlFindNode returns the node at the end of the path for `key`, nil if there is no path.
+func (tt *Trie[K, V]) nlFindNode(key K) *TrieNode[K, V] {
+	nd := &tt.root
+	for ii := 0; ii < len(key) && nd != nil; ii++ {
+		nd = nd.child(key[ii])
+	}
+	return nd
+}
+
+// Delete removes `key` from the trie.  It returns true if the key was in the trie.  Nodes
+// that are no longer on the path to any key are removed.
+// Complexity is O(k).
+func (tt *Trie[K, V]) Delete(key K) (found bool) {
+	if tt == nil {
+		panic("trie sholud not be a nil")
+	}
+
+	// tt.lock.Lock()
+	// defer tt.lock.Unlock()
+
+	return tt.nlDelete(key)
+}
+
+func (tt *Trie[K, V]) nlDelete(key K) (found bool) {
+	path := make([]*TrieNode[K, V], 0, len(key)+1)
+	nd := &tt.root
+	path = append(path, nd)
+	for ii := 0; ii < len(key); ii++ {
+		if nd = nd.child(key[ii]); nd == nil {
+			return false
+		}
+		path = append(path, nd)
+	}
+	if !nd.hasValue {
+		return false
+	}
+	var zeroKey K
+	var zeroValue V
+	nd.key, nd.value, nd.hasValue = zeroKey, zeroValue, false
+	tt.length--
+
+	// Remove the nodes at the end of the path that now lead nowhere.
+	for ii := len(key); ii > 0; ii-- {
+		if nd := path[ii]; nd.hasValue || len(nd.edges) > 0 {
+			break
+		}
+		parent := path[ii-1]
+		pos, _ := parent.find(key[ii-1])
+		parent.edges = slices.Delete(parent.edges, pos, pos+1)
+	}
+	return true
+}
+
+// LongestPrefix returns the longest key in the trie that is a prefix of `key`, or is equal to
+// it, and its value.  If there is no such key then found is false.
+//
+//	trie.Insert("/api/", apiHandler)
+//	prefix, handler, found := trie.LongestPrefix("/api/users/12")	// "/api/", apiHandler, true
+//
+// Complexity is O(k).
+func (tt *Trie[K, V]) LongestPrefix(key K) (prefix K, value V, found bool) {
+	if tt == nil {
+		panic("trie sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	nd := &tt.root
+	for ii := 0; nd != nil; ii++ {
+		if nd.hasValue {
+			prefix, value, found = nd.key, nd.value, true
+		}
+		if ii == len(key) {
+			break
+		}
+		nd = nd.child(key[ii])
+	}
+	return
+}
+
+// WalkPrefix calls `fx` in order for each key that starts with `prefix`, and its value.  An
+// empty prefix walks all the keys.  The walk stops if `fx` returns false.
+// Complexity is O(k+m), m the size of the part of the trie under the prefix.
+func (tt *Trie[K, V]) WalkPrefix(prefix K, fx func(key K, value V) bool) {
+	if tt == nil {
+		panic("trie sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	tt.nlWalkPrefix(prefix, fx)
+}
+
+func (tt *Trie[K, V]) nlWalkPrefix(prefix K, fx func(key K, value V) bool) {
+	if nd := tt.nlFindNode(prefix); nd != nil {
+		nlWalk(nd, fx)
+	}
+}
+
+// nlWalk calls `fx` for the keys at and under `nd` in order.  It returns false if `fx` did.
+func nlWalk[K Key, V any](nd *TrieNode[K, V], fx func(key K, value V) bool) bool {
+	if nd.hasValue && !fx(nd.key, nd.value) {
+		return false
+	}
+	for _, edge := range nd.edges {
+		if !nlWalk(edge.node, fx) {
+			return false
+		}
+	}
+	return true
+}
+
+// WithPrefix returns an iterator over the keys that start with `prefix` and their values,
+// in order.
+//
+//	for key, value := range trie.WithPrefix("ab") {
+//		...
+//	}
+func (tt *Trie[K, V]) WithPrefix(prefix K) iter.Seq2[K, V] {
+	if tt == nil {
+		panic("trie sholud not be a nil")
+	}
+	return func(yield func(K, V) bool) {
+		tt.WalkPrefix(prefix, yield)
+	}
+}
+
+// All returns an iterator over all the keys and their values in order.
+func (tt *Trie[K, V]) All() iter.Seq2[K, V] {
+	var empty K
+	return tt.WithPrefix(empty)
+}
+
+// Dump will print out the trie to the file `fo`, one node per line indented by its depth.
+// Complexity is O(n).
+func (tt *Trie[K, V]) Dump(fo io.Writer) {
+	if tt == nil {
+		panic("trie sholud not be a nil")
+	}
+
+	// tt.lock.RLock()
+	// defer tt.lock.RUnlock()
+
+	var dump func(nd *TrieNode[K, V], depth int)
+	dump = func(nd *TrieNode[K, V], depth int) {
+		for _, edge := range nd.edges {
+			fmt.Fprintf(fo, "%s%q", strings.Repeat(" ", 4*depth), edge.label)
+			if edge.node.hasValue {
+				fmt.Fprintf(fo, " = %v", edge.node.value)
+			}
+			fmt.Fprintf(fo, "\n")
+			dump(edge.node, depth+1)
+		}
+	}
+	if tt.root.hasValue {
+		fmt.Fprintf(fo, "\"\" = %v\n", tt.root.value)
+	}
+	dump(&tt.root, 0)
+}
@@ -0,0 +1,86 @@
+package trie_ts
+
+/*
+Copyright (C) Philip Schlump, 2012-2024.
+
+BSD 3 Clause Licensed.
+*/
+
+// This test is meant to be run with the race detector, `go test -race`.
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestTrieGoroutines(t *testing.T) {
+	var Tr1 Trie[string, int]
+
+	// Writers insert and delete their own keys under a shared prefix while readers do Get,
+	// LongestPrefix and walks.  The "/base/" keys are never deleted so they must always be found.
+	const nKeys = 500
+	for k := 0; k < nKeys; k++ {
+		Tr1.Insert(fmt.Sprintf("/base/%04d", k), k)
+	}
+
+	var wg sync.WaitGroup
+	for ww := 0; ww < 2; ww++ {
+		wg.Add(1)
+		go func(ww int) {
+			defer wg.Done()
+			for k := 0; k < nKeys; k++ {
+				Tr1.Insert(fmt.Sprintf("/w%d/%04d", ww, k), k)
+			}
+			for k := 0; k < nKeys; k += 2 {
+				if !Tr1.Delete(fmt.Sprintf("/w%d/%04d", ww, k)) {
+					t.Errorf("Expected to delete /w%d/%04d", ww, k)
+				}
+			}
+		}(ww)
+	}
+	for rd := 0; rd < 4; rd++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ii := 0; ii < 20; ii++ {
+				for k := 0; k < nKeys; k += 25 {
+					if got, found := Tr1.Get(fmt.Sprintf("/base/%04d", k)); !found || got != k {
+						t.Errorf("Expected to find /base/%04d", k)
+					}
+					if prefix, _, found := Tr1.LongestPrefix(fmt.Sprintf("/base/%04d/x", k)); !found || prefix != fmt.Sprintf("/base/%04d", k) {
+						t.Errorf("LongestPrefix expcted /base/%04d got %s", k, prefix)
+					}
+				}
+				prev, n := "", 0
+				for key := range Tr1.WithPrefix("/base/") {
+					if key <= prev {
+						t.Errorf("Walk is out of order at %s", key)
+					}
+					prev = key
+					n++
+				}
+				if n != nKeys {
+					t.Errorf("Expected %d keys under /base/ got %d", nKeys, n)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if Tr1.Length() != nKeys+2*(nKeys/2) {
+		t.Errorf("Expected length %d got %d", nKeys+2*(nKeys/2), Tr1.Length())
+	}
+
+	// A read-modify-write done under the write lock with the no-lock methods.
+	Tr1.WriteLock()
+	if value, found := Tr1.NlGet("/base/0001"); found {
+		Tr1.NlInsert("/base/0001", value+100)
+	}
+	Tr1.WriteUnlock()
+	Tr1.ReadLock()
+	if got, _ := Tr1.NlGet("/base/0001"); got != 101 {
+		t.Errorf("Expected 101 got %d", got)
+	}
+	Tr1.ReadUnlock()
+}